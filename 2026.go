@@ -835,6 +835,10 @@ func LogAI(format string, v ...any) {
 	slog.Info(fmt.Sprintf(format, v...), slog.String("component", "ai"))
 }
 
+func LogGame(format string, v ...any) {
+	slog.Info(fmt.Sprintf(format, v...), slog.String("component", "game"))
+}
+
 func LogLoader(format string, v ...any) {
 	slog.Info(fmt.Sprintf(format, v...), slog.String("component", "loader"))
 }
@@ -962,9 +966,25 @@ const (
 	MsgDBScanLoopConfigFail    = "failed to scan loop config: %w"
 	MsgDBParseLoopConfigIDFail = "failed to parse channel ID '%s' for loop config: %w"
 	MsgDBParseRoleIDFail       = "failed to parse role ID: %w"
+	MsgDBParseDJRoleIDFail     = "failed to parse DJ role ID '%s': %w"
+	MsgDBParseRadioChanFail    = "failed to parse radio channel ID '%s': %w"
+	MsgDBParseRadioGuildFail   = "failed to parse guild ID '%s' for radio: %w"
 	MsgDBScanGuildConfigFail   = "failed to scan guild config: %w"
 	MsgDBParseGuildIDColorFail = "failed to parse guild ID '%s' in random colors: %w"
 	MsgDBParseRoleIDColorFail  = "failed to parse role ID '%s' in random colors: %w"
+	MsgDBScanGameSessionFail   = "failed to scan game session: %w"
+	MsgDBScanGameRatingFail    = "failed to scan game rating: %w"
+	MsgDBParseRatingUserFail   = "failed to parse user ID '%s' for game rating: %w"
+	MsgDBScanPlaylistFail      = "failed to scan playlist: %w"
+	MsgDBScanPlaylistTrackFail = "failed to scan playlist track: %w"
+	MsgDBParsePlaylistUserFail = "failed to parse user ID '%s' for playlist %d: %w"
+	MsgDBScanVoiceSessionFail  = "failed to scan voice session: %w"
+	MsgDBParseVoiceGuildFail   = "failed to parse guild ID '%s' for voice session: %w"
+	MsgDBParseVoiceChanFail    = "failed to parse channel ID '%s' for voice session: %w"
+	MsgDBScanVoicePlayFail     = "failed to scan voice play: %w"
+	MsgDBParseVoicePlayFail    = "failed to parse requester ID '%s' for voice play: %w"
+	MsgDBScanStationFail       = "failed to scan station: %w"
+	MsgDBParseStationUserFail  = "failed to parse user ID '%s' for station %q: %w"
 
 	// Environment Variables
 	EnvDiscordToken = "DISCORD_TOKEN"
@@ -972,6 +992,9 @@ const (
 	EnvStreamingURL = "STREAMING_URL"
 	EnvOwnerIDs     = "OWNER_IDS"
 	EnvGuildID      = "GUILD_ID"
+	EnvMusicDir     = "MUSIC_DIR"
+	EnvLyricsDir    = "LYRICS_DIR"
+	EnvAudioCache   = "AUDIO_CACHE_MAX_MB"
 	EnvAIMaxLen     = "AI_MAX_LENGTH"
 	EnvAIKeySize    = "AI_MAX_KEY_SIZE"
 	EnvAITry        = "AI_ATTEMPTS"
@@ -989,6 +1012,9 @@ type Config struct {
 	DatabasePath           string
 	OwnerIDs               []string
 	StreamingURL           string
+	MusicDir               string
+	LyricsDir              string
+	AudioCacheMaxMB        int
 	Silent                 bool
 	AIMaxLength            int
 	AIMaxKeySize           int
@@ -1026,9 +1052,15 @@ func LoadConfig() (*Config, error) {
 		DatabasePath: dbPath,
 		OwnerIDs:     ownerIDs,
 		StreamingURL: streamingURL,
+		MusicDir:     os.Getenv(EnvMusicDir),
+		LyricsDir:    os.Getenv(EnvLyricsDir),
 		Silent:       silent,
 	}
 
+	cfg.AudioCacheMaxMB, _ = strconv.Atoi(os.Getenv(EnvAudioCache))
+	if cfg.AudioCacheMaxMB == 0 {
+		cfg.AudioCacheMaxMB = 2048
+	}
 	cfg.AIMaxLength, _ = strconv.Atoi(os.Getenv(EnvAIMaxLen))
 	if cfg.AIMaxLength == 0 {
 		cfg.AIMaxLength = 15
@@ -1077,6 +1109,14 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// IsBotOwner reports whether userID is listed in OWNER_IDS
+func IsBotOwner(userID snowflake.ID) bool {
+	if GlobalConfig == nil {
+		return false
+	}
+	return slices.Contains(GlobalConfig.OwnerIDs, userID.String())
+}
+
 func GetProjectName() string {
 	exePath, err := os.Executable()
 	projectName := "bot"
@@ -1203,6 +1243,69 @@ func InitDatabase(ctx context.Context, dataSourceName string) error {
 			PRIMARY KEY (channel_id, key_text, next_id)
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_ai_messages_channel_id ON ai_messages(channel_id)`,
+		`CREATE TABLE IF NOT EXISTS game_sessions (
+			game_id TEXT PRIMARY KEY,
+			game_type TEXT NOT NULL,
+			state TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS game_ratings (
+			user_id TEXT NOT NULL,
+			game_type TEXT NOT NULL,
+			rating REAL NOT NULL,
+			wins INTEGER DEFAULT 0,
+			losses INTEGER DEFAULT 0,
+			draws INTEGER DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, game_type)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_game_ratings_type_rating ON game_ratings(game_type, rating DESC)`,
+		`CREATE TABLE IF NOT EXISTS playlists (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			scope TEXT NOT NULL,
+			owner_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			created_by TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (scope, owner_id, name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS playlist_tracks (
+			playlist_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			url TEXT NOT NULL,
+			title TEXT,
+			channel TEXT,
+			duration_ms INTEGER DEFAULT 0,
+			PRIMARY KEY (playlist_id, position)
+		)`,
+		`CREATE TABLE IF NOT EXISTS voice_sessions (
+			guild_id TEXT PRIMARY KEY,
+			channel_id TEXT NOT NULL,
+			state TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS voice_plays (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			guild_id TEXT NOT NULL,
+			requested_by TEXT,
+			url TEXT NOT NULL,
+			title TEXT,
+			channel TEXT,
+			duration_ms INTEGER DEFAULT 0,
+			played_ms INTEGER DEFAULT 0,
+			skipped INTEGER DEFAULT 0,
+			played_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_voice_plays_guild_time ON voice_plays(guild_id, played_at)`,
+		`CREATE TABLE IF NOT EXISTS voice_stations (
+			guild_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			added_by TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (guild_id, name)
+		)`,
 	}
 
 	for _, q := range tableQueries {
@@ -1231,6 +1334,11 @@ func InitDatabase(ctx context.Context, dataSourceName string) error {
 		"CREATE INDEX IF NOT EXISTS idx_ai_messages_sticker_hash ON ai_messages(sticker_hash)",
 		"CREATE INDEX IF NOT EXISTS idx_ai_messages_attachment_hash ON ai_messages(attachment_hash)",
 		"CREATE INDEX IF NOT EXISTS idx_ai_messages_reaction_hash ON ai_messages(reaction_hash)",
+		"ALTER TABLE guild_configs ADD COLUMN dj_role_id TEXT",
+		"ALTER TABLE guild_configs ADD COLUMN vote_skip_percent INTEGER DEFAULT 50",
+		"ALTER TABLE guild_configs ADD COLUMN radio_playlist TEXT",
+		"ALTER TABLE guild_configs ADD COLUMN radio_seeds TEXT",
+		"ALTER TABLE guild_configs ADD COLUMN radio_channel_id TEXT",
 	}
 
 	legacyColumns := []string{"content", "sticker_id", "attachment_id", "attachment_url", "reactions"}
@@ -2028,6 +2136,687 @@ func GetAllGuildRandomColorConfigs(ctx context.Context) (map[snowflake.ID]snowfl
 	return configs, nil
 }
 
+// DefaultVoteSkipPercent is the share of listeners needed to vote-skip when a guild hasn't configured one
+const DefaultVoteSkipPercent = 50
+
+func SetGuildDJRole(ctx context.Context, guildID, roleID snowflake.ID) error {
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO guild_configs (guild_id, dj_role_id) VALUES (?, ?)
+		ON CONFLICT(guild_id) DO UPDATE SET dj_role_id = excluded.dj_role_id, updated_at = CURRENT_TIMESTAMP
+	`, guildID.String(), roleID.String())
+	return err
+}
+
+func SetGuildVoteSkipPercent(ctx context.Context, guildID snowflake.ID, percent int) error {
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO guild_configs (guild_id, vote_skip_percent) VALUES (?, ?)
+		ON CONFLICT(guild_id) DO UPDATE SET vote_skip_percent = excluded.vote_skip_percent, updated_at = CURRENT_TIMESTAMP
+	`, guildID.String(), percent)
+	return err
+}
+
+// GetGuildVoiceSettings returns the guild's DJ role (0 when unset) and vote-skip share in percent
+func GetGuildVoiceSettings(ctx context.Context, guildID snowflake.ID) (snowflake.ID, int, error) {
+	var roleIDStr sql.NullString
+	var percent sql.NullInt64
+	err := DB.QueryRowContext(ctx, "SELECT dj_role_id, vote_skip_percent FROM guild_configs WHERE guild_id = ?", guildID.String()).Scan(&roleIDStr, &percent)
+	if err == sql.ErrNoRows {
+		return 0, DefaultVoteSkipPercent, nil
+	}
+	if err != nil {
+		return 0, DefaultVoteSkipPercent, err
+	}
+	votePercent := DefaultVoteSkipPercent
+	if percent.Valid && percent.Int64 > 0 {
+		votePercent = int(percent.Int64)
+	}
+	if !roleIDStr.Valid || roleIDStr.String == "" {
+		return 0, votePercent, nil
+	}
+	roleID, err := snowflake.Parse(roleIDStr.String)
+	if err != nil {
+		return 0, votePercent, fmt.Errorf(MsgDBParseDJRoleIDFail, roleIDStr.String, err)
+	}
+	return roleID, votePercent, nil
+}
+
+// GuildRadioConfig is what /voice radio draws from: a saved server playlist and/or seed URLs or searches.
+// ChannelID is set while the radio is on so it can be resumed after a restart.
+type GuildRadioConfig struct {
+	Playlist  string
+	Seeds     []string
+	ChannelID snowflake.ID
+}
+
+func SetGuildRadioSeed(ctx context.Context, guildID snowflake.ID, playlist string, seeds []string) error {
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO guild_configs (guild_id, radio_playlist, radio_seeds) VALUES (?, ?, ?)
+		ON CONFLICT(guild_id) DO UPDATE SET radio_playlist = excluded.radio_playlist, radio_seeds = excluded.radio_seeds, updated_at = CURRENT_TIMESTAMP
+	`, guildID.String(), playlist, strings.Join(seeds, "\n"))
+	return err
+}
+
+// SetGuildRadioChannel records where the radio is playing; 0 turns it off
+func SetGuildRadioChannel(ctx context.Context, guildID, channelID snowflake.ID) error {
+	channel := ""
+	if channelID != 0 {
+		channel = channelID.String()
+	}
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO guild_configs (guild_id, radio_channel_id) VALUES (?, ?)
+		ON CONFLICT(guild_id) DO UPDATE SET radio_channel_id = excluded.radio_channel_id, updated_at = CURRENT_TIMESTAMP
+	`, guildID.String(), channel)
+	return err
+}
+
+func GetGuildRadio(ctx context.Context, guildID snowflake.ID) (GuildRadioConfig, error) {
+	var cfg GuildRadioConfig
+	var playlist, seeds, channel sql.NullString
+	err := DB.QueryRowContext(ctx, "SELECT radio_playlist, radio_seeds, radio_channel_id FROM guild_configs WHERE guild_id = ?", guildID.String()).Scan(&playlist, &seeds, &channel)
+	if err == sql.ErrNoRows {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Playlist = playlist.String
+	for s := range strings.SplitSeq(seeds.String, "\n") {
+		if s = strings.TrimSpace(s); s != "" {
+			cfg.Seeds = append(cfg.Seeds, s)
+		}
+	}
+	if channel.String != "" {
+		if cfg.ChannelID, err = snowflake.Parse(channel.String); err != nil {
+			return cfg, fmt.Errorf(MsgDBParseRadioChanFail, channel.String, err)
+		}
+	}
+	return cfg, nil
+}
+
+// GetRadioChannels maps every guild with the radio on to its voice channel
+func GetRadioChannels(ctx context.Context) (map[snowflake.ID]snowflake.ID, error) {
+	rows, err := DB.QueryContext(ctx, "SELECT guild_id, radio_channel_id FROM guild_configs WHERE radio_channel_id IS NOT NULL AND radio_channel_id != ''")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := make(map[snowflake.ID]snowflake.ID)
+	for rows.Next() {
+		var gStr, cStr string
+		if err := rows.Scan(&gStr, &cStr); err != nil {
+			return nil, fmt.Errorf(MsgDBScanGuildConfigFail, err)
+		}
+		gID, err := snowflake.Parse(gStr)
+		if err != nil {
+			return nil, fmt.Errorf(MsgDBParseRadioGuildFail, gStr, err)
+		}
+		cID, err := snowflake.Parse(cStr)
+		if err != nil {
+			return nil, fmt.Errorf(MsgDBParseRadioChanFail, cStr, err)
+		}
+		channels[gID] = cID
+	}
+	return channels, nil
+}
+
+// --- Phase 7: Application Logic (Game Sessions) ---
+
+type GameSession struct {
+	GameID    string
+	GameType  string
+	State     string
+	UpdatedAt time.Time
+}
+
+func SaveGameSession(ctx context.Context, gameID, gameType, state string) error {
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO game_sessions (game_id, game_type, state) VALUES (?, ?, ?)
+		ON CONFLICT(game_id) DO UPDATE SET game_type = excluded.game_type, state = excluded.state, updated_at = CURRENT_TIMESTAMP
+	`, gameID, gameType, state)
+	return err
+}
+
+func DeleteGameSession(ctx context.Context, gameID string) error {
+	_, err := DB.ExecContext(ctx, "DELETE FROM game_sessions WHERE game_id = ?", gameID)
+	return err
+}
+
+func DeleteStaleGameSessions(ctx context.Context, olderThan time.Time) (int64, error) {
+	result, err := DB.ExecContext(ctx, "DELETE FROM game_sessions WHERE updated_at < ?", olderThan.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func GetAllGameSessions(ctx context.Context) ([]*GameSession, error) {
+	rows, err := DB.QueryContext(ctx, "SELECT game_id, game_type, state, updated_at FROM game_sessions ORDER BY updated_at ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*GameSession
+	for rows.Next() {
+		s := &GameSession{}
+		if err := rows.Scan(&s.GameID, &s.GameType, &s.State, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf(MsgDBScanGameSessionFail, err)
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// --- Phase 8: Application Logic (Game Ratings) ---
+
+type GameRating struct {
+	UserID   snowflake.ID
+	GameType string
+	Rating   float64
+	Wins     int
+	Losses   int
+	Draws    int
+}
+
+// UpdateGameRatings reads the users' ratings, lets update change them and writes them back in a single transaction,
+// so both sides of a game stay consistent and a concurrent result can't overwrite the change. Users who have never
+// finished a game of this type are passed to update as nil; update returns the ratings to save.
+func UpdateGameRatings(ctx context.Context, gameType string, userIDs []snowflake.ID, update func([]*GameRating) []*GameRating) error {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current := make([]*GameRating, len(userIDs))
+	for i, id := range userIDs {
+		r := &GameRating{UserID: id, GameType: gameType}
+		err := tx.QueryRowContext(ctx, `
+			SELECT rating, wins, losses, draws FROM game_ratings WHERE user_id = ? AND game_type = ?
+		`, id.String(), gameType).Scan(&r.Rating, &r.Wins, &r.Losses, &r.Draws)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		current[i] = r
+	}
+
+	for _, r := range update(current) {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO game_ratings (user_id, game_type, rating, wins, losses, draws) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(user_id, game_type) DO UPDATE SET
+				rating = excluded.rating,
+				wins = excluded.wins,
+				losses = excluded.losses,
+				draws = excluded.draws,
+				updated_at = CURRENT_TIMESTAMP
+		`, r.UserID.String(), r.GameType, r.Rating, r.Wins, r.Losses, r.Draws)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func GetGameLeaderboard(ctx context.Context, gameType string, limit int) ([]*GameRating, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT user_id, game_type, rating, wins, losses, draws FROM game_ratings
+		WHERE game_type = ? ORDER BY rating DESC, wins DESC LIMIT ?
+	`, gameType, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanGameRatings(rows)
+}
+
+func GetGameRatingsForUser(ctx context.Context, userID snowflake.ID) ([]*GameRating, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT user_id, game_type, rating, wins, losses, draws FROM game_ratings
+		WHERE user_id = ? ORDER BY game_type ASC
+	`, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanGameRatings(rows)
+}
+
+func scanGameRatings(rows *sql.Rows) ([]*GameRating, error) {
+	var ratings []*GameRating
+	for rows.Next() {
+		r := &GameRating{}
+		var uid string
+		if err := rows.Scan(&uid, &r.GameType, &r.Rating, &r.Wins, &r.Losses, &r.Draws); err != nil {
+			return nil, fmt.Errorf(MsgDBScanGameRatingFail, err)
+		}
+		id, err := snowflake.Parse(uid)
+		if err != nil {
+			return nil, fmt.Errorf(MsgDBParseRatingUserFail, uid, err)
+		}
+		r.UserID = id
+		ratings = append(ratings, r)
+	}
+	return ratings, nil
+}
+
+// --- Phase 9: Application Logic (Voice Playlists) ---
+
+const (
+	PlaylistScopeUser  = "user"
+	PlaylistScopeGuild = "guild"
+)
+
+// Playlist is a saved queue owned by a user (scope user) or a guild (scope guild).
+type Playlist struct {
+	ID         int64
+	Scope      string
+	OwnerID    snowflake.ID
+	Name       string
+	CreatedBy  snowflake.ID
+	TrackCount int
+	Duration   time.Duration
+	UpdatedAt  time.Time
+}
+
+type PlaylistTrack struct {
+	URL      string
+	Title    string
+	Channel  string
+	Duration time.Duration
+}
+
+// SavePlaylist creates or replaces a playlist and its tracks in a single transaction.
+func SavePlaylist(ctx context.Context, scope string, ownerID, createdBy snowflake.ID, name string, tracks []PlaylistTrack) error {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO playlists (scope, owner_id, name, created_by) VALUES (?, ?, ?, ?)
+		ON CONFLICT(scope, owner_id, name) DO UPDATE SET updated_at = CURRENT_TIMESTAMP
+		RETURNING id
+	`, scope, ownerID.String(), name, createdBy.String()).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM playlist_tracks WHERE playlist_id = ?", id); err != nil {
+		return err
+	}
+	for i, t := range tracks {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO playlist_tracks (playlist_id, position, url, title, channel, duration_ms) VALUES (?, ?, ?, ?, ?, ?)
+		`, id, i, t.URL, t.Title, t.Channel, t.Duration.Milliseconds())
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPlaylist returns nil when the owner has no playlist with that name.
+func GetPlaylist(ctx context.Context, scope string, ownerID snowflake.ID, name string) (*Playlist, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT p.id, p.scope, p.owner_id, p.name, p.created_by, p.updated_at, COUNT(t.position), COALESCE(SUM(t.duration_ms), 0)
+		FROM playlists p LEFT JOIN playlist_tracks t ON t.playlist_id = p.id
+		WHERE p.scope = ? AND p.owner_id = ? AND p.name = ? GROUP BY p.id
+	`, scope, ownerID.String(), name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	playlists, err := scanPlaylists(rows)
+	if err != nil || len(playlists) == 0 {
+		return nil, err
+	}
+	return playlists[0], nil
+}
+
+func GetPlaylists(ctx context.Context, scope string, ownerID snowflake.ID) ([]*Playlist, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT p.id, p.scope, p.owner_id, p.name, p.created_by, p.updated_at, COUNT(t.position), COALESCE(SUM(t.duration_ms), 0)
+		FROM playlists p LEFT JOIN playlist_tracks t ON t.playlist_id = p.id
+		WHERE p.scope = ? AND p.owner_id = ? GROUP BY p.id ORDER BY p.name COLLATE NOCASE ASC
+	`, scope, ownerID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPlaylists(rows)
+}
+
+func GetPlaylistTracks(ctx context.Context, playlistID int64) ([]PlaylistTrack, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT url, COALESCE(title, ''), COALESCE(channel, ''), duration_ms FROM playlist_tracks
+		WHERE playlist_id = ? ORDER BY position ASC
+	`, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tracks []PlaylistTrack
+	for rows.Next() {
+		var t PlaylistTrack
+		var ms int64
+		if err := rows.Scan(&t.URL, &t.Title, &t.Channel, &ms); err != nil {
+			return nil, fmt.Errorf(MsgDBScanPlaylistTrackFail, err)
+		}
+		t.Duration = time.Duration(ms) * time.Millisecond
+		tracks = append(tracks, t)
+	}
+	return tracks, nil
+}
+
+func DeletePlaylist(ctx context.Context, playlistID int64) error {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM playlist_tracks WHERE playlist_id = ?", playlistID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM playlists WHERE id = ?", playlistID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func scanPlaylists(rows *sql.Rows) ([]*Playlist, error) {
+	var playlists []*Playlist
+	for rows.Next() {
+		p := &Playlist{}
+		var ownerStr, creatorStr string
+		var ms int64
+		if err := rows.Scan(&p.ID, &p.Scope, &ownerStr, &p.Name, &creatorStr, &p.UpdatedAt, &p.TrackCount, &ms); err != nil {
+			return nil, fmt.Errorf(MsgDBScanPlaylistFail, err)
+		}
+		ownerID, err := snowflake.Parse(ownerStr)
+		if err != nil {
+			return nil, fmt.Errorf(MsgDBParsePlaylistUserFail, ownerStr, p.ID, err)
+		}
+		creatorID, err := snowflake.Parse(creatorStr)
+		if err != nil {
+			return nil, fmt.Errorf(MsgDBParsePlaylistUserFail, creatorStr, p.ID, err)
+		}
+		p.OwnerID, p.CreatedBy = ownerID, creatorID
+		p.Duration = time.Duration(ms) * time.Millisecond
+		playlists = append(playlists, p)
+	}
+	return playlists, nil
+}
+
+// --- Phase 10: Application Logic (Voice Sessions) ---
+
+type SavedVoiceSession struct {
+	GuildID   snowflake.ID
+	ChannelID snowflake.ID
+	State     string
+	UpdatedAt time.Time
+}
+
+func SaveVoiceSession(ctx context.Context, guildID, channelID snowflake.ID, state string) error {
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO voice_sessions (guild_id, channel_id, state) VALUES (?, ?, ?)
+		ON CONFLICT(guild_id) DO UPDATE SET channel_id = excluded.channel_id, state = excluded.state, updated_at = CURRENT_TIMESTAMP
+	`, guildID.String(), channelID.String(), state)
+	return err
+}
+
+// TakeVoiceSessions returns every saved voice session and clears the table so each snapshot is resumed at most once
+func TakeVoiceSessions(ctx context.Context) ([]*SavedVoiceSession, error) {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT guild_id, channel_id, state, updated_at FROM voice_sessions")
+	if err != nil {
+		return nil, err
+	}
+	var sessions []*SavedVoiceSession
+	for rows.Next() {
+		s := &SavedVoiceSession{}
+		var gStr, cStr string
+		if err := rows.Scan(&gStr, &cStr, &s.State, &s.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf(MsgDBScanVoiceSessionFail, err)
+		}
+		if s.GuildID, err = snowflake.Parse(gStr); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf(MsgDBParseVoiceGuildFail, gStr, err)
+		}
+		if s.ChannelID, err = snowflake.Parse(cStr); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf(MsgDBParseVoiceChanFail, cStr, err)
+		}
+		sessions = append(sessions, s)
+	}
+	rows.Close()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM voice_sessions"); err != nil {
+		return nil, err
+	}
+	return sessions, tx.Commit()
+}
+
+// --- Phase 11: Application Logic (Voice Play Log) ---
+
+// VoicePlay is one track played in a guild; RequestedBy is 0 for autoplay and restored tracks
+type VoicePlay struct {
+	ID          int64
+	GuildID     snowflake.ID
+	RequestedBy snowflake.ID
+	URL         string
+	Title       string
+	Channel     string
+	Duration    time.Duration
+	Played      time.Duration
+	Skipped     bool
+	PlayedAt    time.Time
+}
+
+type VoiceTrackStat struct {
+	URL, Title, Channel string
+	Plays               int
+	Played              time.Duration
+}
+
+type VoiceRequesterStat struct {
+	UserID snowflake.ID
+	Plays  int
+	Played time.Duration
+}
+
+type VoiceListeningTotals struct {
+	Plays, Skipped int
+	Played         time.Duration
+}
+
+func LogVoicePlay(ctx context.Context, p *VoicePlay) error {
+	requester := ""
+	if p.RequestedBy != 0 {
+		requester = p.RequestedBy.String()
+	}
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO voice_plays (guild_id, requested_by, url, title, channel, duration_ms, played_ms, skipped)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, p.GuildID.String(), requester, p.URL, p.Title, p.Channel, p.Duration.Milliseconds(), p.Played.Milliseconds(), p.Skipped)
+	return err
+}
+
+func GetVoiceListeningTotals(ctx context.Context, guildID snowflake.ID, since time.Time) (VoiceListeningTotals, error) {
+	var t VoiceListeningTotals
+	var ms int64
+	err := DB.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(skipped), 0), COALESCE(SUM(played_ms), 0)
+		FROM voice_plays WHERE guild_id = ? AND played_at >= ?
+	`, guildID.String(), since.UTC()).Scan(&t.Plays, &t.Skipped, &ms)
+	t.Played = time.Duration(ms) * time.Millisecond
+	return t, err
+}
+
+func GetVoiceTopTracks(ctx context.Context, guildID snowflake.ID, since time.Time, limit int) ([]VoiceTrackStat, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT url, MAX(title), MAX(channel), COUNT(*), SUM(played_ms)
+		FROM voice_plays WHERE guild_id = ? AND played_at >= ?
+		GROUP BY url ORDER BY COUNT(*) DESC, SUM(played_ms) DESC LIMIT ?
+	`, guildID.String(), since.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []VoiceTrackStat
+	for rows.Next() {
+		var st VoiceTrackStat
+		var title, channel sql.NullString
+		var ms int64
+		if err := rows.Scan(&st.URL, &title, &channel, &st.Plays, &ms); err != nil {
+			return nil, fmt.Errorf(MsgDBScanVoicePlayFail, err)
+		}
+		st.Title, st.Channel = title.String, channel.String
+		st.Played = time.Duration(ms) * time.Millisecond
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+func GetVoiceTopRequesters(ctx context.Context, guildID snowflake.ID, since time.Time, limit int) ([]VoiceRequesterStat, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT requested_by, COUNT(*), SUM(played_ms)
+		FROM voice_plays WHERE guild_id = ? AND played_at >= ? AND requested_by IS NOT NULL AND requested_by != ''
+		GROUP BY requested_by ORDER BY COUNT(*) DESC, SUM(played_ms) DESC LIMIT ?
+	`, guildID.String(), since.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []VoiceRequesterStat
+	for rows.Next() {
+		var st VoiceRequesterStat
+		var uStr string
+		var ms int64
+		if err := rows.Scan(&uStr, &st.Plays, &ms); err != nil {
+			return nil, fmt.Errorf(MsgDBScanVoicePlayFail, err)
+		}
+		if st.UserID, err = snowflake.Parse(uStr); err != nil {
+			return nil, fmt.Errorf(MsgDBParseVoicePlayFail, uStr, err)
+		}
+		st.Played = time.Duration(ms) * time.Millisecond
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+// GetVoicePlays returns a guild's play log since a point in time, oldest first
+func GetVoicePlays(ctx context.Context, guildID snowflake.ID, since time.Time) ([]*VoicePlay, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT id, requested_by, url, title, channel, duration_ms, played_ms, skipped, played_at
+		FROM voice_plays WHERE guild_id = ? AND played_at >= ? ORDER BY played_at ASC, id ASC
+	`, guildID.String(), since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plays []*VoicePlay
+	for rows.Next() {
+		p := &VoicePlay{GuildID: guildID}
+		var requester, title, channel sql.NullString
+		var durMs, playedMs int64
+		if err := rows.Scan(&p.ID, &requester, &p.URL, &title, &channel, &durMs, &playedMs, &p.Skipped, &p.PlayedAt); err != nil {
+			return nil, fmt.Errorf(MsgDBScanVoicePlayFail, err)
+		}
+		if requester.String != "" {
+			if p.RequestedBy, err = snowflake.Parse(requester.String); err != nil {
+				return nil, fmt.Errorf(MsgDBParseVoicePlayFail, requester.String, err)
+			}
+		}
+		p.Title, p.Channel = title.String, channel.String
+		p.Duration = time.Duration(durMs) * time.Millisecond
+		p.Played = time.Duration(playedMs) * time.Millisecond
+		plays = append(plays, p)
+	}
+	return plays, nil
+}
+
+// --- Phase 12: Application Logic (Voice Stations) ---
+
+// VoiceStation is a live stream bookmarked for a guild under a name.
+type VoiceStation struct {
+	Name      string
+	URL       string
+	AddedBy   snowflake.ID
+	CreatedAt time.Time
+}
+
+// SaveVoiceStation adds a station or points an existing name at a new URL.
+func SaveVoiceStation(ctx context.Context, guildID, addedBy snowflake.ID, name, url string) error {
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO voice_stations (guild_id, name, url, added_by) VALUES (?, ?, ?, ?)
+		ON CONFLICT(guild_id, name) DO UPDATE SET url = excluded.url, added_by = excluded.added_by, created_at = CURRENT_TIMESTAMP
+	`, guildID.String(), name, url, addedBy.String())
+	return err
+}
+
+// GetVoiceStation returns nil when the guild has no station with that name.
+func GetVoiceStation(ctx context.Context, guildID snowflake.ID, name string) (*VoiceStation, error) {
+	rows, err := DB.QueryContext(ctx, "SELECT name, url, added_by, created_at FROM voice_stations WHERE guild_id = ? AND name = ?", guildID.String(), name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stations, err := scanVoiceStations(rows)
+	if err != nil || len(stations) == 0 {
+		return nil, err
+	}
+	return stations[0], nil
+}
+
+func GetVoiceStations(ctx context.Context, guildID snowflake.ID) ([]*VoiceStation, error) {
+	rows, err := DB.QueryContext(ctx, "SELECT name, url, added_by, created_at FROM voice_stations WHERE guild_id = ? ORDER BY name COLLATE NOCASE", guildID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanVoiceStations(rows)
+}
+
+func DeleteVoiceStation(ctx context.Context, guildID snowflake.ID, name string) error {
+	_, err := DB.ExecContext(ctx, "DELETE FROM voice_stations WHERE guild_id = ? AND name = ?", guildID.String(), name)
+	return err
+}
+
+func scanVoiceStations(rows *sql.Rows) ([]*VoiceStation, error) {
+	var stations []*VoiceStation
+	for rows.Next() {
+		st := &VoiceStation{}
+		var addedBy string
+		if err := rows.Scan(&st.Name, &st.URL, &addedBy, &st.CreatedAt); err != nil {
+			return nil, fmt.Errorf(MsgDBScanStationFail, err)
+		}
+		id, err := snowflake.Parse(addedBy)
+		if err != nil {
+			return nil, fmt.Errorf(MsgDBParseStationUserFail, addedBy, st.Name, err)
+		}
+		st.AddedBy = id
+		stations = append(stations, st)
+	}
+	return stations, nil
+}
+
 // ============================================================================
 // V2 Components
 // ============================================================================
@@ -2253,6 +3042,44 @@ func EditInteractionContainerV2(client bot.Client, interaction discord.Interacti
 	return doRequestNoEscape(client, compiledRoute, data, nil)
 }
 
+// EditInteractionContainerV2Files replaces a deferred response with a container and the files it references
+func EditInteractionContainerV2Files(client bot.Client, interaction discord.Interaction, container Container, files []*discord.File) error {
+	route := rest.NewEndpoint(http.MethodPatch, "/webhooks/{application.id}/{interaction.token}/messages/@original")
+
+	data := struct {
+		Components  []any                      `json:"components"`
+		Flags       discord.MessageFlags       `json:"flags"`
+		Attachments []discord.AttachmentCreate `json:"attachments"`
+	}{
+		Components:  []any{container},
+		Flags:       MessageFlagsIsComponentsV2,
+		Attachments: attachmentsFor(files),
+	}
+
+	compiledRoute := route.Compile(nil, client.ApplicationID.String(), interaction.Token())
+
+	return doMultipartNoEscape(client, compiledRoute, data, files, nil)
+}
+
+// FollowupContainerV2Files sends a further message on an interaction with a container and the files it references
+func FollowupContainerV2Files(client bot.Client, interaction discord.Interaction, container Container, files []*discord.File) error {
+	route := rest.NewEndpoint(http.MethodPost, "/webhooks/{application.id}/{interaction.token}")
+
+	data := struct {
+		Components  []any                      `json:"components"`
+		Flags       discord.MessageFlags       `json:"flags"`
+		Attachments []discord.AttachmentCreate `json:"attachments"`
+	}{
+		Components:  []any{container},
+		Flags:       MessageFlagsIsComponentsV2,
+		Attachments: attachmentsFor(files),
+	}
+
+	compiledRoute := route.Compile(nil, client.ApplicationID.String(), interaction.Token())
+
+	return doMultipartNoEscape(client, compiledRoute, data, files, nil)
+}
+
 func EditInteractionV2(client bot.Client, interaction discord.Interaction, content string) error {
 	route := rest.NewEndpoint(http.MethodPatch, "/webhooks/{application.id}/{interaction.token}/messages/@original")
 	data := struct {
@@ -2430,6 +3257,35 @@ func UpdateInteractionContainerV2(client bot.Client, interaction discord.Interac
 	return doRequestNoEscape(client, compiledRoute, data, nil)
 }
 
+// UpdateInteractionContainerV2Files updates the component's message, replacing its attachments with files
+func UpdateInteractionContainerV2Files(client bot.Client, interaction discord.Interaction, container Container, files []*discord.File) error {
+	route := rest.NewEndpoint(http.MethodPost, "/interactions/{interaction.id}/{interaction.token}/callback")
+
+	data := struct {
+		Type discord.InteractionResponseType `json:"type"`
+		Data struct {
+			Components  []any                      `json:"components"`
+			Flags       discord.MessageFlags       `json:"flags"`
+			Attachments []discord.AttachmentCreate `json:"attachments"`
+		} `json:"data"`
+	}{
+		Type: discord.InteractionResponseTypeUpdateMessage,
+		Data: struct {
+			Components  []any                      `json:"components"`
+			Flags       discord.MessageFlags       `json:"flags"`
+			Attachments []discord.AttachmentCreate `json:"attachments"`
+		}{
+			Components:  []any{container},
+			Flags:       MessageFlagsIsComponentsV2,
+			Attachments: attachmentsFor(files),
+		},
+	}
+
+	compiledRoute := route.Compile(nil, interaction.ID().String(), interaction.Token())
+
+	return doMultipartNoEscape(client, compiledRoute, data, files, nil)
+}
+
 func SendContainerV2(client bot.Client, channelID snowflake.ID, container Container, ref *discord.MessageReference, stickers []snowflake.ID, embeds []discord.Embed) (*discord.Message, error) {
 	route := rest.NewEndpoint(http.MethodPost, "/channels/{channel.id}/messages")
 
@@ -2457,6 +3313,30 @@ func SendContainerV2(client bot.Client, channelID snowflake.ID, container Contai
 	return &msg, nil
 }
 
+// SendContainerV2Files sends a container together with the files it references
+func SendContainerV2Files(client bot.Client, channelID snowflake.ID, container Container, files []*discord.File) (*discord.Message, error) {
+	route := rest.NewEndpoint(http.MethodPost, "/channels/{channel.id}/messages")
+
+	data := struct {
+		Components  []any                      `json:"components"`
+		Flags       discord.MessageFlags       `json:"flags"`
+		Attachments []discord.AttachmentCreate `json:"attachments"`
+	}{
+		Components:  []any{container},
+		Flags:       MessageFlagsIsComponentsV2,
+		Attachments: attachmentsFor(files),
+	}
+
+	compiledRoute := route.Compile(nil, channelID.String())
+
+	var msg discord.Message
+	err := doMultipartNoEscape(client, compiledRoute, data, files, &msg)
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
 // SendMessageV2 sends a message using the components v2 flag
 func SendMessageV2(client bot.Client, channelID snowflake.ID, content string, ref *discord.MessageReference, stickers []snowflake.ID, embeds []discord.Embed) (*discord.Message, error) {
 	route := rest.NewEndpoint(http.MethodPost, "/channels/{channel.id}/messages")
@@ -2515,6 +3395,30 @@ func EditContainerV2(client bot.Client, channelID, messageID snowflake.ID, conta
 	return &msg, nil
 }
 
+// EditContainerV2Files edits a message, replacing its attachments with files
+func EditContainerV2Files(client bot.Client, channelID, messageID snowflake.ID, container Container, files []*discord.File) (*discord.Message, error) {
+	route := rest.NewEndpoint(http.MethodPatch, "/channels/{channel.id}/messages/{message.id}")
+
+	data := struct {
+		Components  []any                      `json:"components"`
+		Flags       discord.MessageFlags       `json:"flags"`
+		Attachments []discord.AttachmentCreate `json:"attachments"`
+	}{
+		Components:  []any{container},
+		Flags:       MessageFlagsIsComponentsV2,
+		Attachments: attachmentsFor(files),
+	}
+
+	compiledRoute := route.Compile(nil, channelID.String(), messageID.String())
+
+	var msg discord.Message
+	err := doMultipartNoEscape(client, compiledRoute, data, files, &msg)
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
 func EditMessageV2(client bot.Client, channelID, messageID snowflake.ID, content string, stickers []snowflake.ID, embeds []discord.Embed) (*discord.Message, error) {
 	route := rest.NewEndpoint(http.MethodPatch, "/channels/{channel.id}/messages/{message.id}")
 
@@ -2615,6 +3519,46 @@ func doRequestNoEscape(client bot.Client, route *rest.CompiledEndpoint, body any
 	return client.Rest.Do(route, json.RawMessage(buf.Bytes()), dst)
 }
 
+// doMultipartNoEscape is doRequestNoEscape for payloads that upload files
+func doMultipartNoEscape(client bot.Client, route *rest.CompiledEndpoint, body any, files []*discord.File, dst any) error {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+
+	part, err := writer.CreateFormField("payload_json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(part)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(body); err != nil {
+		return err
+	}
+
+	for i, file := range files {
+		part, err := writer.CreateFormFile(fmt.Sprintf("files[%d]", i), file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Rest.Do(route, &discord.MultipartBuffer{Buffer: buf, ContentType: writer.FormDataContentType()}, dst)
+}
+
+// attachmentsFor lists the uploaded files so an edit drops whatever was attached before
+func attachmentsFor(files []*discord.File) []discord.AttachmentCreate {
+	attachments := make([]discord.AttachmentCreate, len(files))
+	for i := range files {
+		attachments[i] = discord.AttachmentCreate{ID: i}
+	}
+	return attachments
+}
+
 func safeGo(f func()) {
 	go func() {
 		defer func() {
@@ -2638,6 +3582,10 @@ func intPtr(i int) *int {
 	return &i
 }
 
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
@@ -2,11 +2,17 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
 	"math/rand"
-	"net/http"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,14 +33,26 @@ import (
 
 const (
 	// Commands & Options
-	CmdGame         = "game"
-	CmdGameDesc     = "Good games."
-	CmdConnect4     = "connect4"
-	CmdConnect4Desc = "Play Connect Four against another player or AI"
-	CmdCheckers     = "checkers"
-	CmdCheckersDesc = "Play Checkers (Draughts) against another player or AI"
-	CmdChess        = "chess"
-	CmdChessDesc    = "Play Chess against another player or AI"
+	CmdGame            = "game"
+	CmdGameDesc        = "Good games."
+	CmdConnect4        = "connect4"
+	CmdConnect4Desc    = "Play Connect Four against another player or AI"
+	CmdCheckers        = "checkers"
+	CmdCheckersDesc    = "Play Checkers (Draughts) against another player or AI"
+	CmdChess           = "chess"
+	CmdChessDesc       = "Play Chess against another player or AI"
+	CmdOthello         = "othello"
+	CmdOthelloDesc     = "Play Othello (Reversi) against another player or AI"
+	CmdTicTacToe       = "tictactoe"
+	CmdTicTacToeDesc   = "Play Ultimate Tic-Tac-Toe against another player"
+	CmdMinesweeper     = "minesweeper"
+	CmdMinesweeperDesc = "Clear a minefield on your own"
+	CmdHangman         = "hangman"
+	CmdHangmanDesc     = "Guess the hidden word before the gallows is finished"
+	CmdLeaderboard     = "leaderboard"
+	CmdLeaderboardDesc = "Show the top rated players for a game"
+	CmdProfile         = "profile"
+	CmdProfileDesc     = "Show a player's ratings and record"
 
 	OptOpponent       = "opponent"
 	OptOpponentDesc   = "Challenge another user (leave empty to play against AI)"
@@ -44,6 +62,28 @@ const (
 	OptTimerDesc      = "Turn timer in seconds (leave empty or 0 to disable)"
 	OptSize           = "size"
 	OptSizeDesc       = "Board size"
+	OptClock          = "clock"
+	OptClockDesc      = "Minutes on each player's clock (leave empty for no clock)"
+	OptIncrement      = "increment"
+	OptIncrementDesc  = "Seconds of increment per move (requires a clock)"
+	OptClockMode      = "clock_mode"
+	OptClockModeDesc  = "How the increment is applied"
+	OptFEN            = "fen"
+	OptFENDesc        = "Start from a position in FEN notation"
+	OptPGN            = "pgn"
+	OptPGNDesc        = "Continue a game from its PGN move list"
+	OptGameType       = "game"
+	OptGameTypeDesc   = "Which game to rank"
+	OptPlayer         = "player"
+	OptPlayerDesc     = "Whose ratings to show (defaults to you)"
+	OptBoard          = "board"
+	OptBoardDesc      = "How to draw the board (defaults to an image)"
+	OptVariant        = "variant"
+	OptVariantDesc    = "Which draughts rules to play (defaults to English)"
+	OptForcedCapture  = "forced_capture"
+	OptForcedCaptDesc = "Whether capturing is compulsory (defaults to on)"
+	OptRivalDesc      = "The player to challenge"
+	OptFieldDesc      = "Minefield size (defaults to normal)"
 
 	ChoiceEasy    = "easy"
 	ChoiceNormal  = "normal"
@@ -53,26 +93,99 @@ const (
 	ChoiceLarge   = "large"
 	ChoiceMaster  = "master"
 
+	ChoiceFischer   = "fischer"
+	ChoiceBronstein = "bronstein"
+
+	ChoiceImage = "image"
+	ChoiceEmoji = "emoji"
+
+	ChoiceEnglish       = "english"
+	ChoiceInternational = "international"
+	ChoiceRussian       = "russian"
+	ChoiceBrazilian     = "brazilian"
+
 	// General Messages
-	MsgGamePanic           = "Panic in %s: %v"
-	MsgGameNotFound        = "Game not found or expired."
-	MsgGameNotPlayer       = "You're not a player in this game!"
-	MsgGameNotTurn         = "It's not your turn!"
-	MsgGameAlreadyActive   = "You are already in a game! (ID: %s)"
-	MsgGameOpponentActive  = "<@%d> is already in a game! (ID: %s)"
-	MsgGameChallengeSelf   = "You cannot challenge yourself!"
-	MsgGameForfeitSuccess  = "**<@%d> Forfeited 🛑 - <@%d> Won! 🎉**"
-	MsgGameClaimWinSuccess = "**<@%d> Claimed Victory! 🏆**"
-	MsgGameDraw            = "**<@%d> and <@%d> ended with a Draw!**"
-	MsgGameWin             = "**<@%d> Lost 💩 - <@%d> Won! 🎉**"
-	MsgGameTurn            = "**<@%d>'s Turn** %s"
-	MsgGameAIHardFallback  = "⚠️ *Hard AI service unavailable, using Normal AI.*"
-	MsgGameAINoMoves       = "AI has no moves!"
-	MsgGameHopelessFail    = "The AI is not in a hopeless position yet!"
-	MsgGameRestarted       = "🔄 Game Restarted!"
+	MsgGamePanic               = "Panic in %s: %v"
+	MsgGameNotFound            = "Game not found or expired."
+	MsgGameNotPlayer           = "You're not a player in this game!"
+	MsgGameNotTurn             = "It's not your turn!"
+	MsgGameAlreadyActive       = "You are already in a game! (ID: %s)"
+	MsgGameOpponentActive      = "<@%d> is already in a game! (ID: %s)"
+	MsgGameChallengeSelf       = "You cannot challenge yourself!"
+	MsgGameForfeitSuccess      = "**<@%d> Forfeited 🛑 - <@%d> Won! 🎉**"
+	MsgGameClaimWinSuccess     = "**<@%d> Claimed Victory! 🏆**"
+	MsgGameDraw                = "**<@%d> and <@%d> ended with a Draw!**"
+	MsgGameWin                 = "**<@%d> Lost 💩 - <@%d> Won! 🎉**"
+	MsgGameTurn                = "**<@%d>'s Turn** %s"
+	MsgGameAINoMoves           = "AI has no moves!"
+	MsgGameHopelessFail        = "The AI is not in a hopeless position yet!"
+	MsgGameRestarted           = "🔄 Game Restarted!"
+	MsgGamePersistFail         = "Failed to persist game %s: %v"
+	MsgGameRestoreFail         = "Failed to restore game %s: %v"
+	MsgGameRestoreLoadFail     = "Failed to load saved games: %v"
+	MsgGameRestored            = "Restored %d game(s) from the database"
+	MsgGameStalePruned         = "Pruned %d stale game(s)"
+	MsgGameEngineBadMove       = "Chess engine produced an unplayable move %s: %v"
+	MsgGameEngineTimeout       = "Chess engine hit its %v safety cap at depth %d of %d for %s"
+	MsgGameRatingFail          = "Failed to update %s ratings: %v"
+	MsgGameRatingDropped       = "Rating queue is full, dropping a %s result"
+	MsgGameLeaderboard         = "## 🏆 %s Leaderboard"
+	MsgGameLeaderboardNone     = "No rated %s games have been played yet."
+	MsgGameLeaderboardRow      = "**%d.** <@%d> · **%d** · %s"
+	MsgGameLeaderboardFail     = "❌ Failed to load the leaderboard."
+	MsgGameProfile             = "## 📊 Game Profile\n<@%d>"
+	MsgGameProfileNone         = "No rated games played yet."
+	MsgGameProfileRow          = "**%s:** **%d** · %s"
+	MsgGameProfileFail         = "❌ Failed to load the profile."
+	MsgGameRecord              = "%dW %dL %dD"
+	MsgCheckersCaptureOptional = " · captures optional"
+	MsgGameFlagFall            = "**<@%d> Ran Out of Time ⏱️ - <@%d> Won! 🎉**"
+	MsgGameDrawOffered         = "🤝 <@%d> offers a draw."
+	MsgGameDrawOwnOffer        = "You have already offered a draw."
+	MsgGameDrawNoOffer         = "There is no draw offer to accept."
+	MsgGameDrawDeclined        = "🤖 The AI declined the draw."
+	MsgGameChallenge           = "⚔️ <@%d> challenges <@%d> to **%s**!\n-# Expires <t:%d:R>"
+	MsgGameChallengeDeclined   = "❌ <@%d> declined the **%s** challenge."
+	MsgGameChallengeWithdrawn  = "🚫 <@%d> withdrew the **%s** challenge."
+	MsgGameChallengeExpired    = "⌛ <@%d> didn't answer the **%s** challenge in time."
+	MsgGameChallengeNotYours   = "This challenge isn't for you."
+	MsgGameChallengeGone       = "This challenge is no longer open."
+	MsgGameRematchNotYou       = "Only the original players can ask for a rematch."
+	MsgGameRematchNotOver      = "The game isn't over yet."
+	MsgGameSpectateLink        = "👁️ Follow the game in <#%d>"
+	MsgGameSpectateFail        = "❌ Couldn't open a spectator thread here."
+	MsgGameSpectateLogFail     = "Failed to open spectator thread for %s: %v"
+	MsgGameSpectateThread      = "👁️ %s spectators"
+	MsgChessBadFEN             = "❌ Invalid FEN: %v"
+	MsgChessBadPGN             = "❌ Invalid PGN: %v"
+	MsgChessStartConflict      = "❌ Provide either a FEN or a PGN, not both."
+	MsgChessStartFinished      = "❌ That position is already finished."
+	MsgChessPGNReady           = "📄 PGN export"
+	MsgOthelloPass             = "<@%d> has no legal move and passes."
+	MsgTicTacToeNeedsOpponent  = "❌ Ultimate Tic-Tac-Toe needs an opponent - pick another player."
+	MsgTicTacToeAnyBoard       = "-# Play in any open board"
+	MsgTicTacToeInBoard        = "-# Play in board %s"
+	MsgMinesweeperStatus       = "**<@%d>'s Minefield** · %s %d left · started <t:%d:R>"
+	MsgMinesweeperModeDig      = "-# ⛏️ Digging - pick a cell to uncover it"
+	MsgMinesweeperModeFlag     = "-# 🚩 Flagging - pick a cell to mark or unmark it"
+	MsgMinesweeperWin          = "**<@%d> Cleared the Field in %s! 🎉**"
+	MsgMinesweeperLose         = "**<@%d> Hit a Mine 💥**"
+	MsgMinesweeperQuit         = "**<@%d> Walked Off the Field 🛑**"
+	MsgHangmanStatus           = "**<@%d>'s Word** - pick a letter"
+	MsgHangmanMisses           = "Misses: **%s** · %d left"
+	MsgHangmanWin              = "**<@%d> Guessed %s! 🎉**"
+	MsgHangmanLose             = "**<@%d> Was Hanged 💀 - the word was %s**"
+	MsgHangmanQuit             = "**<@%d> Gave Up 🛑 - the word was %s**"
 
 	// Interaction Labels
 	LabelForfeit         = "Forfeit"
+	LabelResign          = "Resign"
+	LabelOfferDraw       = "Offer Draw"
+	LabelAcceptDraw      = "Accept Draw"
+	LabelAccept          = "Accept"
+	LabelDecline         = "Decline"
+	LabelRematch         = "Rematch"
+	LabelSpectate        = "Spectate"
 	LabelClaimWin        = "Claim Win"
 	LabelPlayAgain       = "Play Again?"
 	LabelYes             = "Yes!"
@@ -80,6 +193,17 @@ const (
 	LabelSelectPiece     = "Select Piece..."
 	LabelSelectDest      = "Select Destination..."
 	LabelSelectPieceMove = "Select a piece to move..."
+	LabelDownloadPGN     = "Download PGN"
+	LabelSelectSquare    = "Select a square..."
+	LabelSelectMore      = "More squares..."
+	LabelSelectRow       = "Select a row..."
+	LabelSelectCell      = "Select a cell..."
+	LabelCancelSelect    = "Cancel"
+	LabelFlagMode        = "🚩 Flag Mode"
+	LabelDigMode         = "⛏️ Dig Mode"
+	LabelNewGame         = "New Game"
+	LabelGuessAM         = "Guess A-M..."
+	LabelGuessNZ         = "Guess N-Z..."
 
 	// Interaction Custom IDs
 	CIDConnect4Prefix   = "connect4"
@@ -88,6 +212,11 @@ const (
 	CIDConnect4Forfeit  = "connect4:%s:forfeit"
 	CIDConnect4Move     = "connect4:%s:%d"
 	CIDConnect4Disabled = "connect4:disabled:info"
+	CIDConnect4Spectate = "connect4:%s:spectate"
+
+	CIDChallengePrefix  = "challenge"
+	CIDChallengeAccept  = "challenge:%s:accept"
+	CIDChallengeDecline = "challenge:%s:decline"
 
 	CIDCheckersPrefix   = "checkers"
 	CIDCheckersGameID   = "checkers_%d_%d"
@@ -95,18 +224,57 @@ const (
 	CIDCheckersForfeit  = "checkers:%s:forfeit"
 	CIDCheckersYes      = "checkers:%s:yes"
 	CIDCheckersNo       = "checkers:%s:no"
+	CIDCheckersOffer    = "checkers:%s:offer_draw"
+	CIDCheckersAccept   = "checkers:%s:accept_draw"
+	CIDCheckersRematch  = "checkers:%s:rematch"
+	CIDCheckersSpectate = "checkers:%s:spectate"
 
 	CIDChessPrefix       = "chess"
+	CIDChessGameID       = "chess_%d_%d"
 	CIDChessMoveTo       = "chess:%s:move_to"
 	CIDChessSelectPiece  = "chess:%s:select_piece"
 	CIDChessCancelSelect = "chess:%s:cancel_select"
 	CIDChessForfeit      = "chess:%s:forfeit"
+	CIDChessPGN          = "chess:%s:pgn"
+	CIDChessOfferDraw    = "chess:%s:offer_draw"
+	CIDChessAcceptDraw   = "chess:%s:accept_draw"
+	CIDChessRematch      = "chess:%s:rematch"
+	CIDChessSpectate     = "chess:%s:spectate"
+
+	CIDOthelloGameID   = "othello_%d_%d"
+	CIDOthelloMove     = "othello:%s:move"
+	CIDOthelloMoveMore = "othello:%s:move_more"
+	CIDOthelloForfeit  = "othello:%s:forfeit"
+	CIDOthelloRematch  = "othello:%s:rematch"
+	CIDOthelloSpectate = "othello:%s:spectate"
+
+	CIDTicTacToeGameID   = "tictactoe_%d_%d"
+	CIDTicTacToeBoard    = "tictactoe:%s:board_%d"
+	CIDTicTacToeCell     = "tictactoe:%s:cell_%d"
+	CIDTicTacToeCancel   = "tictactoe:%s:cancel_select"
+	CIDTicTacToeForfeit  = "tictactoe:%s:forfeit"
+	CIDTicTacToeRematch  = "tictactoe:%s:rematch"
+	CIDTicTacToeSpectate = "tictactoe:%s:spectate"
+
+	CIDMinesweeperGameID  = "minesweeper_%d_%d"
+	CIDMinesweeperRow     = "minesweeper:%s:row"
+	CIDMinesweeperCell    = "minesweeper:%s:cell"
+	CIDMinesweeperCancel  = "minesweeper:%s:cancel_select"
+	CIDMinesweeperMode    = "minesweeper:%s:mode"
+	CIDMinesweeperForfeit = "minesweeper:%s:forfeit"
+	CIDMinesweeperAgain   = "minesweeper:%s:again"
+
+	CIDHangmanGameID  = "hangman_%d_%d"
+	CIDHangmanGuessAM = "hangman:%s:guess_am"
+	CIDHangmanGuessNZ = "hangman:%s:guess_nz"
+	CIDHangmanForfeit = "hangman:%s:forfeit"
+	CIDHangmanAgain   = "hangman:%s:again"
 )
 
 var (
 	BoardCorner       = "⏺️"
-	BoardColumnEmojis = []string{"🇦\u200b", "🇧\u200b", "🇨\u200b", "🇩\u200b", "🇪\u200b", "🇫\u200b", "🇬\u200b", "🇭\u200b"}
-	BoardRowEmojis    = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣"}
+	BoardColumnEmojis = []string{"🇦\u200b", "🇧\u200b", "🇨\u200b", "🇩\u200b", "🇪\u200b", "🇫\u200b", "🇬\u200b", "🇭\u200b", "🇮\u200b", "🇯\u200b"}
+	BoardRowEmojis    = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
 )
 
 // ===========================
@@ -153,6 +321,15 @@ func init() {
 							{Name: "Master (10x10)", Value: ChoiceMaster},
 						},
 					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptBoard,
+						Description: OptBoardDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Image", Value: ChoiceImage},
+							{Name: "Emoji", Value: ChoiceEmoji},
+						},
+					},
 				},
 			},
 			discord.ApplicationCommandOptionSubCommand{
@@ -174,6 +351,50 @@ func init() {
 							{Name: "Hard", Value: ChoiceHard},
 						},
 					},
+					discord.ApplicationCommandOptionInt{
+						Name:        OptClock,
+						Description: OptClockDesc,
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        OptIncrement,
+						Description: OptIncrementDesc,
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptClockMode,
+						Description: OptClockModeDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Fischer (full increment)", Value: ChoiceFischer},
+							{Name: "Bronstein (delay, capped at time used)", Value: ChoiceBronstein},
+						},
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptBoard,
+						Description: OptBoardDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Image", Value: ChoiceImage},
+							{Name: "Emoji", Value: ChoiceEmoji},
+						},
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptVariant,
+						Description: OptVariantDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "English (8x8)", Value: ChoiceEnglish},
+							{Name: "International (10x10, flying kings)", Value: ChoiceInternational},
+							{Name: "Russian (8x8, flying kings)", Value: ChoiceRussian},
+							{Name: "Brazilian (8x8, international rules)", Value: ChoiceBrazilian},
+						},
+					},
+					discord.ApplicationCommandOptionBool{
+						Name:        OptForcedCapture,
+						Description: OptForcedCaptDesc,
+						Required:    false,
+					},
 				},
 			},
 			discord.ApplicationCommandOptionSubCommand{
@@ -195,6 +416,152 @@ func init() {
 							{Name: "Hard", Value: ChoiceHard},
 						},
 					},
+					discord.ApplicationCommandOptionInt{
+						Name:        OptClock,
+						Description: OptClockDesc,
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        OptIncrement,
+						Description: OptIncrementDesc,
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptClockMode,
+						Description: OptClockModeDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Fischer (full increment)", Value: ChoiceFischer},
+							{Name: "Bronstein (delay, capped at time used)", Value: ChoiceBronstein},
+						},
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptFEN,
+						Description: OptFENDesc,
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptPGN,
+						Description: OptPGNDesc,
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptBoard,
+						Description: OptBoardDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Image", Value: ChoiceImage},
+							{Name: "Emoji", Value: ChoiceEmoji},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        CmdOthello,
+				Description: CmdOthelloDesc,
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionUser{
+						Name:        OptOpponent,
+						Description: OptOpponentDesc,
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptDifficulty,
+						Description: OptDifficultyDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Easy", Value: ChoiceEasy},
+							{Name: "Normal", Value: ChoiceNormal},
+							{Name: "Hard", Value: ChoiceHard},
+						},
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptBoard,
+						Description: OptBoardDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Image", Value: ChoiceImage},
+							{Name: "Emoji", Value: ChoiceEmoji},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        CmdTicTacToe,
+				Description: CmdTicTacToeDesc,
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionUser{
+						Name:        OptOpponent,
+						Description: OptRivalDesc,
+						Required:    true,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptBoard,
+						Description: OptBoardDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Image", Value: ChoiceImage},
+							{Name: "Emoji", Value: ChoiceEmoji},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        CmdMinesweeper,
+				Description: CmdMinesweeperDesc,
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:        OptDifficulty,
+						Description: OptFieldDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Easy (8x8, 8 mines)", Value: ChoiceEasy},
+							{Name: "Normal (9x9, 12 mines)", Value: ChoiceNormal},
+							{Name: "Hard (10x10, 20 mines)", Value: ChoiceHard},
+						},
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        OptBoard,
+						Description: OptBoardDesc,
+						Required:    false,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Image", Value: ChoiceImage},
+							{Name: "Emoji", Value: ChoiceEmoji},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        CmdHangman,
+				Description: CmdHangmanDesc,
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        CmdLeaderboard,
+				Description: CmdLeaderboardDesc,
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:        OptGameType,
+						Description: OptGameTypeDesc,
+						Required:    true,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Connect Four", Value: CmdConnect4},
+							{Name: "Checkers", Value: CmdCheckers},
+							{Name: "Chess", Value: CmdChess},
+							{Name: "Othello", Value: CmdOthello},
+							{Name: "Ultimate Tic-Tac-Toe", Value: CmdTicTacToe},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        CmdProfile,
+				Description: CmdProfileDesc,
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionUser{
+						Name:        OptPlayer,
+						Description: OptPlayerDesc,
+						Required:    false,
+					},
 				},
 			},
 		},
@@ -212,12 +579,33 @@ func init() {
 			HandlePlayCheckers(event, data)
 		case CmdChess:
 			HandlePlayChess(event, data)
+		case CmdOthello:
+			handlePlayOthello(event, data)
+		case CmdTicTacToe:
+			handlePlayTictactoe(event, data)
+		case CmdMinesweeper:
+			handlePlayMinesweeper(event, data)
+		case CmdHangman:
+			handlePlayHangman(event, data)
+		case CmdLeaderboard:
+			handleGameLeaderboard(event, data)
+		case CmdProfile:
+			handleGameProfile(event, data)
 		}
 	})
 
 	RegisterComponentHandler(CmdConnect4+":", connect4HandleMove)
 	RegisterComponentHandler(CmdCheckers+":", HandleCheckersInteraction)
 	RegisterComponentHandler(CmdChess+":", HandleChessInteraction)
+	RegisterComponentHandler(CmdOthello+":", othelloHandleInteraction)
+	RegisterComponentHandler(CmdTicTacToe+":", tictactoeHandleInteraction)
+	RegisterComponentHandler(CmdMinesweeper+":", minesweeperHandleInteraction)
+	RegisterComponentHandler(CmdHangman+":", hangmanHandleInteraction)
+	RegisterComponentHandler(CIDChallengePrefix+":", handleGameChallenge)
+
+	OnClientReady(func(ctx context.Context, client bot.Client) {
+		RegisterDaemon("GAME", LogGame, func(ctx context.Context) (bool, func(), func()) { return RestoreGameSessions(ctx, client) })
+	})
 }
 
 // ===========================
@@ -234,8 +622,23 @@ var (
 	activeChessGames   = make(map[string]*ChessGame)
 	activeChessGamesMu sync.RWMutex
 
+	activeOthelloGames   = make(map[string]*othelloGame)
+	activeOthelloGamesMu sync.RWMutex
+
+	activeTictactoeGames   = make(map[string]*tictactoeGame)
+	activeTictactoeGamesMu sync.RWMutex
+
+	activeMinesweeperGames   = make(map[string]*minesweeperGame)
+	activeMinesweeperGamesMu sync.RWMutex
+
+	activeHangmanGames   = make(map[string]*hangmanGame)
+	activeHangmanGamesMu sync.RWMutex
+
 	userActiveGame   = make(map[snowflake.ID]string)
 	userActiveGameMu sync.RWMutex
+
+	pendingChallenges   = make(map[string]*gameChallenge)
+	pendingChallengesMu sync.Mutex
 )
 
 type GameColorVariant int
@@ -246,1205 +649,5828 @@ const (
 )
 
 // ===========================
-// Connect Four Game Constants & Types
+// Persistence
 // ===========================
 
-const (
-	connect4ToWin = 4
-	connect4P1    = "🔵"
-	connect4P2    = "🔴"
-	connect4Empty = "⚫"
-	connect4P1Win = "🟦"
-	connect4P2Win = "🟥"
-
-	connect4StatusTurn     = MsgGameTurn
-	connect4StatusDraw     = MsgGameDraw
-	connect4StatusWin      = MsgGameWin
-	connect4StatusForfeit  = MsgGameForfeitSuccess
-	connect4StatusTimeout  = "**<@%d> Took Too Long ⏱️ - <@%d> Won! 🎉**"
-	connect4StatusInactive = "**❌ `This game is no longer active.`**"
-	connect4StatusFull     = "**❌ `Column is full.`**"
-)
-
-var (
-	connect4ColumnEmojis = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
-)
-
-// connect4Difficulty represents the AI difficulty level for Connect Four
-type connect4Difficulty int
-
-const (
-	connect4Easy connect4Difficulty = iota
-	connect4Normal
-	connect4Hard
-)
+// gameSessionMaxAge is how long an untouched game is kept in the database before it is pruned on startup
+const gameSessionMaxAge = 24 * time.Hour
 
-// connect4Game represents a Connect Four game session with all its state
-type connect4Game struct {
-	board         [][]int            // Game board (0=empty, 1=player1, 2=player2)
-	rows          int                // Number of rows
-	cols          int                // Number of columns
-	player1ID     snowflake.ID       // Player 1's Discord ID
-	player2ID     snowflake.ID       // Player 2's Discord ID
-	isAI          bool               // Whether this is a PvAI game
-	aiDifficulty  connect4Difficulty // AI difficulty level
-	aiPlayerNum   int                // Which player is AI (1 or 2)
-	colorVariant  GameColorVariant   // Color variant (standard or inverted)
-	currentTurn   int                // Current turn (1 or 2)
-	gameOver      bool               // Whether the game has ended
-	winner        int                // Winner (0=draw, 1=player1, 2=player2)
-	winCells      [][2]int           // Coordinates of winning cells
-	moveCount     int                // Total moves made
-	timerEnabled  bool               // Whether turn timer is enabled
-	timerDuration time.Duration      // Duration for each turn
-	turnTimer     *time.Timer        // Timer for the current turn
-	lastMoveTime  time.Time          // Time of last move
-	messageID     snowflake.ID       // Discord message ID
-	channelID     snowflake.ID       // Discord channel ID
-	originalP1ID  snowflake.ID       // Original player 1 ID (for replays)
-	originalP2ID  snowflake.ID       // Original player 2 ID (for replays)
-}
+// RestoreGameSessions rehydrates saved games so the buttons on their existing messages keep working
+func RestoreGameSessions(ctx context.Context, client bot.Client) (bool, func(), func()) {
+	if n, err := DeleteStaleGameSessions(ctx, time.Now().Add(-gameSessionMaxAge)); err == nil && n > 0 {
+		LogGame(MsgGameStalePruned, n)
+	}
 
-// ===========================
-// Interaction Handlers
-// ===========================
+	sessions, err := GetAllGameSessions(ctx)
+	if err != nil {
+		LogGame(MsgGameRestoreLoadFail, err)
+		return true, nil, nil
+	}
 
-// handlePlayConnect4 initiates a new game session (Slash Command)
-func handlePlayConnect4(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
-	defer func() {
-		if r := recover(); r != nil {
-			LogError("Panic in handlePlayConnect4: %v", r)
-			fmt.Printf("%s\n", debug.Stack())
+	restored := 0
+	for _, s := range sessions {
+		var err error
+		switch s.GameType {
+		case CmdConnect4:
+			err = connect4Restore(client, s.GameID, s.State)
+		case CmdCheckers:
+			err = checkersRestore(client, s.GameID, s.State)
+		case CmdChess:
+			err = chessRestore(client, s.GameID, s.State)
+		case CmdOthello:
+			err = othelloRestore(client, s.GameID, s.State)
+		case CmdTicTacToe:
+			err = tictactoeRestore(client, s.GameID, s.State)
+		case CmdMinesweeper:
+			err = minesweeperRestore(client, s.GameID, s.State)
+		case CmdHangman:
+			err = hangmanRestore(client, s.GameID, s.State)
+		default:
+			err = fmt.Errorf("unknown game type %q", s.GameType)
 		}
-	}()
-
-	var opponentID *snowflake.ID
-	difficulty := connect4Normal
+		if err != nil {
+			LogGame(MsgGameRestoreFail, s.GameID, err)
+			_ = DeleteGameSession(ctx, s.GameID)
+			continue
+		}
+		restored++
+	}
 
-	var isOpponentBot bool
-	if opponent, ok := data.OptUser(OptOpponent); ok {
-		opponentID = &opponent.ID
-		isOpponentBot = opponent.Bot
+	if restored > 0 {
+		LogGame(MsgGameRestored, restored)
 	}
+	return true, nil, nil
+}
 
-	if diff, ok := data.OptString(OptDifficulty); ok {
-		switch diff {
-		case ChoiceEasy:
-			difficulty = connect4Easy
-		case ChoiceNormal:
-			difficulty = connect4Normal
-		case ChoiceHard:
-			difficulty = connect4Hard
-		}
+// gameSaveState writes a serialized game snapshot to the database
+func gameSaveState(gameID, gameType string, snapshot any) {
+	state, err := json.Marshal(snapshot)
+	if err != nil {
+		LogGame(MsgGamePersistFail, gameID, err)
+		return
 	}
+	if err := SaveGameSession(context.Background(), gameID, gameType, string(state)); err != nil {
+		LogGame(MsgGamePersistFail, gameID, err)
+	}
+}
 
-	timerSeconds := 0
-	if timer, ok := data.OptInt(OptTimer); ok {
-		timerSeconds = timer
+// gameForget removes a finished or abandoned game from the database
+func gameForget(gameID string) {
+	if err := DeleteGameSession(context.Background(), gameID); err != nil {
+		LogGame(MsgGamePersistFail, gameID, err)
 	}
+}
 
-	rows, cols := 6, 7
-	if size, ok := data.OptString(OptSize); ok {
-		switch size {
-		case ChoiceSmall:
-			rows, cols = 4, 5
-		case ChoiceClassic:
-			rows, cols = 6, 7
-		case ChoiceLarge:
-			rows, cols = 8, 9
-		case ChoiceMaster:
-			rows, cols = 10, 10
+// gameLockPlayers marks the players of a restored game as busy, skipping the bot itself
+func gameLockPlayers(client bot.Client, gameID string, players ...snowflake.ID) {
+	userActiveGameMu.Lock()
+	defer userActiveGameMu.Unlock()
+	for _, p := range players {
+		if p != 0 && p != client.ApplicationID {
+			userActiveGame[p] = gameID
 		}
 	}
+}
 
-	cid := event.Channel().ID()
-	gameID := fmt.Sprintf("connect4_%d_%d", cid, time.Now().UnixNano())
+// ===========================
+// Ratings
+// ===========================
 
-	appID := event.ApplicationID()
-	p1 := event.User().ID
+const (
+	gameRatingDefault     = 1200.0
+	gameRatingK           = 32.0
+	gameLeaderboardLength = 10
+)
 
-	userActiveGameMu.Lock()
-	if gid, ok := userActiveGame[p1]; ok {
-		userActiveGameMu.Unlock()
-		event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameAlreadyActive, gid)).WithEphemeral(true))
-		return
-	}
-	if opponentID != nil && *opponentID != appID {
-		if gid, ok := userActiveGame[*opponentID]; ok {
-			userActiveGameMu.Unlock()
-			event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameOpponentActive, *opponentID, gid)).WithEphemeral(true))
-			return
+// gameAIRatings are the fixed strengths the AI plays at; the AI itself is never rated
+var gameAIRatings = map[string]float64{
+	ChoiceEasy:   800,
+	ChoiceNormal: 1200,
+	ChoiceHard:   1600,
+}
+
+// gameTypeNames maps a game type to its display name
+var gameTypeNames = map[string]string{
+	CmdConnect4:    "Connect Four",
+	CmdCheckers:    "Checkers",
+	CmdChess:       "Chess",
+	CmdOthello:     "Othello",
+	CmdTicTacToe:   "Ultimate Tic-Tac-Toe",
+	CmdMinesweeper: "Minesweeper",
+	CmdHangman:     "Hangman",
+}
+
+// gameResults queues rating updates for a single worker, so results involving the same player are applied one at a
+// time and in the order the games finished. Callers hold a game's lock, so a full queue drops the result rather than
+// stall every game of that type behind the database
+var (
+	gameResults     = make(chan func(), 64)
+	gameResultsOnce sync.Once
+)
+
+// gameRecordResult applies an Elo update for a finished game (winner 0 = draw, 1 or 2 = that player)
+func gameRecordResult(gameType string, p1, p2 snowflake.ID, winner, aiPlayerNum int, difficulty string) {
+	gameResultsOnce.Do(func() {
+		safeGo(func() {
+			for apply := range gameResults {
+				apply()
+			}
+		})
+	})
+
+	apply := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				LogGame(MsgGameRatingFail, gameType, r)
+			}
+		}()
+
+		var userIDs []snowflake.ID
+		for i, id := range []snowflake.ID{p1, p2} {
+			if aiPlayerNum != i+1 {
+				userIDs = append(userIDs, id)
+			}
+		}
+
+		err := UpdateGameRatings(context.Background(), gameType, userIDs, func(stored []*GameRating) []*GameRating {
+			var ratings [2]*GameRating
+			var current [2]float64
+			for i, id := range []snowflake.ID{p1, p2} {
+				if aiPlayerNum == i+1 {
+					current[i] = gameRatingDefault
+					if r, ok := gameAIRatings[difficulty]; ok {
+						current[i] = r
+					}
+					continue
+				}
+				r := stored[0]
+				stored = stored[1:]
+				if r == nil {
+					r = &GameRating{UserID: id, GameType: gameType, Rating: gameRatingDefault}
+				}
+				ratings[i] = r
+				current[i] = r.Rating
+			}
+
+			score := 0.5
+			switch winner {
+			case 1:
+				score = 1
+			case 2:
+				score = 0
+			}
+			expected := 1 / (1 + math.Pow(10, (current[1]-current[0])/400))
+			delta := gameRatingK * (score - expected)
+
+			var updated []*GameRating
+			for i, r := range ratings {
+				if r == nil {
+					continue
+				}
+				switch winner {
+				case 0:
+					r.Draws++
+				case i + 1:
+					r.Wins++
+				default:
+					r.Losses++
+				}
+				if i == 0 {
+					r.Rating += delta
+				} else {
+					r.Rating -= delta
+				}
+				updated = append(updated, r)
+			}
+			return updated
+		})
+		if err != nil {
+			LogGame(MsgGameRatingFail, gameType, err)
 		}
 	}
-	userActiveGame[p1] = gameID
-	if opponentID != nil && *opponentID != appID {
-		userActiveGame[*opponentID] = gameID
+
+	select {
+	case gameResults <- apply:
+	default:
+		LogGame(MsgGameRatingDropped, gameType)
 	}
-	userActiveGameMu.Unlock()
+}
 
-	game := &connect4Game{
-		rows:          rows,
-		cols:          cols,
-		player1ID:     p1,
-		currentTurn:   1,
-		timerEnabled:  timerSeconds > 0,
-		timerDuration: time.Duration(timerSeconds) * time.Second,
-		lastMoveTime:  time.Now(),
-		channelID:     cid,
+// gameFormatRecord renders a win/loss/draw tally
+func gameFormatRecord(r *GameRating) string {
+	return fmt.Sprintf(MsgGameRecord, r.Wins, r.Losses, r.Draws)
+}
+
+func handleGameLeaderboard(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	gameType := data.String(OptGameType)
+	name := gameTypeNames[gameType]
+
+	ratings, err := GetGameLeaderboard(AppContext, gameType, gameLeaderboardLength)
+	if err != nil {
+		LogGame(MsgGameRatingFail, gameType, err)
+		_ = RespondInteractionV2(*event.Client(), event, MsgGameLeaderboardFail, true)
+		return
 	}
-	game.board = make([][]int, rows)
-	for i := range game.board {
-		game.board[i] = make([]int, cols)
+
+	var sb strings.Builder
+	if len(ratings) == 0 {
+		sb.WriteString(fmt.Sprintf(MsgGameLeaderboardNone, name))
+	}
+	for i, r := range ratings {
+		sb.WriteString(fmt.Sprintf(MsgGameLeaderboardRow, i+1, r.UserID, int(math.Round(r.Rating)), gameFormatRecord(r)))
+		sb.WriteString("\n")
 	}
 
-	var p2 snowflake.ID
-	if opponentID == nil || *opponentID == appID || isOpponentBot {
-		if opponentID != nil {
-			p2 = *opponentID
-		} else {
-			p2 = appID
-		}
-		game.isAI = true
-		game.aiDifficulty = difficulty
-	} else {
-		p2 = *opponentID
-		game.isAI = false
+	container := NewV2Container(
+		NewTextDisplay(fmt.Sprintf(MsgGameLeaderboard, name)),
+		NewSeparator(true),
+		NewTextDisplay(sb.String()),
+	)
+	_ = RespondInteractionContainerV2(*event.Client(), event, container, false)
+}
+
+func handleGameProfile(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	userID := event.User().ID
+	if u, ok := data.OptUser(OptPlayer); ok {
+		userID = u.ID
 	}
 
-	game.originalP1ID = p1
-	game.originalP2ID = p2
-	var player1, player2 snowflake.ID
-	if rand.Intn(2) == 0 {
-		player1 = p1
-		player2 = p2
-	} else {
-		player1 = p2
-		player2 = p1
+	ratings, err := GetGameRatingsForUser(AppContext, userID)
+	if err != nil {
+		LogGame(MsgGameRatingFail, CmdProfile, err)
+		_ = RespondInteractionV2(*event.Client(), event, MsgGameProfileFail, true)
+		return
 	}
 
-	aiPlayerNum := 0
-	if game.isAI {
-		if player1 == appID || (opponentID != nil && player1 == *opponentID && game.isAI) {
-			aiPlayerNum = 1
-		} else {
-			aiPlayerNum = 2
+	var sb strings.Builder
+	if len(ratings) == 0 {
+		sb.WriteString(MsgGameProfileNone)
+	}
+	for _, r := range ratings {
+		name, ok := gameTypeNames[r.GameType]
+		if !ok {
+			name = r.GameType
 		}
+		sb.WriteString(fmt.Sprintf(MsgGameProfileRow, name, int(math.Round(r.Rating)), gameFormatRecord(r)))
+		sb.WriteString("\n")
 	}
 
-	game.player1ID = player1
-	game.player2ID = player2
-	game.aiPlayerNum = aiPlayerNum
+	container := NewV2Container(
+		NewTextDisplay(fmt.Sprintf(MsgGameProfile, userID)),
+		NewSeparator(true),
+		NewTextDisplay(sb.String()),
+	)
+	_ = RespondInteractionContainerV2(*event.Client(), event, container, false)
+}
 
-	variant := VariantStandard
-	if rand.Intn(2) == 1 {
-		variant = VariantInverted
+// ===========================
+// Clocks & Draw Offers
+// ===========================
+
+// gameOutOfTurnActions can be used by either player regardless of whose move it is
+var gameOutOfTurnActions = map[string]bool{
+	"forfeit":     true,
+	"offer_draw":  true,
+	"accept_draw": true,
+}
+
+var gameClockModeNames = map[string]string{
+	ChoiceFischer:   "Fischer",
+	ChoiceBronstein: "Bronstein",
+}
+
+// gameClock is a two-player time control; it is guarded by the owning game's mutex
+type gameClock struct {
+	Mode      string
+	Base      time.Duration
+	Increment time.Duration
+	Remaining [2]time.Duration
+	TurnStart time.Time
+	timer     *time.Timer
+}
+
+// gameClockState is a clock as saved with its game. It keeps how long the turn in progress had run instead of when
+// it started, so a clock restored after downtime picks up where it was saved rather than flagging the player to move
+type gameClockState struct {
+	Mode      string           `json:"mode"`
+	Base      time.Duration    `json:"base"`
+	Increment time.Duration    `json:"increment"`
+	Remaining [2]time.Duration `json:"remaining"`
+	Running   bool             `json:"running,omitempty"`
+	TurnUsed  time.Duration    `json:"turn_used,omitempty"`
+}
+
+func (c *gameClock) MarshalJSON() ([]byte, error) {
+	state := gameClockState{Mode: c.Mode, Base: c.Base, Increment: c.Increment, Remaining: c.Remaining}
+	if !c.TurnStart.IsZero() {
+		state.Running = true
+		state.TurnUsed = time.Since(c.TurnStart)
 	}
-	game.colorVariant = variant
+	return json.Marshal(state)
+}
 
-	activeConnect4GamesMu.Lock()
-	activeConnect4Games[gameID] = game
-	activeConnect4GamesMu.Unlock()
+func (c *gameClock) UnmarshalJSON(data []byte) error {
+	var state gameClockState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	*c = gameClock{Mode: state.Mode, Base: state.Base, Increment: state.Increment, Remaining: state.Remaining}
+	if state.Running {
+		c.TurnStart = time.Now().Add(-state.TurnUsed)
+	}
+	return nil
+}
 
-	builder := connect4BuildMessage(game, gameID, "")
-	if err := RespondInteractionContainerV2(*event.Client(), event, builder, false); err != nil {
-		activeConnect4GamesMu.Lock()
-		delete(activeConnect4Games, gameID)
-		activeConnect4GamesMu.Unlock()
+// gameParseClock builds a clock from the slash command options, or nil if no clock was requested
+func gameParseClock(data discord.SlashCommandInteractionData) *gameClock {
+	minutes, ok := data.OptInt(OptClock)
+	if !ok || minutes <= 0 {
+		return nil
+	}
+	mode := ChoiceFischer
+	if m, ok := data.OptString(OptClockMode); ok {
+		mode = m
+	}
+	increment, _ := data.OptInt(OptIncrement)
 
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
-		return
+	base := time.Duration(minutes) * time.Minute
+	return &gameClock{
+		Mode:      mode,
+		Base:      base,
+		Increment: time.Duration(max(increment, 0)) * time.Second,
+		Remaining: [2]time.Duration{base, base},
 	}
+}
 
-	msg, err := event.Client().Rest.GetInteractionResponse(event.ApplicationID(), event.Token())
-	if err == nil && msg != nil {
-		game.messageID = msg.ID
+// left returns the player's remaining time, counting the turn in progress when it is theirs (turn 0 = stopped)
+func (c *gameClock) left(player, turn int) time.Duration {
+	left := c.Remaining[player-1]
+	if player == turn && !c.TurnStart.IsZero() {
+		left -= time.Since(c.TurnStart)
 	}
+	return max(left, 0)
+}
 
-	if game.timerEnabled {
-		connect4StartTimer(*event.Client(), game, gameID, 0)
+// press ends the mover's turn, charging the time used and applying the increment
+func (c *gameClock) press(player int) {
+	now := time.Now()
+	var used time.Duration
+	if !c.TurnStart.IsZero() {
+		used = now.Sub(c.TurnStart)
 	}
 
-	if game.isAI && game.aiPlayerNum == 1 {
-		time.AfterFunc(1*time.Second, func() {
-			connect4MakeAIMove(*event.Client(), game, gameID)
-		})
+	c.Remaining[player-1] -= used
+	if c.Mode == ChoiceBronstein {
+		c.Remaining[player-1] += min(used, c.Increment)
+	} else {
+		c.Remaining[player-1] += c.Increment
 	}
+	c.TurnStart = now
 }
 
-// connect4StartTimer watches for turn expiry and forfeits if time runs out
-func connect4StartTimer(client bot.Client, game *connect4Game, gameID string, moveCount int) {
-	activeConnect4GamesMu.Lock()
-	if game.turnTimer != nil {
-		game.turnTimer.Stop()
+// arm schedules onFlag for when the player to move runs out of time, replacing any earlier timer
+func (c *gameClock) arm(player int, onFlag func()) {
+	c.stop()
+	if c.TurnStart.IsZero() {
+		c.TurnStart = time.Now()
 	}
-	game.turnTimer = time.AfterFunc(game.timerDuration, func() {
-		activeConnect4GamesMu.Lock()
-		defer activeConnect4GamesMu.Unlock()
+	c.timer = time.AfterFunc(c.left(player, player), onFlag)
+}
 
-		if game.moveCount != moveCount || game.gameOver || game.messageID == 0 {
-			return
-		}
+func (c *gameClock) stop() {
+	if c == nil || c.timer == nil {
+		return
+	}
+	c.timer.Stop()
+	c.timer = nil
+}
 
-		if _, exists := activeConnect4Games[gameID]; !exists {
-			return
-		}
+// render shows both clocks, with a live countdown for the player to move (turn 0 = stopped)
+func (c *gameClock) render(turn int, p1Label, p2Label string) string {
+	line := fmt.Sprintf("-# ⏱️ %s **%s** | %s **%s** · %d+%d %s",
+		p1Label, gameFormatClock(c.left(1, turn)),
+		p2Label, gameFormatClock(c.left(2, turn)),
+		int(c.Base.Minutes()), int(c.Increment.Seconds()), gameClockModeNames[c.Mode])
+	if turn != 0 {
+		line += fmt.Sprintf(" · flag <t:%d:R>", time.Now().Add(c.left(turn, turn)).Unix())
+	}
+	return line
+}
 
-		winnerID := game.player1ID
-		loserID := game.player2ID
-		if game.currentTurn == 1 {
-			winnerID = game.player2ID
-			loserID = game.player1ID
-		}
+// fresh returns an unstarted copy of the clock with the same time control
+func (c *gameClock) fresh() *gameClock {
+	if c == nil {
+		return nil
+	}
+	return &gameClock{
+		Mode:      c.Mode,
+		Base:      c.Base,
+		Increment: c.Increment,
+		Remaining: [2]time.Duration{c.Base, c.Base},
+	}
+}
 
-		game.winner = 2
-		if game.currentTurn == 2 {
-			game.winner = 1
+func gameFormatClock(d time.Duration) string {
+	secs := int(d.Round(time.Second).Seconds())
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+// ===========================
+// Challenges, Rematches & Spectators
+// ===========================
+
+// gameChallengeTimeout is how long the challenged player has to accept a PvP game
+const gameChallengeTimeout = 60 * time.Second
+
+// gameChallenge is a PvP game waiting on the challenged player; launch starts it on the challenge message
+type gameChallenge struct {
+	gameType     string
+	challengerID snowflake.ID
+	opponentID   snowflake.ID
+	channelID    snowflake.ID
+	messageID    snowflake.ID
+	timer        *time.Timer
+	launch       func(client bot.Client, channelID, messageID snowflake.ID) Container
+}
+
+// gameSpectate is a read-only mirror of a game board posted in its own thread
+type gameSpectate struct {
+	ThreadID  snowflake.ID `json:"thread_id"`
+	MessageID snowflake.ID `json:"message_id"`
+}
+
+// gameReservePlayers marks players as busy with gameID, returning a user-facing error if any of them already are
+func gameReservePlayers(client bot.Client, gameID string, players ...snowflake.ID) string {
+	userActiveGameMu.Lock()
+	defer userActiveGameMu.Unlock()
+
+	for i, p := range players {
+		if gid, ok := userActiveGame[p]; ok {
+			if i == 0 {
+				return fmt.Sprintf(MsgGameAlreadyActive, gid)
+			}
+			return fmt.Sprintf(MsgGameOpponentActive, p, gid)
 		}
+	}
+	for _, p := range players {
+		if p != client.ApplicationID {
+			userActiveGame[p] = gameID
+		}
+	}
+	return ""
+}
 
-		game.gameOver = true
+func gameReleasePlayers(players ...snowflake.ID) {
+	userActiveGameMu.Lock()
+	defer userActiveGameMu.Unlock()
+	for _, p := range players {
+		delete(userActiveGame, p)
+	}
+}
 
-		status := fmt.Sprintf(connect4StatusTimeout, loserID, winnerID)
-		builder := connect4BuildMessage(game, gameID, status)
-		_, _ = EditContainerV2(client, game.channelID, game.messageID, builder, nil, nil)
+// gameIssueChallenge posts a challenge in reply to the interaction; the players must already be reserved
+func gameIssueChallenge(client bot.Client, interaction discord.Interaction, gameID string, ch *gameChallenge) {
+	ch.channelID = interaction.Channel().ID()
+	expires := time.Now().Add(gameChallengeTimeout)
+
+	pendingChallengesMu.Lock()
+	pendingChallenges[gameID] = ch
+	ch.timer = time.AfterFunc(gameChallengeTimeout, func() { gameExpireChallenge(client, gameID) })
+	pendingChallengesMu.Unlock()
+
+	container := NewV2Container(
+		NewTextDisplay(fmt.Sprintf(MsgGameChallenge, ch.challengerID, ch.opponentID, gameTypeNames[ch.gameType], expires.Unix())),
+		NewSeparator(true),
+		discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSuccess, LabelAccept, fmt.Sprintf(CIDChallengeAccept, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleDanger, LabelDecline, fmt.Sprintf(CIDChallengeDecline, gameID), "", 0),
+		),
+	)
+	if err := RespondInteractionContainerV2(client, interaction, container, false); err != nil {
+		pendingChallengesMu.Lock()
+		delete(pendingChallenges, gameID)
+		ch.timer.Stop()
+		pendingChallengesMu.Unlock()
+		gameReleasePlayers(ch.challengerID, ch.opponentID)
+		return
+	}
 
-		delete(activeConnect4Games, gameID)
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
-	})
-	activeConnect4GamesMu.Unlock()
+	if msg, err := client.Rest.GetInteractionResponse(interaction.ApplicationID(), interaction.Token()); err == nil && msg != nil {
+		pendingChallengesMu.Lock()
+		ch.messageID = msg.ID
+		pendingChallengesMu.Unlock()
+	}
 }
 
-// connect4HandleMove processes player moves and forfeits (Component Interaction)
-func connect4HandleMove(event *events.ComponentInteractionCreate) {
+// gameExpireChallenge withdraws an unanswered challenge and frees both players
+func gameExpireChallenge(client bot.Client, gameID string) {
+	pendingChallengesMu.Lock()
+	ch, ok := pendingChallenges[gameID]
+	delete(pendingChallenges, gameID)
+	pendingChallengesMu.Unlock()
+	if !ok {
+		return
+	}
+
+	gameReleasePlayers(ch.challengerID, ch.opponentID)
+	if ch.messageID != 0 {
+		msg := NewV2Container(NewTextDisplay(fmt.Sprintf(MsgGameChallengeExpired, ch.opponentID, gameTypeNames[ch.gameType])))
+		_, _ = EditContainerV2(client, ch.channelID, ch.messageID, msg, nil, nil)
+	}
+}
+
+func handleGameChallenge(event *events.ComponentInteractionCreate) {
 	parts := strings.Split(event.Data.CustomID(), ":")
 	if len(parts) < 3 {
-		event.DeferUpdateMessage()
 		return
 	}
 	gameID := parts[1]
 	action := parts[2]
+	userID := event.User().ID
 
-	activeConnect4GamesMu.Lock()
-	game, exists := activeConnect4Games[gameID]
-	if !exists {
-		activeConnect4GamesMu.Unlock()
-		if event.Message.ID == 0 {
-			event.DeferUpdateMessage()
-			return
-		}
-
-		var newComponents []any
-		for i, comp := range event.Message.Components {
-			modified := connect4DisableInteractive(comp)
-			if i == 0 {
-				if td, ok := modified.(discord.TextDisplayComponent); ok {
-					newComponents = append(newComponents, NewTextDisplay(td.Content+"\n"+connect4StatusInactive))
-					continue
-				} else if td, ok := modified.(*discord.TextDisplayComponent); ok {
-					newComponents = append(newComponents, NewTextDisplay(td.Content+"\n"+connect4StatusInactive))
-					continue
-				}
-			}
-			newComponents = append(newComponents, modified)
-		}
-
-		_ = UpdateInteractionContainerV2(*event.Client(), event, NewV2Container(newComponents...))
+	pendingChallengesMu.Lock()
+	ch, ok := pendingChallenges[gameID]
+	if !ok {
+		pendingChallengesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameChallengeGone).WithEphemeral(true))
 		return
 	}
+	if userID != ch.opponentID && (action != "decline" || userID != ch.challengerID) {
+		pendingChallengesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameChallengeNotYours).WithEphemeral(true))
+		return
+	}
+	delete(pendingChallenges, gameID)
+	ch.timer.Stop()
+	pendingChallengesMu.Unlock()
 
-	if game.gameOver && action != "yes" && action != "no" {
-		activeConnect4GamesMu.Unlock()
-		event.DeferUpdateMessage()
+	if action == "accept" {
+		board := ch.launch(*event.Client(), event.Channel().ID(), event.Message.ID)
+		_ = gameUpdateBoard(*event.Client(), event, board)
 		return
 	}
-	activeConnect4GamesMu.Unlock()
 
-	if action == "yes" || action == "no" {
-		userID := event.User().ID
-		if userID != game.originalP1ID && userID != game.originalP2ID {
-			event.CreateMessage(discord.NewMessageCreate().
-				WithContent(MsgGameNotPlayer).
-				WithEphemeral(true))
-			return
+	gameReleasePlayers(ch.challengerID, ch.opponentID)
+	status := MsgGameChallengeDeclined
+	if userID == ch.challengerID {
+		status = MsgGameChallengeWithdrawn
+	}
+	_ = UpdateInteractionContainerV2(*event.Client(), event, NewV2Container(NewTextDisplay(fmt.Sprintf(status, userID, gameTypeNames[ch.gameType]))))
+}
+
+// gameIsOriginalPlayer reports whether userID took part in the game that is being rematched
+func gameIsOriginalPlayer(userID, originalP1, originalP2, p1, p2 snowflake.ID) bool {
+	if originalP1 == 0 && originalP2 == 0 {
+		return userID == p1 || userID == p2
+	}
+	return userID == originalP1 || userID == originalP2
+}
+
+// gameRematchRefusal explains why userID can't ask for a rematch, or returns "" if they can
+func gameRematchRefusal(userID snowflake.ID, gameOver bool, originalP1, originalP2, p1, p2 snowflake.ID) string {
+	if !gameOver {
+		return MsgGameRematchNotOver
+	}
+	if !gameIsOriginalPlayer(userID, originalP1, originalP2, p1, p2) {
+		return MsgGameRematchNotYou
+	}
+	return ""
+}
+
+// gameSpectatorView strips the interactive rows from a board so the mirror can't be played from
+func gameSpectatorView(board Container) Container {
+	var components []any
+	for _, c := range board.Components {
+		if _, ok := c.(discord.ActionRowComponent); ok {
+			continue
 		}
+		components = append(components, c)
+	}
+	return NewV2Container(components...)
+}
 
-		if action == "no" {
-			activeConnect4GamesMu.Lock()
-			if game.turnTimer != nil {
-				game.turnTimer.Stop()
-			}
-			delete(activeConnect4Games, gameID)
-			activeConnect4GamesMu.Unlock()
+// gameSpectateThread opens a spectator thread with the current board; the caller must not hold the game's lock
+func gameSpectateThread(client bot.Client, channelID snowflake.ID, gameType string, board Container) (*gameSpectate, error) {
+	thread, err := client.Rest.CreateThread(channelID, discord.GuildPublicThreadCreate{
+		Name:                fmt.Sprintf(MsgGameSpectateThread, gameTypeNames[gameType]),
+		AutoArchiveDuration: discord.AutoArchiveDuration24h,
+	})
+	if err != nil {
+		return nil, err
+	}
+	msg, err := gameSendBoard(client, thread.ID(), gameSpectatorView(board))
+	if err != nil {
+		_ = client.Rest.DeleteChannel(thread.ID())
+		return nil, err
+	}
+	return &gameSpectate{ThreadID: thread.ID(), MessageID: msg.ID}, nil
+}
 
-			userActiveGameMu.Lock()
-			delete(userActiveGame, game.originalP1ID)
-			delete(userActiveGame, game.originalP2ID)
-			userActiveGameMu.Unlock()
+// gameHandleSpectate answers a spectate click. It is called with the game type's mutex held and releases it before
+// opening the thread, so a slow Discord round trip doesn't stall every other game of the type. attach runs with the
+// mutex held again to store the new thread and returns the one to link, which is an earlier thread if another click
+// got there first; the duplicate is then deleted
+func gameHandleSpectate(event *events.ComponentInteractionCreate, mu sync.Locker, gameID, gameType string, current *gameSpectate, board Container, attach func(*gameSpectate) *gameSpectate) {
+	mu.Unlock()
+	if current != nil {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(gameSpectateReply(gameID, current, nil)).WithEphemeral(true))
+		return
+	}
 
-			fullMsg := connect4BuildMessage(game, gameID, "")
+	// Opening the thread takes two round trips, which can outlast the interaction deadline
+	_ = event.DeferCreateMessage(true)
+	client := *event.Client()
+	spectate, err := gameSpectateThread(client, event.Channel().ID(), gameType, board)
+	if err == nil {
+		mu.Lock()
+		kept := attach(spectate)
+		mu.Unlock()
+		if kept != spectate {
+			_ = client.Rest.DeleteChannel(spectate.ThreadID)
+			spectate = kept
+		}
+	}
+	_ = EditInteractionV2(client, event, gameSpectateReply(gameID, spectate, err))
+}
 
-			var newComponents []any
+// gameSpectateReply points the user at the spectator thread, or explains why it couldn't be opened
+func gameSpectateReply(gameID string, spectate *gameSpectate, err error) string {
+	if err != nil {
+		LogGame(MsgGameSpectateLogFail, gameID, err)
+		return MsgGameSpectateFail
+	}
+	return fmt.Sprintf(MsgGameSpectateLink, spectate.ThreadID)
+}
 
-			if len(fullMsg.Components) > 3 {
-				newComponents = append(newComponents, fullMsg.Components[:3]...)
-			} else {
-				newComponents = append(newComponents, fullMsg.Components...)
-			}
+// gameMirror copies the latest board into the spectator thread, if one is open
+func gameMirror(client bot.Client, spectate *gameSpectate, board Container) {
+	if spectate == nil {
+		return
+	}
+	_, _ = gameEditBoard(client, spectate.ThreadID, spectate.MessageID, gameSpectatorView(board))
+}
 
-			_ = UpdateInteractionContainerV2(*event.Client(), event, NewV2Container(newComponents...))
-			return
+// ===========================
+// Board Images
+// ===========================
+
+const (
+	gameBoardCell     = 56
+	gameBoardMargin   = 28
+	gameBoardFileName = "board.png"
+	gameGlyphScale    = 2
+)
+
+var (
+	gameImageBackground = color.RGBA{0x2B, 0x2D, 0x31, 0xFF}
+	gameImageLabel      = color.RGBA{0xB5, 0xBA, 0xC1, 0xFF}
+	gameImageRed        = color.RGBA{0xE5, 0x48, 0x48, 0xFF}
+	gameImageBlue       = color.RGBA{0x3B, 0x82, 0xF6, 0xFF}
+	gameImageGold       = color.RGBA{0xF5, 0xC5, 0x18, 0xFF}
+	gameImageLastMove   = color.RGBA{0xF6, 0xF6, 0x69, 0xFF}
+	gameImageSelected   = color.RGBA{0x5D, 0xA9, 0xFF, 0xFF}
+	gameImageTarget     = color.RGBA{0x3B, 0xA5, 0x5C, 0xFF}
+	gameImageWhite      = color.RGBA{0xF8, 0xF8, 0xF8, 0xFF}
+	gameImageBlack      = color.RGBA{0x26, 0x26, 0x26, 0xFF}
+
+	connect4ImageBoard = color.RGBA{0x1E, 0x1F, 0x22, 0xFF}
+	connect4ImageHole  = color.RGBA{0x11, 0x12, 0x14, 0xFF}
+	checkersImageLight = color.RGBA{0xEE, 0xD7, 0xB5, 0xFF}
+	checkersImageDark  = color.RGBA{0x8B, 0x5A, 0x3C, 0xFF}
+	chessImageLight    = color.RGBA{0xEE, 0xEE, 0xD2, 0xFF}
+	chessImageDark     = color.RGBA{0x76, 0x96, 0x56, 0xFF}
+)
+
+// gameGlyphs is a 5x7 bitmap font covering the board coordinates and piece letters; each row uses the low 5 bits
+var gameGlyphs = map[rune][7]uint8{
+	'0': {0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E},
+	'1': {0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'2': {0x0E, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1F},
+	'3': {0x1F, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0E},
+	'4': {0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02},
+	'5': {0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E},
+	'6': {0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E},
+	'7': {0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8': {0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E},
+	'9': {0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C},
+	'A': {0x0E, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11},
+	'B': {0x1E, 0x11, 0x11, 0x1E, 0x11, 0x11, 0x1E},
+	'C': {0x0E, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0E},
+	'D': {0x1C, 0x12, 0x11, 0x11, 0x11, 0x12, 0x1C},
+	'E': {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x1F},
+	'F': {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x10},
+	'G': {0x0E, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0F},
+	'H': {0x11, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11},
+	'I': {0x0E, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'J': {0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0C},
+	'K': {0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11},
+	'N': {0x11, 0x11, 0x19, 0x15, 0x13, 0x11, 0x11},
+	'O': {0x0E, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'P': {0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10, 0x10},
+	'Q': {0x0E, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0D},
+	'R': {0x1E, 0x11, 0x11, 0x1E, 0x14, 0x12, 0x11},
+	'X': {0x11, 0x11, 0x0A, 0x04, 0x0A, 0x11, 0x11},
+}
+
+// gameBoardImage is a MediaGallery showing a rendered board; the PNG travels with it so the board can be uploaded
+type gameBoardImage struct {
+	MediaGallery
+	png []byte
+}
+
+// gameCanvas draws a grid board with a coordinate margin; cells are addressed by their on-screen row and column
+type gameCanvas struct {
+	img *image.RGBA
+}
+
+func newGameCanvas(rows, cols int) *gameCanvas {
+	img := image.NewRGBA(image.Rect(0, 0, cols*gameBoardCell+2*gameBoardMargin, rows*gameBoardCell+2*gameBoardMargin))
+	draw.Draw(img, img.Bounds(), &image.Uniform{gameImageBackground}, image.Point{}, draw.Src)
+	return &gameCanvas{img: img}
+}
+
+func (g *gameCanvas) cellRect(r, c int) image.Rectangle {
+	x := gameBoardMargin + c*gameBoardCell
+	y := gameBoardMargin + r*gameBoardCell
+	return image.Rect(x, y, x+gameBoardCell, y+gameBoardCell)
+}
+
+func (g *gameCanvas) fill(r, c int, col color.RGBA) {
+	draw.Draw(g.img, g.cellRect(r, c), &image.Uniform{col}, image.Point{}, draw.Src)
+}
+
+// tint blends col over the cell at the given opacity
+func (g *gameCanvas) tint(r, c int, col color.RGBA, alpha float64) {
+	rect := g.cellRect(r, c)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			g.blend(x, y, col, alpha)
 		}
+	}
+}
 
-		activeConnect4GamesMu.Lock()
-		for r := 0; r < game.rows; r++ {
-			for c := 0; c < game.cols; c++ {
-				game.board[r][c] = 0
+// disc draws an anti-aliased circle centred in the cell, sized as a fraction of the cell, with a rim of rimWidth pixels
+func (g *gameCanvas) disc(r, c int, size float64, fill, rim color.RGBA, rimWidth float64) {
+	rect := g.cellRect(r, c)
+	cx := float64(rect.Min.X) + gameBoardCell/2.0
+	cy := float64(rect.Min.Y) + gameBoardCell/2.0
+	radius := size * gameBoardCell / 2
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			d := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy)
+			if outer := radius - d + 0.5; outer > 0 {
+				g.blend(x, y, rim, min(outer, 1))
+			}
+			if inner := radius - rimWidth - d + 0.5; inner > 0 {
+				g.blend(x, y, fill, min(inner, 1))
 			}
 		}
-		game.gameOver = false
-		game.winner = 0
-		game.winCells = nil
-		game.moveCount = 0
-		game.lastMoveTime = time.Now()
-		game.currentTurn = 1
+	}
+}
 
-		if rand.Intn(2) == 0 {
-			game.player1ID = game.originalP1ID
-			game.player2ID = game.originalP2ID
-			if game.isAI {
-				game.aiPlayerNum = 2
+// label centres text in the cell
+func (g *gameCanvas) label(r, c int, text string, scale int, col color.RGBA) {
+	rect := g.cellRect(r, c)
+	g.text(rect.Min.X+(gameBoardCell-gameTextWidth(text, scale))/2, rect.Min.Y+(gameBoardCell-7*scale)/2, text, scale, col)
+}
+
+// coords writes column labels above and below the board and row labels on both sides; nil skips an axis
+func (g *gameCanvas) coords(colLabels, rowLabels []string) {
+	bottom := g.img.Bounds().Max.Y - gameBoardMargin
+	right := g.img.Bounds().Max.X - gameBoardMargin
+	glyphHeight := 7 * gameGlyphScale
+	for c, text := range colLabels {
+		x := gameBoardMargin + c*gameBoardCell + (gameBoardCell-gameTextWidth(text, gameGlyphScale))/2
+		g.text(x, (gameBoardMargin-glyphHeight)/2, text, gameGlyphScale, gameImageLabel)
+		g.text(x, bottom+(gameBoardMargin-glyphHeight)/2, text, gameGlyphScale, gameImageLabel)
+	}
+	for r, text := range rowLabels {
+		y := gameBoardMargin + r*gameBoardCell + (gameBoardCell-glyphHeight)/2
+		width := gameTextWidth(text, gameGlyphScale)
+		g.text((gameBoardMargin-width)/2, y, text, gameGlyphScale, gameImageLabel)
+		g.text(right+(gameBoardMargin-width)/2, y, text, gameGlyphScale, gameImageLabel)
+	}
+}
+
+// grid rules lines between the cells, thickening every major-th line; a major of 0 keeps them all thin
+func (g *gameCanvas) grid(rows, cols, major int, col color.RGBA) {
+	width := func(i int) int {
+		if major > 0 && i%major == 0 {
+			return 3
+		}
+		return 1
+	}
+	bounds := g.img.Bounds()
+	for r := 0; r <= rows; r++ {
+		y, w := gameBoardMargin+r*gameBoardCell, width(r)
+		line := image.Rect(gameBoardMargin, y-w/2, bounds.Max.X-gameBoardMargin, y-w/2+w)
+		draw.Draw(g.img, line, &image.Uniform{col}, image.Point{}, draw.Src)
+	}
+	for c := 0; c <= cols; c++ {
+		x, w := gameBoardMargin+c*gameBoardCell, width(c)
+		line := image.Rect(x-w/2, gameBoardMargin, x-w/2+w, bounds.Max.Y-gameBoardMargin)
+		draw.Draw(g.img, line, &image.Uniform{col}, image.Point{}, draw.Src)
+	}
+}
+
+func (g *gameCanvas) text(x, y int, text string, scale int, col color.RGBA) {
+	for _, ch := range text {
+		glyph := gameGlyphs[ch]
+		for row, bits := range glyph {
+			for bit := range 5 {
+				if bits&(0x10>>bit) == 0 {
+					continue
+				}
+				px := image.Rect(x+bit*scale, y+row*scale, x+(bit+1)*scale, y+(row+1)*scale)
+				draw.Draw(g.img, px, &image.Uniform{col}, image.Point{}, draw.Src)
 			}
+		}
+		x += 6 * scale
+	}
+}
+
+func (g *gameCanvas) blend(x, y int, col color.RGBA, alpha float64) {
+	dst := g.img.RGBAAt(x, y)
+	mix := func(a, b uint8) uint8 { return uint8(float64(a)*(1-alpha) + float64(b)*alpha + 0.5) }
+	g.img.SetRGBA(x, y, color.RGBA{mix(dst.R, col.R), mix(dst.G, col.G), mix(dst.B, col.B), 0xFF})
+}
+
+// attach encodes the canvas and wraps it in a gallery pointing at the upload
+func (g *gameCanvas) attach() gameBoardImage {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, g.img)
+	return gameBoardImage{MediaGallery: NewMediaGallery("attachment://" + gameBoardFileName), png: buf.Bytes()}
+}
+
+func gameTextWidth(text string, scale int) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return (6*n - 1) * scale
+}
+
+// gameBoardLabels returns the labels for n columns or rows, starting from first and optionally reversed
+func gameBoardLabels(n int, first rune, reverse bool) []string {
+	labels := make([]string, n)
+	for i := range n {
+		if first == '1' {
+			labels[i] = strconv.Itoa(i + 1)
 		} else {
-			game.player1ID = game.originalP2ID
-			game.player2ID = game.originalP1ID
-			if game.isAI {
-				game.aiPlayerNum = 1
-			}
+			labels[i] = string(first + rune(i))
 		}
-		activeConnect4GamesMu.Unlock()
+	}
+	if reverse {
+		slices.Reverse(labels)
+	}
+	return labels
+}
 
-		builder := connect4BuildMessage(game, gameID, MsgGameRestarted)
-		_ = UpdateInteractionContainerV2(*event.Client(), event, builder)
+// gameParseEmojiBoard reports whether the player asked for the emoji board instead of an image
+func gameParseEmojiBoard(data discord.SlashCommandInteractionData) bool {
+	style, _ := data.OptString(OptBoard)
+	return style == ChoiceEmoji
+}
 
-		if game.timerEnabled {
-			connect4StartTimer(*event.Client(), game, gameID, 0)
+// gameBoardFiles returns the rendered boards a container shows, ready to upload
+func gameBoardFiles(board Container) []*discord.File {
+	var files []*discord.File
+	for _, c := range board.Components {
+		if img, ok := c.(gameBoardImage); ok {
+			files = append(files, discord.NewFile(gameBoardFileName, "", bytes.NewReader(img.png)))
 		}
-		if game.isAI && game.aiPlayerNum == 1 {
-			time.AfterFunc(1*time.Second, func() {
-				connect4MakeAIMove(*event.Client(), game, gameID)
-			})
+	}
+	return files
+}
+
+// gameRespondBoard posts a board in reply to the interaction, uploading its image if it has one
+func gameRespondBoard(client bot.Client, interaction discord.Interaction, board Container) error {
+	if files := gameBoardFiles(board); len(files) > 0 {
+		return RespondInteractionContainerV2Files(client, interaction, board, files, false)
+	}
+	return RespondInteractionContainerV2(client, interaction, board, false)
+}
+
+// gameUpdateBoard replaces the board on the message a component belongs to
+func gameUpdateBoard(client bot.Client, interaction discord.Interaction, board Container) error {
+	if files := gameBoardFiles(board); len(files) > 0 {
+		return UpdateInteractionContainerV2Files(client, interaction, board, files)
+	}
+	return UpdateInteractionContainerV2(client, interaction, board)
+}
+
+// gameEditBoard replaces the board on an existing message
+func gameEditBoard(client bot.Client, channelID, messageID snowflake.ID, board Container) (*discord.Message, error) {
+	if files := gameBoardFiles(board); len(files) > 0 {
+		return EditContainerV2Files(client, channelID, messageID, board, files)
+	}
+	return EditContainerV2(client, channelID, messageID, board, nil, nil)
+}
+
+// gameSendBoard posts a board as a new message
+func gameSendBoard(client bot.Client, channelID snowflake.ID, board Container) (*discord.Message, error) {
+	if files := gameBoardFiles(board); len(files) > 0 {
+		return SendContainerV2Files(client, channelID, board, files)
+	}
+	return SendContainerV2(client, channelID, board, nil, nil, nil)
+}
+
+// ===========================
+// Connect Four Game Constants & Types
+// ===========================
+
+const (
+	connect4ToWin = 4
+	connect4P1    = "🔵"
+	connect4P2    = "🔴"
+	connect4Empty = "⚫"
+	connect4P1Win = "🟦"
+	connect4P2Win = "🟥"
+
+	connect4StatusTurn     = MsgGameTurn
+	connect4StatusDraw     = MsgGameDraw
+	connect4StatusWin      = MsgGameWin
+	connect4StatusForfeit  = MsgGameForfeitSuccess
+	connect4StatusTimeout  = "**<@%d> Took Too Long ⏱️ - <@%d> Won! 🎉**"
+	connect4StatusInactive = "**❌ `This game is no longer active.`**"
+	connect4StatusFull     = "**❌ `Column is full.`**"
+)
+
+var (
+	connect4ColumnEmojis = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
+)
+
+// connect4Difficulty represents the AI difficulty level for Connect Four
+type connect4Difficulty int
+
+const (
+	connect4Easy connect4Difficulty = iota
+	connect4Normal
+	connect4Hard
+)
+
+// connect4Game represents a Connect Four game session with all its state
+type connect4Game struct {
+	board         [][]int            // Game board (0=empty, 1=player1, 2=player2)
+	rows          int                // Number of rows
+	cols          int                // Number of columns
+	player1ID     snowflake.ID       // Player 1's Discord ID
+	player2ID     snowflake.ID       // Player 2's Discord ID
+	isAI          bool               // Whether this is a PvAI game
+	aiDifficulty  connect4Difficulty // AI difficulty level
+	aiPlayerNum   int                // Which player is AI (1 or 2)
+	colorVariant  GameColorVariant   // Color variant (standard or inverted)
+	currentTurn   int                // Current turn (1 or 2)
+	gameOver      bool               // Whether the game has ended
+	winner        int                // Winner (0=draw, 1=player1, 2=player2)
+	winCells      [][2]int           // Coordinates of winning cells
+	moveCount     int                // Total moves made
+	timerEnabled  bool               // Whether turn timer is enabled
+	timerDuration time.Duration      // Duration for each turn
+	turnTimer     *time.Timer        // Timer for the current turn
+	lastMoveTime  time.Time          // Time of last move
+	messageID     snowflake.ID       // Discord message ID
+	channelID     snowflake.ID       // Discord channel ID
+	originalP1ID  snowflake.ID       // Original player 1 ID (for replays)
+	originalP2ID  snowflake.ID       // Original player 2 ID (for replays)
+	spectate      *gameSpectate      // Spectator thread mirror, if opened
+	lastMove      *[2]int            // Last disc dropped (row, col)
+	emojiBoard    bool               // Draw the board with emoji instead of an image
+}
+
+// connect4Snapshot is the serialized form of a connect4Game stored in the database
+type connect4Snapshot struct {
+	Board         [][]int            `json:"board"`
+	Rows          int                `json:"rows"`
+	Cols          int                `json:"cols"`
+	Player1ID     snowflake.ID       `json:"player1_id"`
+	Player2ID     snowflake.ID       `json:"player2_id"`
+	IsAI          bool               `json:"is_ai"`
+	AIDifficulty  connect4Difficulty `json:"ai_difficulty"`
+	AIPlayerNum   int                `json:"ai_player_num"`
+	ColorVariant  GameColorVariant   `json:"color_variant"`
+	CurrentTurn   int                `json:"current_turn"`
+	GameOver      bool               `json:"game_over"`
+	Winner        int                `json:"winner"`
+	WinCells      [][2]int           `json:"win_cells"`
+	MoveCount     int                `json:"move_count"`
+	TimerEnabled  bool               `json:"timer_enabled"`
+	TimerDuration time.Duration      `json:"timer_duration"`
+	LastMoveTime  time.Time          `json:"last_move_time"`
+	MessageID     snowflake.ID       `json:"message_id"`
+	ChannelID     snowflake.ID       `json:"channel_id"`
+	OriginalP1ID  snowflake.ID       `json:"original_p1_id"`
+	OriginalP2ID  snowflake.ID       `json:"original_p2_id"`
+	Spectate      *gameSpectate      `json:"spectate,omitempty"`
+	LastMove      *[2]int            `json:"last_move,omitempty"`
+	EmojiBoard    bool               `json:"emoji_board,omitempty"`
+}
+
+// ===========================
+// Interaction Handlers
+// ===========================
+
+// handlePlayConnect4 initiates a new game session (Slash Command)
+func handlePlayConnect4(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in handlePlayConnect4: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
+
+	var opponentID *snowflake.ID
+	difficulty := connect4Normal
+
+	var isOpponentBot bool
+	if opponent, ok := data.OptUser(OptOpponent); ok {
+		opponentID = &opponent.ID
+		isOpponentBot = opponent.Bot
+	}
+
+	if diff, ok := data.OptString(OptDifficulty); ok {
+		switch diff {
+		case ChoiceEasy:
+			difficulty = connect4Easy
+		case ChoiceNormal:
+			difficulty = connect4Normal
+		case ChoiceHard:
+			difficulty = connect4Hard
+		}
+	}
+
+	timerSeconds := 0
+	if timer, ok := data.OptInt(OptTimer); ok {
+		timerSeconds = timer
+	}
+
+	rows, cols := 6, 7
+	if size, ok := data.OptString(OptSize); ok {
+		switch size {
+		case ChoiceSmall:
+			rows, cols = 4, 5
+		case ChoiceClassic:
+			rows, cols = 6, 7
+		case ChoiceLarge:
+			rows, cols = 8, 9
+		case ChoiceMaster:
+			rows, cols = 10, 10
+		}
+	}
+
+	cid := event.Channel().ID()
+	gameID := fmt.Sprintf("connect4_%d_%d", cid, time.Now().UnixNano())
+
+	appID := event.ApplicationID()
+	p1 := event.User().ID
+
+	if opponentID != nil && *opponentID == p1 {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameChallengeSelf).WithEphemeral(true))
+		return
+	}
+
+	userActiveGameMu.Lock()
+	if gid, ok := userActiveGame[p1]; ok {
+		userActiveGameMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameAlreadyActive, gid)).WithEphemeral(true))
+		return
+	}
+	if opponentID != nil && *opponentID != appID {
+		if gid, ok := userActiveGame[*opponentID]; ok {
+			userActiveGameMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameOpponentActive, *opponentID, gid)).WithEphemeral(true))
+			return
+		}
+	}
+	userActiveGame[p1] = gameID
+	if opponentID != nil && *opponentID != appID {
+		userActiveGame[*opponentID] = gameID
+	}
+	userActiveGameMu.Unlock()
+
+	game := &connect4Game{
+		rows:          rows,
+		cols:          cols,
+		player1ID:     p1,
+		currentTurn:   1,
+		timerEnabled:  timerSeconds > 0,
+		timerDuration: time.Duration(timerSeconds) * time.Second,
+		lastMoveTime:  time.Now(),
+		channelID:     cid,
+	}
+	game.board = make([][]int, rows)
+	for i := range game.board {
+		game.board[i] = make([]int, cols)
+	}
+
+	var p2 snowflake.ID
+	if opponentID == nil || *opponentID == appID || isOpponentBot {
+		if opponentID != nil {
+			p2 = *opponentID
+		} else {
+			p2 = appID
+		}
+		game.isAI = true
+		game.aiDifficulty = difficulty
+	} else {
+		p2 = *opponentID
+		game.isAI = false
+	}
+
+	game.originalP1ID = p1
+	game.originalP2ID = p2
+	var player1, player2 snowflake.ID
+	if rand.Intn(2) == 0 {
+		player1 = p1
+		player2 = p2
+	} else {
+		player1 = p2
+		player2 = p1
+	}
+
+	aiPlayerNum := 0
+	if game.isAI {
+		if player1 == appID || (opponentID != nil && player1 == *opponentID && game.isAI) {
+			aiPlayerNum = 1
+		} else {
+			aiPlayerNum = 2
+		}
+	}
+
+	game.player1ID = player1
+	game.player2ID = player2
+	game.aiPlayerNum = aiPlayerNum
+
+	variant := VariantStandard
+	if rand.Intn(2) == 1 {
+		variant = VariantInverted
+	}
+	game.colorVariant = variant
+	game.emojiBoard = gameParseEmojiBoard(data)
+
+	connect4Open(*event.Client(), event, game, gameID)
+}
+
+// connect4Open posts a new game: PvP games wait for the opponent to accept, AI games start immediately
+func connect4Open(client bot.Client, interaction discord.Interaction, game *connect4Game, gameID string) {
+	if !game.isAI {
+		gameIssueChallenge(client, interaction, gameID, &gameChallenge{
+			gameType:     CmdConnect4,
+			challengerID: game.originalP1ID,
+			opponentID:   game.originalP2ID,
+			launch: func(client bot.Client, channelID, messageID snowflake.ID) Container {
+				return connect4Launch(client, game, gameID, channelID, messageID)
+			},
+		})
+		return
+	}
+
+	activeConnect4GamesMu.Lock()
+	activeConnect4Games[gameID] = game
+	activeConnect4GamesMu.Unlock()
+
+	builder := connect4BuildMessage(game, gameID, "")
+	if err := gameRespondBoard(client, interaction, builder); err != nil {
+		activeConnect4GamesMu.Lock()
+		delete(activeConnect4Games, gameID)
+		activeConnect4GamesMu.Unlock()
+
+		gameReleasePlayers(game.player1ID, game.player2ID)
+		return
+	}
+
+	var messageID snowflake.ID
+	if msg, err := client.Rest.GetInteractionResponse(interaction.ApplicationID(), interaction.Token()); err == nil && msg != nil {
+		messageID = msg.ID
+	}
+	connect4Launch(client, game, gameID, interaction.Channel().ID(), messageID)
+}
+
+// connect4Launch attaches a game to its message, starts its timer and AI, and returns the opening board
+func connect4Launch(client bot.Client, game *connect4Game, gameID string, channelID, messageID snowflake.ID) Container {
+	activeConnect4GamesMu.Lock()
+	activeConnect4Games[gameID] = game
+	game.channelID = channelID
+	game.messageID = messageID
+	game.lastMoveTime = time.Now()
+	connect4Persist(game, gameID)
+	builder := connect4BuildMessage(game, gameID, "")
+	activeConnect4GamesMu.Unlock()
+
+	if game.timerEnabled {
+		connect4StartTimer(client, game, gameID, 0)
+	}
+
+	if game.isAI && game.aiPlayerNum == 1 {
+		time.AfterFunc(1*time.Second, func() {
+			connect4MakeAIMove(client, game, gameID)
+		})
+	}
+	return builder
+}
+
+// connect4StartTimer watches for turn expiry and forfeits if time runs out
+func connect4StartTimer(client bot.Client, game *connect4Game, gameID string, moveCount int) {
+	activeConnect4GamesMu.Lock()
+	if game.turnTimer != nil {
+		game.turnTimer.Stop()
+	}
+	game.turnTimer = time.AfterFunc(time.Until(game.lastMoveTime.Add(game.timerDuration)), func() {
+		activeConnect4GamesMu.Lock()
+		defer activeConnect4GamesMu.Unlock()
+
+		if game.moveCount != moveCount || game.gameOver || game.messageID == 0 {
+			return
+		}
+
+		if _, exists := activeConnect4Games[gameID]; !exists {
+			return
+		}
+
+		winnerID := game.player1ID
+		loserID := game.player2ID
+		if game.currentTurn == 1 {
+			winnerID = game.player2ID
+			loserID = game.player1ID
+		}
+
+		game.winner = 2
+		if game.currentTurn == 2 {
+			game.winner = 1
+		}
+
+		game.gameOver = true
+
+		connect4RecordResult(game)
+
+		status := fmt.Sprintf(connect4StatusTimeout, loserID, winnerID)
+		builder := connect4BuildMessage(game, gameID, status)
+		_, _ = gameEditBoard(client, game.channelID, game.messageID, builder)
+		gameMirror(client, game.spectate, builder)
+
+		delete(activeConnect4Games, gameID)
+		gameForget(gameID)
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
+	})
+	activeConnect4GamesMu.Unlock()
+}
+
+// connect4HandleMove processes player moves and forfeits (Component Interaction)
+func connect4HandleMove(event *events.ComponentInteractionCreate) {
+	parts := strings.Split(event.Data.CustomID(), ":")
+	if len(parts) < 3 {
+		event.DeferUpdateMessage()
+		return
+	}
+	gameID := parts[1]
+	action := parts[2]
+
+	activeConnect4GamesMu.Lock()
+	game, exists := activeConnect4Games[gameID]
+	if !exists {
+		activeConnect4GamesMu.Unlock()
+		if event.Message.ID == 0 {
+			event.DeferUpdateMessage()
+			return
+		}
+
+		var newComponents []any
+		for i, comp := range event.Message.Components {
+			modified := connect4DisableInteractive(comp)
+			if i == 0 {
+				if td, ok := modified.(discord.TextDisplayComponent); ok {
+					newComponents = append(newComponents, NewTextDisplay(td.Content+"\n"+connect4StatusInactive))
+					continue
+				} else if td, ok := modified.(*discord.TextDisplayComponent); ok {
+					newComponents = append(newComponents, NewTextDisplay(td.Content+"\n"+connect4StatusInactive))
+					continue
+				}
+			}
+			newComponents = append(newComponents, modified)
+		}
+
+		_ = UpdateInteractionContainerV2(*event.Client(), event, NewV2Container(newComponents...))
+		return
+	}
+
+	if action == "spectate" {
+		gameHandleSpectate(event, &activeConnect4GamesMu, gameID, CmdConnect4, game.spectate, connect4BuildMessage(game, gameID, ""), func(s *gameSpectate) *gameSpectate {
+			if game.spectate == nil {
+				game.spectate = s
+				connect4Persist(game, gameID)
+			}
+			return game.spectate
+		})
+		return
+	}
+
+	if game.gameOver && action != "yes" && action != "no" {
+		activeConnect4GamesMu.Unlock()
+		event.DeferUpdateMessage()
+		return
+	}
+	activeConnect4GamesMu.Unlock()
+
+	if action == "yes" || action == "no" {
+		userID := event.User().ID
+		if userID != game.originalP1ID && userID != game.originalP2ID {
+			event.CreateMessage(discord.NewMessageCreate().
+				WithContent(MsgGameNotPlayer).
+				WithEphemeral(true))
+			return
+		}
+
+		if action == "no" {
+			activeConnect4GamesMu.Lock()
+			if game.turnTimer != nil {
+				game.turnTimer.Stop()
+			}
+			delete(activeConnect4Games, gameID)
+			activeConnect4GamesMu.Unlock()
+			gameForget(gameID)
+
+			userActiveGameMu.Lock()
+			delete(userActiveGame, game.originalP1ID)
+			delete(userActiveGame, game.originalP2ID)
+			userActiveGameMu.Unlock()
+
+			fullMsg := connect4BuildMessage(game, gameID, "")
+
+			var newComponents []any
+
+			if len(fullMsg.Components) > 3 {
+				newComponents = append(newComponents, fullMsg.Components[:3]...)
+			} else {
+				newComponents = append(newComponents, fullMsg.Components...)
+			}
+
+			_ = gameUpdateBoard(*event.Client(), event, NewV2Container(newComponents...))
+			gameMirror(*event.Client(), game.spectate, NewV2Container(newComponents...))
+			return
+		}
+
+		if errMsg := gameReservePlayers(*event.Client(), gameID, game.originalP1ID, game.originalP2ID); errMsg != "" {
+			event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+			return
+		}
+
+		activeConnect4GamesMu.Lock()
+		for r := 0; r < game.rows; r++ {
+			for c := 0; c < game.cols; c++ {
+				game.board[r][c] = 0
+			}
+		}
+		game.gameOver = false
+		game.winner = 0
+		game.winCells = nil
+		game.lastMove = nil
+		game.moveCount = 0
+		game.lastMoveTime = time.Now()
+		game.currentTurn = 1
+
+		// A rematch swaps who moves first
+		if game.player1ID == game.originalP1ID {
+			game.player1ID = game.originalP2ID
+			game.player2ID = game.originalP1ID
+		} else {
+			game.player1ID = game.originalP1ID
+			game.player2ID = game.originalP2ID
+		}
+		if game.isAI {
+			game.aiPlayerNum = 3 - game.aiPlayerNum
+		}
+		connect4Persist(game, gameID)
+		activeConnect4GamesMu.Unlock()
+
+		builder := connect4BuildMessage(game, gameID, MsgGameRestarted)
+		_ = gameUpdateBoard(*event.Client(), event, builder)
+		gameMirror(*event.Client(), game.spectate, builder)
+
+		if game.timerEnabled {
+			connect4StartTimer(*event.Client(), game, gameID, 0)
+		}
+		if game.isAI && game.aiPlayerNum == 1 {
+			time.AfterFunc(1*time.Second, func() {
+				connect4MakeAIMove(*event.Client(), game, gameID)
+			})
+		}
+		return
+	}
+
+	if action == "forfeit" {
+		userID := event.User().ID
+		if userID != game.player1ID && userID != game.player2ID {
+			event.CreateMessage(discord.NewMessageCreate().
+				WithContent(MsgGameNotPlayer).
+				WithEphemeral(true))
+			return
+		}
+
+		activeConnect4GamesMu.Lock()
+		if game.turnTimer != nil {
+			game.turnTimer.Stop()
+		}
+		game.gameOver = true
+		var forfeitMsg string
+		if userID == game.player1ID {
+			game.winner = 2
+			forfeitMsg = fmt.Sprintf(connect4StatusForfeit, game.player1ID, game.player2ID)
+		} else {
+			game.winner = 1
+			forfeitMsg = fmt.Sprintf(connect4StatusForfeit, game.player2ID, game.player1ID)
+		}
+		connect4RecordResult(game)
+		connect4Persist(game, gameID)
+		activeConnect4GamesMu.Unlock()
+
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
+
+		builder := connect4BuildMessage(game, gameID, forfeitMsg)
+		_ = gameUpdateBoard(*event.Client(), event, builder)
+		gameMirror(*event.Client(), game.spectate, builder)
+		return
+	}
+
+	col, err := strconv.Atoi(action)
+	if err != nil {
+		event.DeferUpdateMessage()
+		return
+	}
+	col--
+
+	userID := event.User().ID
+	expectedPlayerID := game.player1ID
+	if game.currentTurn == 2 {
+		expectedPlayerID = game.player2ID
+	}
+
+	if userID != expectedPlayerID {
+		event.CreateMessage(discord.NewMessageCreate().
+			WithContent(MsgGameNotTurn).
+			WithEphemeral(true))
+		return
+	}
+
+	activeConnect4GamesMu.Lock()
+	if game.gameOver && action != "yes" && action != "no" {
+		activeConnect4GamesMu.Unlock()
+		event.DeferUpdateMessage()
+		return
+	}
+
+	if game.turnTimer != nil {
+		game.turnTimer.Stop()
+	}
+
+	statusMsg := connect4MakeMove(game, col)
+	connect4Persist(game, gameID)
+	activeConnect4GamesMu.Unlock()
+
+	builder := connect4BuildMessage(game, gameID, statusMsg)
+	_ = gameUpdateBoard(*event.Client(), event, builder)
+	gameMirror(*event.Client(), game.spectate, builder)
+
+	if !game.gameOver {
+		if game.timerEnabled {
+			connect4StartTimer(*event.Client(), game, gameID, game.moveCount)
+		}
+		if game.isAI && game.currentTurn == game.aiPlayerNum {
+			time.AfterFunc(1*time.Second, func() {
+				connect4MakeAIMove(*event.Client(), game, gameID)
+			})
+		}
+	}
+}
+
+// connect4RecordResult feeds a finished game into the rating system
+func connect4RecordResult(game *connect4Game) {
+	aiPlayer := 0
+	if game.isAI {
+		aiPlayer = game.aiPlayerNum
+	}
+	difficulty := ChoiceNormal
+	switch game.aiDifficulty {
+	case connect4Easy:
+		difficulty = ChoiceEasy
+	case connect4Hard:
+		difficulty = ChoiceHard
+	}
+	gameRecordResult(CmdConnect4, game.player1ID, game.player2ID, game.winner, aiPlayer, difficulty)
+}
+
+// connect4Persist saves the game state, or forgets it once the game is over (caller must hold activeConnect4GamesMu)
+func connect4Persist(game *connect4Game, gameID string) {
+	if game.gameOver {
+		gameForget(gameID)
+		return
+	}
+	gameSaveState(gameID, CmdConnect4, connect4Snapshot{
+		Board:         game.board,
+		Rows:          game.rows,
+		Cols:          game.cols,
+		Player1ID:     game.player1ID,
+		Player2ID:     game.player2ID,
+		IsAI:          game.isAI,
+		AIDifficulty:  game.aiDifficulty,
+		AIPlayerNum:   game.aiPlayerNum,
+		ColorVariant:  game.colorVariant,
+		CurrentTurn:   game.currentTurn,
+		GameOver:      game.gameOver,
+		Winner:        game.winner,
+		WinCells:      game.winCells,
+		MoveCount:     game.moveCount,
+		TimerEnabled:  game.timerEnabled,
+		TimerDuration: game.timerDuration,
+		LastMoveTime:  game.lastMoveTime,
+		MessageID:     game.messageID,
+		ChannelID:     game.channelID,
+		OriginalP1ID:  game.originalP1ID,
+		OriginalP2ID:  game.originalP2ID,
+		Spectate:      game.spectate,
+		LastMove:      game.lastMove,
+		EmojiBoard:    game.emojiBoard,
+	})
+}
+
+// connect4Restore rebuilds a saved game and resumes its timer and AI turn
+func connect4Restore(client bot.Client, gameID string, state string) error {
+	var snap connect4Snapshot
+	if err := json.Unmarshal([]byte(state), &snap); err != nil {
+		return err
+	}
+	if snap.Rows <= 0 || snap.Cols <= 0 || len(snap.Board) != snap.Rows {
+		return fmt.Errorf("invalid board size %dx%d", snap.Cols, snap.Rows)
+	}
+
+	game := &connect4Game{
+		board:         snap.Board,
+		rows:          snap.Rows,
+		cols:          snap.Cols,
+		player1ID:     snap.Player1ID,
+		player2ID:     snap.Player2ID,
+		isAI:          snap.IsAI,
+		aiDifficulty:  snap.AIDifficulty,
+		aiPlayerNum:   snap.AIPlayerNum,
+		colorVariant:  snap.ColorVariant,
+		currentTurn:   snap.CurrentTurn,
+		gameOver:      snap.GameOver,
+		winner:        snap.Winner,
+		winCells:      snap.WinCells,
+		moveCount:     snap.MoveCount,
+		timerEnabled:  snap.TimerEnabled,
+		timerDuration: snap.TimerDuration,
+		lastMoveTime:  time.Now(), // the turn starts over so downtime isn't charged to the player to move
+		messageID:     snap.MessageID,
+		channelID:     snap.ChannelID,
+		originalP1ID:  snap.OriginalP1ID,
+		originalP2ID:  snap.OriginalP2ID,
+		spectate:      snap.Spectate,
+		lastMove:      snap.LastMove,
+		emojiBoard:    snap.EmojiBoard,
+	}
+
+	activeConnect4GamesMu.Lock()
+	activeConnect4Games[gameID] = game
+	activeConnect4GamesMu.Unlock()
+
+	if game.gameOver {
+		return nil
+	}
+
+	gameLockPlayers(client, gameID, game.player1ID, game.player2ID)
+
+	if game.timerEnabled {
+		connect4StartTimer(client, game, gameID, game.moveCount)
+	}
+	if game.isAI && game.currentTurn == game.aiPlayerNum {
+		time.AfterFunc(1*time.Second, func() {
+			connect4MakeAIMove(client, game, gameID)
+		})
+	}
+	return nil
+}
+
+// ===========================
+// UI & Rendering
+// ===========================
+
+// connect4BuildMessage constructs the Discord message for the current game state
+func connect4BuildMessage(game *connect4Game, gameID string, statusMsg string) Container {
+	var sb strings.Builder
+	header := connect4GetHeader(game.cols)
+	sb.WriteString(header + "\n")
+
+	// Determine colors based on variant
+	p1Emoji, p2Emoji := connect4P1, connect4P2
+	p1WinEmoji, p2WinEmoji := connect4P1Win, connect4P2Win
+	if game.colorVariant == VariantInverted {
+		p1Emoji, p2Emoji = connect4P2, connect4P1
+		p1WinEmoji, p2WinEmoji = connect4P2Win, connect4P1Win
+	}
+
+	for row := 0; row < game.rows; row++ {
+		for col := 0; col < game.cols; col++ {
+			isWinCell := false
+			if game.gameOver && game.winner != 0 {
+				for _, cell := range game.winCells {
+					if cell[0] == row && cell[1] == col {
+						isWinCell = true
+						break
+					}
+				}
+			}
+
+			if isWinCell {
+				if game.winner == 1 {
+					sb.WriteString(p1WinEmoji)
+				} else {
+					sb.WriteString(p2WinEmoji)
+				}
+			} else {
+				switch game.board[row][col] {
+				case 0:
+					sb.WriteString(connect4Empty)
+				case 1:
+					sb.WriteString(p1Emoji)
+				case 2:
+					sb.WriteString(p2Emoji)
+				}
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(header + "\n")
+
+	var statusSB strings.Builder
+	if game.gameOver {
+		if statusMsg != "" {
+			statusSB.WriteString(statusMsg)
+		} else if game.winner == 0 {
+			statusSB.WriteString(fmt.Sprintf(connect4StatusDraw, game.player1ID, game.player2ID))
+		} else {
+			winnerID := game.player1ID
+			loserID := game.player2ID
+			if game.winner == 2 {
+				winnerID = game.player2ID
+				loserID = game.player1ID
+			}
+			statusSB.WriteString(fmt.Sprintf(connect4StatusWin, loserID, winnerID))
+		}
+	} else {
+		currentPlayerID := game.player1ID
+		currentSymbol := p1Emoji
+		if game.currentTurn == 2 {
+			currentPlayerID = game.player2ID
+			currentSymbol = p2Emoji
+		}
+
+		statusSB.WriteString(fmt.Sprintf(connect4StatusTurn, currentPlayerID, currentSymbol))
+
+		if statusMsg != "" {
+			statusSB.WriteString("\n" + statusMsg)
+		}
+
+		if game.timerEnabled {
+			expires := game.lastMoveTime.Add(game.timerDuration)
+			statusSB.WriteString(fmt.Sprintf("\n⏱️ Expires <t:%d:R>", expires.Unix()))
+		}
+	}
+
+	var components []any
+
+	components = append(components, NewTextDisplay(statusSB.String()))
+	if game.emojiBoard {
+		components = append(components, NewTextDisplay(sb.String()))
+	} else {
+		components = append(components, connect4RenderBoard(game))
+	}
+
+	if game.gameOver {
+		components = append(components, NewSeparator(true))
+
+		row := discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSecondary, LabelPlayAgain, CIDConnect4Disabled, "", 0).WithDisabled(true),
+			discord.NewButton(discord.ButtonStyleSuccess, LabelYes, fmt.Sprintf(CIDConnect4Yes, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleDanger, LabelNo, fmt.Sprintf(CIDConnect4No, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDConnect4Spectate, gameID), "", 0),
+		)
+		components = append(components, row)
+	} else {
+		components = append(components, NewSeparator(true))
+
+		for i := 0; i < game.cols; i += 5 {
+			var rowButtons []discord.InteractiveComponent
+			end := min(i+5, game.cols)
+			for col := i + 1; col <= end; col++ {
+				customID := fmt.Sprintf(CIDConnect4Move, gameID, col)
+				btn := discord.NewButton(discord.ButtonStylePrimary, connect4ColumnEmojis[col-1], customID, "", 0)
+				if connect4IsColumnFull(game, col-1) {
+					btn = btn.WithDisabled(true)
+				}
+				rowButtons = append(rowButtons, btn)
+			}
+			components = append(components, discord.NewActionRow(rowButtons...))
+		}
+
+		forfeitBtn := discord.NewButton(discord.ButtonStyleDanger, LabelForfeit, fmt.Sprintf(CIDConnect4Forfeit, gameID), "", 0)
+		spectateBtn := discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDConnect4Spectate, gameID), "", 0)
+		components = append(components, discord.NewActionRow(forfeitBtn, spectateBtn))
+	}
+
+	return NewV2Container(components...)
+}
+
+// connect4RenderBoard draws the board as a PNG, ringing the last disc dropped and the winning line
+func connect4RenderBoard(game *connect4Game) gameBoardImage {
+	p1Color, p2Color := gameImageBlue, gameImageRed
+	if game.colorVariant == VariantInverted {
+		p1Color, p2Color = gameImageRed, gameImageBlue
+	}
+
+	winning := make(map[[2]int]bool)
+	if game.gameOver && game.winner != 0 {
+		for _, cell := range game.winCells {
+			winning[cell] = true
+		}
+	}
+
+	canvas := newGameCanvas(game.rows, game.cols)
+	for r := range game.rows {
+		for c := range game.cols {
+			canvas.fill(r, c, connect4ImageBoard)
+
+			fill := connect4ImageHole
+			switch game.board[r][c] {
+			case 1:
+				fill = p1Color
+			case 2:
+				fill = p2Color
+			}
+			rim := fill
+			if winning[[2]int{r, c}] {
+				rim = gameImageGold
+			} else if game.lastMove != nil && *game.lastMove == [2]int{r, c} {
+				rim = gameImageWhite
+			}
+			canvas.disc(r, c, 0.8, fill, rim, 4)
+		}
+	}
+	canvas.coords(gameBoardLabels(game.cols, '1', false), nil)
+	return canvas.attach()
+}
+
+// ===========================
+// Game Logic
+// ===========================
+
+// connect4MakeMove attempts to place a piece in the specified column
+func connect4MakeMove(game *connect4Game, col int) string {
+	if game.gameOver {
+		return "Game is already over."
+	}
+	row := -1
+	for r := game.rows - 1; r >= 0; r-- {
+		if game.board[r][col] == 0 {
+			row = r
+			break
+		}
+	}
+
+	if row == -1 {
+		return connect4StatusFull
+	}
+
+	game.board[row][col] = game.currentTurn
+	game.lastMove = &[2]int{row, col}
+	game.lastMoveTime = time.Now()
+	game.moveCount++
+
+	if won, cells := connect4CheckWin(game, row, col); won {
+		game.gameOver = true
+		game.winner = game.currentTurn
+		game.winCells = cells
+		connect4RecordResult(game)
+
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
+	} else if connect4IsBoardFull(game) {
+		game.gameOver = true
+		game.winner = 0
+		game.currentTurn = 1
+		connect4RecordResult(game)
+
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
+	} else {
+		if game.currentTurn == 1 {
+			game.currentTurn = 2
+		} else {
+			game.currentTurn = 1
+		}
+	}
+
+	return ""
+}
+
+// connect4CheckWin checks if the last move at (row, col) resulted in a win
+func connect4CheckWin(game *connect4Game, row, col int) (bool, [][2]int) {
+	player := game.board[row][col]
+	if player == 0 {
+		return false, nil
+	}
+
+	collect := func(dr, dc int) [][2]int {
+		cells := [][2]int{{row, col}}
+		for r, c := row+dr, col+dc; r >= 0 && r < game.rows && c >= 0 && c < game.cols && game.board[r][c] == player; r, c = r+dr, c+dc {
+			cells = append(cells, [2]int{r, c})
+		}
+		for r, c := row-dr, col-dc; r >= 0 && r < game.rows && c >= 0 && c < game.cols && game.board[r][c] == player; r, c = r-dr, c-dc {
+			cells = append(cells, [2]int{r, c})
+		}
+		return cells
+	}
+
+	dirs := [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+	for _, d := range dirs {
+		cells := collect(d[0], d[1])
+		if len(cells) >= connect4ToWin {
+			return true, cells
+		}
+	}
+
+	return false, nil
+}
+
+// connect4WouldWin checks if placing a piece in col would result in a win for player
+func connect4WouldWin(game *connect4Game, col int, player int) bool {
+	row := -1
+	for r := game.rows - 1; r >= 0; r-- {
+		if game.board[r][col] == 0 {
+			row = r
+			break
+		}
+	}
+	if row == -1 {
+		return false
+	}
+	game.board[row][col] = player
+	res, _ := connect4CheckWin(game, row, col)
+	game.board[row][col] = 0
+	return res
+}
+
+// connect4IsColumnFull checks if a column is full
+func connect4IsColumnFull(game *connect4Game, col int) bool {
+	return game.board[0][col] != 0
+}
+
+// connect4IsBoardFull checks if the entire board is full
+func connect4IsBoardFull(game *connect4Game) bool {
+	for col := 0; col < game.cols; col++ {
+		if !connect4IsColumnFull(game, col) {
+			return false
+		}
+	}
+	return true
+}
+
+// ===========================
+// AI Logic
+// ===========================
+
+// connect4MakeAIMove executes an AI move based on the configured difficulty
+func connect4MakeAIMove(client bot.Client, game *connect4Game, gameID string) {
+	activeConnect4GamesMu.Lock()
+	defer activeConnect4GamesMu.Unlock()
+
+	if game.gameOver || game.currentTurn != game.aiPlayerNum {
+		return
+	}
+
+	var col int
+	switch game.aiDifficulty {
+	case connect4Easy:
+		col = connect4AIRandomMove(game)
+	case connect4Normal:
+		col = connect4AINormalMove(game)
+	case connect4Hard:
+		col = connect4AIHardMove(game)
+	}
+
+	statusMsg := connect4MakeMove(game, col)
+	connect4Persist(game, gameID)
+
+	builder := connect4BuildMessage(game, gameID, statusMsg)
+	_, _ = gameEditBoard(client, game.channelID, game.messageID, builder)
+	gameMirror(client, game.spectate, builder)
+
+	if !game.gameOver && game.timerEnabled {
+		connect4StartTimer(client, game, gameID, game.moveCount)
+	}
+}
+
+// connect4AIRandomMove selects a random valid column (Easy difficulty)
+func connect4AIRandomMove(game *connect4Game) int {
+	var valid []int
+	for c := 0; c < game.cols; c++ {
+		if !connect4IsColumnFull(game, c) {
+			valid = append(valid, c)
+		}
+	}
+	if len(valid) == 0 {
+		return 0
+	}
+	return valid[rand.Intn(len(valid))]
+}
+
+// connect4AINormalMove tries to win, then block, then random (Normal difficulty)
+func connect4AINormalMove(game *connect4Game) int {
+	ai := game.aiPlayerNum
+	opponent := 3 - ai
+
+	for c := 0; c < game.cols; c++ {
+		if !connect4IsColumnFull(game, c) && connect4WouldWin(game, c, ai) {
+			return c
+		}
+	}
+	for c := 0; c < game.cols; c++ {
+		if !connect4IsColumnFull(game, c) && connect4WouldWin(game, c, opponent) {
+			return c
+		}
+	}
+	return connect4AIRandomMove(game)
+}
+
+func connect4AIHardMove(game *connect4Game) int {
+	ai := game.aiPlayerNum
+	opponent := 3 - ai
+
+	for c := 0; c < game.cols; c++ {
+		if !connect4IsColumnFull(game, c) && connect4WouldWin(game, c, ai) {
+			return c
+		}
+	}
+
+	for c := 0; c < game.cols; c++ {
+		if !connect4IsColumnFull(game, c) && connect4WouldWin(game, c, opponent) {
+			return c
+		}
+	}
+
+	bestScore := -1000000
+	bestCol := -1
+
+	cols := make([]int, game.cols)
+	for i := range cols {
+		cols[i] = i
+	}
+	rand.Shuffle(len(cols), func(i, j int) { cols[i], cols[j] = cols[j], cols[i] })
+
+	for _, c := range cols {
+		if connect4IsColumnFull(game, c) {
+			continue
+		}
+
+		row := -1
+		for r := game.rows - 1; r >= 0; r-- {
+			if game.board[r][c] == 0 {
+				row = r
+				break
+			}
+		}
+
+		if row > 0 {
+			game.board[row][c] = ai
+			if connect4WouldWin(game, c, opponent) {
+				game.board[row][c] = 0
+				continue
+			}
+			game.board[row][c] = 0
+		}
+		game.board[row][c] = ai
+		score := connect4ScorePosition(game, ai)
+		game.board[row][c] = 0
+
+		if score > bestScore {
+			bestScore = score
+			bestCol = c
+		}
+	}
+
+	if bestCol != -1 {
+		return bestCol
+	}
+
+	return connect4AIRandomMove(game)
+}
+
+func connect4ScorePosition(game *connect4Game, player int) int {
+	score := 0
+
+	centerCol := game.cols / 2
+	for r := 0; r < game.rows; r++ {
+		if game.board[r][centerCol] == player {
+			score += 3
+		}
+	}
+
+	for r := 0; r < game.rows; r++ {
+		for c := 0; c < game.cols-3; c++ {
+			window := []int{game.board[r][c], game.board[r][c+1], game.board[r][c+2], game.board[r][c+3]}
+			score += connect4EvaluateWindow(window, player)
+		}
+	}
+	for c := 0; c < game.cols; c++ {
+		for r := 0; r < game.rows-3; r++ {
+			window := []int{game.board[r][c], game.board[r+1][c], game.board[r+2][c], game.board[r+3][c]}
+			score += connect4EvaluateWindow(window, player)
+		}
+	}
+	for r := 0; r < game.rows-3; r++ {
+		for c := 0; c < game.cols-3; c++ {
+			window := []int{game.board[r][c], game.board[r+1][c+1], game.board[r+2][c+2], game.board[r+3][c+3]}
+			score += connect4EvaluateWindow(window, player)
+		}
+	}
+	for r := 0; r < game.rows-3; r++ {
+		for c := 0; c < game.cols-3; c++ {
+			window := []int{game.board[r+3][c], game.board[r+2][c+1], game.board[r+1][c+2], game.board[r][c+3]}
+			score += connect4EvaluateWindow(window, player)
+		}
+	}
+
+	return score
+}
+
+func connect4EvaluateWindow(window []int, player int) int {
+	score := 0
+	pieceCount := 0
+	emptyCount := 0
+	oppCount := 0
+	opponent := 3 - player
+
+	for _, cell := range window {
+		switch cell {
+		case player:
+			pieceCount++
+		case 0:
+			emptyCount++
+		case opponent:
+			oppCount++
+		}
+	}
+
+	if pieceCount == 4 {
+		score += 100
+	} else if pieceCount == 3 && emptyCount == 1 {
+		score += 5
+	} else if pieceCount == 2 && emptyCount == 2 {
+		score += 2
+	}
+
+	if oppCount == 3 && emptyCount == 1 {
+		score -= 4
+	}
+
+	return score
+}
+
+// ===========================
+// UI Utilities
+// ===========================
+
+func connect4GetHeader(cols int) string {
+	var sb strings.Builder
+	for i := range cols {
+		sb.WriteString(connect4ColumnEmojis[i])
+	}
+	return sb.String()
+}
+
+func connect4DisableInteractive(comp discord.LayoutComponent) discord.LayoutComponent {
+	switch c := comp.(type) {
+	case discord.ContainerComponent:
+		for i, sub := range c.Components {
+			c.Components[i] = connect4DisableSub(sub)
+		}
+		return c
+	case *discord.ContainerComponent:
+		for i, sub := range c.Components {
+			c.Components[i] = connect4DisableSub(sub)
+		}
+		return c
+	case discord.ActionRowComponent:
+		for i, inter := range c.Components {
+			c.Components[i] = connect4DisableInter(inter)
+		}
+		return c
+	case *discord.ActionRowComponent:
+		for i, inter := range c.Components {
+			c.Components[i] = connect4DisableInter(inter)
+		}
+		return c
+	}
+	return comp
+}
+
+func connect4DisableSub(sub discord.ContainerSubComponent) discord.ContainerSubComponent {
+	switch s := sub.(type) {
+	case discord.ActionRowComponent:
+		for i, inter := range s.Components {
+			s.Components[i] = connect4DisableInter(inter)
+		}
+		return s
+	case *discord.ActionRowComponent:
+		for i, inter := range s.Components {
+			s.Components[i] = connect4DisableInter(inter)
+		}
+		return s
+	}
+	return sub
+}
+
+func connect4DisableInter(inter discord.InteractiveComponent) discord.InteractiveComponent {
+	switch i := inter.(type) {
+	case discord.ButtonComponent:
+		i.Disabled = true
+		return i
+	case *discord.ButtonComponent:
+		i.Disabled = true
+		return i
+	}
+	return inter
+}
+
+// ===========================
+// Checkers Constants & Types
+// ===========================
+
+const (
+	checkersEmpty         = "⬛"
+	checkersWhiteTile     = "⬜"
+	checkersTarget        = "🔲"
+	checkersP1Piece       = "🔴"
+	checkersP2Piece       = "🔵"
+	checkersP1King        = "❤️"
+	checkersP2King        = "💙"
+	checkersP1Highlight   = "🟥"
+	checkersP2Highlight   = "🟦"
+	checkersStatusTurn    = MsgGameTurn
+	checkersStatusWin     = MsgGameWin
+	checkersStatusDraw    = MsgGameDraw
+	checkersStatusForfeit = MsgGameForfeitSuccess
+)
+
+type CheckersPieceType int
+
+const (
+	PieceNone CheckersPieceType = iota
+	PieceP1
+	PieceP2
+	PieceP1King
+	PieceP2King
+)
+
+// checkersRules describes one draughts variant
+type checkersRules struct {
+	name             string
+	size             int  // the board is size x size
+	rows             int  // rows of men each side starts with
+	flyingKings      bool // kings move and capture any distance along a diagonal
+	menCaptureBack   bool // men may capture backwards
+	maxCapture       bool // the capture taking the most pieces must be played
+	promoteInCapture bool // a man reaching the far row mid-capture carries on as a king
+}
+
+var checkersVariants = map[string]checkersRules{
+	ChoiceEnglish:       {name: "English", size: 8, rows: 3},
+	ChoiceInternational: {name: "International", size: 10, rows: 4, flyingKings: true, menCaptureBack: true, maxCapture: true},
+	ChoiceRussian:       {name: "Russian", size: 8, rows: 3, flyingKings: true, menCaptureBack: true, promoteInCapture: true},
+	ChoiceBrazilian:     {name: "Brazilian", size: 8, rows: 3, flyingKings: true, menCaptureBack: true, maxCapture: true},
+}
+
+// checkersDirections are the four diagonals a piece can travel along
+var checkersDirections = [4][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+
+// checkersMove is one complete turn: the squares the piece visits and the pieces it captures on the way
+type checkersMove struct {
+	path     [][2]int
+	captured [][2]int
+}
+
+func (m checkersMove) from() [2]int { return m.path[0] }
+func (m checkersMove) to() [2]int   { return m.path[len(m.path)-1] }
+
+type CheckersGame struct {
+	board         [][]CheckersPieceType
+	variant       string
+	forcedCapture bool
+	player1ID     snowflake.ID
+	player2ID     snowflake.ID
+	isAI          bool
+	aiDifficulty  string
+	aiPlayerNum   int
+	colorVariant  GameColorVariant
+	currentTurn   int
+	gameOver      bool
+	winner        int
+	moveCount     int
+	lastMoveTime  time.Time
+	messageID     snowflake.ID
+	channelID     snowflake.ID
+	selectedPiece *[2]int
+	lastMoveDest  *[2]int
+	clock         *gameClock
+	drawOffer     int
+	originalP1ID  snowflake.ID
+	originalP2ID  snowflake.ID
+	spectate      *gameSpectate
+	lastMoveFrom  *[2]int
+	emojiBoard    bool
+}
+
+// checkersSnapshot is the serialized form of a CheckersGame stored in the database
+type checkersSnapshot struct {
+	Board         [][]CheckersPieceType `json:"board"`
+	Variant       string                `json:"variant,omitempty"`
+	ForcedCapture bool                  `json:"forced_capture,omitempty"`
+	Player1ID     snowflake.ID          `json:"player1_id"`
+	Player2ID     snowflake.ID          `json:"player2_id"`
+	IsAI          bool                  `json:"is_ai"`
+	AIDifficulty  string                `json:"ai_difficulty"`
+	AIPlayerNum   int                   `json:"ai_player_num"`
+	ColorVariant  GameColorVariant      `json:"color_variant"`
+	CurrentTurn   int                   `json:"current_turn"`
+	MoveCount     int                   `json:"move_count"`
+	LastMoveTime  time.Time             `json:"last_move_time"`
+	MessageID     snowflake.ID          `json:"message_id"`
+	ChannelID     snowflake.ID          `json:"channel_id"`
+	SelectedPiece *[2]int               `json:"selected_piece,omitempty"`
+	LastMoveDest  *[2]int               `json:"last_move_dest,omitempty"`
+	Clock         *gameClock            `json:"clock,omitempty"`
+	DrawOffer     int                   `json:"draw_offer,omitempty"`
+	OriginalP1ID  snowflake.ID          `json:"original_p1_id,omitempty"`
+	OriginalP2ID  snowflake.ID          `json:"original_p2_id,omitempty"`
+	Spectate      *gameSpectate         `json:"spectate,omitempty"`
+	LastMoveFrom  *[2]int               `json:"last_move_from,omitempty"`
+	EmojiBoard    bool                  `json:"emoji_board,omitempty"`
+}
+
+// ===========================
+// Checkers Logic - Core
+// ===========================
+
+func NewCheckersGame(p1, p2 snowflake.ID, isAI bool, aiPlayerNum int, difficulty, variant string, forcedCapture bool) *CheckersGame {
+	colorVariant := VariantStandard
+	if rand.Intn(2) == 1 {
+		colorVariant = VariantInverted
+	}
+	game := &CheckersGame{
+		player1ID:     p1,
+		player2ID:     p2,
+		isAI:          isAI,
+		aiPlayerNum:   aiPlayerNum,
+		aiDifficulty:  difficulty,
+		variant:       variant,
+		forcedCapture: forcedCapture,
+		colorVariant:  colorVariant,
+		currentTurn:   1,
+		lastMoveTime:  time.Now(),
+	}
+	game.InitBoard()
+	return game
+}
+
+func (g *CheckersGame) rules() checkersRules {
+	return checkersRulesFor(g.variant)
+}
+
+// checkersRulesFor looks up a variant, treating games saved before variants existed as English
+func checkersRulesFor(variant string) checkersRules {
+	if rules, ok := checkersVariants[variant]; ok {
+		return rules
+	}
+	return checkersVariants[ChoiceEnglish]
+}
+
+func (g *CheckersGame) InitBoard() {
+	rules := g.rules()
+	g.board = make([][]CheckersPieceType, rules.size)
+	for r := range rules.size {
+		g.board[r] = make([]CheckersPieceType, rules.size)
+		for c := range rules.size {
+			if (r+c)%2 == 1 {
+				if r < rules.rows {
+					g.board[r][c] = PieceP2
+				} else if r >= rules.size-rules.rows {
+					g.board[r][c] = PieceP1
+				}
+			}
+		}
+	}
+}
+
+// checkersRecordResult feeds a finished game into the rating system
+func checkersRecordResult(game *CheckersGame) {
+	aiPlayer := 0
+	if game.isAI {
+		aiPlayer = game.aiPlayerNum
+	}
+	gameRecordResult(CmdCheckers, game.player1ID, game.player2ID, game.winner, aiPlayer, game.aiDifficulty)
+}
+
+// checkersPersist saves the game state, or forgets it once the game is over (caller must hold activeCheckersGamesMu)
+func checkersPersist(game *CheckersGame, gameID string) {
+	if game.gameOver {
+		gameForget(gameID)
+		return
+	}
+	gameSaveState(gameID, CmdCheckers, checkersSnapshot{
+		Board:         game.board,
+		Variant:       game.variant,
+		ForcedCapture: game.forcedCapture,
+		Player1ID:     game.player1ID,
+		Player2ID:     game.player2ID,
+		IsAI:          game.isAI,
+		AIDifficulty:  game.aiDifficulty,
+		AIPlayerNum:   game.aiPlayerNum,
+		ColorVariant:  game.colorVariant,
+		CurrentTurn:   game.currentTurn,
+		MoveCount:     game.moveCount,
+		LastMoveTime:  game.lastMoveTime,
+		MessageID:     game.messageID,
+		ChannelID:     game.channelID,
+		SelectedPiece: game.selectedPiece,
+		LastMoveDest:  game.lastMoveDest,
+		Clock:         game.clock,
+		DrawOffer:     game.drawOffer,
+		OriginalP1ID:  game.originalP1ID,
+		OriginalP2ID:  game.originalP2ID,
+		Spectate:      game.spectate,
+		LastMoveFrom:  game.lastMoveFrom,
+		EmojiBoard:    game.emojiBoard,
+	})
+}
+
+// checkersStartClock arms the flag-fall timer for the player to move (caller must hold activeCheckersGamesMu)
+func checkersStartClock(client bot.Client, game *CheckersGame, gameID string) {
+	if game.clock == nil || game.gameOver {
+		return
+	}
+
+	player, moveCount := game.currentTurn, game.moveCount
+	game.clock.arm(player, func() {
+		activeCheckersGamesMu.Lock()
+		defer activeCheckersGamesMu.Unlock()
+
+		if game.gameOver || game.currentTurn != player || game.moveCount != moveCount {
+			return
+		}
+
+		msg := checkersFlagFall(game, gameID)
+		_, _ = gameEditBoard(client, game.channelID, game.messageID, msg)
+		gameMirror(client, game.spectate, msg)
+	})
+}
+
+// checkersFlagFall ends the game on time against the player to move and returns the final board (caller must hold
+// activeCheckersGamesMu)
+func checkersFlagFall(game *CheckersGame, gameID string) Container {
+	player := game.currentTurn
+	game.gameOver = true
+	game.winner = 3 - player
+	game.selectedPiece = nil
+	game.clock.stop()
+	checkersRecordResult(game)
+	checkersPersist(game, gameID)
+
+	loserID, winnerID := game.player1ID, game.player2ID
+	if player == 2 {
+		loserID, winnerID = game.player2ID, game.player1ID
+	}
+
+	userActiveGameMu.Lock()
+	delete(userActiveGame, game.player1ID)
+	delete(userActiveGame, game.player2ID)
+	userActiveGameMu.Unlock()
+
+	return CheckersBuildMessage(game, gameID, fmt.Sprintf(MsgGameFlagFall, loserID, winnerID))
+}
+
+// checkersRestore rebuilds a saved game and resumes a pending AI turn
+func checkersRestore(client bot.Client, gameID string, state string) error {
+	var snap checkersSnapshot
+	if err := json.Unmarshal([]byte(state), &snap); err != nil {
+		return err
+	}
+	if size := len(snap.Board); size != checkersRulesFor(snap.Variant).size {
+		return fmt.Errorf("invalid board size %d", size)
+	}
+
+	game := &CheckersGame{
+		board:         snap.Board,
+		variant:       snap.Variant,
+		forcedCapture: snap.ForcedCapture,
+		player1ID:     snap.Player1ID,
+		player2ID:     snap.Player2ID,
+		isAI:          snap.IsAI,
+		aiDifficulty:  snap.AIDifficulty,
+		aiPlayerNum:   snap.AIPlayerNum,
+		colorVariant:  snap.ColorVariant,
+		currentTurn:   snap.CurrentTurn,
+		moveCount:     snap.MoveCount,
+		lastMoveTime:  snap.LastMoveTime,
+		messageID:     snap.MessageID,
+		channelID:     snap.ChannelID,
+		selectedPiece: snap.SelectedPiece,
+		lastMoveDest:  snap.LastMoveDest,
+		clock:         snap.Clock,
+		drawOffer:     snap.DrawOffer,
+		originalP1ID:  snap.OriginalP1ID,
+		originalP2ID:  snap.OriginalP2ID,
+		spectate:      snap.Spectate,
+		lastMoveFrom:  snap.LastMoveFrom,
+		emojiBoard:    snap.EmojiBoard,
+	}
+
+	activeCheckersGamesMu.Lock()
+	activeCheckersGames[gameID] = game
+	checkersStartClock(client, game, gameID)
+	activeCheckersGamesMu.Unlock()
+
+	gameLockPlayers(client, gameID, game.player1ID, game.player2ID)
+
+	if game.isAI && game.currentTurn == game.aiPlayerNum {
+		time.AfterFunc(1*time.Second, func() {
+			CheckersMakeAIMove(client, game, gameID)
+		})
+	}
+	return nil
+}
+
+// ===========================
+// Checkers Interaction Handlers
+// ===========================
+
+func HandlePlayCheckers(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in HandlePlayCheckers: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
+
+	var opponentID *snowflake.ID
+	isAI := true
+	var p2 snowflake.ID
+
+	if opponent, ok := data.OptUser("opponent"); ok {
+		opponentID = &opponent.ID
+		isAI = opponent.Bot
+	}
+
+	difficulty := "normal"
+	if diff, ok := data.OptString("difficulty"); ok {
+		difficulty = diff
+	}
+
+	variant := ChoiceEnglish
+	if v, ok := data.OptString(OptVariant); ok {
+		variant = v
+	}
+	forcedCapture := true
+	if forced, ok := data.OptBool(OptForcedCapture); ok {
+		forcedCapture = forced
+	}
+
+	appID := event.ApplicationID()
+	p1 := event.User().ID
+
+	if opponentID != nil && *opponentID == p1 {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameChallengeSelf).WithEphemeral(true))
+		return
+	}
+
+	userActiveGameMu.Lock()
+	if gid, ok := userActiveGame[p1]; ok {
+		userActiveGameMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameAlreadyActive, gid)).WithEphemeral(true))
+		return
+	}
+	if opponentID != nil && *opponentID != appID {
+		if gid, ok := userActiveGame[*opponentID]; ok {
+			userActiveGameMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameOpponentActive, *opponentID, gid)).WithEphemeral(true))
+			return
+		}
+	}
+
+	cid := event.Channel().ID()
+	gameID := fmt.Sprintf(CIDCheckersGameID, cid, time.Now().UnixNano())
+
+	userActiveGame[p1] = gameID
+	if opponentID != nil && *opponentID != appID {
+		userActiveGame[*opponentID] = gameID
+	}
+	userActiveGameMu.Unlock()
+
+	if opponentID != nil {
+		p2 = *opponentID
+		if p2 == appID {
+			isAI = true
+		} else {
+			isAI = false
+		}
+	} else {
+		p2 = appID
+		isAI = true
+	}
+
+	var player1, player2 snowflake.ID
+	if rand.Intn(2) == 0 {
+		player1 = p1
+		player2 = p2
+	} else {
+		player1 = p2
+		player2 = p1
+	}
+
+	aiPlayerNum := 0
+	if isAI {
+		if player1 == appID || (opponentID != nil && player1 == *opponentID && isAI) {
+			aiPlayerNum = 1
+		} else {
+			aiPlayerNum = 2
+		}
+	}
+
+	game := NewCheckersGame(player1, player2, isAI, aiPlayerNum, difficulty, variant, forcedCapture)
+	game.clock = gameParseClock(data)
+	game.originalP1ID = p1
+	game.originalP2ID = p2
+	game.emojiBoard = gameParseEmojiBoard(data)
+
+	checkersOpen(*event.Client(), event, game, gameID)
+}
+
+// checkersOpen posts a new game: PvP games wait for the opponent to accept, AI games start immediately
+func checkersOpen(client bot.Client, interaction discord.Interaction, game *CheckersGame, gameID string) {
+	if !game.isAI {
+		gameIssueChallenge(client, interaction, gameID, &gameChallenge{
+			gameType:     CmdCheckers,
+			challengerID: game.originalP1ID,
+			opponentID:   game.originalP2ID,
+			launch: func(client bot.Client, channelID, messageID snowflake.ID) Container {
+				return checkersLaunch(client, game, gameID, channelID, messageID)
+			},
+		})
+		return
+	}
+
+	activeCheckersGamesMu.Lock()
+	activeCheckersGames[gameID] = game
+	activeCheckersGamesMu.Unlock()
+
+	msg := CheckersBuildMessage(game, gameID, "")
+	if err := gameRespondBoard(client, interaction, msg); err != nil {
+		LogError("Failed to send checkers message: %v", err)
+		activeCheckersGamesMu.Lock()
+		delete(activeCheckersGames, gameID)
+		activeCheckersGamesMu.Unlock()
+
+		gameReleasePlayers(game.player1ID, game.player2ID)
+		return
+	}
+
+	var messageID snowflake.ID
+	resp, err := client.Rest.GetInteractionResponse(interaction.ApplicationID(), interaction.Token())
+	if err == nil && resp != nil {
+		messageID = resp.ID
+	} else {
+		LogError("Failed to fetch interaction response: %v", err)
+	}
+	checkersLaunch(client, game, gameID, interaction.Channel().ID(), messageID)
+}
+
+// checkersLaunch attaches a game to its message, starts its clock and AI, and returns the opening board
+func checkersLaunch(client bot.Client, game *CheckersGame, gameID string, channelID, messageID snowflake.ID) Container {
+	activeCheckersGamesMu.Lock()
+	activeCheckersGames[gameID] = game
+	game.channelID = channelID
+	game.messageID = messageID
+	game.lastMoveTime = time.Now()
+	checkersStartClock(client, game, gameID)
+	checkersPersist(game, gameID)
+	msg := CheckersBuildMessage(game, gameID, "")
+	activeCheckersGamesMu.Unlock()
+
+	if game.isAI && game.aiPlayerNum == 1 {
+		time.AfterFunc(1*time.Second, func() {
+			CheckersMakeAIMove(client, game, gameID)
+		})
+	}
+	return msg
+}
+
+// checkersRematch sets up a fresh game between the same players with colours swapped, requested by userID
+func checkersRematch(game *CheckersGame, userID snowflake.ID) *CheckersGame {
+	aiPlayerNum := 0
+	if game.isAI {
+		aiPlayerNum = 3 - game.aiPlayerNum
+	}
+	next := NewCheckersGame(game.player2ID, game.player1ID, game.isAI, aiPlayerNum, game.aiDifficulty, game.variant, game.forcedCapture)
+	next.clock = game.clock.fresh()
+	next.emojiBoard = game.emojiBoard
+	next.originalP1ID = userID
+	next.originalP2ID = game.player1ID
+	if userID == game.player1ID {
+		next.originalP2ID = game.player2ID
+	}
+	return next
+}
+
+func HandleCheckersInteraction(event *events.ComponentInteractionCreate) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in HandleCheckersInteraction: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
+
+	parts := strings.Split(event.Data.CustomID(), ":")
+	if len(parts) < 3 {
+		return
+	}
+
+	gameID := parts[1]
+	action := parts[2]
+
+	activeCheckersGamesMu.Lock()
+	game, exists := activeCheckersGames[gameID]
+	if !exists {
+		activeCheckersGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotFound).WithEphemeral(true))
+		return
+	}
+
+	userID := event.User().ID
+	switch action {
+	case "spectate":
+		gameHandleSpectate(event, &activeCheckersGamesMu, gameID, CmdCheckers, game.spectate, CheckersBuildMessage(game, gameID, ""), func(s *gameSpectate) *gameSpectate {
+			if game.spectate == nil {
+				game.spectate = s
+				checkersPersist(game, gameID)
+			}
+			return game.spectate
+		})
+		return
+	case "rematch":
+		if msg := gameRematchRefusal(userID, game.gameOver, game.originalP1ID, game.originalP2ID, game.player1ID, game.player2ID); msg != "" {
+			activeCheckersGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(msg).WithEphemeral(true))
+			return
+		}
+		next := checkersRematch(game, userID)
+		activeCheckersGamesMu.Unlock()
+
+		nextID := fmt.Sprintf(CIDCheckersGameID, event.Channel().ID(), time.Now().UnixNano())
+		if errMsg := gameReservePlayers(*event.Client(), nextID, next.originalP1ID, next.originalP2ID); errMsg != "" {
+			event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+			return
+		}
+		checkersOpen(*event.Client(), event, next, nextID)
+		return
+	}
+
+	if userID != game.player1ID && userID != game.player2ID {
+		activeCheckersGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent("You are not part of this game.").WithEphemeral(true))
+		return
+	}
+
+	// The flag can fall moments before the timer gets the lock; nothing played after it counts
+	if game.clock != nil && !game.gameOver && game.clock.left(game.currentTurn, game.currentTurn) <= 0 {
+		msg := checkersFlagFall(game, gameID)
+		activeCheckersGamesMu.Unlock()
+		_ = gameUpdateBoard(*event.Client(), event, msg)
+		gameMirror(*event.Client(), game.spectate, msg)
+		return
+	}
+
+	isP1 := userID == game.player1ID
+	player := 2
+	if isP1 {
+		player = 1
+	}
+	if game.currentTurn != player && !gameOutOfTurnActions[action] {
+		activeCheckersGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotTurn).WithEphemeral(true))
+		return
+	}
+
+	statusMsg := ""
+	switch action {
+	case "select_piece":
+		values := event.StringSelectMenuInteractionData().Values
+		if len(values) > 0 {
+			coords := strings.Split(values[0], ",")
+			r, _ := strconv.Atoi(coords[0])
+			c, _ := strconv.Atoi(coords[1])
+			game.selectedPiece = &[2]int{r, c}
+		}
+	case "move_to":
+		values := event.StringSelectMenuInteractionData().Values
+		if len(values) > 0 {
+			coords := strings.Split(values[0], ",")
+			r, _ := strconv.Atoi(coords[0])
+			c, _ := strconv.Atoi(coords[1])
+			if game.selectedPiece != nil && CheckersMakeMove(game, game.selectedPiece[0], game.selectedPiece[1], r, c) {
+				game.selectedPiece = nil
+				if game.clock != nil {
+					game.clock.press(player)
+				}
+				// Moving declines the opponent's pending draw offer
+				if game.drawOffer != player {
+					game.drawOffer = 0
+				}
+				game.currentTurn = 3 - game.currentTurn
+				if CheckersCheckWin(game) {
+					game.gameOver = true
+					checkersRecordResult(game)
+					userActiveGameMu.Lock()
+					delete(userActiveGame, game.player1ID)
+					delete(userActiveGame, game.player2ID)
+					userActiveGameMu.Unlock()
+				}
+			}
+		}
+	case "cancel_select":
+		game.selectedPiece = nil
+	case "offer_draw", "accept_draw":
+		switch {
+		case game.drawOffer == player:
+			activeCheckersGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameDrawOwnOffer).WithEphemeral(true))
+			return
+		case game.drawOffer == 0 && action == "accept_draw":
+			activeCheckersGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameDrawNoOffer).WithEphemeral(true))
+			return
+		case game.drawOffer == 0 && game.isAI && !checkersAIAcceptsDraw(game):
+			statusMsg = MsgGameDrawDeclined
+		case game.drawOffer == 0 && !game.isAI:
+			game.drawOffer = player
+		default:
+			game.gameOver = true
+			game.winner = 0
+			game.drawOffer = 0
+			game.selectedPiece = nil
+			game.clock.stop()
+			checkersRecordResult(game)
+
+			userActiveGameMu.Lock()
+			delete(userActiveGame, game.player1ID)
+			delete(userActiveGame, game.player2ID)
+			userActiveGameMu.Unlock()
+		}
+	case "forfeit":
+		game.gameOver = true
+		game.clock.stop()
+		if isP1 {
+			game.winner = 2
+		} else {
+			game.winner = 1
+		}
+		if userID == game.player1ID {
+			statusMsg = fmt.Sprintf(checkersStatusForfeit, game.player1ID, game.player2ID)
+		} else {
+			statusMsg = fmt.Sprintf(checkersStatusForfeit, game.player2ID, game.player1ID)
+		}
+		checkersRecordResult(game)
+
+		msg := CheckersBuildMessage(game, gameID, statusMsg)
+		checkersPersist(game, gameID)
+		activeCheckersGamesMu.Unlock()
+
+		_ = gameUpdateBoard(*event.Client(), event, msg)
+		gameMirror(*event.Client(), game.spectate, msg)
+
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
+		return
+
+	case "claim_win":
+		if !CheckersIsHopeless(game) {
+			activeCheckersGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameHopelessFail).WithEphemeral(true))
+			return
+		}
+
+		game.gameOver = true
+		game.clock.stop()
+		game.winner = 1
+		if userID == game.player2ID {
+			game.winner = 2
+		}
+
+		checkersRecordResult(game)
+
+		statusMsg = fmt.Sprintf(MsgGameClaimWinSuccess, userID)
+		msg := CheckersBuildMessage(game, gameID, statusMsg)
+		checkersPersist(game, gameID)
+		activeCheckersGamesMu.Unlock()
+
+		_ = gameUpdateBoard(*event.Client(), event, msg)
+		gameMirror(*event.Client(), game.spectate, msg)
+
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
+		return
+
+	}
+
+	msg := CheckersBuildMessage(game, gameID, statusMsg)
+	checkersStartClock(*event.Client(), game, gameID)
+	checkersPersist(game, gameID)
+	activeCheckersGamesMu.Unlock()
+
+	_ = gameUpdateBoard(*event.Client(), event, msg)
+	gameMirror(*event.Client(), game.spectate, msg)
+
+	if !game.gameOver && game.isAI && game.currentTurn == game.aiPlayerNum {
+		time.AfterFunc(1*time.Second, func() {
+			CheckersMakeAIMove(*event.Client(), game, gameID)
+		})
+	}
+}
+
+// ===========================
+// Checkers Logic - Mechanics
+// ===========================
+
+// CheckersMakeMove plays the mover's legal move taking r1,c1 to r2,c2, preferring the longest capture; it reports whether one existed
+func CheckersMakeMove(g *CheckersGame, r1, c1, r2, c2 int) bool {
+	var best *checkersMove
+	for _, m := range checkersLegalMoves(g, g.currentTurn) {
+		if m.from() == [2]int{r1, c1} && m.to() == [2]int{r2, c2} && (best == nil || len(m.captured) > len(best.captured)) {
+			best = &m
+		}
+	}
+	if best == nil {
+		return false
+	}
+	checkersApplyMove(g, *best)
+	return true
+}
+
+// checkersApplyMove moves the piece along its path, lifts the captured pieces and crowns a man ending on the far row
+func checkersApplyMove(g *CheckersGame, m checkersMove) {
+	from, to := m.from(), m.to()
+	piece := g.board[from[0]][from[1]]
+	g.board[from[0]][from[1]] = PieceNone
+	for _, sq := range m.captured {
+		g.board[sq[0]][sq[1]] = PieceNone
+	}
+	// A man is crowned on the far row; where it's crowned mid-capture it stays a king even if it jumps back out
+	promotionRow := checkersPromotionRow(g, checkersOwner(piece))
+	crowned := to[0] == promotionRow
+	if g.rules().promoteInCapture {
+		crowned = crowned || slices.ContainsFunc(m.path[1:], func(sq [2]int) bool { return sq[0] == promotionRow })
+	}
+	if crowned {
+		piece = checkersCrown(piece)
+	}
+	g.board[to[0]][to[1]] = piece
+
+	g.lastMoveFrom = &from
+	g.lastMoveDest = &to
+	g.moveCount++
+}
+
+func CheckersCheckWin(g *CheckersGame) bool {
+	p1Count, p2Count := 0, 0
+	for r := range g.board {
+		for c := range g.board[r] {
+			switch checkersOwner(g.board[r][c]) {
+			case 1:
+				p1Count++
+			case 2:
+				p2Count++
+			}
+		}
+	}
+
+	if p1Count == 0 {
+		g.winner = 2
+		return true
+	}
+	if p2Count == 0 {
+		g.winner = 1
+		return true
+	}
+
+	validMoves1 := CheckersGetValidMoves(g, 1)
+	if len(validMoves1) == 0 {
+		g.winner = 2
+		return true
+	}
+	validMoves2 := CheckersGetValidMoves(g, 2)
+	if len(validMoves2) == 0 {
+		g.winner = 1
+		return true
+	}
+
+	return false
+}
+
+// GetValidMoves returns a map of "r,c" -> list of valid target "r,c"
+func CheckersGetValidMoves(g *CheckersGame, player int) map[string][][2]int {
+	moves := make(map[string][][2]int)
+	for _, m := range checkersLegalMoves(g, player) {
+		from, to := m.from(), m.to()
+		k := fmt.Sprintf("%d,%d", from[0], from[1])
+		if !slices.Contains(moves[k], to) {
+			moves[k] = append(moves[k], to)
+		}
+	}
+	return moves
+}
+
+// checkersLegalMoves lists every complete move for player under the game's variant and capture rules
+func checkersLegalMoves(g *CheckersGame, player int) []checkersMove {
+	rules := g.rules()
+	var captures, quiet []checkersMove
+	for r := range g.board {
+		for c := range g.board[r] {
+			piece := g.board[r][c]
+			if checkersOwner(piece) != player {
+				continue
+			}
+
+			// Lift the piece so a flying king can pass back over its starting square
+			g.board[r][c] = PieceNone
+			checkersCaptures(g, rules, piece, checkersMove{path: [][2]int{{r, c}}}, &captures)
+			g.board[r][c] = piece
+
+			quiet = append(quiet, checkersSlides(g, rules, piece, r, c)...)
+		}
+	}
+
+	if len(captures) > 0 && rules.maxCapture {
+		most := 0
+		for _, m := range captures {
+			most = max(most, len(m.captured))
+		}
+		captures = slices.DeleteFunc(captures, func(m checkersMove) bool { return len(m.captured) < most })
+	}
+	if len(captures) > 0 && g.forcedCapture {
+		return captures
+	}
+	return append(captures, quiet...)
+}
+
+// checkersSlides lists the non-capturing moves of the piece at r,c
+func checkersSlides(g *CheckersGame, rules checkersRules, piece CheckersPieceType, r, c int) []checkersMove {
+	var moves []checkersMove
+	king := checkersIsKing(piece)
+	for _, d := range checkersDirections {
+		if !king && d[0] != checkersForward(checkersOwner(piece)) {
+			continue
+		}
+		for tr, tc := r+d[0], c+d[1]; CheckersIsValidPos(g, tr, tc) && g.board[tr][tc] == PieceNone; tr, tc = tr+d[0], tc+d[1] {
+			moves = append(moves, checkersMove{path: [][2]int{{r, c}, {tr, tc}}})
+			if !king || !rules.flyingKings {
+				break
+			}
+		}
+	}
+	return moves
+}
+
+// checkersCaptures extends a capture sequence as far as it will go, adding each finished sequence to out.
+// Captured pieces stay on the board until the move ends, so they block the path and can't be taken twice.
+// A flying king must land where it can keep capturing if any square past the victim allows it.
+func checkersCaptures(g *CheckersGame, rules checkersRules, piece CheckersPieceType, seq checkersMove, out *[]checkersMove) {
+	player := checkersOwner(piece)
+	king := checkersIsKing(piece)
+	flying := king && rules.flyingKings
+	pos := seq.to()
+	extended := false
+
+	for _, d := range checkersDirections {
+		if !king && !rules.menCaptureBack && d[0] != checkersForward(player) {
+			continue
+		}
+
+		vr, vc := pos[0]+d[0], pos[1]+d[1]
+		for flying && CheckersIsValidPos(g, vr, vc) && g.board[vr][vc] == PieceNone {
+			vr, vc = vr+d[0], vc+d[1]
+		}
+		victim := [2]int{vr, vc}
+		if !CheckersIsValidPos(g, vr, vc) || checkersOwner(g.board[vr][vc]) != 3-player || slices.Contains(seq.captured, victim) {
+			continue
+		}
+
+		var continuing, stopping []checkersMove
+		for lr, lc := vr+d[0], vc+d[1]; CheckersIsValidPos(g, lr, lc) && g.board[lr][lc] == PieceNone; lr, lc = lr+d[0], lc+d[1] {
+			next := checkersMove{
+				path:     append(slices.Clone(seq.path), [2]int{lr, lc}),
+				captured: append(slices.Clone(seq.captured), victim),
+			}
+			nextPiece := piece
+			if !king && rules.promoteInCapture && lr == checkersPromotionRow(g, player) {
+				nextPiece = checkersCrown(piece)
+			}
+			var found []checkersMove
+			checkersCaptures(g, rules, nextPiece, next, &found)
+			// A landing either ends the sequence there or only yields longer ones
+			if len(found[0].captured) > len(next.captured) {
+				continuing = append(continuing, found...)
+			} else {
+				stopping = append(stopping, found...)
+			}
+			extended = true
+			if !flying {
+				break
+			}
+		}
+		if len(continuing) > 0 {
+			*out = append(*out, continuing...)
+		} else {
+			*out = append(*out, stopping...)
+		}
+	}
+
+	if !extended && len(seq.captured) > 0 {
+		*out = append(*out, seq)
+	}
+}
+
+func CheckersIsValidPos(g *CheckersGame, r, c int) bool {
+	return r >= 0 && r < len(g.board) && c >= 0 && c < len(g.board)
+}
+
+// checkersOwner returns which player a piece belongs to, or 0 for an empty square
+func checkersOwner(p CheckersPieceType) int {
+	switch p {
+	case PieceP1, PieceP1King:
+		return 1
+	case PieceP2, PieceP2King:
+		return 2
+	}
+	return 0
+}
+
+func checkersIsKing(p CheckersPieceType) bool {
+	return p == PieceP1King || p == PieceP2King
+}
+
+func checkersCrown(p CheckersPieceType) CheckersPieceType {
+	switch p {
+	case PieceP1:
+		return PieceP1King
+	case PieceP2:
+		return PieceP2King
+	}
+	return p
+}
+
+// checkersForward is the row direction a player's men move in
+func checkersForward(player int) int {
+	if player == 1 {
+		return -1
+	}
+	return 1
+}
+
+func checkersPromotionRow(g *CheckersGame, player int) int {
+	if player == 1 {
+		return 0
+	}
+	return len(g.board) - 1
+}
+
+// CheckersIsHopeless checks if the AI is in a hopeless position
+func CheckersIsHopeless(game *CheckersGame) bool {
+	if !game.isAI {
+		return false
+	}
+
+	aiPiece := PieceP2
+	aiKingPiece := PieceP2King
+	playerPiece := PieceP1
+	playerKingPiece := PieceP1King
+
+	if game.aiPlayerNum == 1 {
+		aiPiece = PieceP1
+		aiKingPiece = PieceP1King
+		playerPiece = PieceP2
+		playerKingPiece = PieceP2King
+	}
+
+	aiCount := 0
+	aiKingCount := 0
+	playerCount := 0
+	playerKingCount := 0
+
+	for r := range game.board {
+		for c := range game.board[r] {
+			p := game.board[r][c]
+			switch p {
+			case aiPiece:
+				aiCount++
+			case aiKingPiece:
+				aiKingCount++
+			case playerPiece:
+				playerCount++
+			case playerKingPiece:
+				playerKingCount++
+			}
+		}
+	}
+
+	totalAI := aiCount + aiKingCount
+	totalPlayer := playerCount + playerKingCount
+
+	if aiKingCount == 0 && playerKingCount > 0 && totalAI < totalPlayer-1 {
+		return true
+	}
+	if totalAI <= 2 && totalPlayer >= 3 {
+		return true
+	}
+
+	return false
+}
+
+// ===========================
+// Checkers Rendering & Helpers
+// ===========================
+
+func CheckersBuildMessage(game *CheckersGame, gameID string, statusMsg string) Container {
+	var sb strings.Builder
+	n := len(game.board)
+
+	// Determine visual mapping based on randomization
+	p1Piece, p2Piece := checkersP1Piece, checkersP2Piece
+	p1King, p2King := checkersP1King, checkersP2King
+	p1Highlight, p2Highlight := checkersP1Highlight, checkersP2Highlight
+
+	if game.colorVariant == VariantInverted {
+		p1Piece, p2Piece = checkersP2Piece, checkersP1Piece
+		p1King, p2King = checkersP2King, checkersP1King
+		p1Highlight, p2Highlight = checkersP2Highlight, checkersP1Highlight
+	}
+
+	reverse := false
+	if game.currentTurn == 2 {
+		reverse = true
+	}
+
+	getHeader := func(rev bool) string {
+		var hsb strings.Builder
+		if !rev {
+			for i := 0; i < n; i++ {
+				hsb.WriteString(BoardColumnEmojis[i])
+			}
+		} else {
+			for i := n - 1; i >= 0; i-- {
+				hsb.WriteString(BoardColumnEmojis[i])
+			}
+		}
+		return hsb.String()
+	}
+
+	headerStr := BoardCorner + getHeader(reverse) + BoardCorner + "\n"
+	sb.WriteString(headerStr)
+
+	rStart, rEnd, rStep := 0, n, 1
+	cStart, cEnd, cStep := 0, n, 1
+
+	if reverse {
+		rStart, rEnd, rStep = n-1, -1, -1
+		cStart, cEnd, cStep = n-1, -1, -1
+	}
+
+	for r := rStart; r != rEnd; r += rStep {
+		sb.WriteString(BoardRowEmojis[r])
+		for c := cStart; c != cEnd; c += cStep {
+			p := game.board[r][c]
+
+			isTarget := false
+			isSelected := (game.selectedPiece != nil && game.selectedPiece[0] == r && game.selectedPiece[1] == c)
+			isLastMove := (game.lastMoveDest != nil && game.lastMoveDest[0] == r && game.lastMoveDest[1] == c)
+
+			if game.selectedPiece != nil {
+				k := fmt.Sprintf("%d,%d", game.selectedPiece[0], game.selectedPiece[1])
+				validMoves := CheckersGetValidMoves(game, game.currentTurn)
+				if targets, ok := validMoves[k]; ok {
+					for _, t := range targets {
+						if t[0] == r && t[1] == c {
+							isTarget = true
+							break
+						}
+					}
+				}
+			}
+
+			if isSelected || isLastMove {
+				switch p {
+				case PieceP1, PieceP1King:
+					sb.WriteString(p1Highlight)
+				case PieceP2, PieceP2King:
+					sb.WriteString(p2Highlight)
+				default:
+					if (r+c)%2 == 1 {
+						sb.WriteString(checkersEmpty)
+					} else {
+						sb.WriteString(checkersWhiteTile)
+					}
+				}
+			} else if isTarget {
+				sb.WriteString(checkersTarget)
+			} else {
+				switch p {
+				case PieceNone:
+					if (r+c)%2 == 1 {
+						sb.WriteString(checkersEmpty)
+					} else {
+						sb.WriteString(checkersWhiteTile)
+					}
+				case PieceP1:
+					sb.WriteString(p1Piece)
+				case PieceP2:
+					sb.WriteString(p2Piece)
+				case PieceP1King:
+					sb.WriteString(p1King)
+				case PieceP2King:
+					sb.WriteString(p2King)
+				}
+			}
+		}
+		sb.WriteString(BoardRowEmojis[r] + "\n")
+	}
+	sb.WriteString(headerStr)
+
+	p1Count, p2Count := 0, 0
+	for r := range n {
+		for c := range n {
+			p := game.board[r][c]
+			if p == PieceP1 || p == PieceP1King {
+				p1Count++
+			}
+			if p == PieceP2 || p == PieceP2King {
+				p2Count++
+			}
+		}
+	}
+	scoreStr := fmt.Sprintf("-# %s <@%d>: **%d** | %s <@%d>: **%d** · %s",
+		p1Piece, game.player1ID, p1Count,
+		p2Piece, game.player2ID, p2Count,
+		game.rules().name)
+	if !game.forcedCapture {
+		scoreStr += MsgCheckersCaptureOptional
+	}
+
+	var statusSB strings.Builder
+	if game.gameOver {
+		if statusMsg != "" {
+			statusSB.WriteString(statusMsg)
+		} else if game.winner == 0 {
+			statusSB.WriteString(fmt.Sprintf(checkersStatusDraw, game.player1ID, game.player2ID))
+		} else {
+			winnerID := game.player1ID
+			loserID := game.player2ID
+			if game.winner == 2 {
+				winnerID = game.player2ID
+				loserID = game.player1ID
+			}
+			statusSB.WriteString(fmt.Sprintf(checkersStatusWin, loserID, winnerID))
+		}
+	} else {
+		currentPlayer := game.player1ID
+		statusIcon := p1Piece
+		hasKing := false
+
+		checkType := PieceP1King
+		if game.currentTurn == 2 {
+			currentPlayer = game.player2ID
+			statusIcon = p2Piece
+			checkType = PieceP2King
+		}
+
+		for r := range n {
+			for c := range n {
+				if game.board[r][c] == checkType {
+					hasKing = true
+					break
+				}
+			}
+			if hasKing {
+				break
+			}
+		}
+
+		if hasKing {
+			if game.currentTurn == 1 {
+				statusIcon = p1King
+			} else {
+				statusIcon = p2King
+			}
+		}
+		statusSB.WriteString(fmt.Sprintf(checkersStatusTurn, currentPlayer, statusIcon))
+
+		if game.drawOffer != 0 {
+			offerer := game.player1ID
+			if game.drawOffer == 2 {
+				offerer = game.player2ID
+			}
+			statusSB.WriteString("\n" + fmt.Sprintf(MsgGameDrawOffered, offerer))
+		}
+		if statusMsg != "" {
+			statusSB.WriteString("\n" + statusMsg)
+		}
+	}
+
+	if game.clock != nil {
+		turn := game.currentTurn
+		if game.gameOver {
+			turn = 0
+		}
+		scoreStr += "\n" + game.clock.render(turn, p1Piece, p2Piece)
+	}
+
+	var components []interface{}
+	if game.emojiBoard {
+		components = append(components, NewTextDisplay(sb.String()))
+	} else {
+		components = append(components, checkersRenderBoard(game))
+	}
+	components = append(components, NewTextDisplay(scoreStr))
+	components = append(components, NewTextDisplay(statusSB.String()))
+
+	if !game.gameOver {
+		validMoves := CheckersGetValidMoves(game, game.currentTurn)
+		selectedKey := ""
+		if game.selectedPiece != nil {
+			selectedKey = fmt.Sprintf("%d,%d", game.selectedPiece[0], game.selectedPiece[1])
+		}
+
+		var pieceOptions []discord.StringSelectMenuOption
+		for k, targets := range validMoves {
+			if len(targets) > 0 {
+				if selectedKey != "" && k == selectedKey {
+					continue
+				}
+
+				coords := strings.Split(k, ",")
+				r, _ := strconv.Atoi(coords[0])
+				c, _ := strconv.Atoi(coords[1])
+				label := fmt.Sprintf("Row %d, Col %c", r+1, 'A'+c)
+				pieceOptions = append(pieceOptions, discord.NewStringSelectMenuOption(label, k))
+			}
+		}
+		sort.Slice(pieceOptions, func(i, j int) bool { return pieceOptions[i].Label < pieceOptions[j].Label })
+
+		if game.selectedPiece != nil {
+			k := fmt.Sprintf("%d,%d", game.selectedPiece[0], game.selectedPiece[1])
+			if targets, ok := validMoves[k]; ok {
+				var targetOptions []discord.StringSelectMenuOption
+				for _, t := range targets {
+					label := fmt.Sprintf("Row %d, Col %c", t[0]+1, 'A'+t[1])
+					val := fmt.Sprintf("%d,%d", t[0], t[1])
+					targetOptions = append(targetOptions, discord.NewStringSelectMenuOption(label, val))
+				}
+				if len(targetOptions) > 25 {
+					targetOptions = targetOptions[:25]
+				}
+				menu := discord.NewStringSelectMenu(fmt.Sprintf("checkers:%s:move_to", gameID), LabelSelectDest, targetOptions...)
+				components = append(components, discord.NewActionRow(menu))
+
+				cancelBtn := discord.NewButton(discord.ButtonStyleSecondary, "Cancel Selection", fmt.Sprintf("checkers:%s:cancel_select", gameID), "", 0)
+				components = append(components, discord.NewActionRow(cancelBtn))
+			}
+		} else {
+			if len(pieceOptions) > 25 {
+				pieceOptions = pieceOptions[:25]
+			}
+			placeholder := LabelSelectPieceMove
+			if game.selectedPiece != nil {
+				r, c := game.selectedPiece[0], game.selectedPiece[1]
+				placeholder = fmt.Sprintf("Selected: Row %d, Col %c", r+1, 'A'+c)
+			}
+			if len(pieceOptions) > 0 {
+				menu := discord.NewStringSelectMenu(fmt.Sprintf("checkers:%s:select_piece", gameID), placeholder, pieceOptions...)
+				components = append(components, discord.NewActionRow(menu))
+			}
+		}
+
+		var utilityRow []discord.InteractiveComponent
+		utilityRow = append(utilityRow, discord.NewButton(discord.ButtonStyleDanger, LabelResign, fmt.Sprintf(CIDCheckersForfeit, gameID), "", 0))
+		if game.drawOffer != 0 {
+			utilityRow = append(utilityRow, discord.NewButton(discord.ButtonStyleSuccess, LabelAcceptDraw, fmt.Sprintf(CIDCheckersAccept, gameID), "", 0))
+		} else {
+			utilityRow = append(utilityRow, discord.NewButton(discord.ButtonStyleSecondary, LabelOfferDraw, fmt.Sprintf(CIDCheckersOffer, gameID), "", 0))
+		}
+
+		if CheckersIsHopeless(game) {
+			utilityRow = append(utilityRow, discord.NewButton(discord.ButtonStyleSuccess, LabelClaimWin, fmt.Sprintf(CIDCheckersClaimWin, gameID), "", 0))
+		}
+		utilityRow = append(utilityRow, discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDCheckersSpectate, gameID), "", 0))
+
+		components = append(components, discord.NewActionRow(utilityRow...))
+	} else {
+		components = append(components, NewSeparator(true))
+		components = append(components, discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSuccess, LabelRematch, fmt.Sprintf(CIDCheckersRematch, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDCheckersSpectate, gameID), "", 0),
+		))
+	}
+
+	return NewV2Container(components...)
+}
+
+// checkersRenderBoard draws the board as a PNG from the mover's side, marking the selection, its targets and the last move
+func checkersRenderBoard(game *CheckersGame) gameBoardImage {
+	p1Color, p2Color := gameImageRed, gameImageBlue
+	if game.colorVariant == VariantInverted {
+		p1Color, p2Color = gameImageBlue, gameImageRed
+	}
+	reverse := game.currentTurn == 2
+
+	targets := make(map[[2]int]bool)
+	if game.selectedPiece != nil {
+		k := fmt.Sprintf("%d,%d", game.selectedPiece[0], game.selectedPiece[1])
+		for _, t := range CheckersGetValidMoves(game, game.currentTurn)[k] {
+			targets[t] = true
+		}
+	}
+
+	n := len(game.board)
+	canvas := newGameCanvas(n, n)
+	for r := range n {
+		for c := range n {
+			dr, dc := r, c
+			if reverse {
+				dr, dc = n-1-r, n-1-c
+			}
+			pos := [2]int{r, c}
+
+			square := checkersImageLight
+			if (r+c)%2 == 1 {
+				square = checkersImageDark
+			}
+			canvas.fill(dr, dc, square)
+			switch {
+			case game.selectedPiece != nil && *game.selectedPiece == pos:
+				canvas.tint(dr, dc, gameImageSelected, 0.6)
+			case (game.lastMoveFrom != nil && *game.lastMoveFrom == pos) || (game.lastMoveDest != nil && *game.lastMoveDest == pos):
+				canvas.tint(dr, dc, gameImageLastMove, 0.5)
+			}
+
+			p := game.board[r][c]
+			switch p {
+			case PieceP1, PieceP1King:
+				canvas.disc(dr, dc, 0.76, p1Color, gameImageBlack, 2)
+			case PieceP2, PieceP2King:
+				canvas.disc(dr, dc, 0.76, p2Color, gameImageBlack, 2)
+			default:
+				if targets[pos] {
+					canvas.disc(dr, dc, 0.3, gameImageTarget, gameImageTarget, 0)
+				}
+			}
+			if p == PieceP1King || p == PieceP2King {
+				canvas.label(dr, dc, "K", 3, gameImageGold)
+			}
+		}
+	}
+	canvas.coords(gameBoardLabels(n, 'A', reverse), gameBoardLabels(n, '1', reverse))
+	return canvas.attach()
+}
+
+// ===========================
+// Checkers AI
+
+func CheckersMakeAIMove(client bot.Client, game *CheckersGame, gameID string) {
+	activeCheckersGamesMu.Lock()
+	if game.gameOver || game.currentTurn != game.aiPlayerNum || !game.isAI {
+		activeCheckersGamesMu.Unlock()
+		return
+	}
+
+	if len(checkersLegalMoves(game, game.aiPlayerNum)) == 0 {
+		game.gameOver = true
+		game.winner = 3 - game.aiPlayerNum
+		checkersRecordResult(game)
+
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
+
+		checkersPersist(game, gameID)
+		msg := CheckersBuildMessage(game, gameID, MsgGameAINoMoves)
+		_, _ = gameEditBoard(client, game.channelID, game.messageID, msg)
+		gameMirror(client, game.spectate, msg)
+		activeCheckersGamesMu.Unlock()
+		return
+	}
+
+	difficulty := game.aiDifficulty
+	position := checkersClone(game)
+	moveCount := game.moveCount
+	activeCheckersGamesMu.Unlock()
+
+	selectedMove := checkersAIChooseMove(position, game.aiPlayerNum, difficulty)
+
+	activeCheckersGamesMu.Lock()
+	defer activeCheckersGamesMu.Unlock()
+
+	if game.gameOver || game.currentTurn != game.aiPlayerNum || game.moveCount != moveCount {
+		return
+	}
+
+	checkersApplyMove(game, selectedMove)
+	game.lastMoveTime = time.Now()
+	if game.clock != nil {
+		game.clock.press(game.aiPlayerNum)
+	}
+	game.drawOffer = 0
+	game.currentTurn = 3 - game.aiPlayerNum
+
+	if CheckersCheckWin(game) {
+		game.gameOver = true
+		checkersRecordResult(game)
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
+	}
+
+	checkersStartClock(client, game, gameID)
+	checkersPersist(game, gameID)
+	msg := CheckersBuildMessage(game, gameID, "")
+	_, _ = gameEditBoard(client, game.channelID, game.messageID, msg)
+	gameMirror(client, game.spectate, msg)
+}
+
+// checkersAIChooseMove plays randomly on easy, still taking any capture on offer, and searches on the other difficulties
+func checkersAIChooseMove(game *CheckersGame, player int, difficulty string) checkersMove {
+	if cfg, ok := checkersEngineConfigs[difficulty]; ok {
+		if best, ok := checkersEngineSearch(game, player, cfg); ok {
+			return best
+		}
+	}
+
+	moves := checkersLegalMoves(game, player)
+	var jumpMoves []checkersMove
+	for _, m := range moves {
+		if len(m.captured) > 0 {
+			jumpMoves = append(jumpMoves, m)
+		}
+	}
+	if len(jumpMoves) > 0 {
+		moves = jumpMoves
+	}
+	return moves[rand.Intn(len(moves))]
+}
+
+// checkersAIAcceptsDraw takes a draw unless the AI is ahead on material (kings count double)
+func checkersAIAcceptsDraw(game *CheckersGame) bool {
+	var material [3]int
+	for r := range game.board {
+		for c := range game.board[r] {
+			switch game.board[r][c] {
+			case PieceP1:
+				material[1]++
+			case PieceP1King:
+				material[1] += 2
+			case PieceP2:
+				material[2]++
+			case PieceP2King:
+				material[2] += 2
+			}
+		}
+	}
+	return material[game.aiPlayerNum] <= material[3-game.aiPlayerNum]
+}
+
+// checkersClone copies the position and rules of a game so it can be searched outside the lock
+func checkersClone(game *CheckersGame) *CheckersGame {
+	board := make([][]CheckersPieceType, len(game.board))
+	for r := range game.board {
+		board[r] = slices.Clone(game.board[r])
+	}
+	return &CheckersGame{board: board, variant: game.variant, forcedCapture: game.forcedCapture}
+}
+
+// checkersEngineConfig controls how hard the checkers engine thinks for a difficulty
+type checkersEngineConfig struct {
+	depth  int           // Maximum iterative deepening depth (plies)
+	budget time.Duration // Wall-clock budget per move (depth 1 always completes)
+}
+
+// checkersEngineConfigs maps the searched difficulties to their limits; easy stays random
+var checkersEngineConfigs = map[string]checkersEngineConfig{
+	ChoiceNormal: {depth: 4, budget: 1 * time.Second},
+	ChoiceHard:   {depth: 12, budget: 3 * time.Second},
+}
+
+const (
+	checkersInfinity         = 1_000_000
+	checkersWinScore         = 100_000
+	checkersWinThreshold     = checkersWinScore - 1_000
+	checkersCaptureExtension = 6
+
+	checkersManValue        = 100
+	checkersKingValue       = 250
+	checkersFlyingKingValue = 400
+)
+
+// checkersEngine is a depth-limited alpha-beta searcher that plays and takes back moves on a private board
+type checkersEngine struct {
+	cfg      checkersEngineConfig
+	game     *CheckersGame
+	deadline time.Time
+	nodes    int
+	aborted  bool
+}
+
+// checkersUndo records what a move changed so the engine can take it back
+type checkersUndo struct {
+	move     checkersMove
+	piece    CheckersPieceType
+	captured []CheckersPieceType
+}
+
+// checkersEngineSearch returns the best move for player, searching the given game's board in place
+func checkersEngineSearch(game *CheckersGame, player int, cfg checkersEngineConfig) (checkersMove, bool) {
+	moves := checkersLegalMoves(game, player)
+	if len(moves) == 0 {
+		return checkersMove{}, false
+	}
+
+	e := &checkersEngine{cfg: cfg, game: game}
+	// Shuffle first so equal moves are not always played in board order
+	rand.Shuffle(len(moves), func(i, j int) { moves[i], moves[j] = moves[j], moves[i] })
+	e.orderMoves(moves)
+
+	start := time.Now()
+	best := moves[0]
+	for depth := 1; depth <= max(cfg.depth, 1); depth++ {
+		if depth > 1 && cfg.budget > 0 {
+			e.deadline = start.Add(cfg.budget)
+		}
+
+		alpha := -checkersInfinity
+		bestIdx := -1
+		for i := range moves {
+			undo := e.play(moves[i])
+			score := -e.negamax(3-player, depth-1, -checkersInfinity, -alpha, 1)
+			e.undo(undo)
+			if e.aborted {
+				break
+			}
+			if bestIdx == -1 || score > alpha {
+				alpha = score
+				bestIdx = i
+			}
+		}
+		if e.aborted || bestIdx == -1 {
+			break
+		}
+
+		best = moves[bestIdx]
+		// Search the previous best move first on the next iteration
+		copy(moves[1:bestIdx+1], moves[:bestIdx])
+		moves[0] = best
+
+		if alpha >= checkersWinThreshold {
+			break
+		}
+	}
+
+	return best, true
+}
+
+func (e *checkersEngine) expired() bool {
+	e.nodes++
+	if !e.aborted && !e.deadline.IsZero() && e.nodes&255 == 0 && time.Now().After(e.deadline) {
+		e.aborted = true
+	}
+	return e.aborted
+}
+
+func (e *checkersEngine) negamax(player, depth, alpha, beta, ply int) int {
+	if e.expired() {
+		return 0
+	}
+
+	moves := checkersLegalMoves(e.game, player)
+	if len(moves) == 0 {
+		return -checkersWinScore + ply
+	}
+
+	if depth <= 0 {
+		// Past the horizon only captures are followed, so exchanges are never cut off halfway
+		total := len(moves)
+		moves = slices.DeleteFunc(moves, func(m checkersMove) bool { return len(m.captured) == 0 })
+		if len(moves) == 0 || depth <= -checkersCaptureExtension {
+			return checkersEvaluate(e.game, player)
+		}
+		if len(moves) < total {
+			// A quiet move was also allowed, so the side to move can decline the exchange
+			standPat := checkersEvaluate(e.game, player)
+			if standPat >= beta {
+				return standPat
+			}
+			alpha = max(alpha, standPat)
+		}
+	}
+
+	e.orderMoves(moves)
+	for i := range moves {
+		undo := e.play(moves[i])
+		score := -e.negamax(3-player, depth-1, -beta, -alpha, ply+1)
+		e.undo(undo)
+		if e.aborted {
+			return 0
+		}
+		if score >= beta {
+			return score
+		}
+		alpha = max(alpha, score)
+	}
+	return alpha
+}
+
+// orderMoves searches the longest captures first so cut-offs come sooner
+func (e *checkersEngine) orderMoves(moves []checkersMove) {
+	slices.SortStableFunc(moves, func(a, b checkersMove) int {
+		return len(b.captured) - len(a.captured)
+	})
+}
+
+func (e *checkersEngine) play(m checkersMove) checkersUndo {
+	board := e.game.board
+	from := m.from()
+	undo := checkersUndo{move: m, piece: board[from[0]][from[1]]}
+	for _, sq := range m.captured {
+		undo.captured = append(undo.captured, board[sq[0]][sq[1]])
+	}
+	checkersApplyMove(e.game, m)
+	return undo
+}
+
+func (e *checkersEngine) undo(u checkersUndo) {
+	board := e.game.board
+	from, to := u.move.from(), u.move.to()
+	// Clear the landing square first, since a king's capture loop can end where it started
+	board[to[0]][to[1]] = PieceNone
+	for i, sq := range u.move.captured {
+		board[sq[0]][sq[1]] = u.captured[i]
+	}
+	board[from[0]][from[1]] = u.piece
+}
+
+// checkersEvaluate scores the position for player from material, how far men have advanced, the back rank and the centre
+func checkersEvaluate(g *CheckersGame, player int) int {
+	rules := g.rules()
+	n := len(g.board)
+	kingValue := checkersKingValue
+	if rules.flyingKings {
+		kingValue = checkersFlyingKingValue
+	}
+
+	score := 0
+	for r := range g.board {
+		for c, piece := range g.board[r] {
+			owner := checkersOwner(piece)
+			if owner == 0 {
+				continue
+			}
+
+			v := kingValue
+			if !checkersIsKing(piece) {
+				v = checkersManValue
+				advanced := r - (n - 1 - checkersPromotionRow(g, owner))
+				if advanced < 0 {
+					advanced = -advanced
+				}
+				v += advanced * 4
+				if advanced == 0 {
+					// Men left on the home row keep the opponent from crowning
+					v += 6
+				}
+			}
+			if c >= 2 && c < n-2 {
+				v += 5
+			}
+
+			if owner == player {
+				score += v
+			} else {
+				score -= v
+			}
+		}
+	}
+	return score
+}
+
+// ===========================
+// Chess Game Constants & Types
+// ===========================
+
+const (
+	chessWhiteSquare = "▫️"
+	chessBlackSquare = "▪️"
+	chessSelected    = "🟩"
+
+	chessTargetWhite = "◻️"
+	chessTargetBlack = "◼️"
+
+	chessWhiteKing   = "🤟🏻"
+	chessWhiteQueen  = "🖐🏻"
+	chessWhiteRook   = "✊🏻"
+	chessWhiteBishop = "👐🏻"
+	chessWhiteKnight = "🤙🏻"
+	chessWhitePawn   = "☝🏻"
+
+	chessBlackKing   = "🤟🏿"
+	chessBlackQueen  = "🖐🏿"
+	chessBlackRook   = "✊🏿"
+	chessBlackBishop = "👐🏿"
+	chessBlackKnight = "🤙🏿"
+	chessBlackPawn   = "☝🏿"
+
+	chessStatusTurn    = MsgGameTurn
+	chessStatusWin     = MsgGameWin
+	chessStatusDraw    = MsgGameDraw
+	chessStatusForfeit = MsgGameForfeitSuccess
+
+	chessHistoryMaxPlies = 40
+	chessPGNDateFormat   = "2006.01.02"
+	chessTerminationTime = "time forfeit"
+)
+
+// ChessGame represents a Chess game session
+type ChessGame struct {
+	game          *chess.Game
+	player1ID     snowflake.ID
+	player2ID     snowflake.ID
+	isAI          bool
+	aiDifficulty  string
+	aiPlayerNum   int
+	colorVariant  GameColorVariant
+	currentTurn   int
+	gameOver      bool
+	winner        int
+	messageID     snowflake.ID
+	channelID     snowflake.ID
+	selectedPiece *chess.Square
+	lastMove      *chess.Move
+	p1Icon        string
+	p2Icon        string
+	startedAt     time.Time
+	clock         *gameClock
+	drawOffer     int
+	termination   string
+	originalP1ID  snowflake.ID
+	originalP2ID  snowflake.ID
+	spectate      *gameSpectate
+	emojiBoard    bool
+}
+
+// chessSnapshot is the serialized form of a ChessGame stored in the database
+type chessSnapshot struct {
+	FEN           string           `json:"fen"`
+	StartFEN      string           `json:"start_fen,omitempty"`
+	Moves         []string         `json:"moves"`
+	Player1ID     snowflake.ID     `json:"player1_id"`
+	Player2ID     snowflake.ID     `json:"player2_id"`
+	IsAI          bool             `json:"is_ai"`
+	AIDifficulty  string           `json:"ai_difficulty"`
+	AIPlayerNum   int              `json:"ai_player_num"`
+	ColorVariant  GameColorVariant `json:"color_variant"`
+	CurrentTurn   int              `json:"current_turn"`
+	MessageID     snowflake.ID     `json:"message_id"`
+	ChannelID     snowflake.ID     `json:"channel_id"`
+	SelectedPiece *chess.Square    `json:"selected_piece,omitempty"`
+	P1Icon        string           `json:"p1_icon"`
+	P2Icon        string           `json:"p2_icon"`
+	StartedAt     time.Time        `json:"started_at"`
+	Clock         *gameClock       `json:"clock,omitempty"`
+	DrawOffer     int              `json:"draw_offer,omitempty"`
+	OriginalP1ID  snowflake.ID     `json:"original_p1_id,omitempty"`
+	OriginalP2ID  snowflake.ID     `json:"original_p2_id,omitempty"`
+	Spectate      *gameSpectate    `json:"spectate,omitempty"`
+	EmojiBoard    bool             `json:"emoji_board,omitempty"`
+}
+
+func (g *ChessGame) GetPieceIcon(p chess.Piece) string {
+	wKing, bKing := chessWhiteKing, chessBlackKing
+	wQueen, bQueen := chessWhiteQueen, chessBlackQueen
+	wRook, bRook := chessWhiteRook, chessBlackRook
+	wBishop, bBishop := chessWhiteBishop, chessBlackBishop
+	wKnight, bKnight := chessWhiteKnight, chessBlackKnight
+	wPawn, bPawn := chessWhitePawn, chessBlackPawn
+
+	if g.colorVariant == VariantInverted {
+		wKing, bKing = chessBlackKing, chessWhiteKing
+		wQueen, bQueen = chessBlackQueen, chessWhiteQueen
+		wRook, bRook = chessBlackRook, chessWhiteRook
+		wBishop, bBishop = chessBlackBishop, chessWhiteBishop
+		wKnight, bKnight = chessBlackKnight, chessWhiteKnight
+		wPawn, bPawn = chessBlackPawn, chessWhitePawn
+	}
+
+	switch p.Type() {
+	case chess.Pawn:
+		if p.Color() == chess.White {
+			return wPawn
+		}
+		return bPawn
+	case chess.Knight:
+		if p.Color() == chess.White {
+			return wKnight
+		}
+		return bKnight
+	case chess.Bishop:
+		if p.Color() == chess.White {
+			return wBishop
+		}
+		return bBishop
+	case chess.Rook:
+		if p.Color() == chess.White {
+			return wRook
+		}
+		return bRook
+	case chess.Queen:
+		if p.Color() == chess.White {
+			return wQueen
+		}
+		return bQueen
+	case chess.King:
+		if p.Color() == chess.White {
+			return wKing
+		}
+		return bKing
+	}
+	return ""
+}
+
+func NewChessGame(p1, p2 snowflake.ID, isAI bool, aiPlayerNum int, difficulty string) *ChessGame {
+	variant := VariantStandard
+	if rand.Intn(2) == 1 {
+		variant = VariantInverted
+	}
+	game := &ChessGame{
+		game:         chess.NewGame(),
+		player1ID:    p1,
+		player2ID:    p2,
+		isAI:         isAI,
+		aiDifficulty: difficulty,
+		aiPlayerNum:  aiPlayerNum,
+		colorVariant: variant,
+		currentTurn:  1,
+		startedAt:    time.Now(),
+	}
+	game.p1Icon = game.GetPieceIcon(chess.Piece(chess.WhiteKing))
+	game.p2Icon = game.GetPieceIcon(chess.Piece(chess.BlackKing))
+	return game
+}
+
+// chessRecordResult feeds a finished game into the rating system
+func chessRecordResult(game *ChessGame) {
+	aiPlayer := 0
+	if game.isAI {
+		aiPlayer = game.aiPlayerNum
+	}
+	gameRecordResult(CmdChess, game.player1ID, game.player2ID, game.winner, aiPlayer, game.aiDifficulty)
+}
+
+// chessPersist saves the game state, or forgets it once the game is over (caller must hold activeChessGamesMu)
+func chessPersist(game *ChessGame, gameID string) {
+	if game.gameOver {
+		gameForget(gameID)
+		return
+	}
+
+	var moves []string
+	for _, m := range game.game.Moves() {
+		moves = append(moves, chess.UCINotation{}.Encode(nil, m))
+	}
+
+	startFEN := game.game.GetRootMove().Position().String()
+	if startFEN == chess.StartingPosition().String() {
+		startFEN = ""
+	}
+
+	gameSaveState(gameID, CmdChess, chessSnapshot{
+		FEN:           game.game.FEN(),
+		StartFEN:      startFEN,
+		Moves:         moves,
+		Player1ID:     game.player1ID,
+		Player2ID:     game.player2ID,
+		IsAI:          game.isAI,
+		AIDifficulty:  game.aiDifficulty,
+		AIPlayerNum:   game.aiPlayerNum,
+		ColorVariant:  game.colorVariant,
+		CurrentTurn:   game.currentTurn,
+		MessageID:     game.messageID,
+		ChannelID:     game.channelID,
+		SelectedPiece: game.selectedPiece,
+		P1Icon:        game.p1Icon,
+		P2Icon:        game.p2Icon,
+		StartedAt:     game.startedAt,
+		Clock:         game.clock,
+		DrawOffer:     game.drawOffer,
+		OriginalP1ID:  game.originalP1ID,
+		OriginalP2ID:  game.originalP2ID,
+		Spectate:      game.spectate,
+		EmojiBoard:    game.emojiBoard,
+	})
+}
+
+// chessStartClock arms the flag-fall timer for the side to move (caller must hold activeChessGamesMu)
+func chessStartClock(client bot.Client, game *ChessGame, gameID string) {
+	if game.clock == nil || game.gameOver {
+		return
+	}
+
+	player, ply := game.currentTurn, len(game.game.Moves())
+	game.clock.arm(player, func() {
+		activeChessGamesMu.Lock()
+		defer activeChessGamesMu.Unlock()
+
+		if game.gameOver || game.currentTurn != player || len(game.game.Moves()) != ply {
+			return
+		}
+
+		msg := chessFlagFall(game, gameID)
+		_, _ = gameEditBoard(client, game.channelID, game.messageID, msg)
+		gameMirror(client, game.spectate, msg)
+	})
+}
+
+// chessFlagFall ends the game on time against the side to move and returns the final board (caller must hold
+// activeChessGamesMu)
+func chessFlagFall(game *ChessGame, gameID string) Container {
+	player := game.currentTurn
+	game.gameOver = true
+	game.winner = 3 - player
+	game.selectedPiece = nil
+	game.termination = chessTerminationTime
+	game.clock.stop()
+	loserID, winnerID := game.player1ID, game.player2ID
+	if player == 1 {
+		game.game.Resign(chess.White)
+	} else {
+		game.game.Resign(chess.Black)
+		loserID, winnerID = game.player2ID, game.player1ID
+	}
+	chessRecordResult(game)
+	chessPersist(game, gameID)
+
+	userActiveGameMu.Lock()
+	delete(userActiveGame, game.player1ID)
+	delete(userActiveGame, game.player2ID)
+	userActiveGameMu.Unlock()
+
+	return ChessBuildMessage(game, gameID, fmt.Sprintf(MsgGameFlagFall, loserID, winnerID))
+}
+
+// chessRestore replays the saved move list (falling back to the FEN) and resumes a pending AI turn
+func chessRestore(client bot.Client, gameID string, state string) error {
+	var snap chessSnapshot
+	if err := json.Unmarshal([]byte(state), &snap); err != nil {
+		return err
+	}
+
+	g := chess.NewGame()
+	if snap.StartFEN != "" {
+		opt, err := chess.FEN(snap.StartFEN)
+		if err != nil {
+			return err
+		}
+		g = chess.NewGame(opt)
+	}
+	for _, uci := range snap.Moves {
+		if err := g.PushNotationMove(uci, chess.UCINotation{}, nil); err != nil {
+			g = nil
+			break
+		}
+	}
+	if g == nil || g.FEN() != snap.FEN {
+		opt, err := chess.FEN(snap.FEN)
+		if err != nil {
+			return err
+		}
+		g = chess.NewGame(opt)
+	}
+
+	game := &ChessGame{
+		game:          g,
+		player1ID:     snap.Player1ID,
+		player2ID:     snap.Player2ID,
+		isAI:          snap.IsAI,
+		aiDifficulty:  snap.AIDifficulty,
+		aiPlayerNum:   snap.AIPlayerNum,
+		colorVariant:  snap.ColorVariant,
+		currentTurn:   snap.CurrentTurn,
+		messageID:     snap.MessageID,
+		channelID:     snap.ChannelID,
+		selectedPiece: snap.SelectedPiece,
+		p1Icon:        snap.P1Icon,
+		p2Icon:        snap.P2Icon,
+		startedAt:     snap.StartedAt,
+		clock:         snap.Clock,
+		drawOffer:     snap.DrawOffer,
+		originalP1ID:  snap.OriginalP1ID,
+		originalP2ID:  snap.OriginalP2ID,
+		spectate:      snap.Spectate,
+		emojiBoard:    snap.EmojiBoard,
+	}
+	if moves := g.Moves(); len(moves) > 0 {
+		game.lastMove = moves[len(moves)-1]
+	}
+
+	activeChessGamesMu.Lock()
+	activeChessGames[gameID] = game
+	chessStartClock(client, game, gameID)
+	activeChessGamesMu.Unlock()
+
+	gameLockPlayers(client, gameID, game.player1ID, game.player2ID)
+
+	if game.isAI && game.currentTurn == game.aiPlayerNum {
+		time.AfterFunc(1*time.Second, func() {
+			ChessMakeAIMove(client, game, gameID)
+		})
+	}
+	return nil
+}
+
+// ===========================
+// Chess Interaction Handlers
+// ===========================
+
+func HandlePlayChess(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in HandlePlayChess: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
+
+	var opponentID *snowflake.ID
+	isAI := true
+	var p2 snowflake.ID
+
+	if opponent, ok := data.OptUser("opponent"); ok {
+		opponentID = &opponent.ID
+		isAI = opponent.Bot
+	}
+
+	difficulty := "normal"
+	if diff, ok := data.OptString("difficulty"); ok {
+		difficulty = diff
+	}
+
+	start, errMsg := chessParseStart(data)
+	if errMsg != "" {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+		return
+	}
+
+	appID := event.ApplicationID()
+	p1 := event.User().ID
+
+	if opponentID != nil && *opponentID == p1 {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameChallengeSelf).WithEphemeral(true))
+		return
+	}
+
+	userActiveGameMu.Lock()
+	if gid, ok := userActiveGame[p1]; ok {
+		userActiveGameMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameAlreadyActive, gid)).WithEphemeral(true))
+		return
+	}
+	if opponentID != nil && *opponentID != appID {
+		if gid, ok := userActiveGame[*opponentID]; ok {
+			userActiveGameMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameOpponentActive, *opponentID, gid)).WithEphemeral(true))
+			return
+		}
+	}
+
+	cid := event.Channel().ID()
+	gameID := fmt.Sprintf(CIDChessGameID, cid, time.Now().UnixNano())
+
+	userActiveGame[p1] = gameID
+	if opponentID != nil && *opponentID != appID {
+		userActiveGame[*opponentID] = gameID
+	}
+	userActiveGameMu.Unlock()
+
+	if opponentID != nil {
+		p2 = *opponentID
+		if p2 == appID {
+			isAI = true
+		} else {
+			isAI = false
+		}
+	} else {
+		p2 = appID
+		isAI = true
+	}
+
+	var whitePlayer, blackPlayer snowflake.ID
+	if rand.Intn(2) == 0 {
+		whitePlayer = p1
+		blackPlayer = p2
+	} else {
+		whitePlayer = p2
+		blackPlayer = p1
+	}
+
+	aiPlayerNum := 0
+	if isAI {
+		if whitePlayer == appID || (opponentID != nil && whitePlayer == *opponentID && isAI) {
+			aiPlayerNum = 1
+		} else {
+			aiPlayerNum = 2
+		}
+	}
+
+	game := NewChessGame(whitePlayer, blackPlayer, isAI, aiPlayerNum, difficulty)
+	game.clock = gameParseClock(data)
+	if start != nil {
+		game.game = start
+		if start.Position().Turn() == chess.Black {
+			game.currentTurn = 2
+		}
+		if moves := start.Moves(); len(moves) > 0 {
+			game.lastMove = moves[len(moves)-1]
+		}
+	}
+
+	game.originalP1ID = p1
+	game.originalP2ID = p2
+	game.emojiBoard = gameParseEmojiBoard(data)
+
+	chessOpen(*event.Client(), event, game, gameID)
+}
+
+// chessOpen posts a new game: PvP games wait for the opponent to accept, AI games start immediately
+func chessOpen(client bot.Client, interaction discord.Interaction, game *ChessGame, gameID string) {
+	if !game.isAI {
+		gameIssueChallenge(client, interaction, gameID, &gameChallenge{
+			gameType:     CmdChess,
+			challengerID: game.originalP1ID,
+			opponentID:   game.originalP2ID,
+			launch: func(client bot.Client, channelID, messageID snowflake.ID) Container {
+				return chessLaunch(client, game, gameID, channelID, messageID)
+			},
+		})
+		return
+	}
+
+	activeChessGamesMu.Lock()
+	activeChessGames[gameID] = game
+	activeChessGamesMu.Unlock()
+
+	msg := ChessBuildMessage(game, gameID, "")
+	if err := gameRespondBoard(client, interaction, msg); err != nil {
+		activeChessGamesMu.Lock()
+		delete(activeChessGames, gameID)
+		activeChessGamesMu.Unlock()
+
+		gameReleasePlayers(game.player1ID, game.player2ID)
+		return
+	}
+
+	var messageID snowflake.ID
+	if resp, err := client.Rest.GetInteractionResponse(interaction.ApplicationID(), interaction.Token()); err == nil && resp != nil {
+		messageID = resp.ID
+	}
+	chessLaunch(client, game, gameID, interaction.Channel().ID(), messageID)
+}
+
+// chessLaunch attaches a game to its message, starts its clock and AI, and returns the opening board
+func chessLaunch(client bot.Client, game *ChessGame, gameID string, channelID, messageID snowflake.ID) Container {
+	activeChessGamesMu.Lock()
+	activeChessGames[gameID] = game
+	game.channelID = channelID
+	game.messageID = messageID
+	game.startedAt = time.Now()
+	chessStartClock(client, game, gameID)
+	chessPersist(game, gameID)
+	msg := ChessBuildMessage(game, gameID, "")
+	activeChessGamesMu.Unlock()
+
+	if game.isAI && game.currentTurn == game.aiPlayerNum {
+		time.AfterFunc(1*time.Second, func() {
+			ChessMakeAIMove(client, game, gameID)
+		})
+	}
+	return msg
+}
+
+// chessRematch sets up a fresh game from the standard position with colours swapped, requested by userID
+func chessRematch(game *ChessGame, userID snowflake.ID) *ChessGame {
+	aiPlayerNum := 0
+	if game.isAI {
+		aiPlayerNum = 3 - game.aiPlayerNum
+	}
+	next := NewChessGame(game.player2ID, game.player1ID, game.isAI, aiPlayerNum, game.aiDifficulty)
+	next.clock = game.clock.fresh()
+	next.emojiBoard = game.emojiBoard
+	next.originalP1ID = userID
+	next.originalP2ID = game.player1ID
+	if userID == game.player1ID {
+		next.originalP2ID = game.player2ID
+	}
+	return next
+}
+
+// chessParseStart builds the starting game from the fen/pgn options, returning a user-facing error message on failure
+func chessParseStart(data discord.SlashCommandInteractionData) (*chess.Game, string) {
+	fen, hasFEN := data.OptString(OptFEN)
+	pgn, hasPGN := data.OptString(OptPGN)
+
+	var opt func(*chess.Game)
+	var err error
+	switch {
+	case hasFEN && hasPGN:
+		return nil, MsgChessStartConflict
+	case hasFEN:
+		if opt, err = chess.FEN(strings.TrimSpace(fen)); err != nil {
+			return nil, fmt.Sprintf(MsgChessBadFEN, err)
+		}
+	case hasPGN:
+		if opt, err = chess.PGN(strings.NewReader(pgn)); err != nil {
+			return nil, fmt.Sprintf(MsgChessBadPGN, err)
+		}
+	default:
+		return nil, ""
+	}
+
+	g := chess.NewGame(opt)
+	if g.Outcome() != chess.NoOutcome || len(g.ValidMoves()) == 0 {
+		return nil, MsgChessStartFinished
+	}
+	return g, ""
+}
+
+func HandleChessInteraction(event *events.ComponentInteractionCreate) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in HandleChessInteraction: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
+
+	parts := strings.Split(event.Data.CustomID(), ":")
+	if len(parts) < 3 {
+		return
+	}
+
+	gameID := parts[1]
+	action := parts[2]
+	statusMsg := ""
+
+	activeChessGamesMu.Lock()
+	game, exists := activeChessGames[gameID]
+	if !exists {
+		activeChessGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotFound).WithEphemeral(true))
+		return
+	}
+
+	if action == "pgn" {
+		export := *game
+		export.game = game.game.Clone()
+		activeChessGamesMu.Unlock()
+
+		// Player names may need a REST lookup, so acknowledge first
+		_ = event.DeferCreateMessage(true)
+		fileName := gameID + ".pgn"
+		pgn := chessBuildPGN(*event.Client(), event.GuildID(), &export)
+		files := []*discord.File{discord.NewFile(fileName, "", strings.NewReader(pgn))}
+		container := NewV2Container(
+			NewTextDisplay(MsgChessPGNReady),
+			NewFile("attachment://"+fileName, ""),
+		)
+		_ = EditInteractionContainerV2Files(*event.Client(), event, container, files)
+		return
+	}
+
+	userID := event.User().ID
+	switch action {
+	case "spectate":
+		gameHandleSpectate(event, &activeChessGamesMu, gameID, CmdChess, game.spectate, ChessBuildMessage(game, gameID, ""), func(s *gameSpectate) *gameSpectate {
+			if game.spectate == nil {
+				game.spectate = s
+				chessPersist(game, gameID)
+			}
+			return game.spectate
+		})
+		return
+	case "rematch":
+		if msg := gameRematchRefusal(userID, game.gameOver, game.originalP1ID, game.originalP2ID, game.player1ID, game.player2ID); msg != "" {
+			activeChessGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(msg).WithEphemeral(true))
+			return
+		}
+		next := chessRematch(game, userID)
+		activeChessGamesMu.Unlock()
+
+		nextID := fmt.Sprintf(CIDChessGameID, event.Channel().ID(), time.Now().UnixNano())
+		if errMsg := gameReservePlayers(*event.Client(), nextID, next.originalP1ID, next.originalP2ID); errMsg != "" {
+			event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+			return
+		}
+		chessOpen(*event.Client(), event, next, nextID)
+		return
+	}
+
+	if userID != game.player1ID && userID != game.player2ID {
+		activeChessGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent("You are not part of this game.").WithEphemeral(true))
+		return
+	}
+
+	// The flag can fall moments before the timer gets the lock; nothing played after it counts
+	if game.clock != nil && !game.gameOver && game.clock.left(game.currentTurn, game.currentTurn) <= 0 {
+		msg := chessFlagFall(game, gameID)
+		activeChessGamesMu.Unlock()
+		_ = gameUpdateBoard(*event.Client(), event, msg)
+		gameMirror(*event.Client(), game.spectate, msg)
+		return
+	}
+
+	isWhite := userID == game.player1ID
+	player := 2
+	if isWhite {
+		player = 1
+	}
+
+	if game.currentTurn != player && !gameOutOfTurnActions[action] {
+		activeChessGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotTurn).WithEphemeral(true))
+		return
+	}
+
+	switch action {
+	case "select_piece":
+		values := event.StringSelectMenuInteractionData().Values
+		if len(values) > 0 {
+			sqVal, _ := strconv.Atoi(values[0])
+			sq := chess.Square(sqVal)
+			game.selectedPiece = &sq
+		}
+	case "move_to":
+		values := event.StringSelectMenuInteractionData().Values
+		if len(values) > 0 {
+			toSqVal, _ := strconv.Atoi(values[0])
+			toSq := chess.Square(toSqVal)
+
+			if game.selectedPiece != nil {
+				validMoves := game.game.ValidMoves()
+				var selectedMove *chess.Move
+				for _, m := range validMoves {
+					if m.S1() == *game.selectedPiece && m.S2() == toSq {
+						if m.Promo() != chess.NoPieceType && m.Promo() != chess.Queen {
+							continue
+						}
+						selectedMove = &m
+						break
+					}
+				}
+
+				if selectedMove != nil {
+					game.game.PushNotationMove(chess.UCINotation{}.Encode(game.game.Position(), selectedMove), chess.UCINotation{}, nil)
+
+					icon := game.GetPieceIcon(game.game.Position().Board().Piece(selectedMove.S2()))
+					if isWhite {
+						game.p1Icon = icon
+					} else {
+						game.p2Icon = icon
+					}
+
+					game.lastMove = selectedMove
+					game.selectedPiece = nil
+					if game.clock != nil {
+						game.clock.press(player)
+					}
+					// Moving declines the opponent's pending draw offer
+					if game.drawOffer != player {
+						game.drawOffer = 0
+					}
+					game.currentTurn = 3 - game.currentTurn
+
+					outcome := game.game.Outcome()
+					if outcome != chess.NoOutcome {
+						game.gameOver = true
+						switch outcome {
+						case chess.WhiteWon:
+							game.winner = 1
+						case chess.BlackWon:
+							game.winner = 2
+						default:
+							game.winner = 0
+						}
+						chessRecordResult(game)
+
+						userActiveGameMu.Lock()
+						delete(userActiveGame, game.player1ID)
+						delete(userActiveGame, game.player2ID)
+						userActiveGameMu.Unlock()
+					}
+				}
+			}
 		}
-		return
-	}
-
-	if action == "forfeit" {
-		userID := event.User().ID
-		if userID != game.player1ID && userID != game.player2ID {
-			event.CreateMessage(discord.NewMessageCreate().
-				WithContent(MsgGameNotPlayer).
-				WithEphemeral(true))
+	case "offer_draw", "accept_draw":
+		switch {
+		case game.drawOffer == player:
+			activeChessGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameDrawOwnOffer).WithEphemeral(true))
 			return
-		}
+		case game.drawOffer == 0 && action == "accept_draw":
+			activeChessGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameDrawNoOffer).WithEphemeral(true))
+			return
+		case game.drawOffer == 0 && game.isAI && !chessAIAcceptsDraw(game):
+			statusMsg = MsgGameDrawDeclined
+		case game.drawOffer == 0 && !game.isAI:
+			game.drawOffer = player
+		default:
+			game.gameOver = true
+			game.winner = 0
+			game.drawOffer = 0
+			game.selectedPiece = nil
+			game.clock.stop()
+			_ = game.game.Draw(chess.DrawOffer)
+			chessRecordResult(game)
 
-		activeConnect4GamesMu.Lock()
-		if game.turnTimer != nil {
-			game.turnTimer.Stop()
+			userActiveGameMu.Lock()
+			delete(userActiveGame, game.player1ID)
+			delete(userActiveGame, game.player2ID)
+			userActiveGameMu.Unlock()
 		}
+	case "forfeit":
 		game.gameOver = true
-		var forfeitMsg string
-		if userID == game.player1ID {
+		game.clock.stop()
+		winnerID := game.player1ID
+		loserID := game.player2ID
+		if isWhite {
 			game.winner = 2
-			forfeitMsg = fmt.Sprintf(connect4StatusForfeit, game.player1ID, game.player2ID)
+			winnerID = game.player2ID
+			loserID = game.player1ID
+			game.game.Resign(chess.White)
 		} else {
 			game.winner = 1
-			forfeitMsg = fmt.Sprintf(connect4StatusForfeit, game.player2ID, game.player1ID)
+			game.game.Resign(chess.Black)
 		}
-		activeConnect4GamesMu.Unlock()
+		statusMsg = fmt.Sprintf(chessStatusForfeit, loserID, winnerID)
+		chessRecordResult(game)
 
 		userActiveGameMu.Lock()
 		delete(userActiveGame, game.player1ID)
 		delete(userActiveGame, game.player2ID)
 		userActiveGameMu.Unlock()
-
-		builder := connect4BuildMessage(game, gameID, forfeitMsg)
-		UpdateInteractionContainerV2(*event.Client(), event, builder)
-		return
 	}
 
-	col, err := strconv.Atoi(action)
-	if err != nil {
-		event.DeferUpdateMessage()
-		return
-	}
-	col--
+	msg := ChessBuildMessage(game, gameID, statusMsg)
+	chessStartClock(*event.Client(), game, gameID)
+	chessPersist(game, gameID)
+	activeChessGamesMu.Unlock()
 
-	userID := event.User().ID
-	expectedPlayerID := game.player1ID
-	if game.currentTurn == 2 {
-		expectedPlayerID = game.player2ID
-	}
+	_ = gameUpdateBoard(*event.Client(), event, msg)
+	gameMirror(*event.Client(), game.spectate, msg)
 
-	if userID != expectedPlayerID {
-		event.CreateMessage(discord.NewMessageCreate().
-			WithContent(MsgGameNotTurn).
-			WithEphemeral(true))
-		return
+	if !game.gameOver && game.isAI && game.currentTurn == game.aiPlayerNum {
+		time.AfterFunc(1*time.Second, func() {
+			ChessMakeAIMove(*event.Client(), game, gameID)
+		})
 	}
+}
 
-	activeConnect4GamesMu.Lock()
-	if game.gameOver && action != "yes" && action != "no" {
-		activeConnect4GamesMu.Unlock()
-		event.DeferUpdateMessage()
-		return
-	}
+// ===========================
+// Chess Rendering & Helpers
+// ===========================
 
-	if game.turnTimer != nil {
-		game.turnTimer.Stop()
-	}
+func ChessBuildMessage(game *ChessGame, gameID string, statusMsg string) Container {
+	var sb strings.Builder
 
-	statusMsg := connect4MakeMove(game, col)
-	activeConnect4GamesMu.Unlock()
+	corner := BoardCorner
+	cols := BoardColumnEmojis[:8]
+	rows := BoardRowEmojis[:8]
 
-	builder := connect4BuildMessage(game, gameID, statusMsg)
-	UpdateInteractionContainerV2(*event.Client(), event, builder)
+	reverse := false
+	if game.currentTurn == 2 {
+		reverse = true
+	}
 
-	if !game.gameOver {
-		if game.timerEnabled {
-			connect4StartTimer(*event.Client(), game, gameID, game.moveCount)
+	sb.WriteString(corner)
+	if !reverse {
+		for _, c := range cols {
+			sb.WriteString(c)
 		}
-		if game.isAI && game.currentTurn == game.aiPlayerNum {
-			time.AfterFunc(1*time.Second, func() {
-				connect4MakeAIMove(*event.Client(), game, gameID)
-			})
+	} else {
+		for i := len(cols) - 1; i >= 0; i-- {
+			sb.WriteString(cols[i])
 		}
 	}
-}
+	sb.WriteString(corner + "\n")
 
-// ===========================
-// UI & Rendering
-// ===========================
+	rStart, rEnd, rStep := 7, -1, -1
+	cStart, cEnd, cStep := 0, 8, 1
 
-// connect4BuildMessage constructs the Discord message for the current game state
-func connect4BuildMessage(game *connect4Game, gameID string, statusMsg string) Container {
-	var sb strings.Builder
-	header := connect4GetHeader(game.cols)
-	sb.WriteString(header + "\n")
+	if reverse {
+		rStart, rEnd, rStep = 0, 8, 1
+		cStart, cEnd, cStep = 7, -1, -1
+	}
 
-	// Determine colors based on variant
-	p1Emoji, p2Emoji := connect4P1, connect4P2
-	p1WinEmoji, p2WinEmoji := connect4P1Win, connect4P2Win
-	if game.colorVariant == VariantInverted {
-		p1Emoji, p2Emoji = connect4P2, connect4P1
-		p1WinEmoji, p2WinEmoji = connect4P2Win, connect4P1Win
+	board := game.game.Position().Board()
+	validMoves := game.game.ValidMoves()
+	validTargets := make(map[chess.Square]bool)
+
+	if game.selectedPiece != nil {
+		for _, m := range validMoves {
+			if m.S1() == *game.selectedPiece {
+				validTargets[m.S2()] = true
+			}
+		}
 	}
 
-	for row := 0; row < game.rows; row++ {
-		for col := 0; col < game.cols; col++ {
-			isWinCell := false
-			if game.gameOver && game.winner != 0 {
-				for _, cell := range game.winCells {
-					if cell[0] == row && cell[1] == col {
-						isWinCell = true
-						break
-					}
-				}
+	for r := rStart; r != rEnd; r += rStep {
+		sb.WriteString(rows[r])
+		for c := cStart; c != cEnd; c += cStep {
+			sq := chess.Square(r*8 + c)
+			p := board.Piece(sq)
+
+			bg := chessWhiteSquare
+			if (r+c)%2 == 0 {
+				bg = chessBlackSquare
 			}
 
-			if isWinCell {
-				if game.winner == 1 {
-					sb.WriteString(p1WinEmoji)
+			isSelected := game.selectedPiece != nil && *game.selectedPiece == sq
+
+			isLastMoveSrc := game.lastMove != nil && game.lastMove.S1() == sq
+			isLastMoveDst := game.lastMove != nil && game.lastMove.S2() == sq
+			isTarget := validTargets[sq]
+
+			display := bg
+
+			if isSelected {
+				display = chessSelected
+			} else if isTarget || isLastMoveSrc || isLastMoveDst {
+				if bg == chessWhiteSquare {
+					display = chessTargetWhite
 				} else {
-					sb.WriteString(p2WinEmoji)
-				}
-			} else {
-				switch game.board[row][col] {
-				case 0:
-					sb.WriteString(connect4Empty)
-				case 1:
-					sb.WriteString(p1Emoji)
-				case 2:
-					sb.WriteString(p2Emoji)
+					display = chessTargetBlack
 				}
 			}
+
+			pieceIcon := game.GetPieceIcon(p)
+			if pieceIcon != "" {
+				display = pieceIcon
+			}
+
+			sb.WriteString(display)
 		}
-		sb.WriteString("\n")
+		sb.WriteString(rows[r] + "\n")
 	}
+	sb.WriteString(corner)
 
-	sb.WriteString(header + "\n")
+	wCount, bCount := 0, 0
+	wMap := board.SquareMap()
+	for _, p := range wMap {
+		if p.Color() == chess.White {
+			wCount++
+		} else {
+			bCount++
+		}
+	}
+
+	p1Disp := fmt.Sprintf("%s <@%d>: **%d**", game.p1Icon, game.player1ID, wCount)
+	p2Disp := fmt.Sprintf("%s <@%d>: **%d**", game.p2Icon, game.player2ID, bCount)
+
+	scoreStr := fmt.Sprintf("\n-# %s | %s", p1Disp, p2Disp)
+	sb.WriteString(scoreStr)
 
 	var statusSB strings.Builder
 	if game.gameOver {
 		if statusMsg != "" {
 			statusSB.WriteString(statusMsg)
-		} else if game.winner == 0 {
-			statusSB.WriteString(fmt.Sprintf(connect4StatusDraw, game.player1ID, game.player2ID))
 		} else {
+			outcome := game.game.Outcome()
 			winnerID := game.player1ID
 			loserID := game.player2ID
 			if game.winner == 2 {
 				winnerID = game.player2ID
 				loserID = game.player1ID
 			}
-			statusSB.WriteString(fmt.Sprintf(connect4StatusWin, loserID, winnerID))
+
+			if outcome == chess.WhiteWon || outcome == chess.BlackWon {
+				statusSB.WriteString(fmt.Sprintf(chessStatusWin, loserID, winnerID))
+			} else {
+				statusSB.WriteString(fmt.Sprintf(chessStatusDraw, game.player1ID, game.player2ID))
+			}
 		}
 	} else {
-		currentPlayerID := game.player1ID
-		currentSymbol := p1Emoji
+		currentPlayer := game.player1ID
+		statusIcon := game.p1Icon
 		if game.currentTurn == 2 {
-			currentPlayerID = game.player2ID
-			currentSymbol = p2Emoji
+			currentPlayer = game.player2ID
+			statusIcon = game.p2Icon
+		}
+		statusSB.WriteString(fmt.Sprintf(chessStatusTurn, currentPlayer, statusIcon))
+
+		if game.drawOffer != 0 {
+			offerer := game.player1ID
+			if game.drawOffer == 2 {
+				offerer = game.player2ID
+			}
+			statusSB.WriteString("\n" + fmt.Sprintf(MsgGameDrawOffered, offerer))
+		}
+		if statusMsg != "" {
+			statusSB.WriteString("\n" + statusMsg)
+		}
+		if game.game.Position().Status() == chess.Checkmate {
+			statusSB.WriteString(" (CHECKMATE)")
+		} else if game.lastMove != nil && game.lastMove.HasTag(chess.Check) {
+			statusSB.WriteString(" (CHECK)")
+		}
+	}
+
+	var components []interface{}
+	if game.emojiBoard {
+		components = append(components, NewTextDisplay(sb.String()))
+	} else {
+		components = append(components, chessRenderBoard(game), NewTextDisplay(strings.TrimPrefix(scoreStr, "\n")))
+	}
+	if history := chessMoveHistory(game.game); history != "" {
+		components = append(components, NewTextDisplay(history))
+	}
+	if game.clock != nil {
+		turn := game.currentTurn
+		if game.gameOver {
+			turn = 0
+		}
+		components = append(components, NewTextDisplay(game.clock.render(turn, game.p1Icon, game.p2Icon)))
+	}
+	components = append(components, NewTextDisplay(statusSB.String()))
+
+	pgnBtn := discord.NewButton(discord.ButtonStyleSecondary, LabelDownloadPGN, fmt.Sprintf(CIDChessPGN, gameID), "", 0)
+	spectateBtn := discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDChessSpectate, gameID), "", 0)
+
+	if !game.gameOver {
+		seen := make(map[chess.Square]bool)
+		var pieceOpts []discord.StringSelectMenuOption
+
+		for _, m := range validMoves {
+			s1 := m.S1()
+			if !seen[s1] {
+				seen[s1] = true
+
+				label := fmt.Sprintf("%c%d", 'A'+s1.File(), int(s1.Rank())+1)
+				val := strconv.Itoa(int(s1))
+
+				p := board.Piece(s1)
+				icon := game.GetPieceIcon(p)
+
+				pieceOpts = append(pieceOpts, discord.NewStringSelectMenuOption(icon+" "+label, val))
+			}
 		}
+		sort.Slice(pieceOpts, func(i, j int) bool { return pieceOpts[i].Label < pieceOpts[j].Label })
 
-		statusSB.WriteString(fmt.Sprintf(connect4StatusTurn, currentPlayerID, currentSymbol))
+		if game.selectedPiece != nil {
+			var destOpts []discord.StringSelectMenuOption
+			for _, m := range validMoves {
+				if m.S1() == *game.selectedPiece {
+					s2 := m.S2()
+					label := fmt.Sprintf("To %c%d", 'A'+s2.File(), int(s2.Rank())+1)
+					val := strconv.Itoa(int(s2))
 
-		if statusMsg != "" {
-			statusSB.WriteString("\n" + statusMsg)
-		}
+					if m.Promo() != chess.NoPieceType && m.Promo() != chess.Queen {
+						continue
+					}
+					if m.Promo() == chess.Queen {
+						label += " (Queen)"
+					}
 
-		if game.timerEnabled {
-			expires := game.lastMoveTime.Add(game.timerDuration)
-			statusSB.WriteString(fmt.Sprintf("\n⏱️ Expires <t:%d:R>", expires.Unix()))
-		}
-	}
+					destOpts = append(destOpts, discord.NewStringSelectMenuOption(label, val))
+				}
+			}
 
-	var components []any
+			if len(destOpts) > 0 {
+				if len(destOpts) > 25 {
+					destOpts = destOpts[:25]
+				}
+				menu := discord.NewStringSelectMenu(fmt.Sprintf(CIDChessMoveTo, gameID), LabelSelectDest, destOpts...)
+				components = append(components, discord.NewActionRow(menu))
 
-	components = append(components, NewTextDisplay(statusSB.String()))
-	components = append(components, NewTextDisplay(sb.String()))
+				cancelBtn := discord.NewButton(discord.ButtonStyleSecondary, "Cancel Selection", fmt.Sprintf(CIDChessCancelSelect, gameID), "", 0)
+				components = append(components, discord.NewActionRow(cancelBtn))
+			}
+		}
 
-	if game.gameOver {
-		components = append(components, NewSeparator(true))
+		if len(pieceOpts) > 0 {
+			if len(pieceOpts) > 25 {
+				pieceOpts = pieceOpts[:25]
+			}
+			placeholder := LabelSelectPiece
+			if game.selectedPiece != nil {
+				s := *game.selectedPiece
+				placeholder = fmt.Sprintf("Selected: %c%d", 'A'+s.File(), int(s.Rank())+1)
+			}
+			menu := discord.NewStringSelectMenu(fmt.Sprintf(CIDChessSelectPiece, gameID), placeholder, pieceOpts...)
+			components = append(components, discord.NewActionRow(menu))
+		}
 
+		drawBtn := discord.NewButton(discord.ButtonStyleSecondary, LabelOfferDraw, fmt.Sprintf(CIDChessOfferDraw, gameID), "", 0)
+		if game.drawOffer != 0 {
+			drawBtn = discord.NewButton(discord.ButtonStyleSuccess, LabelAcceptDraw, fmt.Sprintf(CIDChessAcceptDraw, gameID), "", 0)
+		}
 		row := discord.NewActionRow(
-			discord.NewButton(discord.ButtonStyleSecondary, LabelPlayAgain, CIDConnect4Disabled, "", 0).WithDisabled(true),
-			discord.NewButton(discord.ButtonStyleSuccess, LabelYes, fmt.Sprintf(CIDConnect4Yes, gameID), "", 0),
-			discord.NewButton(discord.ButtonStyleDanger, LabelNo, fmt.Sprintf(CIDConnect4No, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleDanger, LabelResign, fmt.Sprintf(CIDChessForfeit, gameID), "", 0),
+			drawBtn,
+			pgnBtn,
+			spectateBtn,
 		)
 		components = append(components, row)
 	} else {
-		components = append(components, NewSeparator(true))
-
-		for i := 0; i < game.cols; i += 5 {
-			var rowButtons []discord.InteractiveComponent
-			end := min(i+5, game.cols)
-			for col := i + 1; col <= end; col++ {
-				customID := fmt.Sprintf(CIDConnect4Move, gameID, col)
-				btn := discord.NewButton(discord.ButtonStylePrimary, connect4ColumnEmojis[col-1], customID, "", 0)
-				if connect4IsColumnFull(game, col-1) {
-					btn = btn.WithDisabled(true)
-				}
-				rowButtons = append(rowButtons, btn)
-			}
-			components = append(components, discord.NewActionRow(rowButtons...))
-		}
-
-		forfeitBtn := discord.NewButton(discord.ButtonStyleDanger, LabelForfeit, fmt.Sprintf(CIDConnect4Forfeit, gameID), "", 0)
-		components = append(components, discord.NewActionRow(forfeitBtn))
+		components = append(components, discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSuccess, LabelRematch, fmt.Sprintf(CIDChessRematch, gameID), "", 0),
+			pgnBtn,
+			spectateBtn,
+		))
 	}
 
 	return NewV2Container(components...)
 }
 
-// ===========================
-// Game Logic
-// ===========================
+// chessPieceLetters are the letters drawn on pieces in the board image
+var chessPieceLetters = map[chess.PieceType]string{
+	chess.King:   "K",
+	chess.Queen:  "Q",
+	chess.Rook:   "R",
+	chess.Bishop: "B",
+	chess.Knight: "N",
+	chess.Pawn:   "P",
+}
 
-// connect4MakeMove attempts to place a piece in the specified column
-func connect4MakeMove(game *connect4Game, col int) string {
-	if game.gameOver {
-		return "Game is already over."
-	}
-	row := -1
-	for r := game.rows - 1; r >= 0; r-- {
-		if game.board[r][col] == 0 {
-			row = r
-			break
+// chessRenderBoard draws the board as a PNG from the mover's side, marking the selection, its targets, the last move and a checked king
+func chessRenderBoard(game *ChessGame) gameBoardImage {
+	reverse := game.currentTurn == 2
+	pos := game.game.Position()
+	board := pos.Board()
+
+	targets := make(map[chess.Square]bool)
+	if game.selectedPiece != nil {
+		for _, m := range game.game.ValidMoves() {
+			if m.S1() == *game.selectedPiece {
+				targets[m.S2()] = true
+			}
 		}
 	}
 
-	if row == -1 {
-		return connect4StatusFull
+	checked := chess.NoSquare
+	if game.lastMove != nil && game.lastMove.HasTag(chess.Check) {
+		for sq, p := range board.SquareMap() {
+			if p.Type() == chess.King && p.Color() == pos.Turn() {
+				checked = sq
+			}
+		}
 	}
 
-	game.board[row][col] = game.currentTurn
-	game.lastMoveTime = time.Now()
-	game.moveCount++
-
-	if won, cells := connect4CheckWin(game, row, col); won {
-		game.gameOver = true
-		game.winner = game.currentTurn
-		game.winCells = cells
+	canvas := newGameCanvas(8, 8)
+	for r := range 8 {
+		for c := range 8 {
+			dr, dc := 7-r, c
+			if reverse {
+				dr, dc = r, 7-c
+			}
+			sq := chess.Square(r*8 + c)
+			p := board.Piece(sq)
 
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
-	} else if connect4IsBoardFull(game) {
-		game.gameOver = true
-		game.winner = 0
-		game.currentTurn = 1
+			square := chessImageLight
+			if (r+c)%2 == 0 {
+				square = chessImageDark
+			}
+			canvas.fill(dr, dc, square)
+			switch {
+			case game.selectedPiece != nil && *game.selectedPiece == sq:
+				canvas.tint(dr, dc, gameImageSelected, 0.6)
+			case sq == checked:
+				canvas.tint(dr, dc, gameImageRed, 0.6)
+			case game.lastMove != nil && (game.lastMove.S1() == sq || game.lastMove.S2() == sq):
+				canvas.tint(dr, dc, gameImageLastMove, 0.5)
+			}
 
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
-	} else {
-		if game.currentTurn == 1 {
-			game.currentTurn = 2
-		} else {
-			game.currentTurn = 1
+			if p == chess.NoPiece {
+				if targets[sq] {
+					canvas.disc(dr, dc, 0.3, gameImageTarget, gameImageTarget, 0)
+				}
+				continue
+			}
+			if targets[sq] {
+				canvas.tint(dr, dc, gameImageTarget, 0.45)
+			}
+			fill, ink := gameImageWhite, gameImageBlack
+			if p.Color() == chess.Black {
+				fill, ink = gameImageBlack, gameImageWhite
+			}
+			size := 0.78
+			if p.Type() == chess.Pawn {
+				size = 0.62
+			}
+			canvas.disc(dr, dc, size, fill, ink, 2)
+			canvas.label(dr, dc, chessPieceLetters[p.Type()], 3, ink)
 		}
 	}
-
-	return ""
+	canvas.coords(gameBoardLabels(8, 'A', reverse), gameBoardLabels(8, '1', !reverse))
+	return canvas.attach()
 }
 
-// connect4CheckWin checks if the last move at (row, col) resulted in a win
-func connect4CheckWin(game *connect4Game, row, col int) (bool, [][2]int) {
-	player := game.board[row][col]
-	if player == 0 {
-		return false, nil
+// chessMoveHistory renders the most recent moves in SAN as a compact move list
+func chessMoveHistory(g *chess.Game) string {
+	moves := g.Moves()
+	if len(moves) == 0 {
+		return ""
 	}
+	positions := g.Positions()
 
-	collect := func(dr, dc int) [][2]int {
-		cells := [][2]int{{row, col}}
-		for r, c := row+dr, col+dc; r >= 0 && r < game.rows && c >= 0 && c < game.cols && game.board[r][c] == player; r, c = r+dr, c+dc {
-			cells = append(cells, [2]int{r, c})
-		}
-		for r, c := row-dr, col-dc; r >= 0 && r < game.rows && c >= 0 && c < game.cols && game.board[r][c] == player; r, c = r-dr, c-dc {
-			cells = append(cells, [2]int{r, c})
-		}
-		return cells
+	start := 0
+	if len(moves) > chessHistoryMaxPlies {
+		start = len(moves) - chessHistoryMaxPlies
 	}
 
-	dirs := [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
-	for _, d := range dirs {
-		cells := collect(d[0], d[1])
-		if len(cells) >= connect4ToWin {
-			return true, cells
+	var sb strings.Builder
+	sb.WriteString("-# 📜")
+	if start > 0 {
+		sb.WriteString(" …")
+	}
+	for i := start; i < len(moves); i++ {
+		pos := positions[i]
+		num := (pos.Ply() + 1) / 2
+		if pos.Turn() == chess.White {
+			sb.WriteString(fmt.Sprintf(" %d.", num))
+		} else if i == start {
+			sb.WriteString(fmt.Sprintf(" %d...", num))
 		}
+		sb.WriteString(" " + chess.AlgebraicNotation{}.Encode(pos, moves[i]))
 	}
+	return sb.String()
+}
 
-	return false, nil
+// chessBuildPGN renders the game as PGN with the standard tag roster filled in
+func chessBuildPGN(client bot.Client, guildID *snowflake.ID, game *ChessGame) string {
+	g := game.game
+
+	date := "????.??.??"
+	if !game.startedAt.IsZero() {
+		date = game.startedAt.Format(chessPGNDateFormat)
+	}
+	// Termination takes the PGN standard's values; checkmate, resignation and draws all end a game normally
+	termination := "unterminated"
+	if game.termination != "" {
+		termination = game.termination
+	} else if g.Outcome() != chess.NoOutcome {
+		termination = "normal"
+	}
+
+	g.AddTagPair("Event", "Casual Game")
+	g.AddTagPair("Site", "Discord")
+	g.AddTagPair("Date", date)
+	g.AddTagPair("White", chessPlayerName(client, guildID, game, 1))
+	g.AddTagPair("Black", chessPlayerName(client, guildID, game, 2))
+	g.AddTagPair("Result", g.Outcome().String())
+	g.AddTagPair("Termination", termination)
+	if root := g.GetRootMove().Position().String(); root != chess.StartingPosition().String() {
+		g.AddTagPair("SetUp", "1")
+		g.AddTagPair("FEN", root)
+	}
+	return g.String() + "\n"
 }
 
-// connect4WouldWin checks if placing a piece in col would result in a win for player
-func connect4WouldWin(game *connect4Game, col int, player int) bool {
-	row := -1
-	for r := game.rows - 1; r >= 0; r-- {
-		if game.board[r][col] == 0 {
-			row = r
-			break
+// chessPlayerName resolves a display name for the PGN header from the member cache, asking Discord only when the
+// player isn't cached, and tags the AI with its difficulty
+func chessPlayerName(client bot.Client, guildID *snowflake.ID, game *ChessGame, playerNum int) string {
+	id := game.player1ID
+	if playerNum == 2 {
+		id = game.player2ID
+	}
+
+	if guildID != nil {
+		if m, ok := client.Caches.Member(*guildID, id); ok {
+			return chessTagAI(game, playerNum, m.User.EffectiveName())
 		}
 	}
-	if row == -1 {
-		return false
+	name := id.String()
+	if u, err := client.Rest.GetUser(id); err == nil {
+		name = u.EffectiveName()
 	}
-	game.board[row][col] = player
-	res, _ := connect4CheckWin(game, row, col)
-	game.board[row][col] = 0
-	return res
-}
-
-// connect4IsColumnFull checks if a column is full
-func connect4IsColumnFull(game *connect4Game, col int) bool {
-	return game.board[0][col] != 0
+	return chessTagAI(game, playerNum, name)
 }
 
-// connect4IsBoardFull checks if the entire board is full
-func connect4IsBoardFull(game *connect4Game) bool {
-	for col := 0; col < game.cols; col++ {
-		if !connect4IsColumnFull(game, col) {
-			return false
-		}
+// chessTagAI marks the AI's name with its difficulty
+func chessTagAI(game *ChessGame, playerNum int, name string) string {
+	if game.isAI && game.aiPlayerNum == playerNum {
+		name = fmt.Sprintf("%s (AI, %s)", name, game.aiDifficulty)
 	}
-	return true
+	return name
 }
 
 // ===========================
-// AI Logic
+// Chess AI
 // ===========================
 
-// connect4MakeAIMove executes an AI move based on the configured difficulty
-func connect4MakeAIMove(client bot.Client, game *connect4Game, gameID string) {
-	activeConnect4GamesMu.Lock()
-	defer activeConnect4GamesMu.Unlock()
-
+// ChessMakeAIMove searches the current position with the local engine and plays the result
+func ChessMakeAIMove(client bot.Client, game *ChessGame, gameID string) {
+	activeChessGamesMu.Lock()
 	if game.gameOver || game.currentTurn != game.aiPlayerNum {
+		activeChessGamesMu.Unlock()
 		return
 	}
 
-	var col int
-	switch game.aiDifficulty {
-	case connect4Easy:
-		col = connect4AIRandomMove(game)
-	case connect4Normal:
-		col = connect4AINormalMove(game)
-	case connect4Hard:
-		col = connect4AIHardMove(game)
+	difficulty := game.aiDifficulty
+	fen := game.game.FEN()
+	activeChessGamesMu.Unlock()
+
+	bestMove := chessEngineSearch(fen, chessEngineConfigFor(difficulty))
+	if bestMove == "" {
+		return
+	}
+
+	activeChessGamesMu.Lock()
+	defer activeChessGamesMu.Unlock()
+
+	if game.gameOver || game.currentTurn != game.aiPlayerNum || game.game.FEN() != fen {
+		return
+	}
+
+	selectedMove, err := chess.UCINotation{}.Decode(game.game.Position(), bestMove)
+	if err != nil {
+		LogGame(MsgGameEngineBadMove, bestMove, err)
+		return
+	}
+
+	game.game.PushNotationMove(chess.UCINotation{}.Encode(game.game.Position(), selectedMove), chess.UCINotation{}, nil)
+	game.lastMove = selectedMove
+	if game.clock != nil {
+		game.clock.press(game.aiPlayerNum)
+	}
+	game.drawOffer = 0
+
+	icon := game.GetPieceIcon(game.game.Position().Board().Piece(selectedMove.S2()))
+	if game.aiPlayerNum == 1 {
+		game.p1Icon = icon
+	} else {
+		game.p2Icon = icon
+	}
+
+	game.currentTurn = 3 - game.currentTurn
+
+	outcome := game.game.Outcome()
+	if outcome != chess.NoOutcome {
+		game.gameOver = true
+		switch outcome {
+		case chess.WhiteWon:
+			game.winner = 1
+		case chess.BlackWon:
+			game.winner = 2
+		default:
+			game.winner = 0
+		}
+		chessRecordResult(game)
+
+		userActiveGameMu.Lock()
+		delete(userActiveGame, game.player1ID)
+		delete(userActiveGame, game.player2ID)
+		userActiveGameMu.Unlock()
 	}
 
-	statusMsg := connect4MakeMove(game, col)
+	chessStartClock(client, game, gameID)
+	chessPersist(game, gameID)
+
+	msg := ChessBuildMessage(game, gameID, "")
+	_, _ = gameEditBoard(client, game.channelID, game.messageID, msg)
+	gameMirror(client, game.spectate, msg)
+}
+
+// chessEngineConfig controls how hard the local engine thinks for a difficulty
+type chessEngineConfig struct {
+	depth      int  // Maximum iterative deepening depth (plies)
+	nodes      int  // Node budget per move; the deepest iteration finished within it is played (depth 1 always completes)
+	quiescence bool // Whether to extend captures past the horizon
+}
 
-	builder := connect4BuildMessage(game, gameID, statusMsg)
-	_, _ = EditContainerV2(client, game.channelID, game.messageID, builder, nil, nil)
+// chessEngineConfigs maps each difficulty to its search limits. Nodes rather than time bound the search, so the same
+// position always gets the same move however loaded the machine is
+var chessEngineConfigs = map[string]chessEngineConfig{
+	ChoiceEasy:   {depth: 1, quiescence: false},
+	ChoiceNormal: {depth: 2, nodes: 20_000, quiescence: true},
+	ChoiceHard:   {depth: 5, nodes: 150_000, quiescence: true},
+}
 
-	if !game.gameOver && game.timerEnabled {
-		connect4StartTimer(client, game, gameID, game.moveCount)
+const (
+	chessInfinity        = 1_000_000
+	chessMateScore       = 100_000
+	chessMateThreshold   = chessMateScore - 1_000
+	chessQuiescenceDepth = 4
+	// chessEngineTimeout only guards against a search running away on a starved machine; hitting it is logged
+	// because the move then depends on timing
+	chessEngineTimeout = 10 * time.Second
+)
+
+func chessEngineConfigFor(difficulty string) chessEngineConfig {
+	if cfg, ok := chessEngineConfigs[difficulty]; ok {
+		return cfg
 	}
+	return chessEngineConfigs[ChoiceNormal]
 }
 
-// connect4AIRandomMove selects a random valid column (Easy difficulty)
-func connect4AIRandomMove(game *connect4Game) int {
-	var valid []int
-	for c := 0; c < game.cols; c++ {
-		if !connect4IsColumnFull(game, c) {
-			valid = append(valid, c)
-		}
+// Piece-square tables from white's point of view, rank 8 first (simplified evaluation function)
+var (
+	chessPawnTable = [64]int{
+		0, 0, 0, 0, 0, 0, 0, 0,
+		50, 50, 50, 50, 50, 50, 50, 50,
+		10, 10, 20, 30, 30, 20, 10, 10,
+		5, 5, 10, 25, 25, 10, 5, 5,
+		0, 0, 0, 20, 20, 0, 0, 0,
+		5, -5, -10, 0, 0, -10, -5, 5,
+		5, 10, 10, -20, -20, 10, 10, 5,
+		0, 0, 0, 0, 0, 0, 0, 0,
+	}
+	chessKnightTable = [64]int{
+		-50, -40, -30, -30, -30, -30, -40, -50,
+		-40, -20, 0, 0, 0, 0, -20, -40,
+		-30, 0, 10, 15, 15, 10, 0, -30,
+		-30, 5, 15, 20, 20, 15, 5, -30,
+		-30, 0, 15, 20, 20, 15, 0, -30,
+		-30, 5, 10, 15, 15, 10, 5, -30,
+		-40, -20, 0, 5, 5, 0, -20, -40,
+		-50, -40, -30, -30, -30, -30, -40, -50,
+	}
+	chessBishopTable = [64]int{
+		-20, -10, -10, -10, -10, -10, -10, -20,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-10, 0, 5, 10, 10, 5, 0, -10,
+		-10, 5, 5, 10, 10, 5, 5, -10,
+		-10, 0, 10, 10, 10, 10, 0, -10,
+		-10, 10, 10, 10, 10, 10, 10, -10,
+		-10, 5, 0, 0, 0, 0, 5, -10,
+		-20, -10, -10, -10, -10, -10, -10, -20,
+	}
+	chessRookTable = [64]int{
+		0, 0, 0, 0, 0, 0, 0, 0,
+		5, 10, 10, 10, 10, 10, 10, 5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		0, 0, 0, 5, 5, 0, 0, 0,
+	}
+	chessQueenTable = [64]int{
+		-20, -10, -10, -5, -5, -10, -10, -20,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-10, 0, 5, 5, 5, 5, 0, -10,
+		-5, 0, 5, 5, 5, 5, 0, -5,
+		0, 0, 5, 5, 5, 5, 0, -5,
+		-10, 5, 5, 5, 5, 5, 0, -10,
+		-10, 0, 5, 0, 0, 0, 0, -10,
+		-20, -10, -10, -5, -5, -10, -10, -20,
+	}
+	chessKingMiddleTable = [64]int{
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-20, -30, -30, -40, -40, -30, -30, -20,
+		-10, -20, -20, -20, -20, -20, -20, -10,
+		20, 20, 0, 0, 0, 0, 20, 20,
+		20, 30, 10, 0, 0, 10, 30, 20,
+	}
+	chessKingEndTable = [64]int{
+		-50, -40, -30, -20, -20, -30, -40, -50,
+		-30, -20, -10, 0, 0, -10, -20, -30,
+		-30, -10, 20, 30, 30, 20, -10, -30,
+		-30, -10, 30, 40, 40, 30, -10, -30,
+		-30, -10, 30, 40, 40, 30, -10, -30,
+		-30, -10, 20, 30, 30, 20, -10, -30,
+		-30, -30, 0, 0, 0, 0, -30, -30,
+		-50, -30, -30, -30, -30, -30, -30, -50,
 	}
-	if len(valid) == 0 {
-		return 0
+)
+
+func chessPieceValue(t chess.PieceType) int {
+	switch t {
+	case chess.Pawn:
+		return 100
+	case chess.Knight:
+		return 320
+	case chess.Bishop:
+		return 330
+	case chess.Rook:
+		return 500
+	case chess.Queen:
+		return 900
+	case chess.King:
+		return 20000
 	}
-	return valid[rand.Intn(len(valid))]
+	return 0
 }
 
-// connect4AINormalMove tries to win, then block, then random (Normal difficulty)
-func connect4AINormalMove(game *connect4Game) int {
-	ai := game.aiPlayerNum
-	opponent := 3 - ai
+// chessEvaluate scores a position in centipawns from the side to move's point of view
+func chessEvaluate(pos *chess.Position) int {
+	board := pos.Board()
 
-	for c := 0; c < game.cols; c++ {
-		if !connect4IsColumnFull(game, c) && connect4WouldWin(game, c, ai) {
-			return c
+	nonPawnMaterial := 0
+	for sq := chess.A1; sq <= chess.H8; sq++ {
+		t := board.Piece(sq).Type()
+		if t != chess.Pawn && t != chess.King {
+			nonPawnMaterial += chessPieceValue(t)
 		}
 	}
-	for c := 0; c < game.cols; c++ {
-		if !connect4IsColumnFull(game, c) && connect4WouldWin(game, c, opponent) {
-			return c
+	endgame := nonPawnMaterial <= 2*chessPieceValue(chess.Rook)+2*chessPieceValue(chess.Bishop)
+
+	score := 0
+	for sq := chess.A1; sq <= chess.H8; sq++ {
+		p := board.Piece(sq)
+		if p == chess.NoPiece {
+			continue
 		}
-	}
-	return connect4AIRandomMove(game)
-}
 
-func connect4AIHardMove(game *connect4Game) int {
-	ai := game.aiPlayerNum
-	opponent := 3 - ai
+		idx := (7-int(sq.Rank()))*8 + int(sq.File())
+		if p.Color() == chess.Black {
+			idx = int(sq.Rank())*8 + int(sq.File())
+		}
+
+		v := chessPieceValue(p.Type())
+		switch p.Type() {
+		case chess.Pawn:
+			v += chessPawnTable[idx]
+		case chess.Knight:
+			v += chessKnightTable[idx]
+		case chess.Bishop:
+			v += chessBishopTable[idx]
+		case chess.Rook:
+			v += chessRookTable[idx]
+		case chess.Queen:
+			v += chessQueenTable[idx]
+		case chess.King:
+			if endgame {
+				v += chessKingEndTable[idx]
+			} else {
+				v += chessKingMiddleTable[idx]
+			}
+		}
 
-	for c := 0; c < game.cols; c++ {
-		if !connect4IsColumnFull(game, c) && connect4WouldWin(game, c, ai) {
-			return c
+		if p.Color() == chess.White {
+			score += v
+		} else {
+			score -= v
 		}
 	}
 
-	for c := 0; c < game.cols; c++ {
-		if !connect4IsColumnFull(game, c) && connect4WouldWin(game, c, opponent) {
-			return c
-		}
+	if pos.Turn() == chess.Black {
+		return -score
 	}
+	return score
+}
 
-	bestScore := -1000000
-	bestCol := -1
+// chessEngine is a depth-limited alpha-beta searcher over corentings/chess positions
+type chessEngine struct {
+	cfg      chessEngineConfig
+	deadline time.Time
+	limit    int
+	nodes    int
+	aborted  bool
+	timedOut bool
+}
 
-	cols := make([]int, game.cols)
-	for i := range cols {
-		cols[i] = i
+// chessAIAcceptsDraw takes a draw unless the engine thinks the AI is better
+func chessAIAcceptsDraw(game *ChessGame) bool {
+	pos := game.game.Position()
+	score := chessEvaluate(pos)
+	if (pos.Turn() == chess.White) != (game.aiPlayerNum == 1) {
+		score = -score
 	}
-	rand.Shuffle(len(cols), func(i, j int) { cols[i], cols[j] = cols[j], cols[i] })
+	return score <= 0
+}
 
-	for _, c := range cols {
-		if connect4IsColumnFull(game, c) {
-			continue
+// chessEngineSearch returns the best move for the side to move in UCI notation, or "" if there is none
+func chessEngineSearch(fen string, cfg chessEngineConfig) string {
+	opt, err := chess.FEN(fen)
+	if err != nil {
+		return ""
+	}
+	pos := chess.NewGame(opt).Position()
+
+	moves := pos.ValidMoves()
+	if len(moves) == 0 {
+		return ""
+	}
+
+	e := &chessEngine{cfg: cfg}
+	e.orderMoves(pos, moves)
+
+	start := time.Now()
+	best := moves[0]
+	depth := 1
+	for ; depth <= max(cfg.depth, 1); depth++ {
+		if depth > 1 {
+			e.limit = cfg.nodes
+			e.deadline = start.Add(chessEngineTimeout)
 		}
 
-		row := -1
-		for r := game.rows - 1; r >= 0; r-- {
-			if game.board[r][c] == 0 {
-				row = r
+		alpha := -chessInfinity
+		bestIdx := -1
+		for i := range moves {
+			score := -e.negamax(pos.Update(&moves[i]), depth-1, -chessInfinity, -alpha, 1)
+			if e.aborted {
 				break
 			}
-		}
-
-		if row > 0 {
-			game.board[row][c] = ai
-			if connect4WouldWin(game, c, opponent) {
-				game.board[row][c] = 0
-				continue
+			if bestIdx == -1 || score > alpha {
+				alpha = score
+				bestIdx = i
 			}
-			game.board[row][c] = 0
 		}
-		game.board[row][c] = ai
-		score := connect4ScorePosition(game, ai)
-		game.board[row][c] = 0
+		if e.aborted || bestIdx == -1 {
+			break
+		}
 
-		if score > bestScore {
-			bestScore = score
-			bestCol = c
+		best = moves[bestIdx]
+		// Search the previous best move first on the next iteration
+		copy(moves[1:bestIdx+1], moves[:bestIdx])
+		moves[0] = best
+
+		if alpha >= chessMateThreshold {
+			break
 		}
 	}
-
-	if bestCol != -1 {
-		return bestCol
+	if e.timedOut {
+		LogGame(MsgGameEngineTimeout, chessEngineTimeout, depth, cfg.depth, fen)
 	}
 
-	return connect4AIRandomMove(game)
+	return chess.UCINotation{}.Encode(pos, &best)
 }
 
-func connect4ScorePosition(game *connect4Game, player int) int {
-	score := 0
+func (e *chessEngine) expired() bool {
+	e.nodes++
+	switch {
+	case e.aborted:
+	case e.limit > 0 && e.nodes > e.limit:
+		e.aborted = true
+	case !e.deadline.IsZero() && e.nodes&255 == 0 && time.Now().After(e.deadline):
+		e.aborted, e.timedOut = true, true
+	}
+	return e.aborted
+}
 
-	centerCol := game.cols / 2
-	for r := 0; r < game.rows; r++ {
-		if game.board[r][centerCol] == player {
-			score += 3
-		}
+func (e *chessEngine) negamax(pos *chess.Position, depth, alpha, beta, ply int) int {
+	if e.expired() {
+		return 0
 	}
 
-	for r := 0; r < game.rows; r++ {
-		for c := 0; c < game.cols-3; c++ {
-			window := []int{game.board[r][c], game.board[r][c+1], game.board[r][c+2], game.board[r][c+3]}
-			score += connect4EvaluateWindow(window, player)
+	moves := pos.ValidMoves()
+	if len(moves) == 0 {
+		if pos.Status() == chess.Checkmate {
+			return -chessMateScore + ply
 		}
+		return 0
 	}
-	for c := 0; c < game.cols; c++ {
-		for r := 0; r < game.rows-3; r++ {
-			window := []int{game.board[r][c], game.board[r+1][c], game.board[r+2][c], game.board[r+3][c]}
-			score += connect4EvaluateWindow(window, player)
-		}
+	if pos.HalfMoveClock() >= 100 {
+		return 0
 	}
-	for r := 0; r < game.rows-3; r++ {
-		for c := 0; c < game.cols-3; c++ {
-			window := []int{game.board[r][c], game.board[r+1][c+1], game.board[r+2][c+2], game.board[r+3][c+3]}
-			score += connect4EvaluateWindow(window, player)
+
+	if depth <= 0 {
+		if e.cfg.quiescence {
+			return e.quiesce(pos, moves, alpha, beta, ply, chessQuiescenceDepth)
 		}
+		return chessEvaluate(pos)
 	}
-	for r := 0; r < game.rows-3; r++ {
-		for c := 0; c < game.cols-3; c++ {
-			window := []int{game.board[r+3][c], game.board[r+2][c+1], game.board[r+1][c+2], game.board[r][c+3]}
-			score += connect4EvaluateWindow(window, player)
+
+	e.orderMoves(pos, moves)
+	for i := range moves {
+		score := -e.negamax(pos.Update(&moves[i]), depth-1, -beta, -alpha, ply+1)
+		if e.aborted {
+			return 0
+		}
+		if score >= beta {
+			return beta
+		}
+		if score > alpha {
+			alpha = score
 		}
 	}
-
-	return score
+	return alpha
 }
 
-func connect4EvaluateWindow(window []int, player int) int {
-	score := 0
-	pieceCount := 0
-	emptyCount := 0
-	oppCount := 0
-	opponent := 3 - player
-
-	for _, cell := range window {
-		switch cell {
-		case player:
-			pieceCount++
-		case 0:
-			emptyCount++
-		case opponent:
-			oppCount++
-		}
+// quiesce keeps searching captures and promotions so the evaluation isn't taken mid-exchange
+func (e *chessEngine) quiesce(pos *chess.Position, moves []chess.Move, alpha, beta, ply, depth int) int {
+	standPat := chessEvaluate(pos)
+	if standPat >= beta {
+		return beta
 	}
-
-	if pieceCount == 4 {
-		score += 100
-	} else if pieceCount == 3 && emptyCount == 1 {
-		score += 5
-	} else if pieceCount == 2 && emptyCount == 2 {
-		score += 2
+	if standPat > alpha {
+		alpha = standPat
 	}
-
-	if oppCount == 3 && emptyCount == 1 {
-		score -= 4
+	if depth == 0 {
+		return alpha
 	}
 
-	return score
-}
-
-// ===========================
-// UI Utilities
-// ===========================
-
-func connect4GetHeader(cols int) string {
-	var sb strings.Builder
-	for i := range cols {
-		sb.WriteString(connect4ColumnEmojis[i])
+	var tactical []chess.Move
+	for _, m := range moves {
+		if m.HasTag(chess.Capture) || m.HasTag(chess.EnPassant) || m.Promo() != chess.NoPieceType {
+			tactical = append(tactical, m)
+		}
 	}
-	return sb.String()
-}
+	e.orderMoves(pos, tactical)
 
-func connect4DisableInteractive(comp discord.LayoutComponent) discord.LayoutComponent {
-	switch c := comp.(type) {
-	case discord.ContainerComponent:
-		for i, sub := range c.Components {
-			c.Components[i] = connect4DisableSub(sub)
+	for i := range tactical {
+		if e.expired() {
+			return 0
 		}
-		return c
-	case *discord.ContainerComponent:
-		for i, sub := range c.Components {
-			c.Components[i] = connect4DisableSub(sub)
+
+		next := pos.Update(&tactical[i])
+		replies := next.ValidMoves()
+		var score int
+		if len(replies) == 0 {
+			if next.Status() == chess.Checkmate {
+				score = chessMateScore - (ply + 1)
+			}
+		} else {
+			score = -e.quiesce(next, replies, -beta, -alpha, ply+1, depth-1)
 		}
-		return c
-	case discord.ActionRowComponent:
-		for i, inter := range c.Components {
-			c.Components[i] = connect4DisableInter(inter)
+		if e.aborted {
+			return 0
 		}
-		return c
-	case *discord.ActionRowComponent:
-		for i, inter := range c.Components {
-			c.Components[i] = connect4DisableInter(inter)
+
+		if score >= beta {
+			return beta
+		}
+		if score > alpha {
+			alpha = score
 		}
-		return c
 	}
-	return comp
+	return alpha
 }
 
-func connect4DisableSub(sub discord.ContainerSubComponent) discord.ContainerSubComponent {
-	switch s := sub.(type) {
-	case discord.ActionRowComponent:
-		for i, inter := range s.Components {
-			s.Components[i] = connect4DisableInter(inter)
+// orderMoves sorts promising moves (captures by MVV-LVA, promotions, checks) to the front
+func (e *chessEngine) orderMoves(pos *chess.Position, moves []chess.Move) {
+	board := pos.Board()
+	score := func(m *chess.Move) int {
+		s := 0
+		if m.HasTag(chess.Capture) || m.HasTag(chess.EnPassant) {
+			victim := board.Piece(m.S2()).Type()
+			if victim == chess.NoPieceType {
+				victim = chess.Pawn
+			}
+			s += 10_000 + 10*chessPieceValue(victim) - chessPieceValue(board.Piece(m.S1()).Type())/10
 		}
-		return s
-	case *discord.ActionRowComponent:
-		for i, inter := range s.Components {
-			s.Components[i] = connect4DisableInter(inter)
+		if m.Promo() != chess.NoPieceType {
+			s += 9_000 + chessPieceValue(m.Promo())
+		}
+		if m.HasTag(chess.Check) {
+			s += 500
 		}
 		return s
 	}
-	return sub
-}
-
-func connect4DisableInter(inter discord.InteractiveComponent) discord.InteractiveComponent {
-	switch i := inter.(type) {
-	case discord.ButtonComponent:
-		i.Disabled = true
-		return i
-	case *discord.ButtonComponent:
-		i.Disabled = true
-		return i
-	}
-	return inter
+	sort.SliceStable(moves, func(i, j int) bool { return score(&moves[i]) > score(&moves[j]) })
 }
 
 // ===========================
-// Checkers Constants & Types
+// Othello Game Constants & Types
 // ===========================
 
 const (
-	checkersRows = 8
-	checkersCols = 8
-
-	checkersEmpty         = "⬛"
-	checkersWhiteTile     = "⬜"
-	checkersTarget        = "🔲"
-	checkersP1Piece       = "🔴"
-	checkersP2Piece       = "🔵"
-	checkersP1King        = "❤️"
-	checkersP2King        = "💙"
-	checkersP1Highlight   = "🟥"
-	checkersP2Highlight   = "🟦"
-	checkersStatusTurn    = MsgGameTurn
-	checkersStatusWin     = MsgGameWin
-	checkersStatusDraw    = MsgGameDraw
-	checkersStatusForfeit = MsgGameForfeitSuccess
-)
-
-type CheckersPieceType int
-
-const (
-	PieceNone CheckersPieceType = iota
-	PieceP1
-	PieceP2
-	PieceP1King
-	PieceP2King
+	othelloSize  = 8
+	othelloBlack = "⚫"
+	othelloWhite = "⚪"
+	othelloEmpty = "🟩"
+	othelloHint  = "❇️"
 )
 
-type CheckersGame struct {
-	board         [checkersRows][checkersCols]CheckersPieceType
-	player1ID     snowflake.ID
-	player2ID     snowflake.ID
-	isAI          bool
-	aiDifficulty  string
-	aiPlayerNum   int
-	colorVariant  GameColorVariant
-	currentTurn   int
-	gameOver      bool
-	winner        int
-	moveCount     int
-	lastMoveTime  time.Time
-	messageID     snowflake.ID
-	channelID     snowflake.ID
-	selectedPiece *[2]int
-	lastMoveDest  *[2]int
+var (
+	othelloImageBoard = color.RGBA{0x2E, 0x7D, 0x32, 0xFF}
+	othelloImageLine  = color.RGBA{0x1B, 0x5E, 0x20, 0xFF}
+	othelloImageRim   = color.RGBA{0x9E, 0x9E, 0x9E, 0xFF}
+)
+
+// othelloDirections are the eight lines a placed disc can flank along
+var othelloDirections = [8][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+
+// othelloWeights scores each square for the engine: corners are gold, the squares handing them over are poison
+var othelloWeights = othelloBoard{
+	{100, -20, 10, 5, 5, 10, -20, 100},
+	{-20, -50, -2, -2, -2, -2, -50, -20},
+	{10, -2, 1, 1, 1, 1, -2, 10},
+	{5, -2, 1, 0, 0, 1, -2, 5},
+	{5, -2, 1, 0, 0, 1, -2, 5},
+	{10, -2, 1, 1, 1, 1, -2, 10},
+	{-20, -50, -2, -2, -2, -2, -50, -20},
+	{100, -20, 10, 5, 5, 10, -20, 100},
+}
+
+// othelloBoard holds 0 for an empty square, 1 for a black disc (player 1) and 2 for a white disc (player 2)
+type othelloBoard [othelloSize][othelloSize]int
+
+// othelloGame represents an Othello (Reversi) session; player 1 plays black and moves first
+type othelloGame struct {
+	board        othelloBoard
+	player1ID    snowflake.ID
+	player2ID    snowflake.ID
+	isAI         bool
+	aiDifficulty string
+	aiPlayerNum  int
+	currentTurn  int
+	gameOver     bool
+	winner       int
+	moveCount    int
+	lastMoveTime time.Time
+	messageID    snowflake.ID
+	channelID    snowflake.ID
+	lastMove     *[2]int
+	flipped      [][2]int
+	originalP1ID snowflake.ID
+	originalP2ID snowflake.ID
+	spectate     *gameSpectate
+	emojiBoard   bool
+}
+
+// othelloSnapshot is the serialized form of an othelloGame stored in the database
+type othelloSnapshot struct {
+	Board        othelloBoard  `json:"board"`
+	Player1ID    snowflake.ID  `json:"player1_id"`
+	Player2ID    snowflake.ID  `json:"player2_id"`
+	IsAI         bool          `json:"is_ai"`
+	AIDifficulty string        `json:"ai_difficulty"`
+	AIPlayerNum  int           `json:"ai_player_num"`
+	CurrentTurn  int           `json:"current_turn"`
+	MoveCount    int           `json:"move_count"`
+	LastMoveTime time.Time     `json:"last_move_time"`
+	MessageID    snowflake.ID  `json:"message_id"`
+	ChannelID    snowflake.ID  `json:"channel_id"`
+	LastMove     *[2]int       `json:"last_move,omitempty"`
+	Flipped      [][2]int      `json:"flipped,omitempty"`
+	OriginalP1ID snowflake.ID  `json:"original_p1_id,omitempty"`
+	OriginalP2ID snowflake.ID  `json:"original_p2_id,omitempty"`
+	Spectate     *gameSpectate `json:"spectate,omitempty"`
+	EmojiBoard   bool          `json:"emoji_board,omitempty"`
 }
 
 // ===========================
-// Checkers Logic - Core
+// Othello Logic - Core
 // ===========================
 
-func NewCheckersGame(p1, p2 snowflake.ID, isAI bool, aiPlayerNum int, difficulty string) *CheckersGame {
-	variant := VariantStandard
-	if rand.Intn(2) == 1 {
-		variant = VariantInverted
-	}
-	game := &CheckersGame{
+func newOthelloGame(p1, p2 snowflake.ID, isAI bool, aiPlayerNum int, difficulty string) *othelloGame {
+	game := &othelloGame{
 		player1ID:    p1,
 		player2ID:    p2,
 		isAI:         isAI,
 		aiPlayerNum:  aiPlayerNum,
 		aiDifficulty: difficulty,
-		colorVariant: variant,
 		currentTurn:  1,
 		lastMoveTime: time.Now(),
 	}
-	game.InitBoard()
+	mid := othelloSize / 2
+	game.board[mid-1][mid-1], game.board[mid][mid] = 2, 2
+	game.board[mid-1][mid], game.board[mid][mid-1] = 1, 1
 	return game
 }
 
-func (g *CheckersGame) InitBoard() {
-	for r := range checkersRows {
-		for c := range checkersCols {
-			if (r+c)%2 == 1 {
-				if r < 3 {
-					g.board[r][c] = PieceP2
-				} else if r > 4 {
-					g.board[r][c] = PieceP1
-				} else {
-					g.board[r][c] = PieceNone
-				}
-			} else {
-				g.board[r][c] = PieceNone
-			}
-		}
+// othelloRecordResult feeds a finished game into the rating system
+func othelloRecordResult(game *othelloGame) {
+	aiPlayer := 0
+	if game.isAI {
+		aiPlayer = game.aiPlayerNum
+	}
+	gameRecordResult(CmdOthello, game.player1ID, game.player2ID, game.winner, aiPlayer, game.aiDifficulty)
+}
+
+// othelloPersist saves the game state, or forgets it once the game is over (caller must hold activeOthelloGamesMu)
+func othelloPersist(game *othelloGame, gameID string) {
+	if game.gameOver {
+		gameForget(gameID)
+		return
+	}
+	gameSaveState(gameID, CmdOthello, othelloSnapshot{
+		Board:        game.board,
+		Player1ID:    game.player1ID,
+		Player2ID:    game.player2ID,
+		IsAI:         game.isAI,
+		AIDifficulty: game.aiDifficulty,
+		AIPlayerNum:  game.aiPlayerNum,
+		CurrentTurn:  game.currentTurn,
+		MoveCount:    game.moveCount,
+		LastMoveTime: game.lastMoveTime,
+		MessageID:    game.messageID,
+		ChannelID:    game.channelID,
+		LastMove:     game.lastMove,
+		Flipped:      game.flipped,
+		OriginalP1ID: game.originalP1ID,
+		OriginalP2ID: game.originalP2ID,
+		Spectate:     game.spectate,
+		EmojiBoard:   game.emojiBoard,
+	})
+}
+
+// othelloRestore rebuilds a saved game and resumes a pending AI turn
+func othelloRestore(client bot.Client, gameID string, state string) error {
+	var snap othelloSnapshot
+	if err := json.Unmarshal([]byte(state), &snap); err != nil {
+		return err
+	}
+	if snap.CurrentTurn != 1 && snap.CurrentTurn != 2 {
+		return fmt.Errorf("invalid turn %d", snap.CurrentTurn)
+	}
+
+	game := &othelloGame{
+		board:        snap.Board,
+		player1ID:    snap.Player1ID,
+		player2ID:    snap.Player2ID,
+		isAI:         snap.IsAI,
+		aiDifficulty: snap.AIDifficulty,
+		aiPlayerNum:  snap.AIPlayerNum,
+		currentTurn:  snap.CurrentTurn,
+		moveCount:    snap.MoveCount,
+		lastMoveTime: snap.LastMoveTime,
+		messageID:    snap.MessageID,
+		channelID:    snap.ChannelID,
+		lastMove:     snap.LastMove,
+		flipped:      snap.Flipped,
+		originalP1ID: snap.OriginalP1ID,
+		originalP2ID: snap.OriginalP2ID,
+		spectate:     snap.Spectate,
+		emojiBoard:   snap.EmojiBoard,
+	}
+
+	activeOthelloGamesMu.Lock()
+	activeOthelloGames[gameID] = game
+	activeOthelloGamesMu.Unlock()
+
+	gameLockPlayers(client, gameID, game.player1ID, game.player2ID)
+
+	if game.isAI && game.currentTurn == game.aiPlayerNum {
+		time.AfterFunc(1*time.Second, func() {
+			othelloMakeAIMove(client, game, gameID)
+		})
 	}
+	return nil
 }
 
 // ===========================
-// Checkers Interaction Handlers
+// Othello Interaction Handlers
 // ===========================
 
-func HandlePlayCheckers(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+func handlePlayOthello(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
 	defer func() {
 		if r := recover(); r != nil {
-			LogError("Panic in HandlePlayCheckers: %v", r)
+			LogError("Panic in handlePlayOthello: %v", r)
 			fmt.Printf("%s\n", debug.Stack())
 		}
 	}()
 
-	var opponentID *snowflake.ID
+	p1 := event.User().ID
+	p2 := event.ApplicationID()
 	isAI := true
-	var p2 snowflake.ID
-
-	if opponent, ok := data.OptUser("opponent"); ok {
-		opponentID = &opponent.ID
+	if opponent, ok := data.OptUser(OptOpponent); ok {
+		p2 = opponent.ID
 		isAI = opponent.Bot
 	}
-
-	difficulty := "normal"
-	if diff, ok := data.OptString("difficulty"); ok {
-		difficulty = diff
-	}
-
-	appID := event.ApplicationID()
-	p1 := event.User().ID
-
-	userActiveGameMu.Lock()
-	if gid, ok := userActiveGame[p1]; ok {
-		userActiveGameMu.Unlock()
-		event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameAlreadyActive, gid)).WithEphemeral(true))
+	if p2 == p1 {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameChallengeSelf).WithEphemeral(true))
 		return
 	}
-	if opponentID != nil && *opponentID != appID {
-		if gid, ok := userActiveGame[*opponentID]; ok {
-			userActiveGameMu.Unlock()
-			event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameOpponentActive, *opponentID, gid)).WithEphemeral(true))
-			return
-		}
-	}
-
-	cid := event.Channel().ID()
-	gameID := fmt.Sprintf(CIDCheckersGameID, cid, time.Now().UnixNano())
 
-	userActiveGame[p1] = gameID
-	if opponentID != nil && *opponentID != appID {
-		userActiveGame[*opponentID] = gameID
+	difficulty := ChoiceNormal
+	if diff, ok := data.OptString(OptDifficulty); ok {
+		difficulty = diff
 	}
-	userActiveGameMu.Unlock()
 
-	if opponentID != nil {
-		p2 = *opponentID
-		if p2 == appID {
-			isAI = true
-		} else {
-			isAI = false
-		}
-	} else {
-		p2 = appID
-		isAI = true
+	gameID := fmt.Sprintf(CIDOthelloGameID, event.Channel().ID(), time.Now().UnixNano())
+	if errMsg := gameReservePlayers(*event.Client(), gameID, p1, p2); errMsg != "" {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+		return
 	}
 
-	var player1, player2 snowflake.ID
-	if rand.Intn(2) == 0 {
-		player1 = p1
-		player2 = p2
-	} else {
-		player1 = p2
-		player2 = p1
+	player1, player2 := p1, p2
+	if rand.Intn(2) == 1 {
+		player1, player2 = p2, p1
 	}
-
 	aiPlayerNum := 0
 	if isAI {
-		if player1 == appID || (opponentID != nil && player1 == *opponentID && isAI) {
-			aiPlayerNum = 1
-		} else {
+		aiPlayerNum = 1
+		if player2 == p2 {
 			aiPlayerNum = 2
 		}
 	}
 
-	game := NewCheckersGame(player1, player2, isAI, aiPlayerNum, difficulty)
+	game := newOthelloGame(player1, player2, isAI, aiPlayerNum, difficulty)
+	game.originalP1ID = p1
+	game.originalP2ID = p2
+	game.emojiBoard = gameParseEmojiBoard(data)
 
-	activeCheckersGamesMu.Lock()
-	activeCheckersGames[gameID] = game
-	activeCheckersGamesMu.Unlock()
+	othelloOpen(*event.Client(), event, game, gameID)
+}
 
-	msg := CheckersBuildMessage(game, gameID, "")
-	if err := RespondInteractionContainerV2(*event.Client(), event, msg, false); err != nil {
-		LogError("Failed to send checkers message: %v", err)
-		activeCheckersGamesMu.Lock()
-		delete(activeCheckersGames, gameID)
-		activeCheckersGamesMu.Unlock()
+// othelloOpen posts a new game: PvP games wait for the opponent to accept, AI games start immediately
+func othelloOpen(client bot.Client, interaction discord.Interaction, game *othelloGame, gameID string) {
+	if !game.isAI {
+		gameIssueChallenge(client, interaction, gameID, &gameChallenge{
+			gameType:     CmdOthello,
+			challengerID: game.originalP1ID,
+			opponentID:   game.originalP2ID,
+			launch: func(client bot.Client, channelID, messageID snowflake.ID) Container {
+				return othelloLaunch(client, game, gameID, channelID, messageID)
+			},
+		})
+		return
+	}
 
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
+	activeOthelloGamesMu.Lock()
+	activeOthelloGames[gameID] = game
+	activeOthelloGamesMu.Unlock()
+
+	msg := othelloBuildMessage(game, gameID, "")
+	if err := gameRespondBoard(client, interaction, msg); err != nil {
+		activeOthelloGamesMu.Lock()
+		delete(activeOthelloGames, gameID)
+		activeOthelloGamesMu.Unlock()
+
+		gameReleasePlayers(game.player1ID, game.player2ID)
 		return
 	}
 
-	resp, err := event.Client().Rest.GetInteractionResponse(event.ApplicationID(), event.Token())
-	if err == nil && resp != nil {
-		game.messageID = resp.ID
-		game.channelID = cid
-	} else {
-		LogError("Failed to fetch interaction response: %v", err)
+	var messageID snowflake.ID
+	if resp, err := client.Rest.GetInteractionResponse(interaction.ApplicationID(), interaction.Token()); err == nil && resp != nil {
+		messageID = resp.ID
 	}
+	othelloLaunch(client, game, gameID, interaction.Channel().ID(), messageID)
+}
+
+// othelloLaunch attaches a game to its message, starts its AI and returns the opening board
+func othelloLaunch(client bot.Client, game *othelloGame, gameID string, channelID, messageID snowflake.ID) Container {
+	activeOthelloGamesMu.Lock()
+	activeOthelloGames[gameID] = game
+	game.channelID = channelID
+	game.messageID = messageID
+	game.lastMoveTime = time.Now()
+	othelloPersist(game, gameID)
+	msg := othelloBuildMessage(game, gameID, "")
+	activeOthelloGamesMu.Unlock()
 
 	if game.isAI && game.aiPlayerNum == 1 {
 		time.AfterFunc(1*time.Second, func() {
-			CheckersMakeAIMove(*event.Client(), game, gameID)
+			othelloMakeAIMove(client, game, gameID)
 		})
 	}
+	return msg
 }
 
-func HandleCheckersInteraction(event *events.ComponentInteractionCreate) {
+// othelloRematch sets up a fresh game between the same players with colours swapped, requested by userID
+func othelloRematch(game *othelloGame, userID snowflake.ID) *othelloGame {
+	aiPlayerNum := 0
+	if game.isAI {
+		aiPlayerNum = 3 - game.aiPlayerNum
+	}
+	next := newOthelloGame(game.player2ID, game.player1ID, game.isAI, aiPlayerNum, game.aiDifficulty)
+	next.emojiBoard = game.emojiBoard
+	next.originalP1ID = userID
+	next.originalP2ID = game.player1ID
+	if userID == game.player1ID {
+		next.originalP2ID = game.player2ID
+	}
+	return next
+}
+
+func othelloHandleInteraction(event *events.ComponentInteractionCreate) {
 	defer func() {
 		if r := recover(); r != nil {
-			LogError("Panic in HandleCheckersInteraction: %v", r)
+			LogError("Panic in othelloHandleInteraction: %v", r)
 			fmt.Printf("%s\n", debug.Stack())
 		}
 	}()
@@ -1453,979 +6479,1374 @@ func HandleCheckersInteraction(event *events.ComponentInteractionCreate) {
 	if len(parts) < 3 {
 		return
 	}
-
 	gameID := parts[1]
 	action := parts[2]
 
-	activeCheckersGamesMu.Lock()
-	game, exists := activeCheckersGames[gameID]
+	activeOthelloGamesMu.Lock()
+	game, exists := activeOthelloGames[gameID]
 	if !exists {
-		activeCheckersGamesMu.Unlock()
+		activeOthelloGamesMu.Unlock()
 		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotFound).WithEphemeral(true))
 		return
 	}
 
 	userID := event.User().ID
+	switch action {
+	case "spectate":
+		gameHandleSpectate(event, &activeOthelloGamesMu, gameID, CmdOthello, game.spectate, othelloBuildMessage(game, gameID, ""), func(s *gameSpectate) *gameSpectate {
+			if game.spectate == nil {
+				game.spectate = s
+				othelloPersist(game, gameID)
+			}
+			return game.spectate
+		})
+		return
+	case "rematch":
+		if msg := gameRematchRefusal(userID, game.gameOver, game.originalP1ID, game.originalP2ID, game.player1ID, game.player2ID); msg != "" {
+			activeOthelloGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(msg).WithEphemeral(true))
+			return
+		}
+		next := othelloRematch(game, userID)
+		activeOthelloGamesMu.Unlock()
+
+		nextID := fmt.Sprintf(CIDOthelloGameID, event.Channel().ID(), time.Now().UnixNano())
+		if errMsg := gameReservePlayers(*event.Client(), nextID, next.originalP1ID, next.originalP2ID); errMsg != "" {
+			event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+			return
+		}
+		othelloOpen(*event.Client(), event, next, nextID)
+		return
+	}
+
 	if userID != game.player1ID && userID != game.player2ID {
-		activeCheckersGamesMu.Unlock()
-		event.CreateMessage(discord.NewMessageCreate().WithContent("You are not part of this game.").WithEphemeral(true))
+		activeOthelloGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotPlayer).WithEphemeral(true))
+		return
+	}
+	if game.gameOver {
+		activeOthelloGamesMu.Unlock()
+		event.DeferUpdateMessage()
 		return
 	}
 
-	isP1 := userID == game.player1ID
-	if (game.currentTurn == 1 && !isP1) || (game.currentTurn == 2 && isP1) && action != "forfeit" {
-		activeCheckersGamesMu.Unlock()
+	player := 2
+	if userID == game.player1ID {
+		player = 1
+	}
+	if game.currentTurn != player && !gameOutOfTurnActions[action] {
+		activeOthelloGamesMu.Unlock()
 		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotTurn).WithEphemeral(true))
 		return
 	}
 
+	statusMsg := ""
 	switch action {
-	case "select_piece":
-		values := event.StringSelectMenuInteractionData().Values
-		if len(values) > 0 {
-			coords := strings.Split(values[0], ",")
-			r, _ := strconv.Atoi(coords[0])
-			c, _ := strconv.Atoi(coords[1])
-			game.selectedPiece = &[2]int{r, c}
-		}
-	case "move_to":
+	case "move", "move_more":
 		values := event.StringSelectMenuInteractionData().Values
 		if len(values) > 0 {
 			coords := strings.Split(values[0], ",")
 			r, _ := strconv.Atoi(coords[0])
 			c, _ := strconv.Atoi(coords[1])
-			if game.selectedPiece != nil {
-				CheckersMakeMove(game, game.selectedPiece[0], game.selectedPiece[1], r, c)
-				game.selectedPiece = nil
-				game.currentTurn = 3 - game.currentTurn
-				if CheckersCheckWin(game) {
-					game.gameOver = true
-					userActiveGameMu.Lock()
-					delete(userActiveGame, game.player1ID)
-					delete(userActiveGame, game.player2ID)
-					userActiveGameMu.Unlock()
-				}
-			}
+			statusMsg, _ = othelloMakeMove(game, r, c)
 		}
-	case "cancel_select":
-		game.selectedPiece = nil
 	case "forfeit":
 		game.gameOver = true
-		game.winner = 3 - game.currentTurn
-		if isP1 {
-			game.winner = 2
-		} else {
-			game.winner = 1
-		}
-		statusMsg := fmt.Sprintf(checkersStatusForfeit, userID, game.winner)
-		if userID == game.player1ID {
-			statusMsg = fmt.Sprintf(checkersStatusForfeit, game.player1ID, game.player2ID)
-		} else {
-			statusMsg = fmt.Sprintf(checkersStatusForfeit, game.player2ID, game.player1ID)
-		}
-
-		msg := CheckersBuildMessage(game, gameID, statusMsg)
-		UpdateInteractionContainerV2(*event.Client(), event, msg)
-
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
-		return
-
-	case "claim_win":
-		if !CheckersIsHopeless(game) {
-			event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameHopelessFail).WithEphemeral(true))
-			return
-		}
-
-		game.gameOver = true
-		game.winner = 1
-		if userID == game.player2ID {
-			game.winner = 2
+		game.winner = 3 - player
+		loserID, winnerID := game.player1ID, game.player2ID
+		if player == 2 {
+			loserID, winnerID = game.player2ID, game.player1ID
 		}
-
-		statusMsg := fmt.Sprintf(MsgGameClaimWinSuccess, userID)
-		msg := CheckersBuildMessage(game, gameID, statusMsg)
-
-		UpdateInteractionContainerV2(*event.Client(), event, msg)
-
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
-		return
-
+		statusMsg = fmt.Sprintf(MsgGameForfeitSuccess, loserID, winnerID)
+		othelloRecordResult(game)
+		gameReleasePlayers(game.player1ID, game.player2ID)
 	}
 
-	msg := CheckersBuildMessage(game, gameID, "")
-	activeCheckersGamesMu.Unlock()
+	msg := othelloBuildMessage(game, gameID, statusMsg)
+	othelloPersist(game, gameID)
+	activeOthelloGamesMu.Unlock()
 
-	_ = UpdateInteractionContainerV2(*event.Client(), event, msg)
+	_ = gameUpdateBoard(*event.Client(), event, msg)
+	gameMirror(*event.Client(), game.spectate, msg)
 
 	if !game.gameOver && game.isAI && game.currentTurn == game.aiPlayerNum {
 		time.AfterFunc(1*time.Second, func() {
-			CheckersMakeAIMove(*event.Client(), game, gameID)
+			othelloMakeAIMove(*event.Client(), game, gameID)
 		})
 	}
 }
 
 // ===========================
-// Checkers Logic - Mechanics
+// Othello Logic - Mechanics
 // ===========================
 
-func CheckersMakeMove(g *CheckersGame, r1, c1, r2, c2 int) {
-	piece := g.board[r1][c1]
-	g.board[r2][c2] = piece
-	g.board[r1][c1] = PieceNone
-	g.lastMoveDest = &[2]int{r2, c2}
+// othelloMakeMove places the mover's disc at r,c and hands the turn over, passing for a player left without a move;
+// it reports whether the move was legal and returns a status line when someone had to pass
+func othelloMakeMove(game *othelloGame, r, c int) (string, bool) {
+	if r < 0 || r >= othelloSize || c < 0 || c >= othelloSize {
+		return "", false
+	}
+	flips := othelloFlips(&game.board, game.currentTurn, r, c)
+	if len(flips) == 0 {
+		return "", false
+	}
+
+	othelloApply(&game.board, game.currentTurn, r, c, flips)
+	game.lastMove = &[2]int{r, c}
+	game.flipped = flips
+	game.lastMoveTime = time.Now()
+	game.moveCount++
+
+	opponent := 3 - game.currentTurn
+	switch {
+	case len(othelloLegalMoves(&game.board, opponent)) > 0:
+		game.currentTurn = opponent
+	case len(othelloLegalMoves(&game.board, game.currentTurn)) > 0:
+		passer := game.player1ID
+		if opponent == 2 {
+			passer = game.player2ID
+		}
+		return fmt.Sprintf(MsgOthelloPass, passer), true
+	default:
+		game.gameOver = true
+		black, white := othelloCount(&game.board)
+		switch {
+		case black > white:
+			game.winner = 1
+		case white > black:
+			game.winner = 2
+		default:
+			game.winner = 0
+		}
+		othelloRecordResult(game)
+		gameReleasePlayers(game.player1ID, game.player2ID)
+	}
+	return "", true
+}
 
-	dr := r2 - r1
-	dc := c2 - c1
-	if dr*dr > 1 {
-		midR := r1 + dr/2
-		midC := c1 + dc/2
-		g.board[midR][midC] = PieceNone
+// othelloFlips returns the discs that placing player's disc at r,c would turn over; none means the move is illegal
+func othelloFlips(board *othelloBoard, player, r, c int) [][2]int {
+	if board[r][c] != 0 {
+		return nil
+	}
+	var flips [][2]int
+	for _, d := range othelloDirections {
+		var line [][2]int
+		nr, nc := r+d[0], c+d[1]
+		for nr >= 0 && nr < othelloSize && nc >= 0 && nc < othelloSize && board[nr][nc] == 3-player {
+			line = append(line, [2]int{nr, nc})
+			nr, nc = nr+d[0], nc+d[1]
+		}
+		if len(line) > 0 && nr >= 0 && nr < othelloSize && nc >= 0 && nc < othelloSize && board[nr][nc] == player {
+			flips = append(flips, line...)
+		}
 	}
+	return flips
+}
 
-	if piece == PieceP1 && r2 == 0 {
-		g.board[r2][c2] = PieceP1King
-	} else if piece == PieceP2 && r2 == checkersRows-1 {
-		g.board[r2][c2] = PieceP2King
+func othelloApply(board *othelloBoard, player, r, c int, flips [][2]int) {
+	board[r][c] = player
+	for _, sq := range flips {
+		board[sq[0]][sq[1]] = player
 	}
 }
 
-func CheckersCheckWin(g *CheckersGame) bool {
-	p1Count, p2Count := 0, 0
-	for r := range checkersRows {
-		for c := range checkersCols {
-			p := g.board[r][c]
-			if p == PieceP1 || p == PieceP1King {
-				p1Count++
-			}
-			if p == PieceP2 || p == PieceP2King {
-				p2Count++
+// othelloLegalMoves lists the empty squares where player would flip at least one disc
+func othelloLegalMoves(board *othelloBoard, player int) [][2]int {
+	var moves [][2]int
+	for r := range othelloSize {
+		for c := range othelloSize {
+			if len(othelloFlips(board, player, r, c)) > 0 {
+				moves = append(moves, [2]int{r, c})
 			}
 		}
 	}
+	return moves
+}
 
-	if p1Count == 0 {
-		g.winner = 2
-		return true
-	}
-	if p2Count == 0 {
-		g.winner = 1
-		return true
-	}
-
-	validMoves1 := CheckersGetValidMoves(g, 1)
-	if len(validMoves1) == 0 {
-		g.winner = 2
-		return true
-	}
-	validMoves2 := CheckersGetValidMoves(g, 2)
-	if len(validMoves2) == 0 {
-		g.winner = 1
-		return true
+// othelloCount returns the number of black and white discs
+func othelloCount(board *othelloBoard) (int, int) {
+	black, white := 0, 0
+	for r := range othelloSize {
+		for c := range othelloSize {
+			switch board[r][c] {
+			case 1:
+				black++
+			case 2:
+				white++
+			}
+		}
 	}
-
-	return false
+	return black, white
 }
 
-// GetValidMoves returns a map of "r,c" -> list of valid target "r,c"
-func CheckersGetValidMoves(g *CheckersGame, player int) map[string][][2]int {
-	moves := make(map[string][][2]int)
-
-	for r := range checkersRows {
-		for c := range checkersCols {
-			p := g.board[r][c]
-			isP1 := p == PieceP1 || p == PieceP1King
-			isP2 := p == PieceP2 || p == PieceP2King
-
-			if (player == 1 && isP1) || (player == 2 && isP2) {
-				var dirs [][2]int
-				if p == PieceP1 {
-					dirs = [][2]int{{-1, -1}, {-1, 1}}
-				}
-				if p == PieceP2 {
-					dirs = [][2]int{{1, -1}, {1, 1}}
-				}
-				if p == PieceP1King || p == PieceP2King {
-					dirs = [][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
-				}
-
-				for _, d := range dirs {
-					targetR, targetC := r+d[0], c+d[1]
-					if CheckersIsValidPos(targetR, targetC) && g.board[targetR][targetC] == PieceNone {
-						k := fmt.Sprintf("%d,%d", r, c)
-						moves[k] = append(moves[k], [2]int{targetR, targetC})
-					}
+// ===========================
+// Othello Rendering & Helpers
+// ===========================
 
-					jumpR, jumpC := r+2*d[0], c+2*d[1]
-					if CheckersIsValidPos(jumpR, jumpC) && g.board[jumpR][jumpC] == PieceNone {
-						midR, midC := r+d[0], c+d[1]
-						midP := g.board[midR][midC]
-						isOpponent := false
-						if player == 1 && (midP == PieceP2 || midP == PieceP2King) {
-							isOpponent = true
-						}
-						if player == 2 && (midP == PieceP1 || midP == PieceP1King) {
-							isOpponent = true
-						}
+func othelloBuildMessage(game *othelloGame, gameID string, statusMsg string) Container {
+	black, white := othelloCount(&game.board)
+	scoreStr := fmt.Sprintf("-# %s <@%d>: **%d** | %s <@%d>: **%d**",
+		othelloBlack, game.player1ID, black,
+		othelloWhite, game.player2ID, white)
 
-						if isOpponent {
-							k := fmt.Sprintf("%d,%d", r, c)
-							moves[k] = append(moves[k], [2]int{jumpR, jumpC})
-						}
-					}
-				}
+	var statusSB strings.Builder
+	if game.gameOver {
+		if statusMsg != "" {
+			statusSB.WriteString(statusMsg)
+		} else if game.winner == 0 {
+			statusSB.WriteString(fmt.Sprintf(MsgGameDraw, game.player1ID, game.player2ID))
+		} else {
+			loserID, winnerID := game.player2ID, game.player1ID
+			if game.winner == 2 {
+				loserID, winnerID = game.player1ID, game.player2ID
 			}
+			statusSB.WriteString(fmt.Sprintf(MsgGameWin, loserID, winnerID))
+		}
+	} else {
+		currentID, icon := game.player1ID, othelloBlack
+		if game.currentTurn == 2 {
+			currentID, icon = game.player2ID, othelloWhite
+		}
+		statusSB.WriteString(fmt.Sprintf(MsgGameTurn, currentID, icon))
+		if statusMsg != "" {
+			statusSB.WriteString("\n" + statusMsg)
 		}
 	}
-	return moves
-}
-
-func CheckersIsValidPos(r, c int) bool {
-	return r >= 0 && r < checkersRows && c >= 0 && c < checkersCols
-}
 
-// CheckersIsHopeless checks if the AI is in a hopeless position
-func CheckersIsHopeless(game *CheckersGame) bool {
-	if !game.isAI {
-		return false
+	var moves [][2]int
+	if !game.gameOver {
+		moves = othelloLegalMoves(&game.board, game.currentTurn)
 	}
 
-	aiPiece := PieceP2
-	aiKingPiece := PieceP2King
-	playerPiece := PieceP1
-	playerKingPiece := PieceP1King
+	var components []any
+	if game.emojiBoard {
+		components = append(components, NewTextDisplay(othelloEmojiBoard(game, moves)))
+	} else {
+		components = append(components, othelloRenderBoard(game, moves))
+	}
+	components = append(components, NewTextDisplay(scoreStr))
+	components = append(components, NewTextDisplay(statusSB.String()))
 
-	if game.aiPlayerNum == 1 {
-		aiPiece = PieceP1
-		aiKingPiece = PieceP1King
-		playerPiece = PieceP2
-		playerKingPiece = PieceP2King
+	if !game.gameOver {
+		var options []discord.StringSelectMenuOption
+		for _, m := range moves {
+			label := fmt.Sprintf("%c%d", 'A'+m[1], m[0]+1)
+			options = append(options, discord.NewStringSelectMenuOption(label, fmt.Sprintf("%d,%d", m[0], m[1])))
+		}
+		// A select menu holds at most 25 options, so the rare position with more legal moves gets a second menu
+		menus := []struct{ customID, placeholder string }{
+			{CIDOthelloMove, LabelSelectSquare},
+			{CIDOthelloMoveMore, LabelSelectMore},
+		}
+		for _, m := range menus {
+			if len(options) == 0 {
+				break
+			}
+			n := min(len(options), 25)
+			menu := discord.NewStringSelectMenu(fmt.Sprintf(m.customID, gameID), m.placeholder, options[:n]...)
+			components = append(components, discord.NewActionRow(menu))
+			options = options[n:]
+		}
+		components = append(components, discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleDanger, LabelResign, fmt.Sprintf(CIDOthelloForfeit, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDOthelloSpectate, gameID), "", 0),
+		))
+	} else {
+		components = append(components, NewSeparator(true))
+		components = append(components, discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSuccess, LabelRematch, fmt.Sprintf(CIDOthelloRematch, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDOthelloSpectate, gameID), "", 0),
+		))
 	}
 
-	aiCount := 0
-	aiKingCount := 0
-	playerCount := 0
-	playerKingCount := 0
+	return NewV2Container(components...)
+}
 
-	for r := 0; r < checkersRows; r++ {
-		for c := 0; c < checkersCols; c++ {
-			p := game.board[r][c]
-			switch p {
-			case aiPiece:
-				aiCount++
-			case aiKingPiece:
-				aiKingCount++
-			case playerPiece:
-				playerCount++
-			case playerKingPiece:
-				playerKingCount++
+// othelloEmojiBoard draws the board with emoji, marking the squares the mover can play
+func othelloEmojiBoard(game *othelloGame, moves [][2]int) string {
+	var sb strings.Builder
+	header := BoardCorner + strings.Join(BoardColumnEmojis[:othelloSize], "") + BoardCorner + "\n"
+	sb.WriteString(header)
+	for r := range othelloSize {
+		sb.WriteString(BoardRowEmojis[r])
+		for c := range othelloSize {
+			switch game.board[r][c] {
+			case 1:
+				sb.WriteString(othelloBlack)
+			case 2:
+				sb.WriteString(othelloWhite)
+			default:
+				if slices.Contains(moves, [2]int{r, c}) {
+					sb.WriteString(othelloHint)
+				} else {
+					sb.WriteString(othelloEmpty)
+				}
 			}
 		}
+		sb.WriteString(BoardRowEmojis[r] + "\n")
 	}
+	sb.WriteString(header)
+	return sb.String()
+}
 
-	totalAI := aiCount + aiKingCount
-	totalPlayer := playerCount + playerKingCount
+// othelloRenderBoard draws the board as a PNG, marking the last disc placed, the discs it flipped and the mover's options
+func othelloRenderBoard(game *othelloGame, moves [][2]int) gameBoardImage {
+	canvas := newGameCanvas(othelloSize, othelloSize)
+	for r := range othelloSize {
+		for c := range othelloSize {
+			pos := [2]int{r, c}
+			canvas.fill(r, c, othelloImageBoard)
+			if game.lastMove != nil && *game.lastMove == pos {
+				canvas.tint(r, c, gameImageLastMove, 0.45)
+			}
 
-	if aiKingCount == 0 && playerKingCount > 0 && totalAI < totalPlayer-1 {
-		return true
+			rim := gameImageBlack
+			if slices.Contains(game.flipped, pos) {
+				rim = gameImageGold
+			}
+			switch game.board[r][c] {
+			case 1:
+				canvas.disc(r, c, 0.8, gameImageBlack, rim, 3)
+			case 2:
+				if rim == gameImageBlack {
+					rim = othelloImageRim
+				}
+				canvas.disc(r, c, 0.8, gameImageWhite, rim, 3)
+			}
+		}
 	}
-	if totalAI <= 2 && totalPlayer >= 3 {
-		return true
+	for _, m := range moves {
+		canvas.disc(m[0], m[1], 0.26, othelloImageLine, othelloImageLine, 0)
 	}
-
-	return false
+	canvas.grid(othelloSize, othelloSize, 0, othelloImageLine)
+	canvas.coords(gameBoardLabels(othelloSize, 'A', false), gameBoardLabels(othelloSize, '1', false))
+	return canvas.attach()
 }
 
 // ===========================
-// Checkers Rendering & Helpers
+// Othello AI
 // ===========================
 
-func CheckersBuildMessage(game *CheckersGame, gameID string, statusMsg string) Container {
-	var sb strings.Builder
-
-	// Determine visual mapping based on randomization
-	p1Piece, p2Piece := checkersP1Piece, checkersP2Piece
-	p1King, p2King := checkersP1King, checkersP2King
-	p1Highlight, p2Highlight := checkersP1Highlight, checkersP2Highlight
-
-	if game.colorVariant == VariantInverted {
-		p1Piece, p2Piece = checkersP2Piece, checkersP1Piece
-		p1King, p2King = checkersP2King, checkersP1King
-		p1Highlight, p2Highlight = checkersP2Highlight, checkersP1Highlight
+// othelloMakeAIMove picks the AI's move outside the lock and plays it, moving again if the human has to pass
+func othelloMakeAIMove(client bot.Client, game *othelloGame, gameID string) {
+	activeOthelloGamesMu.Lock()
+	if game.gameOver || game.currentTurn != game.aiPlayerNum || !game.isAI {
+		activeOthelloGamesMu.Unlock()
+		return
 	}
+	board := game.board
+	difficulty := game.aiDifficulty
+	moveCount := game.moveCount
+	activeOthelloGamesMu.Unlock()
 
-	reverse := false
-	if game.currentTurn == 2 {
-		reverse = true
+	move, ok := othelloAIChooseMove(board, game.aiPlayerNum, difficulty)
+	if !ok {
+		return
 	}
 
-	getHeader := func(rev bool) string {
-		var hsb strings.Builder
-		if !rev {
-			for i := 0; i < checkersCols; i++ {
-				hsb.WriteString(BoardColumnEmojis[i])
-			}
-		} else {
-			for i := checkersCols - 1; i >= 0; i-- {
-				hsb.WriteString(BoardColumnEmojis[i])
-			}
-		}
-		return hsb.String()
+	activeOthelloGamesMu.Lock()
+	defer activeOthelloGamesMu.Unlock()
+
+	if game.gameOver || game.currentTurn != game.aiPlayerNum || game.moveCount != moveCount {
+		return
 	}
 
-	headerStr := BoardCorner + getHeader(reverse) + BoardCorner + "\n"
-	sb.WriteString(headerStr)
+	statusMsg, _ := othelloMakeMove(game, move[0], move[1])
+	othelloPersist(game, gameID)
+	msg := othelloBuildMessage(game, gameID, statusMsg)
+	_, _ = gameEditBoard(client, game.channelID, game.messageID, msg)
+	gameMirror(client, game.spectate, msg)
 
-	rStart, rEnd, rStep := 0, checkersRows, 1
-	cStart, cEnd, cStep := 0, checkersCols, 1
+	if !game.gameOver && game.currentTurn == game.aiPlayerNum {
+		time.AfterFunc(1*time.Second, func() {
+			othelloMakeAIMove(client, game, gameID)
+		})
+	}
+}
 
-	if reverse {
-		rStart, rEnd, rStep = checkersRows-1, -1, -1
-		cStart, cEnd, cStep = checkersCols-1, -1, -1
+// othelloAIChooseMove plays randomly on easy and searches on the other difficulties
+func othelloAIChooseMove(board othelloBoard, player int, difficulty string) ([2]int, bool) {
+	if cfg, ok := othelloEngineConfigs[difficulty]; ok {
+		return othelloEngineSearch(board, player, cfg)
 	}
+	moves := othelloLegalMoves(&board, player)
+	if len(moves) == 0 {
+		return [2]int{}, false
+	}
+	return moves[rand.Intn(len(moves))], true
+}
 
-	for r := rStart; r != rEnd; r += rStep {
-		sb.WriteString(BoardRowEmojis[r])
-		for c := cStart; c != cEnd; c += cStep {
-			p := game.board[r][c]
+// othelloEngineConfig controls how hard the Othello engine thinks for a difficulty
+type othelloEngineConfig struct {
+	depth  int           // Maximum iterative deepening depth (plies)
+	budget time.Duration // Wall-clock budget per move (depth 1 always completes)
+}
 
-			isTarget := false
-			isSelected := (game.selectedPiece != nil && game.selectedPiece[0] == r && game.selectedPiece[1] == c)
-			isLastMove := (game.lastMoveDest != nil && game.lastMoveDest[0] == r && game.lastMoveDest[1] == c)
+// othelloEngineConfigs maps the searched difficulties to their limits; easy stays random
+var othelloEngineConfigs = map[string]othelloEngineConfig{
+	ChoiceNormal: {depth: 2, budget: 500 * time.Millisecond},
+	ChoiceHard:   {depth: 8, budget: 2 * time.Second},
+}
 
-			if game.selectedPiece != nil {
-				k := fmt.Sprintf("%d,%d", game.selectedPiece[0], game.selectedPiece[1])
-				validMoves := CheckersGetValidMoves(game, game.currentTurn)
-				if targets, ok := validMoves[k]; ok {
-					for _, t := range targets {
-						if t[0] == r && t[1] == c {
-							isTarget = true
-							break
-						}
-					}
-				}
-			}
+const (
+	othelloInfinity      = 1_000_000
+	othelloWinScore      = 100_000
+	othelloWinThreshold  = othelloWinScore - 1_000
+	othelloMobilityScore = 5
+)
 
-			if isSelected || isLastMove {
-				switch p {
-				case PieceP1, PieceP1King:
-					sb.WriteString(p1Highlight)
-				case PieceP2, PieceP2King:
-					sb.WriteString(p2Highlight)
-				default:
-					if (r+c)%2 == 1 {
-						sb.WriteString(checkersEmpty)
-					} else {
-						sb.WriteString(checkersWhiteTile)
-					}
-				}
-			} else if isTarget {
-				sb.WriteString(checkersTarget)
-			} else {
-				switch p {
-				case PieceNone:
-					if (r+c)%2 == 1 {
-						sb.WriteString(checkersEmpty)
-					} else {
-						sb.WriteString(checkersWhiteTile)
-					}
-				case PieceP1:
-					sb.WriteString(p1Piece)
-				case PieceP2:
-					sb.WriteString(p2Piece)
-				case PieceP1King:
-					sb.WriteString(p1King)
-				case PieceP2King:
-					sb.WriteString(p2King)
-				}
-			}
-		}
-		sb.WriteString(BoardRowEmojis[r] + "\n")
-	}
-	sb.WriteString(headerStr)
+// othelloEngine is a depth-limited alpha-beta searcher; boards are small arrays so each ply works on a copy
+type othelloEngine struct {
+	deadline time.Time
+	nodes    int
+	aborted  bool
+}
 
-	p1Count, p2Count := 0, 0
-	for r := range checkersRows {
-		for c := range checkersCols {
-			p := game.board[r][c]
-			if p == PieceP1 || p == PieceP1King {
-				p1Count++
-			}
-			if p == PieceP2 || p == PieceP2King {
-				p2Count++
-			}
-		}
+// othelloEngineSearch returns the best square for player, or false if they have to pass
+func othelloEngineSearch(board othelloBoard, player int, cfg othelloEngineConfig) ([2]int, bool) {
+	moves := othelloLegalMoves(&board, player)
+	if len(moves) == 0 {
+		return [2]int{}, false
 	}
-	scoreStr := fmt.Sprintf("-# %s <@%d>: **%d** | %s <@%d>: **%d**",
-		p1Piece, game.player1ID, p1Count,
-		p2Piece, game.player2ID, p2Count)
 
-	var statusSB strings.Builder
-	if game.gameOver {
-		if statusMsg != "" {
-			statusSB.WriteString(statusMsg)
-		} else if game.winner == 0 {
-			statusSB.WriteString(fmt.Sprintf(checkersStatusDraw, game.player1ID, game.player2ID))
-		} else {
-			winnerID := game.player1ID
-			loserID := game.player2ID
-			if game.winner == 2 {
-				winnerID = game.player2ID
-				loserID = game.player1ID
-			}
-			statusSB.WriteString(fmt.Sprintf(checkersStatusWin, loserID, winnerID))
-		}
-	} else {
-		currentPlayer := game.player1ID
-		statusIcon := p1Piece
-		hasKing := false
+	e := &othelloEngine{}
+	// Shuffle first so equal moves are not always played in board order
+	rand.Shuffle(len(moves), func(i, j int) { moves[i], moves[j] = moves[j], moves[i] })
+	othelloOrderMoves(moves)
 
-		checkType := PieceP1King
-		if game.currentTurn == 2 {
-			currentPlayer = game.player2ID
-			statusIcon = p2Piece
-			checkType = PieceP2King
+	start := time.Now()
+	best := moves[0]
+	for depth := 1; depth <= max(cfg.depth, 1); depth++ {
+		if depth > 1 && cfg.budget > 0 {
+			e.deadline = start.Add(cfg.budget)
 		}
 
-		for r := range checkersRows {
-			for c := range checkersCols {
-				if game.board[r][c] == checkType {
-					hasKing = true
-					break
-				}
-			}
-			if hasKing {
+		alpha := -othelloInfinity
+		bestIdx := -1
+		for i, m := range moves {
+			next := board
+			othelloApply(&next, player, m[0], m[1], othelloFlips(&next, player, m[0], m[1]))
+			score := -e.negamax(next, 3-player, depth-1, -othelloInfinity, -alpha, false)
+			if e.aborted {
 				break
 			}
-		}
-
-		if hasKing {
-			if game.currentTurn == 1 {
-				statusIcon = p1King
-			} else {
-				statusIcon = p2King
+			if bestIdx == -1 || score > alpha {
+				alpha = score
+				bestIdx = i
 			}
 		}
-		statusSB.WriteString(fmt.Sprintf(checkersStatusTurn, currentPlayer, statusIcon))
-
-		if statusMsg != "" {
-			statusSB.WriteString("\n" + statusMsg)
+		if e.aborted || bestIdx == -1 {
+			break
 		}
-	}
 
-	var components []interface{}
-	components = append(components, NewTextDisplay(sb.String()))
-	components = append(components, NewTextDisplay(scoreStr))
-	components = append(components, NewTextDisplay(statusSB.String()))
+		best = moves[bestIdx]
+		// Search the previous best move first on the next iteration
+		copy(moves[1:bestIdx+1], moves[:bestIdx])
+		moves[0] = best
 
-	if !game.gameOver {
-		validMoves := CheckersGetValidMoves(game, game.currentTurn)
-		selectedKey := ""
-		if game.selectedPiece != nil {
-			selectedKey = fmt.Sprintf("%d,%d", game.selectedPiece[0], game.selectedPiece[1])
+		if alpha >= othelloWinThreshold {
+			break
 		}
+	}
 
-		var pieceOptions []discord.StringSelectMenuOption
-		for k, targets := range validMoves {
-			if len(targets) > 0 {
-				if selectedKey != "" && k == selectedKey {
-					continue
-				}
+	return best, true
+}
 
-				coords := strings.Split(k, ",")
-				r, _ := strconv.Atoi(coords[0])
-				c, _ := strconv.Atoi(coords[1])
-				label := fmt.Sprintf("Row %d, Col %c", r+1, 'A'+c)
-				pieceOptions = append(pieceOptions, discord.NewStringSelectMenuOption(label, k))
-			}
-		}
-		sort.Slice(pieceOptions, func(i, j int) bool { return pieceOptions[i].Label < pieceOptions[j].Label })
+func (e *othelloEngine) expired() bool {
+	e.nodes++
+	if !e.aborted && !e.deadline.IsZero() && e.nodes&255 == 0 && time.Now().After(e.deadline) {
+		e.aborted = true
+	}
+	return e.aborted
+}
 
-		if game.selectedPiece != nil {
-			k := fmt.Sprintf("%d,%d", game.selectedPiece[0], game.selectedPiece[1])
-			if targets, ok := validMoves[k]; ok {
-				var targetOptions []discord.StringSelectMenuOption
-				for _, t := range targets {
-					label := fmt.Sprintf("Row %d, Col %c", t[0]+1, 'A'+t[1])
-					val := fmt.Sprintf("%d,%d", t[0], t[1])
-					targetOptions = append(targetOptions, discord.NewStringSelectMenuOption(label, val))
-				}
-				if len(targetOptions) > 25 {
-					targetOptions = targetOptions[:25]
-				}
-				menu := discord.NewStringSelectMenu(fmt.Sprintf("checkers:%s:move_to", gameID), LabelSelectDest, targetOptions...)
-				components = append(components, discord.NewActionRow(menu))
+// negamax scores the board for player; passed is set when the previous player had no move, so a second pass ends the game
+func (e *othelloEngine) negamax(board othelloBoard, player, depth, alpha, beta int, passed bool) int {
+	if e.expired() {
+		return 0
+	}
 
-				cancelBtn := discord.NewButton(discord.ButtonStyleSecondary, "Cancel Selection", fmt.Sprintf("checkers:%s:cancel_select", gameID), "", 0)
-				components = append(components, discord.NewActionRow(cancelBtn))
-			}
-		} else {
-			if len(pieceOptions) > 25 {
-				pieceOptions = pieceOptions[:25]
-			}
-			placeholder := LabelSelectPieceMove
-			if game.selectedPiece != nil {
-				r, c := game.selectedPiece[0], game.selectedPiece[1]
-				placeholder = fmt.Sprintf("Selected: Row %d, Col %c", r+1, 'A'+c)
+	moves := othelloLegalMoves(&board, player)
+	if len(moves) == 0 {
+		if passed {
+			black, white := othelloCount(&board)
+			diff := black - white
+			if player == 2 {
+				diff = -diff
 			}
-			if len(pieceOptions) > 0 {
-				menu := discord.NewStringSelectMenu(fmt.Sprintf("checkers:%s:select_piece", gameID), placeholder, pieceOptions...)
-				components = append(components, discord.NewActionRow(menu))
+			switch {
+			case diff > 0:
+				return othelloWinScore + diff
+			case diff < 0:
+				return -othelloWinScore + diff
 			}
+			return 0
 		}
+		return -e.negamax(board, 3-player, depth, -beta, -alpha, true)
+	}
 
-		var utilityRow []discord.InteractiveComponent
-		utilityRow = append(utilityRow, discord.NewButton(discord.ButtonStyleDanger, LabelForfeit, fmt.Sprintf(CIDCheckersForfeit, gameID), "", 0))
+	if depth <= 0 {
+		return othelloEvaluate(&board, player, len(moves))
+	}
 
-		if CheckersIsHopeless(game) {
-			utilityRow = append(utilityRow, discord.NewButton(discord.ButtonStyleSuccess, LabelClaimWin, fmt.Sprintf(CIDCheckersClaimWin, gameID), "", 0))
+	othelloOrderMoves(moves)
+	for _, m := range moves {
+		next := board
+		othelloApply(&next, player, m[0], m[1], othelloFlips(&next, player, m[0], m[1]))
+		score := -e.negamax(next, 3-player, depth-1, -beta, -alpha, false)
+		if e.aborted {
+			return 0
+		}
+		if score >= beta {
+			return score
 		}
+		alpha = max(alpha, score)
+	}
+	return alpha
+}
 
-		components = append(components, discord.NewActionRow(utilityRow...))
-	} else {
-		components = append(components, NewSeparator(true))
-		components = append(components, discord.NewActionRow(
-			discord.NewButton(discord.ButtonStyleSecondary, LabelPlayAgain, "checkers:disabled", "", 0).WithDisabled(true),
-		))
+// othelloOrderMoves searches the most valuable squares first so cut-offs come sooner
+func othelloOrderMoves(moves [][2]int) {
+	slices.SortStableFunc(moves, func(a, b [2]int) int {
+		return othelloWeights[b[0]][b[1]] - othelloWeights[a[0]][a[1]]
+	})
+}
+
+// othelloEvaluate scores the board for player from square weights and how many more moves they have than the opponent
+func othelloEvaluate(board *othelloBoard, player, mobility int) int {
+	score := 0
+	for r := range othelloSize {
+		for c := range othelloSize {
+			switch board[r][c] {
+			case player:
+				score += othelloWeights[r][c]
+			case 3 - player:
+				score -= othelloWeights[r][c]
+			}
+		}
 	}
+	return score + othelloMobilityScore*(mobility-len(othelloLegalMoves(board, 3-player)))
+}
 
-	return NewV2Container(components...)
+// ===========================
+// Ultimate Tic-Tac-Toe Game Constants & Types
+// ===========================
+
+const (
+	tictactoeX       = "❌"
+	tictactoeO       = "⭕"
+	tictactoeEmpty   = "⬜"
+	tictactoeActive  = "🟨"
+	tictactoeDivider = "⬛"
+	tictactoeXWon    = "🟥"
+	tictactoeOWon    = "🟦"
+)
+
+var tictactoeImageCell = color.RGBA{0xEC, 0xEF, 0xF4, 0xFF}
+
+// tictactoeLines are the rows, columns and diagonals of a 3x3 grid, indexed left to right, top to bottom
+var tictactoeLines = [8][3]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}, {0, 3, 6}, {1, 4, 7}, {2, 5, 8}, {0, 4, 8}, {2, 4, 6}}
+
+// tictactoeGame represents an ultimate tic-tac-toe session; player 1 plays X and moves first.
+// Each cell played sends the opponent to the small board in the same position, or anywhere if that board is decided.
+type tictactoeGame struct {
+	cells         [9][9]int // [board][cell]: 0 empty, 1 X, 2 O
+	boards        [9]int    // small board results: 0 open, 1 or 2 won by that player, 3 drawn
+	nextBoard     int       // board the mover must play in, or -1 for any open board
+	selectedBoard int       // board the mover picked when free to choose, or -1
+	player1ID     snowflake.ID
+	player2ID     snowflake.ID
+	currentTurn   int
+	gameOver      bool
+	winner        int
+	moveCount     int
+	lastMoveTime  time.Time
+	messageID     snowflake.ID
+	channelID     snowflake.ID
+	lastMove      *[2]int // board, cell
+	originalP1ID  snowflake.ID
+	originalP2ID  snowflake.ID
+	spectate      *gameSpectate
+	emojiBoard    bool
+}
+
+// tictactoeSnapshot is the serialized form of a tictactoeGame stored in the database
+type tictactoeSnapshot struct {
+	Cells         [9][9]int     `json:"cells"`
+	Boards        [9]int        `json:"boards"`
+	NextBoard     int           `json:"next_board"`
+	SelectedBoard int           `json:"selected_board"`
+	Player1ID     snowflake.ID  `json:"player1_id"`
+	Player2ID     snowflake.ID  `json:"player2_id"`
+	CurrentTurn   int           `json:"current_turn"`
+	MoveCount     int           `json:"move_count"`
+	LastMoveTime  time.Time     `json:"last_move_time"`
+	MessageID     snowflake.ID  `json:"message_id"`
+	ChannelID     snowflake.ID  `json:"channel_id"`
+	LastMove      *[2]int       `json:"last_move,omitempty"`
+	OriginalP1ID  snowflake.ID  `json:"original_p1_id,omitempty"`
+	OriginalP2ID  snowflake.ID  `json:"original_p2_id,omitempty"`
+	Spectate      *gameSpectate `json:"spectate,omitempty"`
+	EmojiBoard    bool          `json:"emoji_board,omitempty"`
 }
 
 // ===========================
-// Checkers AI
+// Ultimate Tic-Tac-Toe Logic - Core
+// ===========================
 
-// CheckersAIMove represents a possible move for the AI
-type CheckersAIMove struct {
-	r1, c1, r2, c2 int
-	isJump         bool
+func newTictactoeGame(p1, p2 snowflake.ID) *tictactoeGame {
+	return &tictactoeGame{
+		player1ID:     p1,
+		player2ID:     p2,
+		nextBoard:     -1,
+		selectedBoard: -1,
+		currentTurn:   1,
+		lastMoveTime:  time.Now(),
+	}
 }
 
-func CheckersMakeAIMove(client bot.Client, game *CheckersGame, gameID string) {
-	activeCheckersGamesMu.Lock()
-	defer activeCheckersGamesMu.Unlock()
+// tictactoeRecordResult feeds a finished game into the rating system
+func tictactoeRecordResult(game *tictactoeGame) {
+	gameRecordResult(CmdTicTacToe, game.player1ID, game.player2ID, game.winner, 0, "")
+}
 
-	if game.gameOver || game.currentTurn != game.aiPlayerNum || !game.isAI {
+// tictactoePersist saves the game state, or forgets it once the game is over (caller must hold activeTictactoeGamesMu)
+func tictactoePersist(game *tictactoeGame, gameID string) {
+	if game.gameOver {
+		gameForget(gameID)
 		return
 	}
+	gameSaveState(gameID, CmdTicTacToe, tictactoeSnapshot{
+		Cells:         game.cells,
+		Boards:        game.boards,
+		NextBoard:     game.nextBoard,
+		SelectedBoard: game.selectedBoard,
+		Player1ID:     game.player1ID,
+		Player2ID:     game.player2ID,
+		CurrentTurn:   game.currentTurn,
+		MoveCount:     game.moveCount,
+		LastMoveTime:  game.lastMoveTime,
+		MessageID:     game.messageID,
+		ChannelID:     game.channelID,
+		LastMove:      game.lastMove,
+		OriginalP1ID:  game.originalP1ID,
+		OriginalP2ID:  game.originalP2ID,
+		Spectate:      game.spectate,
+		EmojiBoard:    game.emojiBoard,
+	})
+}
 
-	validMoves := CheckersGetValidMoves(game, game.aiPlayerNum)
-	if len(validMoves) == 0 {
-		game.gameOver = true
-		game.winner = 3 - game.aiPlayerNum
+// tictactoeRestore rebuilds a saved game
+func tictactoeRestore(client bot.Client, gameID string, state string) error {
+	var snap tictactoeSnapshot
+	if err := json.Unmarshal([]byte(state), &snap); err != nil {
+		return err
+	}
+	if snap.NextBoard < -1 || snap.NextBoard > 8 || snap.SelectedBoard < -1 || snap.SelectedBoard > 8 {
+		return fmt.Errorf("invalid board %d", snap.NextBoard)
+	}
+
+	game := &tictactoeGame{
+		cells:         snap.Cells,
+		boards:        snap.Boards,
+		nextBoard:     snap.NextBoard,
+		selectedBoard: snap.SelectedBoard,
+		player1ID:     snap.Player1ID,
+		player2ID:     snap.Player2ID,
+		currentTurn:   snap.CurrentTurn,
+		moveCount:     snap.MoveCount,
+		lastMoveTime:  snap.LastMoveTime,
+		messageID:     snap.MessageID,
+		channelID:     snap.ChannelID,
+		lastMove:      snap.LastMove,
+		originalP1ID:  snap.OriginalP1ID,
+		originalP2ID:  snap.OriginalP2ID,
+		spectate:      snap.Spectate,
+		emojiBoard:    snap.EmojiBoard,
+	}
+
+	activeTictactoeGamesMu.Lock()
+	activeTictactoeGames[gameID] = game
+	activeTictactoeGamesMu.Unlock()
+
+	gameLockPlayers(client, gameID, game.player1ID, game.player2ID)
+	return nil
+}
 
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
+// ===========================
+// Ultimate Tic-Tac-Toe Interaction Handlers
+// ===========================
 
-		msg := CheckersBuildMessage(game, gameID, MsgGameAINoMoves)
-		_, _ = EditContainerV2(client, game.channelID, game.messageID, msg, nil, nil)
+func handlePlayTictactoe(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in handlePlayTictactoe: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
+
+	p1 := event.User().ID
+	opponent, ok := data.OptUser(OptOpponent)
+	if !ok || opponent.Bot {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgTicTacToeNeedsOpponent).WithEphemeral(true))
+		return
+	}
+	p2 := opponent.ID
+	if p2 == p1 {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameChallengeSelf).WithEphemeral(true))
+		return
+	}
+
+	gameID := fmt.Sprintf(CIDTicTacToeGameID, event.Channel().ID(), time.Now().UnixNano())
+	if errMsg := gameReservePlayers(*event.Client(), gameID, p1, p2); errMsg != "" {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
 		return
 	}
 
-	var candidateMoves []CheckersAIMove
+	player1, player2 := p1, p2
+	if rand.Intn(2) == 1 {
+		player1, player2 = p2, p1
+	}
+	game := newTictactoeGame(player1, player2)
+	game.originalP1ID = p1
+	game.originalP2ID = p2
+	game.emojiBoard = gameParseEmojiBoard(data)
 
-	for k, targets := range validMoves {
-		coords := strings.Split(k, ",")
-		r1, _ := strconv.Atoi(coords[0])
-		c1, _ := strconv.Atoi(coords[1])
-		for _, t := range targets {
-			m := CheckersAIMove{r1: r1, c1: c1, r2: t[0], c2: t[1]}
-			if (t[0]-r1)*(t[0]-r1) > 1 {
-				m.isJump = true
-			}
-			candidateMoves = append(candidateMoves, m)
+	tictactoeOpen(*event.Client(), event, game, gameID)
+}
+
+// tictactoeOpen posts the challenge for a new game; it starts once the opponent accepts
+func tictactoeOpen(client bot.Client, interaction discord.Interaction, game *tictactoeGame, gameID string) {
+	gameIssueChallenge(client, interaction, gameID, &gameChallenge{
+		gameType:     CmdTicTacToe,
+		challengerID: game.originalP1ID,
+		opponentID:   game.originalP2ID,
+		launch: func(client bot.Client, channelID, messageID snowflake.ID) Container {
+			return tictactoeLaunch(game, gameID, channelID, messageID)
+		},
+	})
+}
+
+// tictactoeLaunch attaches a game to its message and returns the opening board
+func tictactoeLaunch(game *tictactoeGame, gameID string, channelID, messageID snowflake.ID) Container {
+	activeTictactoeGamesMu.Lock()
+	defer activeTictactoeGamesMu.Unlock()
+
+	activeTictactoeGames[gameID] = game
+	game.channelID = channelID
+	game.messageID = messageID
+	game.lastMoveTime = time.Now()
+	tictactoePersist(game, gameID)
+	return tictactoeBuildMessage(game, gameID, "")
+}
+
+// tictactoeRematch sets up a fresh game between the same players with marks swapped, requested by userID
+func tictactoeRematch(game *tictactoeGame, userID snowflake.ID) *tictactoeGame {
+	next := newTictactoeGame(game.player2ID, game.player1ID)
+	next.emojiBoard = game.emojiBoard
+	next.originalP1ID = userID
+	next.originalP2ID = game.player1ID
+	if userID == game.player1ID {
+		next.originalP2ID = game.player2ID
+	}
+	return next
+}
+
+func tictactoeHandleInteraction(event *events.ComponentInteractionCreate) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in tictactoeHandleInteraction: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
 		}
+	}()
+
+	parts := strings.Split(event.Data.CustomID(), ":")
+	if len(parts) < 3 {
+		return
+	}
+	gameID := parts[1]
+	action := parts[2]
+
+	activeTictactoeGamesMu.Lock()
+	game, exists := activeTictactoeGames[gameID]
+	if !exists {
+		activeTictactoeGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotFound).WithEphemeral(true))
+		return
 	}
 
-	if len(candidateMoves) > 0 {
-		var jumpMoves []CheckersAIMove
-		for _, m := range candidateMoves {
-			if m.isJump {
-				jumpMoves = append(jumpMoves, m)
+	userID := event.User().ID
+	switch action {
+	case "spectate":
+		gameHandleSpectate(event, &activeTictactoeGamesMu, gameID, CmdTicTacToe, game.spectate, tictactoeBuildMessage(game, gameID, ""), func(s *gameSpectate) *gameSpectate {
+			if game.spectate == nil {
+				game.spectate = s
+				tictactoePersist(game, gameID)
 			}
+			return game.spectate
+		})
+		return
+	case "rematch":
+		if msg := gameRematchRefusal(userID, game.gameOver, game.originalP1ID, game.originalP2ID, game.player1ID, game.player2ID); msg != "" {
+			activeTictactoeGamesMu.Unlock()
+			event.CreateMessage(discord.NewMessageCreate().WithContent(msg).WithEphemeral(true))
+			return
 		}
+		next := tictactoeRematch(game, userID)
+		activeTictactoeGamesMu.Unlock()
 
-		var possibleMoves []CheckersAIMove
-		if len(jumpMoves) > 0 {
-			possibleMoves = jumpMoves
-		} else {
-			possibleMoves = candidateMoves
+		nextID := fmt.Sprintf(CIDTicTacToeGameID, event.Channel().ID(), time.Now().UnixNano())
+		if errMsg := gameReservePlayers(*event.Client(), nextID, next.originalP1ID, next.originalP2ID); errMsg != "" {
+			event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+			return
 		}
+		tictactoeOpen(*event.Client(), event, next, nextID)
+		return
+	}
 
-		var selectedMove CheckersAIMove
-		switch game.aiDifficulty {
-		case "easy":
-			selectedMove = possibleMoves[rand.Intn(len(possibleMoves))]
-		case "normal":
-			selectedMove = checkersAINormalMove(game, possibleMoves)
-		case "hard":
-			selectedMove = checkersAIHardMove(game, possibleMoves)
-		default:
-			selectedMove = possibleMoves[rand.Intn(len(possibleMoves))]
-		}
+	if userID != game.player1ID && userID != game.player2ID {
+		activeTictactoeGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotPlayer).WithEphemeral(true))
+		return
+	}
+	if game.gameOver {
+		activeTictactoeGamesMu.Unlock()
+		event.DeferUpdateMessage()
+		return
+	}
 
-		CheckersMakeMove(game, selectedMove.r1, selectedMove.c1, selectedMove.r2, selectedMove.c2)
-		game.lastMoveTime = time.Now()
-		game.currentTurn = 3 - game.aiPlayerNum
+	player := 2
+	if userID == game.player1ID {
+		player = 1
+	}
+	if game.currentTurn != player && !gameOutOfTurnActions[action] {
+		activeTictactoeGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotTurn).WithEphemeral(true))
+		return
+	}
 
-		if CheckersCheckWin(game) {
-			game.gameOver = true
-			userActiveGameMu.Lock()
-			delete(userActiveGame, game.player1ID)
-			delete(userActiveGame, game.player2ID)
-			userActiveGameMu.Unlock()
+	statusMsg := ""
+	switch {
+	case strings.HasPrefix(action, "board_"):
+		if b, err := strconv.Atoi(strings.TrimPrefix(action, "board_")); err == nil && game.nextBoard == -1 && b >= 0 && b < 9 && game.boards[b] == 0 {
+			game.selectedBoard = b
+		}
+	case strings.HasPrefix(action, "cell_"):
+		b := game.nextBoard
+		if b == -1 {
+			b = game.selectedBoard
+		}
+		if c, err := strconv.Atoi(strings.TrimPrefix(action, "cell_")); err == nil && b != -1 && c >= 0 && c < 9 {
+			tictactoePlay(game, b, c)
+		}
+	case action == "cancel_select":
+		game.selectedBoard = -1
+	case action == "forfeit":
+		game.gameOver = true
+		game.winner = 3 - player
+		loserID, winnerID := game.player1ID, game.player2ID
+		if player == 2 {
+			loserID, winnerID = game.player2ID, game.player1ID
 		}
+		statusMsg = fmt.Sprintf(MsgGameForfeitSuccess, loserID, winnerID)
+		tictactoeRecordResult(game)
+		gameReleasePlayers(game.player1ID, game.player2ID)
 	}
 
-	msg := CheckersBuildMessage(game, gameID, "")
-	EditContainerV2(client, game.channelID, game.messageID, msg, nil, nil)
+	msg := tictactoeBuildMessage(game, gameID, statusMsg)
+	tictactoePersist(game, gameID)
+	activeTictactoeGamesMu.Unlock()
+
+	_ = gameUpdateBoard(*event.Client(), event, msg)
+	gameMirror(*event.Client(), game.spectate, msg)
 }
 
-// checkersAINormalMove prioritizes promotion (Kings), then random
-func checkersAINormalMove(game *CheckersGame, moves []CheckersAIMove) CheckersAIMove {
-	var promoteMoves []CheckersAIMove
+// ===========================
+// Ultimate Tic-Tac-Toe Logic - Mechanics
+// ===========================
+
+// tictactoePlay marks cell c of board b for the mover and settles the small and big boards; it reports whether the move was legal
+func tictactoePlay(game *tictactoeGame, b, c int) bool {
+	if game.boards[b] != 0 || game.cells[b][c] != 0 || (game.nextBoard != -1 && game.nextBoard != b) {
+		return false
+	}
+
+	game.cells[b][c] = game.currentTurn
+	game.boards[b] = tictactoeResult(game.cells[b])
+	game.lastMove = &[2]int{b, c}
+	game.selectedBoard = -1
+	game.lastMoveTime = time.Now()
+	game.moveCount++
+
+	game.nextBoard = c
+	if game.boards[c] != 0 {
+		game.nextBoard = -1
+	}
 
-	targetRow := 0
-	if game.aiPlayerNum == 2 {
-		targetRow = checkersRows - 1
+	switch result := tictactoeResult(game.boards); result {
+	case 0:
+		game.currentTurn = 3 - game.currentTurn
+	default:
+		game.gameOver = true
+		game.winner = result % 3
+		tictactoeRecordResult(game)
+		gameReleasePlayers(game.player1ID, game.player2ID)
 	}
+	return true
+}
 
-	for _, m := range moves {
-		if m.r2 == targetRow {
-			p := game.board[m.r1][m.c1]
-			isKing := (p == PieceP1King || p == PieceP2King)
-			if !isKing {
-				promoteMoves = append(promoteMoves, m)
-			}
+// tictactoeResult settles a 3x3 grid: 1 or 2 for a completed line, 3 when nothing is left to play, 0 while open.
+// Drawn small boards (3) count for neither player on the big board.
+func tictactoeResult(grid [9]int) int {
+	for _, line := range tictactoeLines {
+		v := grid[line[0]]
+		if (v == 1 || v == 2) && grid[line[1]] == v && grid[line[2]] == v {
+			return v
 		}
 	}
+	if slices.Contains(grid[:], 0) {
+		return 0
+	}
+	return 3
+}
 
-	if len(promoteMoves) > 0 {
-		return promoteMoves[rand.Intn(len(promoteMoves))]
+// tictactoeActiveBoards returns the boards the mover may play in
+func tictactoeActiveBoards(game *tictactoeGame) [9]bool {
+	var active [9]bool
+	if game.gameOver {
+		return active
+	}
+	for b := range 9 {
+		switch {
+		case game.nextBoard != -1:
+			active[b] = b == game.nextBoard
+		case game.selectedBoard != -1:
+			active[b] = b == game.selectedBoard
+		default:
+			active[b] = game.boards[b] == 0
+		}
 	}
-	return moves[rand.Intn(len(moves))]
+	return active
 }
 
-// checkersAIHardMove prioritizes Safety -> Promotion -> Random
-func checkersAIHardMove(game *CheckersGame, moves []CheckersAIMove) CheckersAIMove {
-	var safeMoves []CheckersAIMove
+// ===========================
+// Ultimate Tic-Tac-Toe Rendering & Helpers
+// ===========================
 
-	for _, m := range moves {
-		if !checkersIsVulnerable(game, m.r2, m.c2) {
-			safeMoves = append(safeMoves, m)
+func tictactoeBuildMessage(game *tictactoeGame, gameID string, statusMsg string) Container {
+	won := [3]int{}
+	for _, result := range game.boards {
+		if result == 1 || result == 2 {
+			won[result]++
 		}
 	}
+	scoreStr := fmt.Sprintf("-# %s <@%d>: **%d** | %s <@%d>: **%d**",
+		tictactoeX, game.player1ID, won[1],
+		tictactoeO, game.player2ID, won[2])
 
-	candidates := safeMoves
-	if len(candidates) == 0 {
-		candidates = moves
+	target := game.nextBoard
+	if target == -1 {
+		target = game.selectedBoard
 	}
 
-	return checkersAINormalMove(game, candidates)
-}
-
-// checkersIsVulnerable checks if a piece at r,c can be jumped by the opponent
-func checkersIsVulnerable(game *CheckersGame, r, c int) bool {
-	opponent := 1
-	if game.aiPlayerNum == 1 {
-		opponent = 2
+	var statusSB strings.Builder
+	if game.gameOver {
+		if statusMsg != "" {
+			statusSB.WriteString(statusMsg)
+		} else if game.winner == 0 {
+			statusSB.WriteString(fmt.Sprintf(MsgGameDraw, game.player1ID, game.player2ID))
+		} else {
+			loserID, winnerID := game.player2ID, game.player1ID
+			if game.winner == 2 {
+				loserID, winnerID = game.player1ID, game.player2ID
+			}
+			statusSB.WriteString(fmt.Sprintf(MsgGameWin, loserID, winnerID))
+		}
+	} else {
+		currentID, icon := game.player1ID, tictactoeX
+		if game.currentTurn == 2 {
+			currentID, icon = game.player2ID, tictactoeO
+		}
+		statusSB.WriteString(fmt.Sprintf(MsgGameTurn, currentID, icon))
+		if target == -1 {
+			statusSB.WriteString("\n" + MsgTicTacToeAnyBoard)
+		} else {
+			statusSB.WriteString("\n" + fmt.Sprintf(MsgTicTacToeInBoard, BoardRowEmojis[target]))
+		}
+		if statusMsg != "" {
+			statusSB.WriteString("\n" + statusMsg)
+		}
 	}
 
-	dirs := [][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
-
-	for _, d := range dirs {
-		attackerR, attackerC := r+d[0], c+d[1]
+	var components []any
+	if game.emojiBoard {
+		components = append(components, NewTextDisplay(tictactoeEmojiBoard(game)))
+	} else {
+		components = append(components, tictactoeRenderBoard(game))
+	}
+	components = append(components, NewTextDisplay(scoreStr))
+	components = append(components, NewTextDisplay(statusSB.String()))
 
-		if !CheckersIsValidPos(attackerR, attackerC) {
-			continue
+	if !game.gameOver {
+		// The keypad picks a board when the mover is free to choose, then a cell within it
+		for row := range 3 {
+			var buttons []discord.InteractiveComponent
+			for col := range 3 {
+				i := row*3 + col
+				var btn discord.ButtonComponent
+				if target == -1 {
+					btn = discord.NewButton(discord.ButtonStyleSecondary, BoardRowEmojis[i], fmt.Sprintf(CIDTicTacToeBoard, gameID, i), "", 0).
+						WithDisabled(game.boards[i] != 0)
+				} else {
+					label := BoardRowEmojis[i]
+					switch game.cells[target][i] {
+					case 1:
+						label = tictactoeX
+					case 2:
+						label = tictactoeO
+					}
+					btn = discord.NewButton(discord.ButtonStylePrimary, label, fmt.Sprintf(CIDTicTacToeCell, gameID, i), "", 0).
+						WithDisabled(game.cells[target][i] != 0)
+				}
+				buttons = append(buttons, btn)
+			}
+			components = append(components, discord.NewActionRow(buttons...))
 		}
 
-		piece := game.board[attackerR][attackerC]
-		if piece == PieceNone {
-			continue
+		var utilityRow []discord.InteractiveComponent
+		if game.selectedBoard != -1 {
+			utilityRow = append(utilityRow, discord.NewButton(discord.ButtonStyleSecondary, LabelCancelSelect, fmt.Sprintf(CIDTicTacToeCancel, gameID), "", 0))
 		}
+		utilityRow = append(utilityRow,
+			discord.NewButton(discord.ButtonStyleDanger, LabelResign, fmt.Sprintf(CIDTicTacToeForfeit, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDTicTacToeSpectate, gameID), "", 0),
+		)
+		components = append(components, discord.NewActionRow(utilityRow...))
+	} else {
+		components = append(components, NewSeparator(true))
+		components = append(components, discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSuccess, LabelRematch, fmt.Sprintf(CIDTicTacToeRematch, gameID), "", 0),
+			discord.NewButton(discord.ButtonStyleSecondary, LabelSpectate, fmt.Sprintf(CIDTicTacToeSpectate, gameID), "", 0),
+		))
+	}
 
-		isOpponent := false
-		isKing := (piece == PieceP1King || piece == PieceP2King)
-
-		if opponent == 1 && (piece == PieceP1 || piece == PieceP1King) {
-			isOpponent = true
-		} else if opponent == 2 && (piece == PieceP2 || piece == PieceP2King) {
-			isOpponent = true
-		}
+	return NewV2Container(components...)
+}
 
-		if !isOpponent {
-			continue
+// tictactoeEmojiBoard draws the nine small boards with emoji, filling won boards with the winner's colour
+func tictactoeEmojiBoard(game *tictactoeGame) string {
+	active := tictactoeActiveBoards(game)
+	var sb strings.Builder
+	for br := range 3 {
+		if br > 0 {
+			sb.WriteString(strings.Repeat(tictactoeDivider, 11) + "\n")
+		}
+		for sr := range 3 {
+			for bc := range 3 {
+				if bc > 0 {
+					sb.WriteString(tictactoeDivider)
+				}
+				b := br*3 + bc
+				for sc := range 3 {
+					c := sr*3 + sc
+					switch {
+					case game.boards[b] == 1:
+						sb.WriteString(tictactoeXWon)
+					case game.boards[b] == 2:
+						sb.WriteString(tictactoeOWon)
+					case game.cells[b][c] == 1:
+						sb.WriteString(tictactoeX)
+					case game.cells[b][c] == 2:
+						sb.WriteString(tictactoeO)
+					case active[b]:
+						sb.WriteString(tictactoeActive)
+					default:
+						sb.WriteString(tictactoeEmpty)
+					}
+				}
+			}
+			sb.WriteString("\n")
 		}
+	}
+	return sb.String()
+}
 
-		canAttackDir := false
-		if isKing {
-			canAttackDir = true
-		} else if opponent == 1 && d[0] == 1 {
-			canAttackDir = true
-		} else if opponent == 2 && d[0] == -1 {
-			canAttackDir = true
-		}
+// tictactoeRenderBoard draws the board as a PNG, shading won boards, the boards in play and the last move
+func tictactoeRenderBoard(game *tictactoeGame) gameBoardImage {
+	active := tictactoeActiveBoards(game)
+	canvas := newGameCanvas(9, 9)
+	for b := range 9 {
+		for c := range 9 {
+			r, col := b/3*3+c/3, b%3*3+c%3
+			canvas.fill(r, col, tictactoeImageCell)
+			switch {
+			case game.boards[b] == 1:
+				canvas.tint(r, col, gameImageRed, 0.3)
+			case game.boards[b] == 2:
+				canvas.tint(r, col, gameImageBlue, 0.3)
+			case active[b]:
+				canvas.tint(r, col, gameImageGold, 0.25)
+			}
+			if game.lastMove != nil && *game.lastMove == [2]int{b, c} {
+				canvas.tint(r, col, gameImageLastMove, 0.6)
+			}
 
-		if canAttackDir {
-			landR, landC := r-d[0], c-d[1]
-			if CheckersIsValidPos(landR, landC) && game.board[landR][landC] == PieceNone {
-				return true
+			switch game.cells[b][c] {
+			case 1:
+				canvas.label(r, col, "X", 4, gameImageRed)
+			case 2:
+				canvas.label(r, col, "O", 4, gameImageBlue)
 			}
 		}
 	}
-	return false
+	canvas.grid(9, 9, 3, gameImageBackground)
+	return canvas.attach()
 }
 
 // ===========================
-// Chess Game Constants & Types
+// Minesweeper Game Constants & Types
 // ===========================
 
 const (
-	chessWhiteSquare = "▫️"
-	chessBlackSquare = "▪️"
-	chessSelected    = "🟩"
-
-	chessTargetWhite = "◻️"
-	chessTargetBlack = "◼️"
-
-	chessWhiteKing   = "🤟🏻"
-	chessWhiteQueen  = "🖐🏻"
-	chessWhiteRook   = "✊🏻"
-	chessWhiteBishop = "👐🏻"
-	chessWhiteKnight = "🤙🏻"
-	chessWhitePawn   = "☝🏻"
-
-	chessBlackKing   = "🤟🏿"
-	chessBlackQueen  = "🖐🏿"
-	chessBlackRook   = "✊🏿"
-	chessBlackBishop = "👐🏿"
-	chessBlackKnight = "🤙🏿"
-	chessBlackPawn   = "☝🏿"
-
-	chessStatusTurn    = MsgGameTurn
-	chessStatusWin     = MsgGameWin
-	chessStatusDraw    = MsgGameDraw
-	chessStatusForfeit = MsgGameForfeitSuccess
+	minesweeperHidden   = "🟦"
+	minesweeperFlag     = "🚩"
+	minesweeperMine     = "💣"
+	minesweeperExploded = "💥"
+	minesweeperClear    = "⬜"
 )
 
-// ChessGame represents a Chess game session
-type ChessGame struct {
-	game          *chess.Game
-	player1ID     snowflake.ID
-	player2ID     snowflake.ID
-	isAI          bool
-	aiDifficulty  string
-	aiPlayerNum   int
-	colorVariant  GameColorVariant
-	currentTurn   int
-	gameOver      bool
-	winner        int
-	messageID     snowflake.ID
-	channelID     snowflake.ID
-	selectedPiece *chess.Square
-	lastMove      *chess.Move
-	p1Icon        string
-	p2Icon        string
+// minesweeperField is the size and mine count for a difficulty
+type minesweeperField struct {
+	rows, cols, mines int
 }
 
-func (g *ChessGame) GetPieceIcon(p chess.Piece) string {
-	wKing, bKing := chessWhiteKing, chessBlackKing
-	wQueen, bQueen := chessWhiteQueen, chessBlackQueen
-	wRook, bRook := chessWhiteRook, chessBlackRook
-	wBishop, bBishop := chessWhiteBishop, chessBlackBishop
-	wKnight, bKnight := chessWhiteKnight, chessBlackKnight
-	wPawn, bPawn := chessWhitePawn, chessBlackPawn
+var minesweeperFields = map[string]minesweeperField{
+	ChoiceEasy:   {rows: 8, cols: 8, mines: 8},
+	ChoiceNormal: {rows: 9, cols: 9, mines: 12},
+	ChoiceHard:   {rows: 10, cols: 10, mines: 20},
+}
 
-	if g.colorVariant == VariantInverted {
-		wKing, bKing = chessBlackKing, chessWhiteKing
-		wQueen, bQueen = chessBlackQueen, chessWhiteQueen
-		wRook, bRook = chessBlackRook, chessWhiteRook
-		wBishop, bBishop = chessBlackBishop, chessWhiteBishop
-		wKnight, bKnight = chessBlackKnight, chessWhiteKnight
-		wPawn, bPawn = chessBlackPawn, chessWhitePawn
+var (
+	minesweeperImageHidden   = color.RGBA{0x4E, 0x5D, 0x6C, 0xFF}
+	minesweeperImageRevealed = color.RGBA{0xD0, 0xD5, 0xDB, 0xFF}
+
+	// minesweeperImageNumbers are the classic colours for 1 to 8 adjacent mines
+	minesweeperImageNumbers = [9]color.RGBA{
+		{},
+		{0x19, 0x4B, 0xD1, 0xFF},
+		{0x2E, 0x7D, 0x32, 0xFF},
+		{0xD3, 0x2F, 0x2F, 0xFF},
+		{0x1A, 0x23, 0x7E, 0xFF},
+		{0x8E, 0x24, 0x24, 0xFF},
+		{0x00, 0x83, 0x8F, 0xFF},
+		{0x21, 0x21, 0x21, 0xFF},
+		{0x61, 0x61, 0x61, 0xFF},
 	}
+)
 
-	switch p.Type() {
-	case chess.Pawn:
-		if p.Color() == chess.White {
-			return wPawn
-		}
-		return bPawn
-	case chess.Knight:
-		if p.Color() == chess.White {
-			return wKnight
-		}
-		return bKnight
-	case chess.Bishop:
-		if p.Color() == chess.White {
-			return wBishop
-		}
-		return bBishop
-	case chess.Rook:
-		if p.Color() == chess.White {
-			return wRook
-		}
-		return bRook
-	case chess.Queen:
-		if p.Color() == chess.White {
-			return wQueen
-		}
-		return bQueen
-	case chess.King:
-		if p.Color() == chess.White {
-			return wKing
-		}
-		return bKing
-	}
-	return ""
+// minesweeperGame represents a solo minesweeper session; mines are laid on the first dig so it is never fatal
+type minesweeperGame struct {
+	mines        [][]bool
+	revealed     [][]bool
+	flagged      [][]bool
+	difficulty   string
+	seeded       bool
+	playerID     snowflake.ID
+	gameOver     bool
+	won          bool
+	exploded     *[2]int
+	selectedRow  int // row picked in the menu, or -1
+	flagMode     bool
+	moveCount    int
+	startTime    time.Time
+	lastMoveTime time.Time
+	messageID    snowflake.ID
+	channelID    snowflake.ID
+	emojiBoard   bool
 }
 
-func NewChessGame(p1, p2 snowflake.ID, isAI bool, aiPlayerNum int, difficulty string) *ChessGame {
-	variant := VariantStandard
-	if rand.Intn(2) == 1 {
-		variant = VariantInverted
-	}
-	game := &ChessGame{
-		game:         chess.NewGame(),
-		player1ID:    p1,
-		player2ID:    p2,
-		isAI:         isAI,
-		aiDifficulty: difficulty,
-		aiPlayerNum:  aiPlayerNum,
-		colorVariant: variant,
-		currentTurn:  1,
-	}
-	game.p1Icon = game.GetPieceIcon(chess.Piece(chess.WhiteKing))
-	game.p2Icon = game.GetPieceIcon(chess.Piece(chess.BlackKing))
-	return game
+// minesweeperSnapshot is the serialized form of a minesweeperGame stored in the database
+type minesweeperSnapshot struct {
+	Mines        [][]bool     `json:"mines"`
+	Revealed     [][]bool     `json:"revealed"`
+	Flagged      [][]bool     `json:"flagged"`
+	Difficulty   string       `json:"difficulty"`
+	Seeded       bool         `json:"seeded"`
+	PlayerID     snowflake.ID `json:"player_id"`
+	SelectedRow  int          `json:"selected_row"`
+	FlagMode     bool         `json:"flag_mode,omitempty"`
+	MoveCount    int          `json:"move_count"`
+	StartTime    time.Time    `json:"start_time"`
+	LastMoveTime time.Time    `json:"last_move_time"`
+	MessageID    snowflake.ID `json:"message_id"`
+	ChannelID    snowflake.ID `json:"channel_id"`
+	EmojiBoard   bool         `json:"emoji_board,omitempty"`
 }
 
 // ===========================
-// Chess Interaction Handlers
+// Minesweeper Logic - Core
 // ===========================
 
-func HandlePlayChess(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
-	defer func() {
-		if r := recover(); r != nil {
-			LogError("Panic in HandlePlayChess: %v", r)
-			fmt.Printf("%s\n", debug.Stack())
-		}
-	}()
+func newMinesweeperGame(playerID snowflake.ID, difficulty string) *minesweeperGame {
+	if _, ok := minesweeperFields[difficulty]; !ok {
+		difficulty = ChoiceNormal
+	}
+	field := minesweeperFields[difficulty]
+	grid := func() [][]bool {
+		g := make([][]bool, field.rows)
+		for r := range g {
+			g[r] = make([]bool, field.cols)
+		}
+		return g
+	}
+	return &minesweeperGame{
+		mines:        grid(),
+		revealed:     grid(),
+		flagged:      grid(),
+		difficulty:   difficulty,
+		playerID:     playerID,
+		selectedRow:  -1,
+		startTime:    time.Now(),
+		lastMoveTime: time.Now(),
+	}
+}
 
-	var opponentID *snowflake.ID
-	isAI := true
-	var p2 snowflake.ID
+func (g *minesweeperGame) field() minesweeperField {
+	return minesweeperFields[g.difficulty]
+}
 
-	if opponent, ok := data.OptUser("opponent"); ok {
-		opponentID = &opponent.ID
-		isAI = opponent.Bot
+// minesweeperPersist saves the game state, or forgets it once the game is over (caller must hold activeMinesweeperGamesMu)
+func minesweeperPersist(game *minesweeperGame, gameID string) {
+	if game.gameOver {
+		gameForget(gameID)
+		return
 	}
+	gameSaveState(gameID, CmdMinesweeper, minesweeperSnapshot{
+		Mines:        game.mines,
+		Revealed:     game.revealed,
+		Flagged:      game.flagged,
+		Difficulty:   game.difficulty,
+		Seeded:       game.seeded,
+		PlayerID:     game.playerID,
+		SelectedRow:  game.selectedRow,
+		FlagMode:     game.flagMode,
+		MoveCount:    game.moveCount,
+		StartTime:    game.startTime,
+		LastMoveTime: game.lastMoveTime,
+		MessageID:    game.messageID,
+		ChannelID:    game.channelID,
+		EmojiBoard:   game.emojiBoard,
+	})
+}
 
-	difficulty := "normal"
-	if diff, ok := data.OptString("difficulty"); ok {
-		difficulty = diff
+// minesweeperRestore rebuilds a saved game
+func minesweeperRestore(client bot.Client, gameID string, state string) error {
+	var snap minesweeperSnapshot
+	if err := json.Unmarshal([]byte(state), &snap); err != nil {
+		return err
 	}
-
-	appID := event.ApplicationID()
-	p1 := event.User().ID
-
-	userActiveGameMu.Lock()
-	if gid, ok := userActiveGame[p1]; ok {
-		userActiveGameMu.Unlock()
-		event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameAlreadyActive, gid)).WithEphemeral(true))
-		return
+	field, ok := minesweeperFields[snap.Difficulty]
+	if !ok {
+		return fmt.Errorf("unknown difficulty %q", snap.Difficulty)
 	}
-	if opponentID != nil && *opponentID != appID {
-		if gid, ok := userActiveGame[*opponentID]; ok {
-			userActiveGameMu.Unlock()
-			event.CreateMessage(discord.NewMessageCreate().WithContent(fmt.Sprintf(MsgGameOpponentActive, *opponentID, gid)).WithEphemeral(true))
-			return
+	for _, grid := range [][][]bool{snap.Mines, snap.Revealed, snap.Flagged} {
+		if len(grid) != field.rows {
+			return fmt.Errorf("invalid field size for %s", snap.Difficulty)
+		}
+		for _, row := range grid {
+			if len(row) != field.cols {
+				return fmt.Errorf("invalid field size for %s", snap.Difficulty)
+			}
 		}
 	}
 
-	cid := event.Channel().ID()
-	gameID := fmt.Sprintf("chess_%d_%d", cid, time.Now().UnixNano())
-
-	userActiveGame[p1] = gameID
-	if opponentID != nil && *opponentID != appID {
-		userActiveGame[*opponentID] = gameID
+	game := &minesweeperGame{
+		mines:        snap.Mines,
+		revealed:     snap.Revealed,
+		flagged:      snap.Flagged,
+		difficulty:   snap.Difficulty,
+		seeded:       snap.Seeded,
+		playerID:     snap.PlayerID,
+		selectedRow:  snap.SelectedRow,
+		flagMode:     snap.FlagMode,
+		moveCount:    snap.MoveCount,
+		startTime:    snap.StartTime,
+		lastMoveTime: snap.LastMoveTime,
+		messageID:    snap.MessageID,
+		channelID:    snap.ChannelID,
+		emojiBoard:   snap.EmojiBoard,
 	}
-	userActiveGameMu.Unlock()
 
-	if opponentID != nil {
-		p2 = *opponentID
-		if p2 == appID {
-			isAI = true
-		} else {
-			isAI = false
-		}
-	} else {
-		p2 = appID
-		isAI = true
-	}
+	activeMinesweeperGamesMu.Lock()
+	activeMinesweeperGames[gameID] = game
+	activeMinesweeperGamesMu.Unlock()
 
-	var whitePlayer, blackPlayer snowflake.ID
-	if rand.Intn(2) == 0 {
-		whitePlayer = p1
-		blackPlayer = p2
-	} else {
-		whitePlayer = p2
-		blackPlayer = p1
+	gameLockPlayers(client, gameID, game.playerID)
+	return nil
+}
+
+// ===========================
+// Minesweeper Interaction Handlers
+// ===========================
+
+func handlePlayMinesweeper(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in handlePlayMinesweeper: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
+
+	difficulty := ChoiceNormal
+	if diff, ok := data.OptString(OptDifficulty); ok {
+		difficulty = diff
 	}
 
-	aiPlayerNum := 0
-	if isAI {
-		if whitePlayer == appID || (opponentID != nil && whitePlayer == *opponentID && isAI) {
-			aiPlayerNum = 1
-		} else {
-			aiPlayerNum = 2
-		}
+	playerID := event.User().ID
+	gameID := fmt.Sprintf(CIDMinesweeperGameID, event.Channel().ID(), time.Now().UnixNano())
+	if errMsg := gameReservePlayers(*event.Client(), gameID, playerID); errMsg != "" {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+		return
 	}
 
-	game := NewChessGame(whitePlayer, blackPlayer, isAI, aiPlayerNum, difficulty)
+	game := newMinesweeperGame(playerID, difficulty)
+	game.emojiBoard = gameParseEmojiBoard(data)
+	minesweeperOpen(*event.Client(), event, game, gameID)
+}
 
-	activeChessGamesMu.Lock()
-	activeChessGames[gameID] = game
-	activeChessGamesMu.Unlock()
+// minesweeperOpen posts a new field in reply to the interaction; the player must already be reserved
+func minesweeperOpen(client bot.Client, interaction discord.Interaction, game *minesweeperGame, gameID string) {
+	activeMinesweeperGamesMu.Lock()
+	activeMinesweeperGames[gameID] = game
+	game.channelID = interaction.Channel().ID()
+	msg := minesweeperBuildMessage(game, gameID, "")
+	activeMinesweeperGamesMu.Unlock()
 
-	msg := ChessBuildMessage(game, gameID, "")
-	if err := RespondInteractionContainerV2(*event.Client(), event, msg, false); err != nil {
-		activeChessGamesMu.Lock()
-		delete(activeChessGames, gameID)
-		activeChessGamesMu.Unlock()
+	if err := gameRespondBoard(client, interaction, msg); err != nil {
+		activeMinesweeperGamesMu.Lock()
+		delete(activeMinesweeperGames, gameID)
+		activeMinesweeperGamesMu.Unlock()
 
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
+		gameReleasePlayers(game.playerID)
 		return
 	}
 
-	resp, err := event.Client().Rest.GetInteractionResponse(event.ApplicationID(), event.Token())
-	if err == nil && resp != nil {
-		game.messageID = resp.ID
-		game.channelID = cid
+	var messageID snowflake.ID
+	if resp, err := client.Rest.GetInteractionResponse(interaction.ApplicationID(), interaction.Token()); err == nil && resp != nil {
+		messageID = resp.ID
 	}
 
-	if game.isAI && game.aiPlayerNum == 1 {
-		time.AfterFunc(1*time.Second, func() {
-			ChessMakeAIMove(*event.Client(), game, gameID)
-		})
-	}
+	activeMinesweeperGamesMu.Lock()
+	game.messageID = messageID
+	minesweeperPersist(game, gameID)
+	activeMinesweeperGamesMu.Unlock()
 }
 
-func HandleChessInteraction(event *events.ComponentInteractionCreate) {
+func minesweeperHandleInteraction(event *events.ComponentInteractionCreate) {
 	defer func() {
 		if r := recover(); r != nil {
-			LogError("Panic in HandleChessInteraction: %v", r)
+			LogError("Panic in minesweeperHandleInteraction: %v", r)
 			fmt.Printf("%s\n", debug.Stack())
 		}
 	}()
@@ -2434,447 +7855,721 @@ func HandleChessInteraction(event *events.ComponentInteractionCreate) {
 	if len(parts) < 3 {
 		return
 	}
-
 	gameID := parts[1]
 	action := parts[2]
-	statusMsg := ""
 
-	activeChessGamesMu.Lock()
-	game, exists := activeChessGames[gameID]
+	activeMinesweeperGamesMu.Lock()
+	game, exists := activeMinesweeperGames[gameID]
 	if !exists {
-		activeChessGamesMu.Unlock()
+		activeMinesweeperGamesMu.Unlock()
 		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotFound).WithEphemeral(true))
 		return
 	}
-
-	userID := event.User().ID
-	if userID != game.player1ID && userID != game.player2ID {
-		activeChessGamesMu.Unlock()
-		event.CreateMessage(discord.NewMessageCreate().WithContent("You are not part of this game.").WithEphemeral(true))
+	if event.User().ID != game.playerID {
+		activeMinesweeperGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotPlayer).WithEphemeral(true))
 		return
 	}
 
-	isWhite := userID == game.player1ID
-	isTurn := (game.currentTurn == 1 && isWhite) || (game.currentTurn == 2 && !isWhite)
+	if action == "again" {
+		if !game.gameOver {
+			activeMinesweeperGamesMu.Unlock()
+			event.DeferUpdateMessage()
+			return
+		}
+		next := newMinesweeperGame(game.playerID, game.difficulty)
+		next.emojiBoard = game.emojiBoard
+		activeMinesweeperGamesMu.Unlock()
 
-	if !isTurn && action != "forfeit" {
-		activeChessGamesMu.Unlock()
-		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotTurn).WithEphemeral(true))
+		nextID := fmt.Sprintf(CIDMinesweeperGameID, event.Channel().ID(), time.Now().UnixNano())
+		if errMsg := gameReservePlayers(*event.Client(), nextID, next.playerID); errMsg != "" {
+			event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+			return
+		}
+		minesweeperOpen(*event.Client(), event, next, nextID)
+		return
+	}
+
+	if game.gameOver {
+		activeMinesweeperGamesMu.Unlock()
+		event.DeferUpdateMessage()
 		return
 	}
 
+	statusMsg := ""
 	switch action {
-	case "select_piece":
+	case "row":
 		values := event.StringSelectMenuInteractionData().Values
 		if len(values) > 0 {
-			sqVal, _ := strconv.Atoi(values[0])
-			sq := chess.Square(sqVal)
-			game.selectedPiece = &sq
+			if r, err := strconv.Atoi(values[0]); err == nil && r >= 0 && r < len(game.mines) {
+				game.selectedRow = r
+			}
 		}
-	case "move_to":
+	case "cell":
 		values := event.StringSelectMenuInteractionData().Values
 		if len(values) > 0 {
-			toSqVal, _ := strconv.Atoi(values[0])
-			toSq := chess.Square(toSqVal)
-
-			if game.selectedPiece != nil {
-				validMoves := game.game.ValidMoves()
-				var selectedMove *chess.Move
-				for _, m := range validMoves {
-					if m.S1() == *game.selectedPiece && m.S2() == toSq {
-						if m.Promo() != chess.NoPieceType && m.Promo() != chess.Queen {
-							continue
-						}
-						selectedMove = &m
-						break
-					}
+			coords := strings.Split(values[0], ",")
+			r, _ := strconv.Atoi(coords[0])
+			c, _ := strconv.Atoi(coords[1])
+			if r >= 0 && r < len(game.mines) && c >= 0 && c < len(game.mines[r]) {
+				if game.flagMode {
+					minesweeperToggleFlag(game, r, c)
+				} else {
+					minesweeperDig(game, r, c)
 				}
+				game.selectedRow = -1
+			}
+		}
+	case "cancel_select":
+		game.selectedRow = -1
+	case "mode":
+		game.flagMode = !game.flagMode
+	case "forfeit":
+		minesweeperFinish(game, false)
+		statusMsg = fmt.Sprintf(MsgMinesweeperQuit, game.playerID)
+	}
 
-				if selectedMove != nil {
-					game.game.PushNotationMove(chess.UCINotation{}.Encode(game.game.Position(), selectedMove), chess.UCINotation{}, nil)
+	msg := minesweeperBuildMessage(game, gameID, statusMsg)
+	minesweeperPersist(game, gameID)
+	activeMinesweeperGamesMu.Unlock()
 
-					icon := game.GetPieceIcon(game.game.Position().Board().Piece(selectedMove.S2()))
-					if isWhite {
-						game.p1Icon = icon
-					} else {
-						game.p2Icon = icon
-					}
+	_ = gameUpdateBoard(*event.Client(), event, msg)
+}
 
-					game.lastMove = selectedMove
-					game.selectedPiece = nil
-					game.currentTurn = 3 - game.currentTurn
+// ===========================
+// Minesweeper Logic - Mechanics
+// ===========================
 
-					outcome := game.game.Outcome()
-					if outcome != chess.NoOutcome {
-						game.gameOver = true
-						switch outcome {
-						case chess.WhiteWon:
-							game.winner = 1
-						case chess.BlackWon:
-							game.winner = 2
-						default:
-							game.winner = 0
-						}
+// minesweeperDig uncovers a cell, flooding outwards from cells with no neighbouring mines
+func minesweeperDig(game *minesweeperGame, r, c int) {
+	if game.revealed[r][c] || game.flagged[r][c] {
+		return
+	}
+	if !game.seeded {
+		minesweeperSeed(game, r, c)
+	}
+	game.moveCount++
+	game.lastMoveTime = time.Now()
 
-						userActiveGameMu.Lock()
-						delete(userActiveGame, game.player1ID)
-						delete(userActiveGame, game.player2ID)
-						userActiveGameMu.Unlock()
-					}
-				}
-			}
+	if game.mines[r][c] {
+		game.exploded = &[2]int{r, c}
+		minesweeperFinish(game, false)
+		return
+	}
+
+	stack := [][2]int{{r, c}}
+	for len(stack) > 0 {
+		cell := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if game.revealed[cell[0]][cell[1]] {
+			continue
 		}
-	case "forfeit":
-		game.gameOver = true
-		winnerID := game.player1ID
-		loserID := game.player2ID
-		if isWhite {
-			game.winner = 2
-			winnerID = game.player2ID
-			loserID = game.player1ID
-		} else {
-			game.winner = 1
+		game.revealed[cell[0]][cell[1]] = true
+		game.flagged[cell[0]][cell[1]] = false
+		if minesweeperAdjacent(game, cell[0], cell[1]) > 0 {
+			continue
+		}
+		for _, n := range minesweeperNeighbours(game, cell[0], cell[1]) {
+			if !game.revealed[n[0]][n[1]] && !game.mines[n[0]][n[1]] {
+				stack = append(stack, n)
+			}
 		}
-		statusMsg = fmt.Sprintf(chessStatusForfeit, loserID, winnerID)
-
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
 	}
 
-	msg := ChessBuildMessage(game, gameID, statusMsg)
-	activeChessGamesMu.Unlock()
+	if minesweeperCleared(game) {
+		minesweeperFinish(game, true)
+	}
+}
 
-	_ = UpdateInteractionContainerV2(*event.Client(), event, msg)
+// minesweeperSeed lays the mines away from the first cell dug and, where the field allows, its neighbours
+func minesweeperSeed(game *minesweeperGame, r, c int) {
+	field := game.field()
+	safe := append(minesweeperNeighbours(game, r, c), [2]int{r, c})
+	if field.rows*field.cols-len(safe) < field.mines {
+		safe = [][2]int{{r, c}}
+	}
 
-	if !game.gameOver && game.isAI && game.currentTurn == game.aiPlayerNum {
-		time.AfterFunc(1*time.Second, func() {
-			ChessMakeAIMove(*event.Client(), game, gameID)
-		})
+	var candidates [][2]int
+	for cr := range field.rows {
+		for cc := range field.cols {
+			if !slices.Contains(safe, [2]int{cr, cc}) {
+				candidates = append(candidates, [2]int{cr, cc})
+			}
+		}
 	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	for _, cell := range candidates[:field.mines] {
+		game.mines[cell[0]][cell[1]] = true
+	}
+	game.seeded = true
 }
 
-// ===========================
-// Chess Rendering & Helpers
-// ===========================
+func minesweeperToggleFlag(game *minesweeperGame, r, c int) {
+	if !game.revealed[r][c] {
+		game.flagged[r][c] = !game.flagged[r][c]
+	}
+}
 
-func ChessBuildMessage(game *ChessGame, gameID string, statusMsg string) Container {
-	var sb strings.Builder
+// minesweeperFinish ends the game and frees the player
+func minesweeperFinish(game *minesweeperGame, won bool) {
+	game.gameOver = true
+	game.won = won
+	game.selectedRow = -1
+	game.lastMoveTime = time.Now()
+	gameReleasePlayers(game.playerID)
+}
 
-	corner := BoardCorner
-	cols := BoardColumnEmojis
-	rows := BoardRowEmojis
+func minesweeperNeighbours(game *minesweeperGame, r, c int) [][2]int {
+	var cells [][2]int
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			nr, nc := r+dr, c+dc
+			if (dr != 0 || dc != 0) && nr >= 0 && nr < len(game.mines) && nc >= 0 && nc < len(game.mines[nr]) {
+				cells = append(cells, [2]int{nr, nc})
+			}
+		}
+	}
+	return cells
+}
 
-	reverse := false
-	if game.currentTurn == 2 {
-		reverse = true
+// minesweeperAdjacent counts the mines touching a cell
+func minesweeperAdjacent(game *minesweeperGame, r, c int) int {
+	n := 0
+	for _, cell := range minesweeperNeighbours(game, r, c) {
+		if game.mines[cell[0]][cell[1]] {
+			n++
+		}
 	}
+	return n
+}
 
-	sb.WriteString(corner)
-	if !reverse {
-		for _, c := range cols {
-			sb.WriteString(c)
+// minesweeperCleared reports whether every safe cell has been uncovered
+func minesweeperCleared(game *minesweeperGame) bool {
+	for r := range game.mines {
+		for c := range game.mines[r] {
+			if !game.mines[r][c] && !game.revealed[r][c] {
+				return false
+			}
 		}
-	} else {
-		for i := len(cols) - 1; i >= 0; i-- {
-			sb.WriteString(cols[i])
+	}
+	return true
+}
+
+// minesweeperDiggable reports whether a cell can be chosen in the current mode
+func minesweeperDiggable(game *minesweeperGame, r, c int) bool {
+	return !game.revealed[r][c] && (game.flagMode || !game.flagged[r][c])
+}
+
+// ===========================
+// Minesweeper Rendering & Helpers
+// ===========================
+
+func minesweeperBuildMessage(game *minesweeperGame, gameID string, statusMsg string) Container {
+	field := game.field()
+	flags := 0
+	for r := range game.flagged {
+		for c := range game.flagged[r] {
+			if game.flagged[r][c] {
+				flags++
+			}
 		}
 	}
-	sb.WriteString(corner + "\n")
 
-	rStart, rEnd, rStep := 7, -1, -1
-	cStart, cEnd, cStep := 0, 8, 1
+	var statusSB strings.Builder
+	switch {
+	case game.gameOver && statusMsg != "":
+		statusSB.WriteString(statusMsg)
+	case game.gameOver && game.won:
+		statusSB.WriteString(fmt.Sprintf(MsgMinesweeperWin, game.playerID, gameFormatClock(game.lastMoveTime.Sub(game.startTime))))
+	case game.gameOver:
+		statusSB.WriteString(fmt.Sprintf(MsgMinesweeperLose, game.playerID))
+	default:
+		statusSB.WriteString(fmt.Sprintf(MsgMinesweeperStatus, game.playerID, minesweeperMine, field.mines-flags, game.startTime.Unix()))
+		mode := MsgMinesweeperModeDig
+		if game.flagMode {
+			mode = MsgMinesweeperModeFlag
+		}
+		statusSB.WriteString("\n" + mode)
+	}
 
-	if reverse {
-		rStart, rEnd, rStep = 0, 8, 1
-		cStart, cEnd, cStep = 7, -1, -1
+	var components []any
+	if game.emojiBoard {
+		components = append(components, NewTextDisplay(minesweeperEmojiBoard(game)))
+	} else {
+		components = append(components, minesweeperRenderBoard(game))
 	}
+	components = append(components, NewTextDisplay(statusSB.String()))
 
-	board := game.game.Position().Board()
-	validMoves := game.game.ValidMoves()
-	validTargets := make(map[chess.Square]bool)
+	if game.gameOver {
+		components = append(components, NewSeparator(true))
+		components = append(components, discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSuccess, LabelNewGame, fmt.Sprintf(CIDMinesweeperAgain, gameID), "", 0),
+		))
+		return NewV2Container(components...)
+	}
 
-	if game.selectedPiece != nil {
-		for _, m := range validMoves {
-			if m.S1() == *game.selectedPiece {
-				validTargets[m.S2()] = true
+	var cellOptions []discord.StringSelectMenuOption
+	if game.selectedRow != -1 {
+		r := game.selectedRow
+		for c := range game.mines[r] {
+			if minesweeperDiggable(game, r, c) {
+				label := fmt.Sprintf("Row %d, Col %c", r+1, 'A'+c)
+				cellOptions = append(cellOptions, discord.NewStringSelectMenuOption(label, fmt.Sprintf("%d,%d", r, c)))
 			}
 		}
 	}
 
-	for r := rStart; r != rEnd; r += rStep {
-		sb.WriteString(rows[r])
-		for c := cStart; c != cEnd; c += cStep {
-			sq := chess.Square(r*8 + c)
-			p := board.Piece(sq)
-
-			bg := chessWhiteSquare
-			if (r+c)%2 == 0 {
-				bg = chessBlackSquare
+	if len(cellOptions) > 0 {
+		menu := discord.NewStringSelectMenu(fmt.Sprintf(CIDMinesweeperCell, gameID), LabelSelectCell, cellOptions...)
+		components = append(components, discord.NewActionRow(menu))
+		components = append(components, discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSecondary, LabelCancelSelect, fmt.Sprintf(CIDMinesweeperCancel, gameID), "", 0),
+		))
+	} else {
+		var rowOptions []discord.StringSelectMenuOption
+		for r := range game.mines {
+			for c := range game.mines[r] {
+				if minesweeperDiggable(game, r, c) {
+					rowOptions = append(rowOptions, discord.NewStringSelectMenuOption(fmt.Sprintf("Row %d", r+1), strconv.Itoa(r)))
+					break
+				}
 			}
+		}
+		if len(rowOptions) > 0 {
+			menu := discord.NewStringSelectMenu(fmt.Sprintf(CIDMinesweeperRow, gameID), LabelSelectRow, rowOptions...)
+			components = append(components, discord.NewActionRow(menu))
+		}
+	}
 
-			isSelected := game.selectedPiece != nil && *game.selectedPiece == sq
-
-			isLastMoveSrc := game.lastMove != nil && game.lastMove.S1() == sq
-			isLastMoveDst := game.lastMove != nil && game.lastMove.S2() == sq
-			isTarget := validTargets[sq]
+	modeLabel := LabelFlagMode
+	if game.flagMode {
+		modeLabel = LabelDigMode
+	}
+	components = append(components, discord.NewActionRow(
+		discord.NewButton(discord.ButtonStylePrimary, modeLabel, fmt.Sprintf(CIDMinesweeperMode, gameID), "", 0),
+		discord.NewButton(discord.ButtonStyleDanger, LabelForfeit, fmt.Sprintf(CIDMinesweeperForfeit, gameID), "", 0),
+	))
 
-			display := bg
+	return NewV2Container(components...)
+}
 
-			if isSelected {
-				display = chessSelected
-			} else if isTarget || isLastMoveSrc || isLastMoveDst {
-				if bg == chessWhiteSquare {
-					display = chessTargetWhite
+// minesweeperEmojiBoard draws the field with emoji, uncovering every mine once the game is over
+func minesweeperEmojiBoard(game *minesweeperGame) string {
+	field := game.field()
+	var sb strings.Builder
+	header := BoardCorner + strings.Join(BoardColumnEmojis[:field.cols], "") + BoardCorner + "\n"
+	sb.WriteString(header)
+	for r := range field.rows {
+		sb.WriteString(BoardRowEmojis[r])
+		for c := range field.cols {
+			switch {
+			case game.exploded != nil && *game.exploded == [2]int{r, c}:
+				sb.WriteString(minesweeperExploded)
+			case game.gameOver && game.mines[r][c] && !game.flagged[r][c]:
+				sb.WriteString(minesweeperMine)
+			case game.flagged[r][c]:
+				sb.WriteString(minesweeperFlag)
+			case !game.revealed[r][c]:
+				sb.WriteString(minesweeperHidden)
+			default:
+				if n := minesweeperAdjacent(game, r, c); n > 0 {
+					sb.WriteString(BoardRowEmojis[n-1])
 				} else {
-					display = chessTargetBlack
+					sb.WriteString(minesweeperClear)
 				}
 			}
+		}
+		sb.WriteString(BoardRowEmojis[r] + "\n")
+	}
+	sb.WriteString(header)
+	return sb.String()
+}
 
-			pieceIcon := game.GetPieceIcon(p)
-			if pieceIcon != "" {
-				display = pieceIcon
+// minesweeperRenderBoard draws the field as a PNG, highlighting the row being picked from
+func minesweeperRenderBoard(game *minesweeperGame) gameBoardImage {
+	field := game.field()
+	canvas := newGameCanvas(field.rows, field.cols)
+	for r := range field.rows {
+		for c := range field.cols {
+			switch {
+			case game.exploded != nil && *game.exploded == [2]int{r, c}:
+				canvas.fill(r, c, gameImageRed)
+			case game.revealed[r][c] || (game.gameOver && game.mines[r][c]):
+				canvas.fill(r, c, minesweeperImageRevealed)
+			default:
+				canvas.fill(r, c, minesweeperImageHidden)
+				if r == game.selectedRow && minesweeperDiggable(game, r, c) {
+					canvas.tint(r, c, gameImageSelected, 0.5)
+				}
 			}
 
-			sb.WriteString(display)
+			switch {
+			case game.gameOver && game.mines[r][c] && game.flagged[r][c]:
+				// Correct flags turn green when the field is uncovered
+				canvas.label(r, c, "F", 3, gameImageTarget)
+			case game.gameOver && game.mines[r][c]:
+				canvas.disc(r, c, 0.5, gameImageBlack, gameImageBlack, 0)
+			case game.flagged[r][c]:
+				canvas.label(r, c, "F", 3, gameImageRed)
+			case game.revealed[r][c]:
+				if n := minesweeperAdjacent(game, r, c); n > 0 {
+					canvas.label(r, c, strconv.Itoa(n), 3, minesweeperImageNumbers[n])
+				}
+			}
 		}
-		sb.WriteString(rows[r] + "\n")
 	}
-	sb.WriteString(corner)
+	canvas.grid(field.rows, field.cols, 0, gameImageBackground)
+	canvas.coords(gameBoardLabels(field.cols, 'A', false), gameBoardLabels(field.rows, '1', false))
+	return canvas.attach()
+}
 
-	wCount, bCount := 0, 0
-	wMap := board.SquareMap()
-	for _, p := range wMap {
-		if p.Color() == chess.White {
-			wCount++
-		} else {
-			bCount++
-		}
-	}
+// ===========================
+// Hangman Game Constants & Types
+// ===========================
 
-	p1Disp := fmt.Sprintf("%s <@%d>: **%d**", game.p1Icon, game.player1ID, wCount)
-	p2Disp := fmt.Sprintf("%s <@%d>: **%d**", game.p2Icon, game.player2ID, bCount)
+const hangmanMaxMisses = 6
+
+// hangmanStages draws the gallows after each miss
+var hangmanStages = [hangmanMaxMisses + 1]string{
+	"  +---+\n  |   |\n      |\n      |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n      |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n  |   |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n /|   |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n /|\\  |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n /|\\  |\n /    |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n /|\\  |\n / \\  |\n      |\n=========",
+}
 
-	scoreStr := fmt.Sprintf("\n-# %s | %s", p1Disp, p2Disp)
-	sb.WriteString(scoreStr)
+// hangmanWords is the pool the hidden word is drawn from
+var hangmanWords = []string{
+	"ABSURD", "AVENUE", "AWKWARD", "BAGPIPES", "BANJO", "BEEKEEPER", "BLIZZARD", "BOOKWORM", "BUFFALO", "BUZZARD",
+	"CALENDAR", "CANYON", "CAPTAIN", "CASTLE", "CHIMNEY", "COBWEB", "CRYPT", "CURTAIN", "DOLPHIN", "DRAGON",
+	"DUNGEON", "EMBASSY", "EQUATOR", "FESTIVAL", "FISHHOOK", "FJORD", "FLAMINGO", "FOXGLOVE", "GALAXY", "GAZEBO",
+	"GLACIER", "GOSSIP", "GRAVITY", "HARBOR", "HELMET", "HORIZON", "ICEBERG", "IVORY", "JACKPOT", "JIGSAW",
+	"JOURNEY", "JUKEBOX", "KAYAK", "KETCHUP", "KIWIFRUIT", "KNAPSACK", "LANTERN", "LIGHTHOUSE", "LUXURY", "MAGNET",
+	"MARATHON", "MEADOW", "MUSHROOM", "MYSTERY", "NEBULA", "NIGHTCLUB", "NOTEBOOK", "OASIS", "ORCHESTRA", "OXYGEN",
+	"PAJAMA", "PARROT", "PENGUIN", "PHARAOH", "PIRATE", "PLANET", "PUZZLE", "PYRAMID", "QUARTZ", "QUIVER",
+	"RAINBOW", "RHYTHM", "ROCKET", "SAXOPHONE", "SCARECROW", "SPHINX", "SQUIRREL", "SUBWAY", "SUNFLOWER", "SWIVEL",
+	"TELESCOPE", "THUNDER", "TORNADO", "TREASURE", "TUNDRA", "TWELFTH", "UMBRELLA", "UNICORN", "VAMPIRE", "VOLCANO",
+	"VORTEX", "WALKWAY", "WALRUS", "WAVELENGTH", "WHISKEY", "WIZARD", "XYLOPHONE", "YACHT", "ZEPHYR", "ZOMBIE",
+}
 
-	var statusSB strings.Builder
-	if game.gameOver {
-		if statusMsg != "" {
-			statusSB.WriteString(statusMsg)
-		} else {
-			outcome := game.game.Outcome()
-			winnerID := game.player1ID
-			loserID := game.player2ID
-			if game.winner == 2 {
-				winnerID = game.player2ID
-				loserID = game.player1ID
-			}
+// hangmanGame represents a solo hangman session
+type hangmanGame struct {
+	word         string
+	guessed      string // letters tried so far, in the order they were guessed
+	misses       int
+	playerID     snowflake.ID
+	gameOver     bool
+	won          bool
+	moveCount    int
+	lastMoveTime time.Time
+	messageID    snowflake.ID
+	channelID    snowflake.ID
+}
 
-			if outcome == chess.WhiteWon || outcome == chess.BlackWon {
-				statusSB.WriteString(fmt.Sprintf(chessStatusWin, loserID, winnerID))
-			} else {
-				statusSB.WriteString(fmt.Sprintf(chessStatusDraw, game.player1ID, game.player2ID))
-			}
-		}
-	} else {
-		currentPlayer := game.player1ID
-		statusIcon := game.p1Icon
-		if game.currentTurn == 2 {
-			currentPlayer = game.player2ID
-			statusIcon = game.p2Icon
-		}
-		statusSB.WriteString(fmt.Sprintf(chessStatusTurn, currentPlayer, statusIcon))
+// hangmanSnapshot is the serialized form of a hangmanGame stored in the database
+type hangmanSnapshot struct {
+	Word         string       `json:"word"`
+	Guessed      string       `json:"guessed"`
+	Misses       int          `json:"misses"`
+	PlayerID     snowflake.ID `json:"player_id"`
+	MoveCount    int          `json:"move_count"`
+	LastMoveTime time.Time    `json:"last_move_time"`
+	MessageID    snowflake.ID `json:"message_id"`
+	ChannelID    snowflake.ID `json:"channel_id"`
+}
 
-		if statusMsg != "" {
-			statusSB.WriteString("\n" + statusMsg)
-		}
-		if game.game.Position().Status() == chess.Checkmate {
-			statusSB.WriteString(" (CHECKMATE)")
-		} else if game.lastMove != nil && game.lastMove.HasTag(chess.Check) {
-			statusSB.WriteString(" (CHECK)")
-		}
+// ===========================
+// Hangman Logic - Core
+// ===========================
+
+func newHangmanGame(playerID snowflake.ID) *hangmanGame {
+	return &hangmanGame{
+		word:         hangmanWords[rand.Intn(len(hangmanWords))],
+		playerID:     playerID,
+		lastMoveTime: time.Now(),
 	}
+}
 
-	var components []interface{}
-	components = append(components, NewTextDisplay(sb.String()))
-	components = append(components, NewTextDisplay(statusSB.String()))
+// hangmanPersist saves the game state, or forgets it once the game is over (caller must hold activeHangmanGamesMu)
+func hangmanPersist(game *hangmanGame, gameID string) {
+	if game.gameOver {
+		gameForget(gameID)
+		return
+	}
+	gameSaveState(gameID, CmdHangman, hangmanSnapshot{
+		Word:         game.word,
+		Guessed:      game.guessed,
+		Misses:       game.misses,
+		PlayerID:     game.playerID,
+		MoveCount:    game.moveCount,
+		LastMoveTime: game.lastMoveTime,
+		MessageID:    game.messageID,
+		ChannelID:    game.channelID,
+	})
+}
 
-	if !game.gameOver {
-		seen := make(map[chess.Square]bool)
-		var pieceOpts []discord.StringSelectMenuOption
+// hangmanRestore rebuilds a saved game
+func hangmanRestore(client bot.Client, gameID string, state string) error {
+	var snap hangmanSnapshot
+	if err := json.Unmarshal([]byte(state), &snap); err != nil {
+		return err
+	}
+	if snap.Word == "" || snap.Misses < 0 || snap.Misses >= hangmanMaxMisses {
+		return fmt.Errorf("invalid hangman state")
+	}
 
-		for _, m := range validMoves {
-			s1 := m.S1()
-			if !seen[s1] {
-				seen[s1] = true
+	game := &hangmanGame{
+		word:         snap.Word,
+		guessed:      snap.Guessed,
+		misses:       snap.Misses,
+		playerID:     snap.PlayerID,
+		moveCount:    snap.MoveCount,
+		lastMoveTime: snap.LastMoveTime,
+		messageID:    snap.MessageID,
+		channelID:    snap.ChannelID,
+	}
 
-				label := fmt.Sprintf("%c%d", 'A'+s1.File(), int(s1.Rank())+1)
-				val := strconv.Itoa(int(s1))
+	activeHangmanGamesMu.Lock()
+	activeHangmanGames[gameID] = game
+	activeHangmanGamesMu.Unlock()
 
-				p := board.Piece(s1)
-				icon := game.GetPieceIcon(p)
+	gameLockPlayers(client, gameID, game.playerID)
+	return nil
+}
 
-				pieceOpts = append(pieceOpts, discord.NewStringSelectMenuOption(icon+" "+label, val))
-			}
-		}
-		sort.Slice(pieceOpts, func(i, j int) bool { return pieceOpts[i].Label < pieceOpts[j].Label })
+// ===========================
+// Hangman Interaction Handlers
+// ===========================
 
-		if game.selectedPiece != nil {
-			var destOpts []discord.StringSelectMenuOption
-			for _, m := range validMoves {
-				if m.S1() == *game.selectedPiece {
-					s2 := m.S2()
-					label := fmt.Sprintf("To %c%d", 'A'+s2.File(), int(s2.Rank())+1)
-					val := strconv.Itoa(int(s2))
+func handlePlayHangman(event *events.ApplicationCommandInteractionCreate, _ discord.SlashCommandInteractionData) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in handlePlayHangman: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
 
-					if m.Promo() != chess.NoPieceType && m.Promo() != chess.Queen {
-						continue
-					}
-					if m.Promo() == chess.Queen {
-						label += " (Queen)"
-					}
+	playerID := event.User().ID
+	gameID := fmt.Sprintf(CIDHangmanGameID, event.Channel().ID(), time.Now().UnixNano())
+	if errMsg := gameReservePlayers(*event.Client(), gameID, playerID); errMsg != "" {
+		event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+		return
+	}
 
-					destOpts = append(destOpts, discord.NewStringSelectMenuOption(label, val))
-				}
-			}
+	hangmanOpen(*event.Client(), event, newHangmanGame(playerID), gameID)
+}
 
-			if len(destOpts) > 0 {
-				if len(destOpts) > 25 {
-					destOpts = destOpts[:25]
-				}
-				menu := discord.NewStringSelectMenu(fmt.Sprintf(CIDChessMoveTo, gameID), LabelSelectDest, destOpts...)
-				components = append(components, discord.NewActionRow(menu))
+// hangmanOpen posts a new game in reply to the interaction; the player must already be reserved
+func hangmanOpen(client bot.Client, interaction discord.Interaction, game *hangmanGame, gameID string) {
+	activeHangmanGamesMu.Lock()
+	activeHangmanGames[gameID] = game
+	game.channelID = interaction.Channel().ID()
+	msg := hangmanBuildMessage(game, gameID, "")
+	activeHangmanGamesMu.Unlock()
 
-				cancelBtn := discord.NewButton(discord.ButtonStyleSecondary, "Cancel Selection", fmt.Sprintf(CIDChessCancelSelect, gameID), "", 0)
-				components = append(components, discord.NewActionRow(cancelBtn))
-			}
-		}
+	if err := RespondInteractionContainerV2(client, interaction, msg, false); err != nil {
+		activeHangmanGamesMu.Lock()
+		delete(activeHangmanGames, gameID)
+		activeHangmanGamesMu.Unlock()
 
-		if len(pieceOpts) > 0 {
-			if len(pieceOpts) > 25 {
-				pieceOpts = pieceOpts[:25]
-			}
-			placeholder := LabelSelectPiece
-			if game.selectedPiece != nil {
-				s := *game.selectedPiece
-				placeholder = fmt.Sprintf("Selected: %c%d", 'A'+s.File(), int(s.Rank())+1)
-			}
-			menu := discord.NewStringSelectMenu(fmt.Sprintf(CIDChessSelectPiece, gameID), placeholder, pieceOpts...)
-			components = append(components, discord.NewActionRow(menu))
-		}
+		gameReleasePlayers(game.playerID)
+		return
+	}
 
-		row := discord.NewActionRow(
-			discord.NewButton(discord.ButtonStyleDanger, LabelForfeit, fmt.Sprintf(CIDChessForfeit, gameID), "", 0),
-		)
-		components = append(components, row)
+	var messageID snowflake.ID
+	if resp, err := client.Rest.GetInteractionResponse(interaction.ApplicationID(), interaction.Token()); err == nil && resp != nil {
+		messageID = resp.ID
 	}
 
-	return NewV2Container(components...)
+	activeHangmanGamesMu.Lock()
+	game.messageID = messageID
+	hangmanPersist(game, gameID)
+	activeHangmanGamesMu.Unlock()
 }
-func ChessMakeAIMove(client bot.Client, game *ChessGame, gameID string) {
-	activeChessGamesMu.Lock()
-	if game.gameOver || game.currentTurn != game.aiPlayerNum {
-		activeChessGamesMu.Unlock()
+
+func hangmanHandleInteraction(event *events.ComponentInteractionCreate) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("Panic in hangmanHandleInteraction: %v", r)
+			fmt.Printf("%s\n", debug.Stack())
+		}
+	}()
+
+	parts := strings.Split(event.Data.CustomID(), ":")
+	if len(parts) < 3 {
 		return
 	}
+	gameID := parts[1]
+	action := parts[2]
 
-	difficulty := game.aiDifficulty
-	fen := game.game.Position().String()
-	moves := game.game.ValidMoves()
-	activeChessGamesMu.Unlock()
-
-	if len(moves) == 0 {
+	activeHangmanGamesMu.Lock()
+	game, exists := activeHangmanGames[gameID]
+	if !exists {
+		activeHangmanGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotFound).WithEphemeral(true))
+		return
+	}
+	if event.User().ID != game.playerID {
+		activeHangmanGamesMu.Unlock()
+		event.CreateMessage(discord.NewMessageCreate().WithContent(MsgGameNotPlayer).WithEphemeral(true))
 		return
 	}
 
-	var selectedMove *chess.Move
-	isHardFallback := false
+	if action == "again" {
+		if !game.gameOver {
+			activeHangmanGamesMu.Unlock()
+			event.DeferUpdateMessage()
+			return
+		}
+		activeHangmanGamesMu.Unlock()
 
-	if difficulty == "hard" {
-		reqBody, _ := json.Marshal(map[string]string{"fen": fen})
-		resp, err := http.Post("https://chess-api.com/v1", "application/json", bytes.NewBuffer(reqBody))
-		if err == nil {
-			defer resp.Body.Close()
-			var apiResp struct {
-				Move string `json:"move"`
-			}
-			if err := json.NewDecoder(resp.Body).Decode(&apiResp); err == nil {
-				activeChessGamesMu.Lock()
-				move, err := chess.UCINotation{}.Decode(game.game.Position(), apiResp.Move)
-				if err == nil {
-					selectedMove = move
-				}
-				activeChessGamesMu.Unlock()
-			}
+		nextID := fmt.Sprintf(CIDHangmanGameID, event.Channel().ID(), time.Now().UnixNano())
+		if errMsg := gameReservePlayers(*event.Client(), nextID, game.playerID); errMsg != "" {
+			event.CreateMessage(discord.NewMessageCreate().WithContent(errMsg).WithEphemeral(true))
+			return
 		}
+		hangmanOpen(*event.Client(), event, newHangmanGame(game.playerID), nextID)
+		return
+	}
+
+	if game.gameOver {
+		activeHangmanGamesMu.Unlock()
+		event.DeferUpdateMessage()
+		return
+	}
 
-		if selectedMove == nil {
-			isHardFallback = true
-			difficulty = "normal"
+	statusMsg := ""
+	switch {
+	case strings.HasPrefix(action, "guess"):
+		values := event.StringSelectMenuInteractionData().Values
+		if len(values) > 0 && len(values[0]) == 1 {
+			hangmanGuess(game, rune(values[0][0]))
 		}
+	case action == "forfeit":
+		hangmanFinish(game, false)
+		statusMsg = fmt.Sprintf(MsgHangmanQuit, game.playerID, game.word)
 	}
 
-	activeChessGamesMu.Lock()
-	defer activeChessGamesMu.Unlock()
+	msg := hangmanBuildMessage(game, gameID, statusMsg)
+	hangmanPersist(game, gameID)
+	activeHangmanGamesMu.Unlock()
 
-	if game.gameOver || game.currentTurn != game.aiPlayerNum {
+	_ = UpdateInteractionContainerV2(*event.Client(), event, msg)
+}
+
+// ===========================
+// Hangman Logic - Mechanics
+// ===========================
+
+// hangmanGuess tries a letter, ending the game once the word is complete or the gallows is
+func hangmanGuess(game *hangmanGame, letter rune) {
+	if letter < 'A' || letter > 'Z' || strings.ContainsRune(game.guessed, letter) {
 		return
 	}
+	game.guessed += string(letter)
+	game.moveCount++
+	game.lastMoveTime = time.Now()
 
-	if selectedMove == nil {
-		var captureMoves []*chess.Move
-		for _, m := range moves {
-			mCopy := m
-			if m.HasTag(chess.Capture) {
-				captureMoves = append(captureMoves, &mCopy)
-			}
+	if !strings.ContainsRune(game.word, letter) {
+		game.misses++
+		if game.misses >= hangmanMaxMisses {
+			hangmanFinish(game, false)
 		}
+		return
+	}
+	if !strings.ContainsFunc(game.word, func(r rune) bool { return !strings.ContainsRune(game.guessed, r) }) {
+		hangmanFinish(game, true)
+	}
+}
+
+// hangmanFinish ends the game and frees the player
+func hangmanFinish(game *hangmanGame, won bool) {
+	game.gameOver = true
+	game.won = won
+	gameReleasePlayers(game.playerID)
+}
 
-		if len(captureMoves) > 0 && difficulty != "easy" {
-			selectedMove = captureMoves[rand.Intn(len(captureMoves))]
+// ===========================
+// Hangman Rendering & Helpers
+// ===========================
+
+func hangmanBuildMessage(game *hangmanGame, gameID string, statusMsg string) Container {
+	var word strings.Builder
+	for i, r := range game.word {
+		if i > 0 {
+			word.WriteString(" ")
+		}
+		if game.gameOver || strings.ContainsRune(game.guessed, r) {
+			word.WriteRune(r)
 		} else {
-			mCopy := moves[rand.Intn(len(moves))]
-			selectedMove = &mCopy
+			word.WriteString("_")
 		}
 	}
 
-	game.game.PushNotationMove(chess.UCINotation{}.Encode(game.game.Position(), selectedMove), chess.UCINotation{}, nil)
-	game.lastMove = selectedMove
-
-	icon := game.GetPieceIcon(game.game.Position().Board().Piece(selectedMove.S2()))
-	if game.aiPlayerNum == 1 {
-		game.p1Icon = icon
-	} else {
-		game.p2Icon = icon
+	var missed []string
+	for _, r := range game.guessed {
+		if !strings.ContainsRune(game.word, r) {
+			missed = append(missed, string(r))
+		}
+	}
+	missStr := fmt.Sprintf(MsgHangmanMisses, strings.Join(missed, " "), hangmanMaxMisses-game.misses)
+	if len(missed) == 0 {
+		missStr = fmt.Sprintf(MsgHangmanMisses, "-", hangmanMaxMisses-game.misses)
 	}
 
-	game.currentTurn = 3 - game.currentTurn
-
-	outcome := game.game.Outcome()
-	if outcome != chess.NoOutcome {
-		game.gameOver = true
-		switch outcome {
-		case chess.WhiteWon:
-			game.winner = 1
-		case chess.BlackWon:
-			game.winner = 2
-		default:
-			game.winner = 0
-		}
+	var statusSB strings.Builder
+	switch {
+	case game.gameOver && statusMsg != "":
+		statusSB.WriteString(statusMsg)
+	case game.gameOver && game.won:
+		statusSB.WriteString(fmt.Sprintf(MsgHangmanWin, game.playerID, game.word))
+	case game.gameOver:
+		statusSB.WriteString(fmt.Sprintf(MsgHangmanLose, game.playerID, game.word))
+	default:
+		statusSB.WriteString(fmt.Sprintf(MsgHangmanStatus, game.playerID))
+	}
 
-		userActiveGameMu.Lock()
-		delete(userActiveGame, game.player1ID)
-		delete(userActiveGame, game.player2ID)
-		userActiveGameMu.Unlock()
+	components := []any{
+		NewTextDisplay("```\n" + hangmanStages[game.misses] + "\n```"),
+		NewTextDisplay("## `" + word.String() + "`"),
+		NewTextDisplay(missStr),
+		NewTextDisplay(statusSB.String()),
 	}
 
-	statusMsg := ""
-	if isHardFallback {
-		statusMsg = MsgGameAIHardFallback
+	if game.gameOver {
+		components = append(components, NewSeparator(true))
+		components = append(components, discord.NewActionRow(
+			discord.NewButton(discord.ButtonStyleSuccess, LabelNewGame, fmt.Sprintf(CIDHangmanAgain, gameID), "", 0),
+		))
+		return NewV2Container(components...)
+	}
+
+	// The alphabet is split in two because a select menu holds at most 25 options
+	halves := []struct {
+		from, to    rune
+		customID    string
+		placeholder string
+	}{
+		{'A', 'M', CIDHangmanGuessAM, LabelGuessAM},
+		{'N', 'Z', CIDHangmanGuessNZ, LabelGuessNZ},
+	}
+	for _, half := range halves {
+		var options []discord.StringSelectMenuOption
+		for r := half.from; r <= half.to; r++ {
+			if !strings.ContainsRune(game.guessed, r) {
+				options = append(options, discord.NewStringSelectMenuOption(string(r), string(r)))
+			}
+		}
+		if len(options) > 0 {
+			menu := discord.NewStringSelectMenu(fmt.Sprintf(half.customID, gameID), half.placeholder, options...)
+			components = append(components, discord.NewActionRow(menu))
+		}
 	}
+	components = append(components, discord.NewActionRow(
+		discord.NewButton(discord.ButtonStyleDanger, LabelForfeit, fmt.Sprintf(CIDHangmanForfeit, gameID), "", 0),
+	))
 
-	msg := ChessBuildMessage(game, gameID, statusMsg)
-	_, _ = EditContainerV2(client, game.channelID, game.messageID, msg, nil, nil)
+	return NewV2Container(components...)
 }
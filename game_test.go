@@ -0,0 +1,141 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// checkersTestGame sets up an otherwise empty board of the variant's size with the given pieces
+func checkersTestGame(variant string, pieces map[[2]int]CheckersPieceType) *CheckersGame {
+	size := checkersRulesFor(variant).size
+	g := &CheckersGame{variant: variant, forcedCapture: true}
+	g.board = make([][]CheckersPieceType, size)
+	for r := range g.board {
+		g.board[r] = make([]CheckersPieceType, size)
+	}
+	for sq, p := range pieces {
+		g.board[sq[0]][sq[1]] = p
+	}
+	return g
+}
+
+func TestCheckersVariantCaptures(t *testing.T) {
+	// Player 1's men move toward row 0, where they are crowned
+	tests := []struct {
+		name     string
+		variant  string
+		pieces   map[[2]int]CheckersPieceType
+		moves    int      // legal moves for player 1
+		path     [][2]int // the move to play
+		captured int
+		want     CheckersPieceType // what stands on the last square afterwards
+	}{
+		{
+			name:    "english man stops on the far row and is crowned",
+			variant: ChoiceEnglish,
+			pieces: map[[2]int]CheckersPieceType{
+				{2, 1}: PieceP1, {1, 2}: PieceP2, {1, 4}: PieceP2,
+			},
+			moves:    1,
+			path:     [][2]int{{2, 1}, {0, 3}},
+			captured: 1,
+			want:     PieceP1King,
+		},
+		{
+			name:    "brazilian man passes the far row and stays a man",
+			variant: ChoiceBrazilian,
+			pieces: map[[2]int]CheckersPieceType{
+				{2, 1}: PieceP1, {1, 2}: PieceP2, {1, 4}: PieceP2,
+			},
+			moves:    1,
+			path:     [][2]int{{2, 1}, {0, 3}, {2, 5}},
+			captured: 2,
+			want:     PieceP1,
+		},
+		{
+			name:    "russian man crowned mid-capture leaves as a king",
+			variant: ChoiceRussian,
+			pieces: map[[2]int]CheckersPieceType{
+				{2, 1}: PieceP1, {1, 2}: PieceP2, {1, 4}: PieceP2,
+			},
+			moves:    3, // a flying king may land on any square past the second victim
+			path:     [][2]int{{2, 1}, {0, 3}, {2, 5}},
+			captured: 2,
+			want:     PieceP1King,
+		},
+		{
+			name:    "russian man crowned mid-capture carries on capturing as a flying king",
+			variant: ChoiceRussian,
+			pieces: map[[2]int]CheckersPieceType{
+				{2, 1}: PieceP1, {1, 2}: PieceP2, {2, 5}: PieceP2,
+			},
+			moves:    2,
+			path:     [][2]int{{2, 1}, {0, 3}, {3, 6}},
+			captured: 2,
+			want:     PieceP1King,
+		},
+		{
+			name:    "russian flying king must land where it can keep capturing",
+			variant: ChoiceRussian,
+			pieces: map[[2]int]CheckersPieceType{
+				{7, 0}: PieceP1King, {5, 2}: PieceP2, {2, 3}: PieceP2, {0, 1}: PieceP2,
+			},
+			moves:    1,
+			path:     [][2]int{{7, 0}, {3, 4}, {1, 2}},
+			captured: 2,
+			want:     PieceP1King,
+		},
+		{
+			name:    "international requires the longest capture",
+			variant: ChoiceInternational,
+			pieces: map[[2]int]CheckersPieceType{
+				{7, 4}: PieceP1, {6, 3}: PieceP2, {4, 1}: PieceP2, {6, 5}: PieceP2,
+			},
+			moves:    1,
+			path:     [][2]int{{7, 4}, {5, 2}, {3, 0}},
+			captured: 2,
+			want:     PieceP1,
+		},
+		{
+			name:    "russian lets either capture be chosen",
+			variant: ChoiceRussian,
+			pieces: map[[2]int]CheckersPieceType{
+				{7, 4}: PieceP1, {6, 3}: PieceP2, {4, 1}: PieceP2, {6, 5}: PieceP2,
+			},
+			moves:    2,
+			path:     [][2]int{{7, 4}, {5, 6}},
+			captured: 1,
+			want:     PieceP1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := checkersTestGame(tt.variant, tt.pieces)
+			legal := checkersLegalMoves(g, 1)
+			if len(legal) != tt.moves {
+				t.Fatalf("got %d legal moves, want %d: %v", len(legal), tt.moves, legal)
+			}
+
+			i := slices.IndexFunc(legal, func(m checkersMove) bool { return slices.Equal(m.path, tt.path) })
+			if i < 0 {
+				t.Fatalf("move %v is not legal; legal moves are %v", tt.path, legal)
+			}
+			m := legal[i]
+			if len(m.captured) != tt.captured {
+				t.Fatalf("move captures %d pieces, want %d", len(m.captured), tt.captured)
+			}
+
+			checkersApplyMove(g, m)
+			to := m.to()
+			if got := g.board[to[0]][to[1]]; got != tt.want {
+				t.Errorf("piece on %v is %v, want %v", to, got, tt.want)
+			}
+			for _, sq := range m.captured {
+				if g.board[sq[0]][sq[1]] != PieceNone {
+					t.Errorf("captured piece on %v is still on the board", sq)
+				}
+			}
+		})
+	}
+}
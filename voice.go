@@ -3,27 +3,34 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"cmp"
 	"container/heap"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"math"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode"
 
 	"github.com/asticode/go-astiav"
 	"github.com/disgoorg/disgo/bot"
@@ -50,6 +57,8 @@ func init() {
 		RegisterDaemon("VOICE", LogVoice, func(ctx context.Context) (bool, func(), func()) {
 			return true, func() {
 					safeGo(vm.startCacheGC)
+					safeGo(runLyricsTicker)
+					safeGo(func() { vm.RestoreSessions(context.Background(), client) })
 				}, func() {
 					if vm != nil {
 						LogVoice("Shutting down Voice Manager...")
@@ -72,7 +81,18 @@ func init() {
 					discord.ApplicationCommandOptionString{
 						Name:         "query",
 						Description:  "The URL or song name to play",
-						Required:     true,
+						Required:     false,
+						Autocomplete: true,
+					},
+					discord.ApplicationCommandOptionAttachment{
+						Name:        "file",
+						Description: "An audio file to play",
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:         "local",
+						Description:  "A track or folder from the bot's music library",
+						Required:     false,
 						Autocomplete: true,
 					},
 					discord.ApplicationCommandOptionString{
@@ -100,6 +120,63 @@ func init() {
 			discord.ApplicationCommandOptionSubCommand{
 				Name:        "queue",
 				Description: "Show the current queue",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "page",
+						Description: "Page of the queue to show",
+						Required:    false,
+						MinValue:    intPtr(1),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "shuffle",
+				Description: "Shuffle the upcoming tracks",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "remove",
+				Description: "Remove tracks from the queue",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:        "position",
+						Description: "Position or range to remove (e.g. 3 or 2-5)",
+						Required:    true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "move",
+				Description: "Move a track to another position in the queue",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "from",
+						Description: "Current position of the track",
+						Required:    true,
+						MinValue:    intPtr(1),
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "to",
+						Description: "New position of the track",
+						Required:    true,
+						MinValue:    intPtr(1),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "clear",
+				Description: "Remove every upcoming track",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "skipto",
+				Description: "Skip ahead to a track in the queue",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "position",
+						Description: "Position of the track to play next",
+						Required:    true,
+						MinValue:    intPtr(1),
+					},
+				},
 			},
 			discord.ApplicationCommandOptionSubCommand{
 				Name:        "forward",
@@ -144,6 +221,305 @@ func init() {
 				Name:        "panel",
 				Description: "Open a live Now Playing panel",
 			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "filter",
+				Description: "Apply an audio effect to the session",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:        "preset",
+						Description: "Effect preset (defaults to off)",
+						Required:    false,
+						Choices:     audioFilterChoices(),
+					},
+					discord.ApplicationCommandOptionFloat{
+						Name:        "tempo",
+						Description: "Playback speed without changing pitch (0.5-2.0)",
+						Required:    false,
+						MinValue:    floatPtr(0.5),
+						MaxValue:    floatPtr(2),
+					},
+					discord.ApplicationCommandOptionFloat{
+						Name:        "pitch",
+						Description: "Pitch shift without changing speed (0.5-2.0)",
+						Required:    false,
+						MinValue:    floatPtr(0.5),
+						MaxValue:    floatPtr(2),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "crossfade",
+				Description: "Blend one track into the next, or drop the pause between them",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name:        "seconds",
+						Description: "Crossfade length (0 turns it off)",
+						Required:    false,
+						MinValue:    intPtr(0),
+						MaxValue:    intPtr(int(MaxCrossfade / time.Second)),
+					},
+					discord.ApplicationCommandOptionBool{
+						Name:        "gapless",
+						Description: "Skip the silence between tracks even without a crossfade",
+						Required:    false,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "dj",
+				Description: "Show or change who can control playback (requires Manage Server)",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionRole{
+						Name:        "role",
+						Description: "Role allowed to stop, skip and change the queue without a vote",
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionInt{
+						Name:        "votes",
+						Description: "Share of listeners needed to vote-skip, in percent",
+						Required:    false,
+						MinValue:    intPtr(1),
+						MaxValue:    intPtr(100),
+					},
+					discord.ApplicationCommandOptionBool{
+						Name:        "reset",
+						Description: "Remove the DJ role so everyone can control playback again",
+						Required:    false,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "radio",
+				Description: "Keep music playing around the clock from a server playlist or seed tracks",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionBool{
+						Name:        "enabled",
+						Description: "Turn the radio on in your voice channel, or off",
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:         "playlist",
+						Description:  "Server playlist the radio draws from",
+						Required:     false,
+						Autocomplete: true,
+					},
+					discord.ApplicationCommandOptionString{
+						Name:        "seeds",
+						Description: "Seed tracks or searches, separated by ;",
+						Required:    false,
+					},
+					discord.ApplicationCommandOptionBool{
+						Name:        "clear",
+						Description: "Forget the saved playlist and seeds",
+						Required:    false,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				Name:        "stats",
+				Description: "Show what this server has been listening to",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:        "period",
+						Description: "Time range to summarize (default: week)",
+						Required:    false,
+						Choices:     voiceStatsPeriodChoices,
+					},
+					discord.ApplicationCommandOptionBool{
+						Name:        "export",
+						Description: "Attach the full play log as a CSV file",
+						Required:    false,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommandGroup{
+				Name:        "playlist",
+				Description: "Saved playlists",
+				Options: []discord.ApplicationCommandOptionSubCommand{
+					{
+						Name:        "save",
+						Description: "Save the current track and queue as a playlist",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:        "name",
+								Description: "Playlist name (an existing playlist is overwritten)",
+								Required:    true,
+								MaxLength:   intPtr(PlaylistMaxNameLength),
+							},
+							discord.ApplicationCommandOptionString{
+								Name:        "scope",
+								Description: "Your own playlists or the server's (defaults to yours)",
+								Required:    false,
+								Choices:     playlistScopeChoices,
+							},
+						},
+					},
+					{
+						Name:        "load",
+						Description: "Queue a saved playlist",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:         "name",
+								Description:  "Playlist name",
+								Required:     true,
+								Autocomplete: true,
+							},
+							discord.ApplicationCommandOptionString{
+								Name:        "scope",
+								Description: "Your own playlists or the server's (defaults to yours)",
+								Required:    false,
+								Choices:     playlistScopeChoices,
+							},
+							discord.ApplicationCommandOptionString{
+								Name:         "queue",
+								Description:  "Playback mode (now, next, or a number)",
+								Required:     false,
+								Autocomplete: true,
+							},
+						},
+					},
+					{
+						Name:        "list",
+						Description: "List saved playlists",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:        "scope",
+								Description: "Your own playlists or the server's (defaults to yours)",
+								Required:    false,
+								Choices:     playlistScopeChoices,
+							},
+						},
+					},
+					{
+						Name:        "delete",
+						Description: "Delete a saved playlist",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:         "name",
+								Description:  "Playlist name",
+								Required:     true,
+								Autocomplete: true,
+							},
+							discord.ApplicationCommandOptionString{
+								Name:        "scope",
+								Description: "Your own playlists or the server's (defaults to yours)",
+								Required:    false,
+								Choices:     playlistScopeChoices,
+							},
+						},
+					},
+					{
+						Name:        "share",
+						Description: "Copy one of your playlists to the server's playlists",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:         "name",
+								Description:  "Playlist name",
+								Required:     true,
+								Autocomplete: true,
+							},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommandGroup{
+				Name:        "station",
+				Description: "Internet radio stations saved for this server",
+				Options: []discord.ApplicationCommandOptionSubCommand{
+					{
+						Name:        "add",
+						Description: "Save an Icecast, SHOUTcast or HLS stream",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:        "name",
+								Description: "Station name (an existing station is overwritten)",
+								Required:    true,
+								MaxLength:   intPtr(PlaylistMaxNameLength),
+							},
+							discord.ApplicationCommandOptionString{
+								Name:        "url",
+								Description: "Stream URL, or a .pls/.m3u/.m3u8 file",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Name:        "play",
+						Description: "Tune in to a saved station",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:         "name",
+								Description:  "Station name",
+								Required:     true,
+								Autocomplete: true,
+							},
+							discord.ApplicationCommandOptionString{
+								Name:         "queue",
+								Description:  "Playback mode (now, next, or a number)",
+								Required:     false,
+								Autocomplete: true,
+							},
+						},
+					},
+					{
+						Name:        "list",
+						Description: "List saved stations",
+					},
+					{
+						Name:        "remove",
+						Description: "Remove a saved station",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:         "name",
+								Description:  "Station name",
+								Required:     true,
+								Autocomplete: true,
+							},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommandGroup{
+				Name:        "record",
+				Description: "Record the voice channel (everyone is told when recording starts)",
+				Options: []discord.ApplicationCommandOptionSubCommand{
+					{
+						Name:        "start",
+						Description: "Start recording everyone in my voice channel",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name:        "format",
+								Description: "File format (default OGG)",
+								Required:    false,
+								Choices:     recordingFormatChoices,
+							},
+							discord.ApplicationCommandOptionBool{
+								Name:        "separate",
+								Description: "Also save a track per speaker",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Name:        "stop",
+						Description: "Stop recording and post the files",
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommandGroup{
+				Name:        "cache",
+				Description: "Downloaded audio cache (bot owners only)",
+				Options: []discord.ApplicationCommandOptionSubCommand{
+					{
+						Name:        "stats",
+						Description: "Show cache size and hit rate",
+					},
+					{
+						Name:        "purge",
+						Description: "Delete cached tracks that aren't playing or queued",
+					},
+				},
+			},
 		},
 	}, handleVoice)
 
@@ -157,15 +533,107 @@ func init() {
 // ===========================
 
 const (
-	AudioCacheDir = ".tracks"
-	MinTrackSize  = 32_000
+	AudioCacheDir     = ".tracks"
+	AudioCacheSeekDir = ".tracks/seek"
+	MinTrackSize      = 32_000
+	QueuePageSize     = 10
+	AudioCachePin     = time.Minute
+
+	SessionSnapshotMaxAge = 15 * time.Minute
+
+	LocalTrackPrefix   = "local:"
+	LocalLibraryRescan = 5 * time.Minute
+
+	LyricsRefreshInterval = 2 * time.Second
+	MaxCrossfade          = 12 * time.Second
+	LyricsContextBefore   = 2
+	LyricsContextAfter    = 4
+
+	PlaylistMaxTracks     = 500
+	PlaylistMaxPerOwner   = 25
+	PlaylistMaxNameLength = 50
+
+	StationMaxPerGuild    = 25
+	LiveProbeTimeout      = 10 * time.Second
+	LiveReconnectAttempts = 5
+	LiveProbeSize         = "131072"
+
+	VoiceStatsTopCount = 10
+
+	// Recordings are encoded RecordingJitter behind real time so late packets still land in place, and stop on their
+	// own after RecordingMaxDuration or once they would outgrow a RecordingMaxUpload attachment
+	RecordingMaxDuration   = 20 * time.Minute
+	RecordingMaxUpload     = 10 << 20
+	RecordingMaxSpeakers   = 9
+	RecordingPostAttempts  = 3
+	RecordingRetryDelay    = 5 * time.Second
+	RecordingJitter        = time.Second
+	RecordingFlushInterval = 200 * time.Millisecond
+	RecordingBitRate       = 48000
+	RecordingFrameSamples  = 960
+	recordingJitterSamples = int64(RecordingJitter) * 48000 / int64(time.Second)
+
+	// The radio avoids anything played within RadioHistoryWindow and returns to its seed every RadioSeedInterval tracks
+	RadioHistoryWindow = 7 * 24 * time.Hour
+	RadioSeedInterval  = 5
+	RadioMaxSeeds      = 25
+
+	// Loudness normalization (EBU R128): tracks are steered toward LoudnessTarget LUFS
+	LoudnessTarget        = -14.0
+	LoudnessMaxBoost      = 6.0
+	LoudnessMaxCut        = -15.0
+	LoudnessFloor         = -70.0
+	LoudnessRampDB        = 0.05
+	LoudnessSettleSamples = 48000 * 5
 )
 
+// voiceDJCommands are the subcommands reserved for DJs once a guild sets a DJ role; skip falls back to a vote instead
+var voiceDJCommands = map[string]bool{
+	"stop": true, "shuffle": true, "remove": true, "move": true, "clear": true,
+	"skipto": true, "forward": true, "rewind": true, "volume": true, "filter": true,
+	"crossfade": true, "radio": true,
+}
+
+// voiceDJPanelActions are the panel buttons reserved for DJs
+var voiceDJPanelActions = map[string]bool{
+	"playpause": true, "stop": true, "loop": true, "autoplay": true,
+	"volup": true, "voldown": true, "filter": true,
+}
+
+var playlistScopeChoices = []discord.ApplicationCommandOptionChoiceString{
+	{Name: "Personal", Value: PlaylistScopeUser},
+	{Name: "Server", Value: PlaylistScopeGuild},
+}
+
+var voiceStatsPeriodChoices = []discord.ApplicationCommandOptionChoiceString{
+	{Name: "Past day", Value: "day"},
+	{Name: "Past week", Value: "week"},
+	{Name: "Past month", Value: "month"},
+	{Name: "All time", Value: "all"},
+}
+
+var recordingFormatChoices = []discord.ApplicationCommandOptionChoiceString{
+	{Name: "OGG (Opus)", Value: "ogg"},
+	{Name: "WAV (uncompressed, under 2 minutes)", Value: "wav"},
+}
+
+// AudioFilterPresets are the effects offered by /voice filter and the panel, in display order
+var AudioFilterPresets = []AudioFilterPreset{
+	{Name: "off", Label: "Off", Rate: 1},
+	{Name: "bassboost", Label: "Bass Boost", Chain: "bass=g=10:f=110:w=0.6,alimiter=limit=0.9", Rate: 1},
+	{Name: "nightcore", Label: "Nightcore", Chain: "asetrate=60000,aresample=48000", Rate: 1.25},
+	{Name: "vaporwave", Label: "Vaporwave", Chain: "asetrate=38400,aresample=48000", Rate: 0.8},
+	{Name: "karaoke", Label: "Karaoke", Chain: "pan=stereo|c0=0.5*c0-0.5*c1|c1=0.5*c1-0.5*c0", Rate: 1},
+	{Name: "8d", Label: "8D", Chain: "apulsator=hz=0.125", Rate: 1},
+	{Name: "normalize", Label: "Normalize", Chain: "dynaudnorm=f=150:g=15", Rate: 1},
+}
+
 var (
 	VoiceManager          *VoiceSystem
 	OnceVoice             sync.Once
 	audioCacheInitialized atomic.Bool
 	audioCacheMu          sync.Mutex
+	audioCache            = &AudioCache{entries: make(map[string]*audioCacheEntry)}
 	voiceShuttingDown     atomic.Bool
 	cachedJSArgs          []string
 	jsOnce                sync.Once
@@ -191,11 +659,14 @@ func ensureAudioCacheDir() {
 		return
 	}
 	LogInfo("Initializing %s...", AudioCacheDir)
-	_ = os.RemoveAll(AudioCacheDir)
-	_ = os.MkdirAll(AudioCacheDir, 0755)
+	_ = os.RemoveAll(AudioCacheSeekDir)
+	_ = os.MkdirAll(AudioCacheSeekDir, 0755)
+	audioCache.Load()
 	audioCacheInitialized.Store(true)
+	safeGo(audioCache.Evict)
 }
 
+// cleanupAudioCache drops unfinished downloads and seek fragments; finished tracks stay cached for the next run
 func cleanupAudioCache() {
 	audioCacheMu.Lock()
 	defer audioCacheMu.Unlock()
@@ -206,9 +677,12 @@ func cleanupAudioCache() {
 	}
 
 	LogInfo("Cleaning up %s...", AudioCacheDir)
-	err := os.RemoveAll(AudioCacheDir)
-	if err != nil {
-		LogError("Failed to remove %s: %v", AudioCacheDir, err)
+	if err := os.RemoveAll(AudioCacheSeekDir); err != nil {
+		LogError("Failed to remove %s: %v", AudioCacheSeekDir, err)
+	}
+	partials, _ := filepath.Glob(filepath.Join(AudioCacheDir, "*.part"))
+	for _, p := range partials {
+		_ = os.Remove(p)
 	}
 	audioCacheInitialized.Store(false)
 }
@@ -273,6 +747,43 @@ type VoiceSession struct {
 	nearingEnd             bool
 	transcoder             *AstiavTranscoder
 	Volume                 atomic.Int32
+	Filter                 atomic.Pointer[AudioFilter]
+	skipVotes              map[snowflake.ID]struct{}
+	skipVoteTrack          *Track
+	Crossfade              atomic.Int64
+	Gapless                atomic.Bool
+	crossfadeNext          *crossfadeSource
+	radio                  *radioState
+	recorder               *VoiceRecorder
+}
+
+// radioState keeps a 24/7 session fed; seen holds every track played within RadioHistoryWindow, loaded from the
+// play log when the radio starts, so it doesn't repeat itself across days
+type radioState struct {
+	seeds  []PlaylistTrack
+	seen   map[string]struct{}
+	played int
+}
+
+// crossfadeSource is the next track's decoder, opened early so its head can be mixed under the current track's tail;
+// whichever stream plays that track next takes it over instead of opening the file again
+type crossfadeSource struct {
+	mu    sync.Mutex
+	track *Track
+	tr    *AstiavTranscoder
+	taken bool
+}
+
+// AudioFilter is the effect chain a session applies to everything it plays; nil on the session means no processing
+type AudioFilter struct {
+	Preset       string
+	Tempo, Pitch float64
+}
+
+// AudioFilterPreset is a named libavfilter chain; Rate is how fast it consumes the source relative to real time
+type AudioFilterPreset struct {
+	Name, Label, Chain string
+	Rate               float64
 }
 
 type VoicePanel struct {
@@ -281,6 +792,8 @@ type VoicePanel struct {
 	Token     string
 	AppID     snowflake.ID
 	ExpiresAt time.Time
+	Lyrics    bool
+	lyricsKey string
 }
 
 type Track struct {
@@ -306,6 +819,13 @@ type Track struct {
 	WrittenBytes              int64
 	TotalSize                 int64
 	SeekOffset                time.Duration
+	Loudness                  float64
+	Local                     bool
+	Live                      bool
+	Station                   string
+	RequestedBy               snowflake.ID
+	lyrics                    *Lyrics
+	lyricsState               int
 	FileCreated               chan struct{}
 	metadataOnce              sync.Once
 }
@@ -364,6 +884,28 @@ type AstiavTranscoder struct {
 	seekChan               chan int64
 	volume                 *atomic.Int32
 	frameCount             int64
+	filter                 *atomic.Pointer[AudioFilter]
+	appliedFilter          *AudioFilter
+	filterGraph            *audioGraph
+	filterFrame            *astiav.Frame
+	rate                   float64
+	loudnessGraph          *audioGraph
+	loudnessFrame          *astiav.Frame
+	loudnessSamples        int64
+	loudnessSkipped        bool
+	loudnessKnown          bool
+	loudness               float64
+	gainDB, targetGainDB   float64
+	OnLoudness             func(lufs float64)
+	pull, pullEOF          bool
+	crossfade              time.Duration
+	crossfadeTried         bool
+	OnCrossfade            func() *crossfadeSource
+	mix                    *crossfadeSource
+	mixLength, mixDone     int64
+	live                   bool
+	liveTitle              string
+	OnMetadata             func(title string)
 }
 
 type SearchResult struct{ Title, ChannelName, URL string }
@@ -371,28 +913,14 @@ type SearchResult struct{ Title, ChannelName, URL string }
 type CachedMetadata struct {
 	Title, Channel string
 	Duration       time.Duration
+	Loudness       float64
 }
 
-type ytdlpSearchResult struct {
-	URL, Title, Uploader string
-	Duration             time.Duration
-}
 type ytdlpMetadata struct {
 	URL, Title, Uploader, Filename, ID string
 	Duration                           time.Duration
 }
 
-type ytdlpPlaylistEntry struct{ URL, Title, Uploader string }
-
-type recResult struct {
-	es   []ytdlpPlaylistEntry
-	prio int
-}
-
-type prioritizedSearchResult struct {
-	res  []ytdlpSearchResult
-	prio int
-}
 type metadataResult struct {
 	title    string
 	artist   string
@@ -410,6 +938,25 @@ func handleVoice(event *events.ApplicationCommandInteractionCreate) {
 	if data.SubCommandName == nil {
 		return
 	}
+	if data.SubCommandGroupName != nil && *data.SubCommandGroupName == "playlist" {
+		handleVoicePlaylist(event, data)
+		return
+	}
+	if data.SubCommandGroupName != nil && *data.SubCommandGroupName == "cache" {
+		handleVoiceCache(event, data)
+		return
+	}
+	if data.SubCommandGroupName != nil && *data.SubCommandGroupName == "station" {
+		handleVoiceStation(event, data)
+		return
+	}
+	if data.SubCommandGroupName != nil && *data.SubCommandGroupName == "record" {
+		handleVoiceRecord(event, data)
+		return
+	}
+	if voiceDJCommands[*data.SubCommandName] && !mustBeVoiceDJ(event) {
+		return
+	}
 	switch *data.SubCommandName {
 	case "play":
 		handleMusicPlay(event, data)
@@ -417,6 +964,16 @@ func handleVoice(event *events.ApplicationCommandInteractionCreate) {
 		handleMusicStop(event, data)
 	case "queue":
 		handleMusicQueue(event, data)
+	case "shuffle":
+		handleMusicShuffle(event)
+	case "remove":
+		handleMusicRemove(event, data)
+	case "move":
+		handleMusicMove(event, data)
+	case "clear":
+		handleMusicClear(event)
+	case "skipto":
+		handleMusicSkipTo(event, data)
 	case "forward":
 		handleMusicSeek(event, data, 1)
 	case "rewind":
@@ -427,6 +984,16 @@ func handleVoice(event *events.ApplicationCommandInteractionCreate) {
 		handleVoiceVolume(event, data)
 	case "panel":
 		handleVoicePanel(event)
+	case "filter":
+		handleVoiceFilter(event, data)
+	case "crossfade":
+		handleVoiceCrossfade(event, data)
+	case "dj":
+		handleVoiceDJ(event, data)
+	case "stats":
+		handleVoiceStats(event, data)
+	case "radio":
+		handleVoiceRadio(event, data)
 	}
 }
 
@@ -518,6 +1085,11 @@ func handleMusicSkip(event *events.ApplicationCommandInteractionCreate) {
 	if !ok {
 		return
 	}
+	if !isVoiceDJ(*event.Client(), event, s) {
+		msg, _ := s.castSkipVote(*event.Client(), event.User().ID)
+		_ = RespondInteractionV2(*event.Client(), event, msg, false)
+		return
+	}
 	_ = event.DeferCreateMessage(false)
 
 	title, err := s.Skip()
@@ -807,6 +1379,8 @@ func (vs *VoiceSystem) Shutdown(ctx context.Context) {
 				channelID := s.ChannelID
 				s.channelMu.RUnlock()
 
+				s.saveSnapshot(ctx)
+
 				route := rest.NewEndpoint(http.MethodPut, "/channels/"+channelID.String()+"/voice-status")
 				_ = s.GetClient().Rest.Do(route.Compile(nil), map[string]string{"status": ""}, nil)
 				s.Stop()
@@ -814,10 +1388,181 @@ func (vs *VoiceSystem) Shutdown(ctx context.Context) {
 		})
 	}
 	wg.Wait()
+
+	uploaded := make(chan struct{})
+	safeGo(func() {
+		recordingPosts.Wait()
+		close(uploaded)
+	})
+	select {
+	case <-uploaded:
+	case <-ctx.Done():
+		LogVoice("Gave up waiting for recordings to upload: %v", ctx.Err())
+	}
 	cleanupAudioCache()
 }
 
-func (vs *VoiceSystem) Play(ctx context.Context, guildID snowflake.ID, url, mode string, pos int) (*Track, int, error) {
+// VoiceSessionSnapshot is what survives a restart: the current track comes first in Tracks and resumes at Position
+type VoiceSessionSnapshot struct {
+	Tracks            []SessionTrack
+	Position          time.Duration
+	Autoplay, Looping bool
+	Volume            int32
+	Filter            *AudioFilter `json:",omitempty"`
+	Crossfade         time.Duration
+	Gapless           bool
+}
+
+// SessionTrack is a snapshot's track entry; RequestedBy keeps restored plays credited to whoever queued them
+type SessionTrack struct {
+	PlaylistTrack
+	RequestedBy snowflake.ID `json:",omitempty"`
+}
+
+// Position reports how far into the current track playback is
+func (s *VoiceSession) Position() time.Duration {
+	s.lockQueue()
+	tr := s.transcoder
+	s.unlockQueue()
+	if tr == nil {
+		return 0
+	}
+	return time.Duration(tr.GetTimestamp()/48) * time.Millisecond
+}
+
+// Snapshot captures the session's queue and settings; ok is false when there is nothing worth resuming
+func (s *VoiceSession) Snapshot() (snap VoiceSessionSnapshot, ok bool) {
+	snap.Tracks = s.snapshotTracks()
+	if len(snap.Tracks) == 0 {
+		return snap, false
+	}
+	s.lockQueue()
+	hasCurrent := s.currentTrack != nil && !s.currentTrack.IsLive() && !isDiscordAttachment(s.currentTrack.URL)
+	snap.Autoplay, snap.Looping = s.Autoplay, s.Looping
+	s.unlockQueue()
+	if hasCurrent {
+		snap.Position = s.Position()
+	}
+	snap.Volume = s.Volume.Load()
+	snap.Filter = s.Filter.Load()
+	snap.Crossfade = time.Duration(s.Crossfade.Load())
+	snap.Gapless = s.Gapless.Load()
+	return snap, true
+}
+
+func (s *VoiceSession) saveSnapshot(ctx context.Context) {
+	s.joinedMu.Lock()
+	joined := s.joined
+	s.joinedMu.Unlock()
+	if !joined {
+		return
+	}
+	snap, ok := s.Snapshot()
+	if !ok {
+		return
+	}
+	state, err := json.Marshal(snap)
+	if err != nil {
+		LogVoice("Failed to snapshot voice session for guild %s: %v", s.GuildID, err)
+		return
+	}
+	s.channelMu.RLock()
+	channelID := s.ChannelID
+	s.channelMu.RUnlock()
+	if err := SaveVoiceSession(ctx, s.GuildID, channelID, string(state)); err != nil {
+		LogVoice("Failed to save voice session for guild %s: %v", s.GuildID, err)
+		return
+	}
+	LogVoice("Saved voice session for guild %s (%d tracks, at %v)", s.GuildID, len(snap.Tracks), snap.Position)
+}
+
+// RestoreSessions rejoins the channels saved at shutdown and picks playback up where it stopped, then turns radios back on
+func (vs *VoiceSystem) RestoreSessions(ctx context.Context, client bot.Client) {
+	radios, err := GetRadioChannels(ctx)
+	if err != nil {
+		LogVoice("Failed to load radio channels: %v", err)
+	}
+	saved, err := TakeVoiceSessions(ctx)
+	if err != nil {
+		LogVoice("Failed to load saved voice sessions: %v", err)
+	}
+	for _, sv := range saved {
+		if time.Since(sv.UpdatedAt) > SessionSnapshotMaxAge {
+			LogVoice("Discarding stale voice session for guild %s", sv.GuildID)
+			continue
+		}
+		var snap VoiceSessionSnapshot
+		if err := json.Unmarshal([]byte(sv.State), &snap); err != nil || len(snap.Tracks) == 0 {
+			continue
+		}
+		_, radio := radios[sv.GuildID]
+		delete(radios, sv.GuildID)
+		safeGo(func() {
+			if err := vs.restoreSession(ctx, client, sv.GuildID, sv.ChannelID, snap); err != nil {
+				LogVoice("Failed to restore voice session for guild %s: %v", sv.GuildID, err)
+				return
+			}
+			if radio {
+				if _, err := vs.StartRadio(ctx, client, sv.GuildID, sv.ChannelID); err != nil {
+					LogVoice("Failed to resume radio in guild %s: %v", sv.GuildID, err)
+				}
+			}
+		})
+	}
+	for guildID, channelID := range radios {
+		safeGo(func() {
+			if _, err := vs.StartRadio(ctx, client, guildID, channelID); err != nil {
+				LogVoice("Failed to resume radio in guild %s: %v", guildID, err)
+			}
+		})
+	}
+}
+
+func (vs *VoiceSystem) restoreSession(ctx context.Context, client bot.Client, guildID, channelID snowflake.ID, snap VoiceSessionSnapshot) error {
+	s := vs.Prepare(client, guildID, channelID)
+	s.lockQueue()
+	s.Autoplay, s.Looping = snap.Autoplay, snap.Looping
+	s.unlockQueue()
+	if snap.Volume > 0 {
+		s.Volume.Store(snap.Volume)
+	}
+	s.SetFilter(snap.Filter)
+	s.Crossfade.Store(int64(min(snap.Crossfade, MaxCrossfade)))
+	s.Gapless.Store(snap.Gapless)
+
+	tracks := make([]*Track, 0, len(snap.Tracks))
+	for _, st := range snap.Tracks {
+		t := NewTrack(st.URL)
+		t.Title, t.Channel, t.Duration = st.Title, st.Channel, st.Duration
+		t.RequestedBy = st.RequestedBy
+		tracks = append(tracks, t)
+	}
+
+	if err := vs.Join(ctx, client, guildID, channelID); err != nil {
+		vs.Leave(context.Background(), guildID)
+		return err
+	}
+	if err := vs.Enqueue(guildID, tracks, "", 0); err != nil {
+		return err
+	}
+	LogVoice("Restored voice session for guild %s (%d tracks)", guildID, len(tracks))
+	UpdateVoicePanels(guildID, client)
+
+	if snap.Position < SilenceDuration {
+		return nil
+	}
+	first := tracks[0]
+	select {
+	case <-first.PlaybackStarted:
+	case <-s.cancelCtx.Done():
+		return nil
+	case <-time.After(30 * time.Second):
+		return errors.New("timed out waiting to resume playback")
+	}
+	return s.Seek(snap.Position - s.Position())
+}
+
+func (vs *VoiceSystem) Play(ctx context.Context, guildID snowflake.ID, url, mode string, pos int, requester snowflake.ID) (*Track, int, error) {
 	s := vs.GetSession(guildID)
 	if s == nil {
 		return nil, 0, errors.New("not connected to voice")
@@ -827,29 +1572,52 @@ func (vs *VoiceSystem) Play(ctx context.Context, guildID snowflake.ID, url, mode
 	if len(tracks) == 0 {
 		tracks = []*Track{NewTrack(url)}
 	}
+	for _, t := range tracks {
+		t.RequestedBy = requester
+	}
+
+	if err := vs.Enqueue(guildID, tracks, mode, pos); err != nil {
+		return nil, 0, err
+	}
+	s.addToHistory(url, "", "")
+
+	return tracks[0], len(tracks), nil
+}
+
+// Enqueue queues already-built tracks on a session and starts fetching the first one
+func (vs *VoiceSystem) Enqueue(guildID snowflake.ID, tracks []*Track, mode string, pos int) error {
+	s := vs.GetSession(guildID)
+	if s == nil {
+		return errors.New("not connected to voice")
+	}
+	if len(tracks) == 0 {
+		return errors.New("no tracks to queue")
+	}
 
 	firstTrack := tracks[0]
 	s.queueTracks(tracks, mode, pos)
 
 	firstTrack.Priority = 1
 	s.scheduleDownload(firstTrack)
-	s.addToHistory(url, "", "")
-
-	return firstTrack, len(tracks), nil
+	return nil
 }
 
+// resolvePlaylist expands a playlist or folder URL into its tracks through the source it belongs to; nil means a single track
 func (vs *VoiceSystem) resolvePlaylist(ctx context.Context, url string) ([]*Track, error) {
-	if !strings.Contains(url, "list=") {
+	src := sourceForURL(url)
+	if src == nil {
 		return nil, nil
 	}
-	entries, err := ytdlpExtractPlaylist(ctx, url, 100)
+	entries, err := src.Resolve(ctx, url, 100)
 	if err != nil || len(entries) == 0 {
 		return nil, err
 	}
 	tracks := make([]*Track, 0, len(entries))
 	for _, e := range entries {
 		nt := NewTrack(e.URL)
-		nt.Title = e.Title
+		if e.Title != "" {
+			nt.Title = e.Title
+		}
 		nt.Channel = e.Uploader
 		tracks = append(tracks, nt)
 	}
@@ -958,17 +1726,7 @@ func (vs *VoiceSystem) updateAutoPauseState(event *events.GuildVoiceStateUpdate,
 	if currentChannelID == 0 {
 		return
 	}
-	humanCount := 0
-	for state := range event.Client().Caches.VoiceStates(event.VoiceState.GuildID) {
-		if state.ChannelID != nil && *state.ChannelID == currentChannelID && state.UserID != event.Client().ID() {
-			if state.SelfDeaf {
-				continue
-			}
-			if m, ok := event.Client().Caches.Member(event.VoiceState.GuildID, state.UserID); !ok || !m.User.Bot {
-				humanCount++
-			}
-		}
-	}
+	humanCount := len(s.Listeners(*event.Client()))
 	s.pauseMu.RLock()
 	paused := false
 	select {
@@ -1028,6 +1786,405 @@ func (vs *VoiceSystem) updateAutoPauseState(event *events.GuildVoiceStateUpdate,
 	}
 }
 
+// Listeners returns the humans in the session's channel who can hear it
+func (s *VoiceSession) Listeners(client bot.Client) []snowflake.ID {
+	s.channelMu.RLock()
+	channelID := s.ChannelID
+	s.channelMu.RUnlock()
+
+	var ids []snowflake.ID
+	if channelID == 0 {
+		return ids
+	}
+	for state := range client.Caches.VoiceStates(s.GuildID) {
+		if state.ChannelID != nil && *state.ChannelID == channelID && state.UserID != client.ID() {
+			if state.SelfDeaf {
+				continue
+			}
+			if m, ok := client.Caches.Member(s.GuildID, state.UserID); !ok || !m.User.Bot {
+				ids = append(ids, state.UserID)
+			}
+		}
+	}
+	return ids
+}
+
+// ===========================
+// DJ & Vote Skip
+// ===========================
+
+// isVoiceDJ reports whether the member may use restricted controls: everyone when no DJ role is set, otherwise the role,
+// server managers, and whoever is listening alone
+func isVoiceDJ(client bot.Client, i discord.Interaction, s *VoiceSession) bool {
+	guildID := i.GuildID()
+	m := i.Member()
+	if guildID == nil || m == nil {
+		return false
+	}
+	roleID, _, err := GetGuildVoiceSettings(context.Background(), *guildID)
+	if err != nil {
+		LogVoice("Failed to load voice settings for guild %s: %v", *guildID, err)
+	}
+	if roleID == 0 || slices.Contains(m.RoleIDs, roleID) || m.Permissions.Has(discord.PermissionManageGuild) {
+		return true
+	}
+	if s != nil {
+		listeners := s.Listeners(client)
+		return len(listeners) == 1 && listeners[0] == m.User.ID
+	}
+	return false
+}
+
+// mustAllowQueueMode keeps the "now" queue mode, which replaces what is playing, to DJs. Commands that take it are
+// open to everyone, so it is checked wherever the mode is parsed rather than by subcommand name
+func mustAllowQueueMode(event *events.ApplicationCommandInteractionCreate, mode string) bool {
+	if mode != "now" || event.GuildID() == nil {
+		return true
+	}
+	if isVoiceDJ(*event.Client(), event, GetVoiceManager().GetSession(*event.GuildID())) {
+		return true
+	}
+	_ = RespondInteractionV2(*event.Client(), event, "Only DJs can replace what's playing; queue it `next` or at a position instead.", true)
+	return false
+}
+
+func mustBeVoiceDJ(event *events.ApplicationCommandInteractionCreate) bool {
+	if event.GuildID() == nil {
+		return true
+	}
+	if isVoiceDJ(*event.Client(), event, GetVoiceManager().GetSession(*event.GuildID())) {
+		return true
+	}
+	_ = RespondInteractionV2(*event.Client(), event, "Only DJs can do that.", true)
+	return false
+}
+
+// castSkipVote records a listener's vote to skip the current track and skips once enough listeners agree
+func (s *VoiceSession) castSkipVote(client bot.Client, userID snowflake.ID) (string, bool) {
+	listeners := s.Listeners(client)
+	if !slices.Contains(listeners, userID) {
+		return "You must be listening to vote.", false
+	}
+	_, percent, _ := GetGuildVoiceSettings(context.Background(), s.GuildID)
+	needed := max(1, int(math.Ceil(float64(len(listeners))*float64(percent)/100)))
+
+	s.lockQueue()
+	if s.currentTrack == nil {
+		s.unlockQueue()
+		return "Nothing is playing.", false
+	}
+	if s.skipVoteTrack != s.currentTrack {
+		s.skipVoteTrack = s.currentTrack
+		s.skipVotes = make(map[snowflake.ID]struct{})
+	}
+	s.skipVotes[userID] = struct{}{}
+	votes := 0
+	for id := range s.skipVotes {
+		if slices.Contains(listeners, id) {
+			votes++
+		}
+	}
+	s.unlockQueue()
+
+	if votes < needed {
+		return fmt.Sprintf("🗳️ <@%d> voted to skip (**%d/%d**).", userID, votes, needed), false
+	}
+	title, err := s.Skip()
+	if err != nil {
+		return fmt.Sprintf("Failed to skip: %v", err), false
+	}
+	UpdateVoicePanels(s.GuildID, client)
+	return fmt.Sprintf("⏭️ Vote passed (**%d/%d**), skipped: %s", votes, needed, title), true
+}
+
+func handleVoiceDJ(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	guildID := event.GuildID()
+	if guildID == nil {
+		_ = RespondInteractionV2(*event.Client(), event, "Not in a guild.", true)
+		return
+	}
+	role, hasRole := data.OptRole("role")
+	votes, hasVotes := data.OptInt("votes")
+	reset, _ := data.OptBool("reset")
+
+	ctx := context.Background()
+	if hasRole || hasVotes || reset {
+		if m := event.Member(); m == nil || !m.Permissions.Has(discord.PermissionManageGuild) {
+			_ = RespondInteractionV2(*event.Client(), event, "You need Manage Server to change DJ settings.", true)
+			return
+		}
+		var err error
+		if reset {
+			err = SetGuildDJRole(ctx, *guildID, 0)
+		} else if hasRole {
+			err = SetGuildDJRole(ctx, *guildID, role.ID)
+		}
+		if err == nil && hasVotes {
+			err = SetGuildVoteSkipPercent(ctx, *guildID, votes)
+		}
+		if err != nil {
+			_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to save DJ settings: %v", err), true)
+			return
+		}
+		LogVoice("User %s (%s) updated DJ settings in guild %s", event.User().Username, event.User().ID, *guildID)
+	}
+
+	roleID, percent, err := GetGuildVoiceSettings(ctx, *guildID)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to load DJ settings: %v", err), true)
+		return
+	}
+	djText := "Everyone"
+	if roleID != 0 {
+		djText = fmt.Sprintf("<@&%d>", roleID)
+	}
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("🎧 **DJ:** %s\n🗳️ **Vote skip:** %d%% of listeners", djText, percent), true)
+}
+
+// ===========================
+// Radio
+// ===========================
+
+func parseRadioSeeds(s string) []string {
+	var seeds []string
+	for seed := range strings.SplitSeq(s, ";") {
+		if seed = strings.TrimSpace(seed); seed != "" && len(seeds) < RadioMaxSeeds {
+			seeds = append(seeds, seed)
+		}
+	}
+	return seeds
+}
+
+// loadRadioSeeds gathers the tracks of the guild's radio playlist followed by its seed URLs or searches
+func loadRadioSeeds(ctx context.Context, guildID snowflake.ID, cfg GuildRadioConfig) ([]PlaylistTrack, error) {
+	var seeds []PlaylistTrack
+	if cfg.Playlist != "" {
+		p, err := GetPlaylist(ctx, PlaylistScopeGuild, guildID, cfg.Playlist)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			tracks, err := GetPlaylistTracks(ctx, p.ID)
+			if err != nil {
+				return nil, err
+			}
+			seeds = append(seeds, tracks...)
+		}
+	}
+	for _, seed := range cfg.Seeds {
+		seeds = append(seeds, PlaylistTrack{URL: seed})
+	}
+	return seeds, nil
+}
+
+// StartRadio joins the channel and keeps it playing until the radio is turned off; the last RadioHistoryWindow of the
+// play log is loaded first so autoplay's repeat and similarity checks span restarts and days
+func (vs *VoiceSystem) StartRadio(ctx context.Context, client bot.Client, guildID, channelID snowflake.ID) (*VoiceSession, error) {
+	cfg, err := GetGuildRadio(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	seeds, err := loadRadioSeeds(ctx, guildID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(seeds) == 0 {
+		return nil, errors.New("no radio seed set, pick a server playlist or seed tracks first")
+	}
+	plays, err := GetVoicePlays(ctx, guildID, time.Now().Add(-RadioHistoryWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	s := vs.Prepare(client, guildID, channelID)
+	r := &radioState{seeds: seeds, seen: make(map[string]struct{}, len(plays))}
+	for _, p := range plays {
+		r.seen[trackKey(p.URL)] = struct{}{}
+	}
+	s.lockQueue()
+	s.radio = r
+	s.unlockQueue()
+	for _, p := range plays[max(0, len(plays)-50):] {
+		s.addToHistory(p.URL, p.Title, p.Channel)
+	}
+
+	if err := vs.Join(ctx, client, guildID, channelID); err != nil {
+		vs.Leave(context.Background(), guildID)
+		return nil, err
+	}
+	if err := SetGuildRadioChannel(ctx, guildID, channelID); err != nil {
+		LogVoice("Failed to save radio channel for guild %s: %v", guildID, err)
+	}
+	LogVoice("Radio started in guild %s (%d seeds, %d recent plays)", guildID, len(seeds), len(plays))
+
+	s.lockQueue()
+	idle := s.currentTrack == nil && len(s.queue) == 0
+	s.unlockQueue()
+	if idle {
+		if _, _, err := vs.Play(ctx, guildID, s.pickRadioSeed(), "", 0, 0); err != nil {
+			return s, err
+		}
+	}
+	UpdateVoicePanels(guildID, client)
+	return s, nil
+}
+
+// StopRadio turns the radio off; whatever is already queued keeps playing
+func (vs *VoiceSystem) StopRadio(guildID snowflake.ID) {
+	if s := vs.GetSession(guildID); s != nil {
+		s.lockQueue()
+		s.radio = nil
+		s.unlockQueue()
+	}
+	if err := SetGuildRadioChannel(context.Background(), guildID, 0); err != nil {
+		LogVoice("Failed to clear radio channel for guild %s: %v", guildID, err)
+	}
+}
+
+// pickRadioSeed returns a random seed that hasn't played recently, or any seed once they all have
+func (s *VoiceSession) pickRadioSeed() string {
+	s.lockQueue()
+	defer s.unlockQueue()
+	if s.radio == nil || len(s.radio.seeds) == 0 {
+		return ""
+	}
+	order := rand.Perm(len(s.radio.seeds))
+	for _, i := range order {
+		if _, ok := s.radio.seen[trackKey(s.radio.seeds[i].URL)]; !ok {
+			return s.radio.seeds[i].URL
+		}
+	}
+	return s.radio.seeds[order[0]].URL
+}
+
+// nextRelated picks what plays after a track once the queue runs out; the radio steers back to its seed every
+// RadioSeedInterval tracks and whenever nothing related is left that it hasn't played recently
+func (s *VoiceSession) nextRelated(url, title, channel string) (string, error) {
+	s.lockQueue()
+	radio := s.radio != nil
+	reseed := false
+	if radio {
+		s.radio.played++
+		reseed = s.radio.played%RadioSeedInterval == 0
+	}
+	s.unlockQueue()
+
+	if reseed {
+		if seed := s.pickRadioSeed(); seed != "" {
+			return seed, nil
+		}
+	}
+	next, err := s.fetchRelated(url, title, channel)
+	if err != nil && radio {
+		if seed := s.pickRadioSeed(); seed != "" {
+			return seed, nil
+		}
+	}
+	return next, err
+}
+
+// autoplaying reports whether the session refills itself when the queue runs out; callers hold the queue lock
+func (s *VoiceSession) autoplaying() bool {
+	return s.Autoplay || s.radio != nil
+}
+
+func radioStatusText(cfg GuildRadioConfig) string {
+	status := "📻 **Radio:** Off"
+	if cfg.ChannelID != 0 {
+		status = fmt.Sprintf("📻 **Radio:** On in <#%d>", cfg.ChannelID)
+	}
+	var seed []string
+	if cfg.Playlist != "" {
+		seed = append(seed, fmt.Sprintf("playlist **%s**", cfg.Playlist))
+	}
+	if len(cfg.Seeds) > 0 {
+		seed = append(seed, fmt.Sprintf("%d seed tracks", len(cfg.Seeds)))
+	}
+	if len(seed) == 0 {
+		return status + "\n🌱 **Seed:** _None set. Use `/voice radio playlist:` or `seeds:`._"
+	}
+	return status + "\n🌱 **Seed:** " + strings.Join(seed, " · ")
+}
+
+func handleVoiceRadio(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	guildID := event.GuildID()
+	if guildID == nil {
+		_ = RespondInteractionV2(*event.Client(), event, "Not in a guild.", true)
+		return
+	}
+	playlist, hasPlaylist := data.OptString("playlist")
+	seeds, hasSeeds := data.OptString("seeds")
+	reset, _ := data.OptBool("clear")
+	enabled, hasEnabled := data.OptBool("enabled")
+	vm := GetVoiceManager()
+	ctx := context.Background()
+
+	if hasPlaylist || hasSeeds || reset {
+		cfg, err := GetGuildRadio(ctx, *guildID)
+		if err != nil {
+			_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to load radio settings: %v", err), true)
+			return
+		}
+		if reset {
+			cfg.Playlist, cfg.Seeds = "", nil
+		}
+		if hasPlaylist {
+			p, err := GetPlaylist(ctx, PlaylistScopeGuild, *guildID, playlist)
+			if err != nil || p == nil {
+				_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("No server playlist named **%s**.", playlist), true)
+				return
+			}
+			cfg.Playlist = p.Name
+		}
+		if hasSeeds {
+			cfg.Seeds = parseRadioSeeds(seeds)
+		}
+		if err := SetGuildRadioSeed(ctx, *guildID, cfg.Playlist, cfg.Seeds); err != nil {
+			_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to save radio settings: %v", err), true)
+			return
+		}
+		LogVoice("User %s (%s) updated the radio seed in guild %s", event.User().Username, event.User().ID, *guildID)
+
+		if s := vm.GetSession(*guildID); s != nil {
+			if pool, err := loadRadioSeeds(ctx, *guildID, cfg); err == nil && len(pool) > 0 {
+				s.lockQueue()
+				if s.radio != nil {
+					s.radio.seeds = pool
+				}
+				s.unlockQueue()
+			}
+		}
+	}
+
+	switch {
+	case hasEnabled && enabled:
+		vs, ok := mustGetUserVoiceState(event)
+		if !ok {
+			return
+		}
+		_ = event.DeferCreateMessage(false)
+		LogVoice("User %s (%s) started the radio in guild %s", event.User().Username, event.User().ID, *guildID)
+		if _, err := vm.StartRadio(ctx, *event.Client(), *guildID, *vs.ChannelID); err != nil {
+			_ = EditInteractionV2(*event.Client(), event, "Failed: "+err.Error())
+			return
+		}
+		cfg, _ := GetGuildRadio(ctx, *guildID)
+		_ = EditInteractionV2(*event.Client(), event, radioStatusText(cfg))
+		return
+	case hasEnabled:
+		LogVoice("User %s (%s) stopped the radio in guild %s", event.User().Username, event.User().ID, *guildID)
+		vm.StopRadio(*guildID)
+		UpdateVoicePanels(*guildID, *event.Client())
+	}
+
+	cfg, err := GetGuildRadio(ctx, *guildID)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to load radio settings: %v", err), true)
+		return
+	}
+	_ = RespondInteractionV2(*event.Client(), event, radioStatusText(cfg), !hasEnabled)
+}
+
 // ===========================
 // Voice Session
 // ===========================
@@ -1038,6 +2195,10 @@ func (s *VoiceSession) Seek(duration time.Duration) error {
 		s.unlockQueue()
 		return fmt.Errorf("no track currently playing")
 	}
+	if s.currentTrack.IsLive() {
+		s.unlockQueue()
+		return errors.New("can't seek in a live stream")
+	}
 
 	ctx, cancel := context.WithCancel(s.cancelCtx)
 	s.streamCancel = cancel
@@ -1091,7 +2252,7 @@ func (s *VoiceSession) Seek(duration time.Duration) error {
 			}
 			cur.mu.Unlock()
 
-			baseName := filepath.Join(".tracks", fmt.Sprintf("%s_%d", id, targetMs))
+			baseName := filepath.Join(AudioCacheSeekDir, fmt.Sprintf("%s_%d", id, targetMs))
 			fragmentPath := baseName + ".webm"
 			partPath := fragmentPath + ".part"
 
@@ -1146,6 +2307,113 @@ func (s *VoiceSession) Skip() (string, error) {
 	return title, nil
 }
 
+// Shuffle randomizes the order of the upcoming tracks and returns how many were shuffled
+func (s *VoiceSession) Shuffle() (int, error) {
+	s.lockQueue()
+	defer s.unlockQueue()
+	if len(s.queue) < 2 {
+		return 0, errors.New("not enough tracks to shuffle")
+	}
+	rand.Shuffle(len(s.queue), func(i, j int) { s.queue[i], s.queue[j] = s.queue[j], s.queue[i] })
+	s.prefetchNext()
+	return len(s.queue), nil
+}
+
+// Remove drops the tracks at 1-based positions from..to (inclusive) and returns them
+func (s *VoiceSession) Remove(from, to int) ([]*Track, error) {
+	s.lockQueue()
+	defer s.unlockQueue()
+	if err := s.checkQueuePosition(from); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueuePosition(to); err != nil {
+		return nil, err
+	}
+	if from > to {
+		from, to = to, from
+	}
+
+	removed := slices.Clone(s.queue[from-1 : to])
+	s.queue = slices.Delete(s.queue, from-1, to)
+	for _, t := range removed {
+		t.Cleanup()
+	}
+	s.prefetchNext()
+	return removed, nil
+}
+
+// Move shifts the track at 1-based position from to position to
+func (s *VoiceSession) Move(from, to int) (*Track, error) {
+	s.lockQueue()
+	defer s.unlockQueue()
+	if err := s.checkQueuePosition(from); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueuePosition(to); err != nil {
+		return nil, err
+	}
+
+	t := s.queue[from-1]
+	s.queue = slices.Delete(s.queue, from-1, from)
+	s.queue = slices.Insert(s.queue, to-1, t)
+	s.prefetchNext()
+	return t, nil
+}
+
+// Clear drops every upcoming track, leaving the current one playing, and returns how many were removed
+func (s *VoiceSession) Clear() int {
+	s.lockQueue()
+	defer s.unlockQueue()
+	n := len(s.queue)
+	for _, t := range s.queue {
+		t.Cleanup()
+	}
+	s.queue = nil
+	return n
+}
+
+// SkipTo drops the tracks ahead of the 1-based position and skips the current one so that track plays next
+func (s *VoiceSession) SkipTo(pos int) (*Track, error) {
+	s.lockQueue()
+	if err := s.checkQueuePosition(pos); err != nil {
+		s.unlockQueue()
+		return nil, err
+	}
+
+	for _, t := range s.queue[:pos-1] {
+		t.Cleanup()
+	}
+	s.queue = slices.Delete(s.queue, 0, pos-1)
+	target := s.queue[0]
+	s.skipLoop = true
+	cancel := s.streamCancel
+	s.unlockQueue()
+
+	if cancel != nil {
+		cancel()
+	}
+	return target, nil
+}
+
+// checkQueuePosition validates a 1-based queue position (caller must hold the queue lock)
+func (s *VoiceSession) checkQueuePosition(pos int) error {
+	if len(s.queue) == 0 {
+		return errors.New("the queue is empty")
+	}
+	if pos < 1 || pos > len(s.queue) {
+		return fmt.Errorf("position %d is out of range (1-%d)", pos, len(s.queue))
+	}
+	return nil
+}
+
+// prefetchNext starts downloading whatever is now at the front of the queue (caller must hold the queue lock)
+func (s *VoiceSession) prefetchNext() {
+	if s.currentTrack != nil && len(s.queue) > 0 {
+		s.queue[0].Priority = 1
+		s.scheduleDownload(s.queue[0])
+	}
+}
+
 func (s *VoiceSession) WaitJoined(ctx context.Context) error {
 	select {
 	case <-s.joinedChan:
@@ -1172,7 +2440,10 @@ func (s *VoiceSession) Stop() {
 		s.setOpusFrameProviderSafe(nil)
 		s.setSpeakingSafe(0)
 	}
+	next := s.crossfadeNext
+	s.crossfadeNext = nil
 	s.unlockQueue()
+	next.discard()
 	s.lockQueue()
 	for _, t := range s.queue {
 		t.Cleanup()
@@ -1193,6 +2464,9 @@ func (s *VoiceSession) Stop() {
 	}
 
 	s.setVoiceStatus("")
+	if rec := s.takeRecorder(nil); rec != nil {
+		postRecording(rec, "Stopped because the bot left the channel.")
+	}
 }
 
 func (s *VoiceSession) WaitForCleanup() {
@@ -1322,7 +2596,7 @@ func (s *VoiceSession) updateNextTrackStatusIfNeeded(next *Track) {
 	isNext := false
 	if len(s.queue) > 0 && s.queue[0] == t {
 		isNext = true
-	} else if s.Autoplay && s.autoplayTrack == t {
+	} else if s.autoplaying() && s.autoplayTrack == t {
 		isNext = true
 	}
 	nearing := s.nearingEnd
@@ -1512,7 +2786,7 @@ func (s *VoiceSession) processQueue() {
 		})
 
 		s.lockQueue()
-		autoplay := s.Autoplay
+		autoplay := s.autoplaying()
 		s.unlockQueue()
 		if autoplay {
 			safeGo(func() {
@@ -1524,12 +2798,12 @@ func (s *VoiceSession) processQueue() {
 					case <-time.After(10 * time.Second):
 					}
 
-					next, err := s.fetchRelated(url, t.Title, t.Channel)
+					next, err := s.nextRelated(url, t.Title, t.Channel)
 					if err == nil && next != "" {
 						nt := NewTrack(next)
 
 						s.lockQueue()
-						if s.Autoplay && s.autoplayTrack == nil && s.currentTrack != nil && s.currentTrack.URL == url {
+						if s.autoplaying() && s.autoplayTrack == nil && s.currentTrack != nil && s.currentTrack.URL == url {
 							if s.autoplayTrack != nil {
 								s.autoplayTrack.Cleanup()
 							}
@@ -1545,11 +2819,15 @@ func (s *VoiceSession) processQueue() {
 			})
 		}
 
+		var played time.Duration
+		var finished bool
 		if t.LiveStream != nil {
-			s.streamCommon(t.URL, t.URL, t.LiveStream)
+			played, finished = s.streamCommon(t.URL, t.URL, t.LiveStream)
 		} else {
-			s.streamFile(t.URL, t.Path)
+			played, finished = s.streamFile(t.URL, t.Path)
 		}
+		skipped := !finished && s.cancelCtx.Err() == nil
+		safeGo(func() { s.recordPlay(t, played, skipped) })
 
 		s.setVoiceStatus("")
 
@@ -1568,7 +2846,7 @@ func (s *VoiceSession) processQueue() {
 
 		s.lockQueue()
 
-		if len(s.queue) == 0 && s.Autoplay {
+		if len(s.queue) == 0 && s.autoplaying() {
 			if s.autoplayTrack != nil {
 				next := s.autoplayTrack
 				s.autoplayTrack = nil
@@ -1581,9 +2859,9 @@ func (s *VoiceSession) processQueue() {
 				continue
 			} else {
 				s.unlockQueue()
-				next, err := s.fetchRelated(t.URL, t.Title, t.Channel)
+				next, err := s.nextRelated(t.URL, t.Title, t.Channel)
 				if err == nil && next != "" {
-					_, _, _ = GetVoiceManager().Play(context.Background(), s.GuildID, next, "", 0)
+					_, _, _ = GetVoiceManager().Play(context.Background(), s.GuildID, next, "", 0, 0)
 				} else {
 					LogVoice("Autoplay sync fetch failed for %s: %v", t.URL, err)
 				}
@@ -1615,6 +2893,12 @@ func (t *Track) FullDisplay() string {
 			title = "Music Track"
 		}
 	}
+	if !strings.HasPrefix(t.URL, "http") {
+		if t.Channel != "" {
+			return fmt.Sprintf("%s · %s", title, t.Channel)
+		}
+		return title
+	}
 	if t.Channel != "" && t.Channel != "NA" {
 		return fmt.Sprintf("[%s](%s) · %s", title, t.URL, t.Channel)
 	}
@@ -1632,7 +2916,7 @@ func (t *Track) Cleanup() {
 	if c, ok := t.LiveStream.(io.Closer); ok {
 		c.Close()
 	}
-	if t.Path != "" {
+	if t.Path != "" && !t.Local && !t.Live && !audioCache.Has(t.Path) {
 		size := int64(0)
 		if st, err := os.Stat(t.Path); err == nil {
 			size = st.Size()
@@ -1643,19 +2927,11 @@ func (t *Track) Cleanup() {
 		} else if err == nil {
 			LogVoice("Cleaned up track file: %s (Size: %d bytes)", t.Path, size)
 		}
-
-		ext := filepath.Ext(t.Path)
-		if ext != "" {
-			metaPath := strings.TrimSuffix(t.Path, ext) + ".meta"
-			_ = os.Remove(metaPath)
-			id := extractVideoID(t.URL)
-			if id != "" {
-				pattern := filepath.Join(".tracks", id+"_*")
-				matches, _ := filepath.Glob(pattern)
-				for _, m := range matches {
-					_ = os.Remove(m)
-				}
-			}
+	}
+	if id := extractVideoID(t.URL); id != "" && !t.Local {
+		matches, _ := filepath.Glob(filepath.Join(AudioCacheSeekDir, id+"_*"))
+		for _, m := range matches {
+			_ = os.Remove(m)
 		}
 	}
 }
@@ -1764,7 +3040,19 @@ func NewTrack(url string) *Track {
 		MetadataReady:   make(chan struct{}),
 		PlaybackStarted: make(chan struct{}),
 	}
-	if !strings.HasPrefix(url, "http") || (isLikelyMusicStreamingSite(url) && !isYouTubeURL(url)) {
+	if rel, ok := strings.CutPrefix(url, LocalTrackPrefix); ok {
+		t.Local = true
+		t.Path, _ = resolveLocalPath(rel)
+		t.Title = strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+		return t
+	}
+	if name, ok := discordAttachmentName(url); ok {
+		t.Local = true
+		t.Path = url
+		t.Title = strings.TrimSuffix(name, filepath.Ext(name))
+		return t
+	}
+	if !strings.HasPrefix(url, "http") || (isLikelyMusicStreamingSite(url) && sourceForURL(url) == nil) {
 		t.NeedsResolution = true
 	}
 	return t
@@ -1805,11 +3093,12 @@ func (t *Track) MarkError(err error) {
 // Transcoding & Helpers
 // ===========================
 
-func (s *VoiceSession) streamFile(url, path string) {
-	s.streamCommon(url, path, nil)
+func (s *VoiceSession) streamFile(url, path string) (time.Duration, bool) {
+	return s.streamCommon(url, path, nil)
 }
 
-func (s *VoiceSession) streamCommon(url, inputPath string, reader io.Reader) {
+// streamCommon plays one input to the end and reports how much audio reached the channel and whether it ran out on its own
+func (s *VoiceSession) streamCommon(url, inputPath string, reader io.Reader) (time.Duration, bool) {
 	s.lockQueue()
 	if s.streamCancel != nil {
 		s.streamCancel()
@@ -1836,8 +3125,16 @@ func (s *VoiceSession) streamCommon(url, inputPath string, reader io.Reader) {
 			p.Close()
 		}()
 		defer p.PushFrame(nil)
-		t := NewAstiavTranscoder()
+		s.lockQueue()
+		track := s.currentTrack
+		s.unlockQueue()
+		t := s.takeCrossfade(track)
+		handoff := t != nil
+		if !handoff {
+			t = NewAstiavTranscoder()
+		}
 		t.volume = &s.Volume
+		t.filter = &s.Filter
 		defer func() {
 			s.lockQueue()
 			if s.transcoder == t {
@@ -1846,47 +3143,61 @@ func (s *VoiceSession) streamCommon(url, inputPath string, reader io.Reader) {
 			s.unlockQueue()
 		}()
 		defer t.Close()
-		var err error
-		for range 100 {
-			err = t.OpenInput(inputPath, reader)
-			if err == nil {
-				break
-			}
-			if ctx.Err() != nil {
-				return
+		if !handoff {
+			t.live = track != nil && track.IsLive()
+			var err error
+			for range 100 {
+				err = t.OpenInput(inputPath, reader)
+				if err == nil {
+					break
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case <-time.After(50 * time.Millisecond):
+				case <-ctx.Done():
+					return
+				}
 			}
-			select {
-			case <-time.After(50 * time.Millisecond):
-			case <-ctx.Done():
+
+			if err != nil {
+				LogVoice("Transcoder OpenInput failed after retries: %v", err)
 				return
 			}
 		}
 
-		if err != nil {
-			LogVoice("Transcoder OpenInput failed after retries: %v", err)
-			return
-		}
-
 		s.lockQueue()
 		s.transcoder = t
 		s.unlockQueue()
 
-		if err := t.SetupDecoder(); err != nil {
-			LogVoice("Transcoder SetupDecoder failed: %v", err)
-			return
-		}
-		if err := t.SetupEncoder(); err != nil {
-			LogVoice("Transcoder SetupEncoder failed: %m", err)
-			return
+		if !handoff {
+			if err := t.SetupDecoder(); err != nil {
+				LogVoice("Transcoder SetupDecoder failed: %v", err)
+				return
+			}
+			if err := t.SetupEncoder(); err != nil {
+				LogVoice("Transcoder SetupEncoder failed: %m", err)
+				return
+			}
+			s.attachLoudness(t, track)
+			if t.live && reader == nil {
+				t.OnMetadata = func(title string) {
+					safeGo(func() { s.setLiveTitle(track, title) })
+				}
+			}
 		}
 
+		t.pull = false
+		t.crossfade = time.Duration(s.Crossfade.Load())
+		t.OnCrossfade = s.prepareCrossfade
 		t.OnNearingEnd = func() {
 			s.lockQueue()
 			s.nearingEnd = true
 			var next *Track
 			if len(s.queue) > 0 {
 				next = s.queue[0]
-			} else if s.Autoplay {
+			} else if s.autoplaying() {
 				next = s.autoplayTrack
 			}
 			s.unlockQueue()
@@ -1896,8 +3207,7 @@ func (s *VoiceSession) streamCommon(url, inputPath string, reader io.Reader) {
 			}
 		}
 
-		err = t.Transcode(ctx, p.PushFrame)
-		if err != nil {
+		if err := t.Transcode(ctx, p.PushFrame); err != nil {
 			LogVoice("Transcoder finished for: %s (Err: %v)", url, err)
 		}
 	})
@@ -1923,8 +3233,10 @@ func (s *VoiceSession) streamCommon(url, inputPath string, reader io.Reader) {
 		}
 		s.unlockQueue()
 	}
+	finished := false
 	select {
 	case <-done:
+		finished = true
 		LogVoice("Playback finished: %s", getMsg())
 	case <-ctx.Done():
 		LogVoice("Playback stopped: %s", getMsg())
@@ -1938,11 +3250,14 @@ func (s *VoiceSession) streamCommon(url, inputPath string, reader io.Reader) {
 			s.setOpusFrameProviderSafe(nil)
 			s.setSpeakingSafe(0)
 		}
-		select {
-		case <-time.After(200 * time.Millisecond):
-		case <-s.cancelCtx.Done():
+		if !s.gapless() {
+			select {
+			case <-time.After(200 * time.Millisecond):
+			case <-s.cancelCtx.Done():
+			}
 		}
 	}
+	return time.Duration(atomic.LoadInt64(&p.frameCount)) * 20 * time.Millisecond, finished
 }
 
 func NewStreamProvider(s *VoiceSession) *StreamProvider {
@@ -1988,6 +3303,9 @@ func (p *StreamProvider) ProvideOpusFrame() ([]byte, error) {
 
 	if p.draining {
 		target := int(SilenceDuration.Milliseconds() / 20)
+		if p.sess.gapless() {
+			target = 0
+		}
 		if p.silenceFrames < target {
 			p.silenceFrames++
 			return OpusSilence, nil
@@ -2002,6 +3320,7 @@ func (p *StreamProvider) ProvideOpusFrame() ([]byte, error) {
 			p.draining = true
 			return OpusSilence, nil
 		}
+		atomic.AddInt64(&p.frameCount, 1)
 		return f, nil
 	case <-p.sess.cancelCtx.Done():
 		p.Close()
@@ -2019,7 +3338,9 @@ func NewAstiavTranscoder() *AstiavTranscoder {
 		packet:        astiav.AllocPacket(),
 		frame:         astiav.AllocFrame(),
 		resampleFrame: astiav.AllocFrame(),
+		filterFrame:   astiav.AllocFrame(),
 		seekChan:      make(chan int64),
+		rate:          1,
 	}
 }
 
@@ -2046,7 +3367,7 @@ func (t *AstiavTranscoder) OpenInput(in string, r io.Reader) error {
 	}
 	if r != nil {
 		t.reader = r
-		seekFunc := func(offset int64, whence int) (int64, error) {
+		var seekFunc astiav.IOContextSeekFunc = func(offset int64, whence int) (int64, error) {
 			if whence == 2 {
 				return -1, errors.New("seeking from end not supported during download")
 			}
@@ -2055,6 +3376,10 @@ func (t *AstiavTranscoder) OpenInput(in string, r io.Reader) error {
 			}
 			return 0, errors.New("seek not supported")
 		}
+		if t.live {
+			// Without a seek callback the input is marked unseekable, so demuxers don't go looking for its end
+			seekFunc = nil
+		}
 
 		ioCtx, err := astiav.AllocIOContext(16*1024, false, func(b []byte) (int, error) {
 			return t.reader.Read(b)
@@ -2067,8 +3392,7 @@ func (t *AstiavTranscoder) OpenInput(in string, r io.Reader) error {
 
 		opts := astiav.NewDictionary()
 		defer opts.Free()
-		opts.Set("probesize", "5000000", 0)
-		opts.Set("analyzeduration", "5000000", 0)
+		t.setProbeOptions(opts)
 		opts.Set("fflags", "nobuffer", 0)
 		opts.Set("flags", "low_delay", 0)
 
@@ -2080,13 +3404,15 @@ func (t *AstiavTranscoder) OpenInput(in string, r io.Reader) error {
 		defer opts.Free()
 		if strings.HasPrefix(in, "http") {
 			opts.Set("reconnect", "1", 0)
-			opts.Set("reconnect_at_eof", "1", 0)
+			if !t.live {
+				// HLS segments end all the time; only whole files should be reopened at EOF
+				opts.Set("reconnect_at_eof", "1", 0)
+			}
 			opts.Set("reconnect_streamed", "1", 0)
 			opts.Set("reconnect_delay_max", "30", 0)
 			opts.Set("timeout", "30000000", 0)
 		}
-		opts.Set("probesize", "5000000", 0)
-		opts.Set("analyzeduration", "5000000", 0)
+		t.setProbeOptions(opts)
 		if err := t.inputCtx.OpenInput(in, nil, opts); err != nil {
 			return err
 		}
@@ -2107,6 +3433,18 @@ func (t *AstiavTranscoder) OpenInput(in string, r io.Reader) error {
 	return nil
 }
 
+// setProbeOptions bounds how much input is read up front to detect the format; a live stream only needs a moment
+// of audio, and waiting for more would delay the start
+func (t *AstiavTranscoder) setProbeOptions(opts *astiav.Dictionary) {
+	if t.live {
+		opts.Set("probesize", LiveProbeSize, 0)
+		opts.Set("analyzeduration", "1000000", 0)
+		return
+	}
+	opts.Set("probesize", "5000000", 0)
+	opts.Set("analyzeduration", "5000000", 0)
+}
+
 func (t *AstiavTranscoder) SetupDecoder() error {
 	p := t.inputCtx.Streams()[t.audioStreamIndex].CodecParameters()
 	d := astiav.FindDecoder(p.CodecID())
@@ -2163,10 +3501,8 @@ func (t *AstiavTranscoder) Transcode(ctx context.Context, on func([]byte)) (err
 		}
 	}()
 
-	fifoSize := 960 * 2
-	t.fifo = astiav.AllocAudioFifo(t.encoderCtx.SampleFormat(), t.encoderCtx.ChannelLayout().Channels(), fifoSize)
-	if t.fifo == nil {
-		return errors.New("failed to alloc fifo")
+	if err := t.allocFifo(); err != nil {
+		return err
 	}
 	defer func() {
 		if t.fifo != nil {
@@ -2186,38 +3522,22 @@ func (t *AstiavTranscoder) Transcode(ctx context.Context, on func([]byte)) (err
 		default:
 		}
 
-		t.packet.Unref()
-
-		if err := t.inputCtx.ReadFrame(t.packet); err != nil {
+		if err := t.decodePacket(); err != nil {
 			if errors.Is(err, astiav.ErrEof) {
 				break
 			}
 			return err
 		}
 
-		if t.packet.StreamIndex() != t.audioStreamIndex {
-			continue
-		}
-
-		if err := t.decoderCtx.SendPacket(t.packet); err != nil {
-			return err
-		}
-
-		for {
-			if err := t.decoderCtx.ReceiveFrame(t.frame); err != nil {
-				break
-			}
-
-			if err := t.pushToFifo(); err != nil {
-				return err
-			}
-
-			t.frame.Unref()
-		}
-
 		if !t.nearingEndTriggered && t.inputCtx.Duration() > 0 {
 			t.checkNearingEnd()
 		}
+		if t.crossfade > 0 && !t.crossfadeTried && t.inputCtx.Duration() > 0 {
+			t.checkCrossfade()
+		}
+		if t.OnMetadata != nil {
+			t.checkMetadata()
+		}
 	}
 
 	if t.decoderCtx != nil {
@@ -2233,6 +3553,14 @@ func (t *AstiavTranscoder) Transcode(ctx context.Context, on func([]byte)) (err
 		}
 	}
 
+	if ctx.Err() == nil {
+		t.finishLoudness()
+	}
+	if t.filterGraph != nil {
+		if err := t.filterToFifo(nil); err != nil {
+			return err
+		}
+	}
 	if err := t.processFifo(true); err != nil {
 		return err
 	}
@@ -2244,6 +3572,41 @@ func (t *AstiavTranscoder) Transcode(ctx context.Context, on func([]byte)) (err
 	return nil
 }
 
+// decodePacket reads one packet and decodes it into the fifo; it returns astiav.ErrEof at the end of the input
+func (t *AstiavTranscoder) decodePacket() error {
+	t.packet.Unref()
+	if err := t.inputCtx.ReadFrame(t.packet); err != nil {
+		return err
+	}
+	if t.packet.StreamIndex() != t.audioStreamIndex {
+		return nil
+	}
+	if err := t.decoderCtx.SendPacket(t.packet); err != nil {
+		return err
+	}
+	for {
+		if err := t.decoderCtx.ReceiveFrame(t.frame); err != nil {
+			break
+		}
+		if err := t.pushToFifo(); err != nil {
+			return err
+		}
+		t.frame.Unref()
+	}
+	return nil
+}
+
+func (t *AstiavTranscoder) allocFifo() error {
+	if t.fifo != nil {
+		return nil
+	}
+	t.fifo = astiav.AllocAudioFifo(t.encoderCtx.SampleFormat(), t.encoderCtx.ChannelLayout().Channels(), 960*2)
+	if t.fifo == nil {
+		return errors.New("failed to alloc fifo")
+	}
+	return nil
+}
+
 func (t *AstiavTranscoder) receiveAndWrite() error {
 	for {
 		t.packet.Unref()
@@ -2294,11 +3657,42 @@ func (t *AstiavTranscoder) handleSeek(ts int64) error {
 			t.fifo.Free()
 			t.fifo = astiav.AllocAudioFifo(t.encoderCtx.SampleFormat(), t.encoderCtx.ChannelLayout().Channels(), 960*2)
 		}
+		// Drop whatever the old graph still buffers; it is rebuilt on the next frame
+		t.freeFilter()
+		t.appliedFilter = nil
+		t.loudnessSkipped = true
+		t.mix, t.crossfadeTried = nil, false
 		atomic.StoreInt64(&t.pts, ts)
 	}
 	return nil
 }
 
+// checkMetadata reports the title a live stream announces in-band, such as the ID3 tags HLS radio puts in its segments
+func (t *AstiavTranscoder) checkMetadata() {
+	flags := astiav.NewDictionaryFlags()
+	for _, md := range []*astiav.Dictionary{t.inputCtx.Streams()[t.audioStreamIndex].Metadata(), t.inputCtx.Metadata()} {
+		if md == nil {
+			continue
+		}
+		var title string
+		if e := md.Get("StreamTitle", nil, flags); e != nil {
+			title = e.Value()
+		} else if e := md.Get("title", nil, flags); e != nil {
+			title = e.Value()
+			if a := md.Get("artist", nil, flags); a != nil && strings.TrimSpace(a.Value()) != "" {
+				title = a.Value() + " - " + title
+			}
+		}
+		if title = strings.TrimSpace(title); title != "" {
+			if title != t.liveTitle {
+				t.liveTitle = title
+				t.OnMetadata(title)
+			}
+			return
+		}
+	}
+}
+
 func (t *AstiavTranscoder) checkNearingEnd() {
 	totalSecs := float64(t.inputCtx.Duration()) / 1000000.0
 	currentSecs := float64(atomic.LoadInt64(&t.pts)) / 48000.0
@@ -2311,6 +3705,69 @@ func (t *AstiavTranscoder) checkNearingEnd() {
 	}
 }
 
+// checkCrossfade starts mixing in the next track once what is left of this one fits in the crossfade window;
+// filtered audio is left alone since the next track's head would bypass the filter chain
+func (t *AstiavTranscoder) checkCrossfade() {
+	total := t.inputCtx.Duration() * 48000 / 1000000
+	remaining := total - atomic.LoadInt64(&t.pts)
+	if remaining > t.crossfade.Milliseconds()*48 {
+		return
+	}
+	t.crossfadeTried = true
+	if remaining <= 0 || t.appliedFilter != nil || t.OnCrossfade == nil {
+		return
+	}
+	if t.mix = t.OnCrossfade(); t.mix != nil {
+		t.mixLength, t.mixDone = remaining, 0
+	}
+}
+
+// pullPCM decodes ahead until n samples are buffered and returns them as interleaved s16, or fewer at the end of the input
+func (t *AstiavTranscoder) pullPCM(n int) []byte {
+	for t.fifo.Size() < n && !t.pullEOF {
+		if err := t.decodePacket(); err != nil {
+			t.pullEOF = true
+		}
+	}
+	sz := min(n, t.fifo.Size())
+	if sz == 0 {
+		return nil
+	}
+	t.resampleFrame.Unref()
+	t.resampleFrame.SetNbSamples(sz)
+	t.resampleFrame.SetChannelLayout(t.encoderCtx.ChannelLayout())
+	t.resampleFrame.SetSampleFormat(t.encoderCtx.SampleFormat())
+	t.resampleFrame.SetSampleRate(t.encoderCtx.SampleRate())
+	_ = t.resampleFrame.AllocBuffer(0)
+	_, _ = t.fifo.Read(t.resampleFrame)
+	atomic.AddInt64(&t.pts, int64(sz))
+
+	data, _ := t.resampleFrame.Data().Bytes(1)
+	return data[:min(len(data), sz*4)]
+}
+
+// pull hands the mixer the next n samples and their gain, unless the source has already been taken over
+func (c *crossfadeSource) pull(n int) ([]byte, float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.taken {
+		return nil, 0, false
+	}
+	return c.tr.pullPCM(n), c.tr.outputGain(), true
+}
+
+func (c *crossfadeSource) discard() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.taken {
+		c.taken = true
+		c.tr.Close()
+	}
+}
+
 func (t *AstiavTranscoder) encodeAndWrite(f *astiav.Frame) error {
 	if err := t.encoderCtx.SendFrame(f); err != nil {
 		return err
@@ -2328,7 +3785,13 @@ func (t *AstiavTranscoder) pushToFifo() error {
 		t.resampleFrame.SetNbSamples(nb)
 		_ = t.resampleFrame.AllocBuffer(0)
 		if t.resampleCtx.ConvertFrame(t.frame, t.resampleFrame) == nil {
-			_, _ = t.fifo.Write(t.resampleFrame)
+			t.measureLoudness(t.resampleFrame)
+			if err := t.filterToFifo(t.resampleFrame); err != nil {
+				return err
+			}
+			if t.pull {
+				return nil
+			}
 			return t.processFifo(false)
 		}
 	}
@@ -2357,31 +3820,49 @@ func (t *AstiavTranscoder) processFifo(drain bool) error {
 
 		t.frameCount++
 
-		if t.volume != nil {
-			vol := t.volume.Load()
-			if vol != 100 {
-				data, _ := t.resampleFrame.Data().Bytes(1)
-				limit := sz * 4
-				if limit > len(data) {
-					limit = len(data)
+		gain := t.outputGain()
+		var mixed []byte
+		var mixGain, fadeIn float64
+		if t.mix != nil {
+			var ok bool
+			if mixed, mixGain, ok = t.mix.pull(sz); ok {
+				// Equal-power fade: the outgoing track follows a cosine, the incoming one a sine
+				progress := math.Min(1, float64(t.mixDone)/float64(t.mixLength))
+				gain *= math.Cos(progress * math.Pi / 2)
+				fadeIn = math.Sin(progress * math.Pi / 2)
+				t.mixDone += int64(sz)
+			} else {
+				t.mix = nil
+			}
+		}
+
+		if math.Abs(gain-1) > 0.001 || mixed != nil {
+			data, _ := t.resampleFrame.Data().Bytes(1)
+			limit := sz * 4
+			if limit > len(data) {
+				limit = len(data)
+			}
+			for i := 0; i < limit; i += 2 {
+				sample := int16(data[i]) | int16(data[i+1])<<8
+				v := float64(sample) * gain
+				if i+1 < len(mixed) {
+					v += float64(int16(mixed[i])|int16(mixed[i+1])<<8) * mixGain * fadeIn
 				}
-				for i := 0; i < limit; i += 2 {
-					sample := int16(data[i]) | int16(data[i+1])<<8
-					scaled := int64(sample) * int64(vol) / 100
-					if scaled > 32767 {
-						scaled = 32767
-					} else if scaled < -32768 {
-						scaled = -32768
-					}
-					data[i] = byte(scaled)
-					data[i+1] = byte(scaled >> 8)
+				scaled := int64(math.Round(v))
+				if scaled > 32767 {
+					scaled = 32767
+				} else if scaled < -32768 {
+					scaled = -32768
 				}
-				_ = t.resampleFrame.Data().SetBytes(data, 1)
+				data[i] = byte(scaled)
+				data[i+1] = byte(scaled >> 8)
 			}
+			_ = t.resampleFrame.Data().SetBytes(data, 1)
 		}
 
 		t.resampleFrame.SetPts(atomic.LoadInt64(&t.pts))
-		atomic.AddInt64(&t.pts, int64(sz))
+		// pts tracks the position in the source, which tempo-changing filters consume faster or slower than real time
+		atomic.AddInt64(&t.pts, int64(float64(sz)*t.rate))
 		if err := t.encodeAndWrite(t.resampleFrame); err != nil {
 			return err
 		}
@@ -2389,6 +3870,15 @@ func (t *AstiavTranscoder) processFifo(drain bool) error {
 }
 
 func (t *AstiavTranscoder) Close() {
+	t.freeFilter()
+	t.freeLoudness()
+	if t.fifo != nil {
+		t.fifo.Free()
+		t.fifo = nil
+	}
+	if t.filterFrame != nil {
+		t.filterFrame.Free()
+	}
 	if t.resampleCtx != nil {
 		t.resampleCtx.Free()
 	}
@@ -2414,2037 +3904,5686 @@ func (t *AstiavTranscoder) Close() {
 }
 
 // ===========================
-// YT-DLP & Autocomplete
+// Audio Filters
 // ===========================
 
-const (
-	YoutubePrefix = "[YT]"
-	YTMusicPrefix = "[YTM]"
-)
-
-func getYoutubePrefix() string {
-	return YoutubePrefix
-}
-
-func getYTMusicPrefix() string {
-	return YTMusicPrefix
+// audioGraph is a libavfilter graph that takes frames in the encoder's format
+type audioGraph struct {
+	graph *astiav.FilterGraph
+	src   *astiav.BuffersrcFilterContext
+	sink  *astiav.BuffersinkFilterContext
+	pts   int64
 }
 
-func (vs *VoiceSystem) Search(q string) ([]SearchResult, error) {
-	vs.cache.RLock()
-	if item, ok := vs.cache.items[q]; ok {
-		if time.Now().Before(item.expiresAt) {
-			vs.cache.RUnlock()
-			return item.results, nil
-		}
+// newAudioGraph builds a graph running content between an abuffer fed in enc's format and an abuffersink
+func newAudioGraph(enc *astiav.CodecContext, content string) (g *audioGraph, err error) {
+	g = &audioGraph{graph: astiav.AllocFilterGraph()}
+	if g.graph == nil {
+		return nil, errors.New("failed to alloc filter graph")
 	}
-	vs.cache.RUnlock()
+	defer func() {
+		if err != nil {
+			g.free()
+		}
+	}()
 
-	src, query := "ytmusic", q
-	ytp, ytmp := getYoutubePrefix(), getYTMusicPrefix()
-	if strings.HasPrefix(strings.ToUpper(q), strings.ToUpper(ytp)) {
-		src, query = "youtube", strings.TrimSpace(q[len(ytp):])
-	} else if strings.HasPrefix(strings.ToUpper(q), strings.ToUpper(ytmp)) {
-		query = strings.TrimSpace(q[len(ytmp):])
+	src := astiav.FindFilterByName("abuffer")
+	sink := astiav.FindFilterByName("abuffersink")
+	if src == nil || sink == nil {
+		return nil, errors.New("abuffer filters unavailable")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 2600*time.Millisecond)
-	defer cancel()
-	resMu := sync.Mutex{}
-	var ytm, yt []SearchResult
-	seen := make(map[string]bool)
-	wg := sync.WaitGroup{}
-	wg.Add(2)
-	safeGo(func() {
-		defer wg.Done()
-		s := ytmusic.TrackSearch(query)
-		r, _ := s.Next()
-		for _, v := range r.Tracks {
-			if v.VideoID == "" {
-				continue
-			}
-			art := ""
-			if len(v.Artists) > 0 {
-				art = " - " + v.Artists[0].Name
-			}
-			resMu.Lock()
-			if !seen[v.VideoID] {
-				seen[v.VideoID] = true
-				ytm = append(ytm, SearchResult{URL: "https://music.youtube.com/watch?v=" + v.VideoID, Title: TruncateWithPreserve(v.Title, 100, "[YTM] ", art)})
-			}
-			resMu.Unlock()
-		}
-	})
-	safeGo(func() {
-		defer wg.Done()
-		c := ytsearch.NewClient(nil)
-		r, _ := c.Search(ctx, query)
-		for _, v := range r.Results {
-			resMu.Lock()
-			if !seen[v.VideoID] {
-				seen[v.VideoID] = true
-				yt = append(yt, SearchResult{URL: "https://www.youtube.com/watch?v=" + v.VideoID, Title: TruncateWithPreserve(v.Title, 100, "[YT] ", "")})
-			}
-			resMu.Unlock()
-		}
-	})
-	d := make(chan struct{})
-	safeGo(func() {
-		wg.Wait()
-		close(d)
-	})
-	select {
-	case <-d:
-	case <-time.After(2300 * time.Millisecond):
+	if g.src, err = g.graph.NewBuffersrcFilterContext(src, "in"); err != nil {
+		return nil, err
 	}
-	resMu.Lock()
-	defer resMu.Unlock()
-	var fin []SearchResult
-	if src == "youtube" {
-		fin = append(yt, ytm...)
-	} else {
-		fin = append(ytm, yt...)
+	if g.sink, err = g.graph.NewBuffersinkFilterContext(sink, "out"); err != nil {
+		return nil, err
 	}
-	if len(fin) > 25 {
-		fin = fin[:25]
+
+	params := astiav.AllocBuffersrcFilterContextParameters()
+	defer params.Free()
+	params.SetChannelLayout(enc.ChannelLayout())
+	params.SetSampleFormat(enc.SampleFormat())
+	params.SetSampleRate(enc.SampleRate())
+	params.SetTimeBase(astiav.NewRational(1, enc.SampleRate()))
+	if err = g.src.SetParameters(params); err != nil {
+		return nil, err
 	}
-	if len(fin) > 0 {
-		vs.cache.Lock()
-		vs.cache.items[q] = cachedItem{results: fin, expiresAt: time.Now().Add(1 * time.Hour)}
-		vs.cache.Unlock()
+	if err = g.src.Initialize(nil); err != nil {
+		return nil, err
 	}
 
-	return fin, nil
-}
-
-func (vs *VoiceSystem) SearchPlaylist(q string) ([]ytdlpSearchResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	var ytRs, ytmRs []ytdlpSearchResult
-	var ytErr, ytmErr error
-	wg := sync.WaitGroup{}
-	wg.Add(2)
+	outputs := astiav.AllocFilterInOut()
+	defer outputs.Free()
+	outputs.SetName("in")
+	outputs.SetFilterContext(g.src.FilterContext())
+	outputs.SetPadIdx(0)
+	outputs.SetNext(nil)
 
-	safeGo(func() {
-		defer wg.Done()
-		ytRs, ytErr = ytdlpSearchPlaylist(ctx, q, 10)
-	})
-	safeGo(func() {
-		defer wg.Done()
-		ytmRs, ytmErr = ytdlpSearchPlaylistYTM(ctx, q, 10)
-	})
-	wg.Wait()
+	inputs := astiav.AllocFilterInOut()
+	defer inputs.Free()
+	inputs.SetName("out")
+	inputs.SetFilterContext(g.sink.FilterContext())
+	inputs.SetPadIdx(0)
+	inputs.SetNext(nil)
 
-	if ytErr != nil && ytmErr != nil {
-		return nil, fmt.Errorf("YouTube: %v, YTM: %v", ytErr, ytmErr)
+	if err = g.graph.Parse(content, inputs, outputs); err != nil {
+		return nil, err
+	}
+	if err = g.graph.Configure(); err != nil {
+		return nil, err
 	}
+	return g, nil
+}
 
-	var res []ytdlpSearchResult
-	seen := make(map[string]bool)
-	for _, r := range ytmRs {
-		if seen[r.URL] {
-			continue
-		}
-		res = append(res, ytdlpSearchResult{Title: "[PL] " + r.Title, Uploader: r.Uploader, URL: r.URL})
-		seen[r.URL] = true
+// send feeds a frame into the graph, stamping it with a running sample count; nil flushes the graph
+func (g *audioGraph) send(f *astiav.Frame) error {
+	if f != nil {
+		f.SetPts(g.pts)
+		g.pts += int64(f.NbSamples())
 	}
-	for _, r := range ytRs {
-		if seen[r.URL] {
-			continue
+	return g.src.AddFrame(f, astiav.NewBuffersrcFlags(astiav.BuffersrcFlagKeepRef))
+}
+
+// receive pulls the next filtered frame, reporting false once the graph needs more input
+func (g *audioGraph) receive(f *astiav.Frame) (bool, error) {
+	if err := g.sink.GetFrame(f, astiav.NewBuffersinkFlags()); err != nil {
+		if errors.Is(err, astiav.ErrEof) || errors.Is(err, astiav.ErrEagain) {
+			return false, nil
 		}
-		res = append(res, ytdlpSearchResult{Title: "[PL] " + r.Title, Uploader: r.Uploader, URL: r.URL})
-		seen[r.URL] = true
+		return false, err
 	}
+	return true, nil
+}
 
-	return res, nil
+func (g *audioGraph) free() {
+	if g.graph != nil {
+		g.graph.Free()
+		g.graph = nil
+	}
 }
 
-func (s *VoiceSession) resolveTrackMetadata(ctx context.Context, t *Track) error {
-	if !t.NeedsResolution {
+// filterToFifo runs a resampled frame through the session's filter graph and queues the result for encoding; a nil frame flushes the graph
+func (t *AstiavTranscoder) filterToFifo(f *astiav.Frame) error {
+	if f != nil {
+		t.syncFilter()
+	}
+	if t.filterGraph == nil {
+		if f != nil {
+			_, _ = t.fifo.Write(f)
+		}
 		return nil
 	}
 
-	start := time.Now()
-	origURL := t.URL
-	defer func() {
-		LogVoice("Metadata resolution for %s took %v", origURL, time.Since(start))
-	}()
+	if err := t.filterGraph.send(f); err != nil {
+		return err
+	}
+	for {
+		ok, err := t.filterGraph.receive(t.filterFrame)
+		if err != nil || !ok {
+			return err
+		}
+		_, _ = t.fifo.Write(t.filterFrame)
+		t.filterFrame.Unref()
+	}
+}
 
-	needsSearch := !strings.HasPrefix(t.URL, "http")
-	var targetDuration time.Duration
+// syncFilter rebuilds the filter graph when the session's filter has changed since the last frame
+func (t *AstiavTranscoder) syncFilter() {
+	if t.filter == nil {
+		return
+	}
+	want := t.filter.Load()
+	if want == t.appliedFilter {
+		return
+	}
+	t.appliedFilter = want
+	t.freeFilter()
+	if want == nil {
+		return
+	}
 
-	if !needsSearch && !isYouTubeURL(t.URL) {
-		likelyDRMSite := isLikelyMusicStreamingSite(t.URL)
+	chain := want.Chain()
+	if chain == "" {
+		return
+	}
+	content := fmt.Sprintf("%s,aformat=sample_fmts=%s:sample_rates=%d:channel_layouts=%s",
+		chain, t.encoderCtx.SampleFormat().Name(), t.encoderCtx.SampleRate(), t.encoderCtx.ChannelLayout().String())
+	g, err := newAudioGraph(t.encoderCtx, content)
+	if err != nil {
+		LogVoice("Audio filter %q failed, playing unfiltered: %v", chain, err)
+		return
+	}
+	t.filterGraph = g
+	t.rate = want.Rate()
+}
 
-		resultChan := make(chan metadataResult, 2)
+func (t *AstiavTranscoder) freeFilter() {
+	if t.filterGraph != nil {
+		t.filterGraph.free()
+		t.filterGraph = nil
+	}
+	t.rate = 1
+}
 
-		safeGo(func() {
-			timeout := 30 * time.Second
-			if likelyDRMSite {
-				timeout = 3 * time.Second
-			}
+// ===========================
+// Loudness Normalization
+// ===========================
 
-			ytdlpCtx, ytdlpCancel := context.WithTimeout(ctx, timeout)
-			defer ytdlpCancel()
+// SetLoudness applies a previously measured integrated loudness from the first frame, skipping analysis
+func (t *AstiavTranscoder) SetLoudness(lufs float64) {
+	t.loudness = lufs
+	t.gainDB = loudnessGain(lufs)
+	t.targetGainDB = t.gainDB
+	t.loudnessKnown = true
+}
 
-			title, uploader, id, dur, sz, err := ytdlpResolveMetadata(ytdlpCtx, t.URL)
-			if err == nil {
-				t.mu.Lock()
-				t.TotalSize = sz
-				t.mu.Unlock()
-			}
-			if id != "" {
-				t.mu.Lock()
-				if !strings.HasPrefix(t.URL, "http") {
-					t.URL = "https://www.youtube.com/watch?v=" + id
+// setupLoudness starts an EBU R128 meter on the unfiltered audio unless the track's loudness is already known
+func (t *AstiavTranscoder) setupLoudness() {
+	if t.loudnessKnown || t.loudnessGraph != nil {
+		return
+	}
+	g, err := newAudioGraph(t.encoderCtx, "ebur128=metadata=1")
+	if err != nil {
+		LogVoice("Loudness meter unavailable: %v", err)
+		return
+	}
+	t.loudnessGraph = g
+	t.loudnessFrame = astiav.AllocFrame()
+}
+
+// measureLoudness feeds a frame to the meter and steers the gain toward the running integrated loudness
+func (t *AstiavTranscoder) measureLoudness(f *astiav.Frame) {
+	if t.loudnessGraph == nil {
+		return
+	}
+	if err := t.loudnessGraph.send(f); err != nil {
+		LogVoice("Loudness meter failed: %v", err)
+		t.freeLoudness()
+		return
+	}
+	for {
+		ok, err := t.loudnessGraph.receive(t.loudnessFrame)
+		if err != nil || !ok {
+			break
+		}
+		if md := t.loudnessFrame.Metadata(); md != nil {
+			if e := md.Get("lavfi.r128.I", nil, astiav.NewDictionaryFlags()); e != nil {
+				if v, err := strconv.ParseFloat(e.Value(), 64); err == nil && v > LoudnessFloor {
+					t.loudness = v
 				}
-				t.mu.Unlock()
 			}
-			resultChan <- metadataResult{title, uploader, dur, "yt-dlp", err}
-		})
+		}
+		t.loudnessFrame.Unref()
+	}
 
-		if likelyDRMSite {
-			safeGo(func() {
-				scrapeCtx, scrapeCancel := context.WithTimeout(ctx, 5*time.Second)
-				defer scrapeCancel()
+	t.loudnessSamples += int64(f.NbSamples())
+	if t.loudness != 0 && t.loudnessSamples >= LoudnessSettleSamples {
+		t.targetGainDB = loudnessGain(t.loudness)
+	}
+}
 
-				title, artist, err := extractMetadataFromDRMSite(scrapeCtx, t.URL)
-				resultChan <- metadataResult{title, artist, 0, "scraper", err}
-			})
-		}
+// finishLoudness reports the measurement at the end of the input, but only if it covers the whole track: a seek, or
+// an input cut off early by a skip, leaves a partial reading that mustn't be cached as the track's loudness
+func (t *AstiavTranscoder) finishLoudness() {
+	if t.loudnessGraph == nil || t.OnLoudness == nil || t.loudnessSkipped {
+		return
+	}
+	if total := t.inputCtx.Duration() * 48000 / 1000000; total > 0 && t.loudnessSamples < total*95/100 {
+		return
+	}
+	if t.loudness != 0 && t.loudnessSamples >= LoudnessSettleSamples {
+		t.OnLoudness(t.loudness)
+	}
+}
 
-		var ytdlpResult, scraperResult *metadataResult
-		resultsReceived := 0
-		expectedResults := 1
-		if likelyDRMSite {
-			expectedResults = 2
-		}
+func (t *AstiavTranscoder) freeLoudness() {
+	if t.loudnessGraph != nil {
+		t.loudnessGraph.free()
+		t.loudnessGraph = nil
+	}
+	if t.loudnessFrame != nil {
+		t.loudnessFrame.Free()
+		t.loudnessFrame = nil
+	}
+}
 
-	waitLoop:
-		for resultsReceived < expectedResults {
-			select {
-			case res := <-resultChan:
-				resultsReceived++
-				if res.source == "yt-dlp" {
-					ytdlpResult = &res
-				} else {
-					scraperResult = &res
-				}
+// outputGain returns the linear factor for the next frame, combining the session volume with the loudness gain;
+// the gain moves gradually so corrections made while a track is still being measured don't pump
+func (t *AstiavTranscoder) outputGain() float64 {
+	if delta := t.targetGainDB - t.gainDB; math.Abs(delta) > LoudnessRampDB {
+		t.gainDB += math.Copysign(LoudnessRampDB, delta)
+	} else {
+		t.gainDB = t.targetGainDB
+	}
 
-				if res.err == nil && res.title != "" {
-					t.Title = res.title
-					t.Channel = res.artist
-					targetDuration = res.duration
-					if res.artist != "" {
-						t.URL = res.title + " " + res.artist
-					} else {
-						t.URL = res.title
-					}
-					needsSearch = true
-					break waitLoop
-				}
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(1 * time.Second):
-				if resultsReceived > 0 {
-					break waitLoop
-				}
-			}
-		}
+	gain := math.Pow(10, t.gainDB/20)
+	if t.volume != nil {
+		gain *= float64(t.volume.Load()) / 100
+	}
+	return gain
+}
 
-		safeGo(func() {
-			for resultsReceived < expectedResults {
-				select {
-				case <-resultChan:
-					resultsReceived++
-				case <-time.After(5 * time.Second):
-					return
-				}
-			}
-		})
+// loudnessGain is the correction in dB that brings a track measured at lufs to the target, within safe limits
+func loudnessGain(lufs float64) float64 {
+	return math.Max(LoudnessMaxCut, math.Min(LoudnessTarget-lufs, LoudnessMaxBoost))
+}
 
-		if !needsSearch {
-			if scraperResult != nil && scraperResult.err == nil && scraperResult.title != "" {
-				t.Title = scraperResult.title
-				t.Channel = scraperResult.artist
-				if scraperResult.artist != "" {
-					t.URL = scraperResult.title + " " + scraperResult.artist
-				} else {
-					t.URL = scraperResult.title
-				}
-				needsSearch = true
-			} else if ytdlpResult != nil && ytdlpResult.err != nil {
-				if strings.Contains(ytdlpResult.err.Error(), "DRM") {
-					LogVoice("DRM detected for %s, but scraping also failed", t.URL)
-					return fmt.Errorf("DRM-protected content not supported: %s", t.URL)
-				}
-			}
+// trackLoudness returns a track's measured loudness from the track itself or the metadata cache, or 0 if unknown
+func trackLoudness(t *Track) float64 {
+	t.mu.Lock()
+	lufs, url := t.Loudness, t.URL
+	t.mu.Unlock()
+	if lufs != 0 {
+		return lufs
+	}
+	if id := extractVideoID(url); id != "" {
+		if cm := readMetadataCache(id); cm != nil {
+			return cm.Loudness
 		}
 	}
+	return 0
+}
 
-	if needsSearch {
-		q := t.URL
-		ytp, ytmp := getYoutubePrefix(), getYTMusicPrefix()
-		if strings.HasPrefix(strings.ToUpper(q), strings.ToUpper(ytp)) {
-			q = strings.TrimSpace(q[len(ytp):])
-		} else if strings.HasPrefix(strings.ToUpper(q), strings.ToUpper(ytmp)) {
-			q = strings.TrimSpace(q[len(ytmp):])
-		}
-		ch := make(chan prioritizedSearchResult, 2)
-		safeGo(func() {
-			r, _ := ytdlpSearchYTM(ctx, q, 5)
-			ch <- prioritizedSearchResult{r, 0}
-		})
-		safeGo(func() {
-			r, _ := ytdlpSearch(ctx, q, 5)
-			ch <- prioritizedSearchResult{r, 1}
-		})
+func findAudioFilterPreset(name string) (AudioFilterPreset, bool) {
+	i := slices.IndexFunc(AudioFilterPresets, func(p AudioFilterPreset) bool { return p.Name == name })
+	if i < 0 {
+		return AudioFilterPreset{}, false
+	}
+	return AudioFilterPresets[i], true
+}
 
-		var combined []ytdlpSearchResult
-		resList := make([][]ytdlpSearchResult, 2)
-		for range 2 {
-			r := <-ch
-			resList[r.prio] = r.res
-		}
-		combined = append(resList[0], resList[1]...)
+func audioFilterChoices() []discord.ApplicationCommandOptionChoiceString {
+	choices := make([]discord.ApplicationCommandOptionChoiceString, 0, len(AudioFilterPresets))
+	for _, p := range AudioFilterPresets {
+		choices = append(choices, discord.ApplicationCommandOptionChoiceString{Name: p.Label, Value: p.Name})
+	}
+	return choices
+}
 
-		if len(combined) > 0 {
-			best := s.SelectBestTrack(combined, t.Title, t.Channel, targetDuration)
-			if strings.Contains(best.URL, "http") {
-				t.URL, t.Title, t.Channel, t.Duration = best.URL, best.Title, best.Uploader, best.Duration
-				s.updateNextTrackStatusIfNeeded(t)
-			}
+// Chain returns the libavfilter description of the filter, or "" when it leaves the audio untouched
+func (f *AudioFilter) Chain() string {
+	var parts []string
+	if p, ok := findAudioFilterPreset(f.Preset); ok && p.Chain != "" {
+		parts = append(parts, p.Chain)
+	}
+
+	// asetrate shifts pitch and speed together, so atempo undoes the speed change and applies the requested tempo
+	tempo := f.Tempo
+	if f.Pitch > 0 && f.Pitch != 1 {
+		parts = append(parts, fmt.Sprintf("asetrate=%d,aresample=48000", int(48000*f.Pitch)))
+		tempo /= f.Pitch
+	}
+	if tempo > 0 {
+		// atempo only accepts factors between 0.5 and 2, so larger changes are chained
+		for tempo < 0.5 {
+			parts = append(parts, "atempo=0.5")
+			tempo /= 0.5
+		}
+		for tempo > 2 {
+			parts = append(parts, "atempo=2")
+			tempo /= 2
+		}
+		if math.Abs(tempo-1) > 0.001 {
+			parts = append(parts, fmt.Sprintf("atempo=%.4f", tempo))
 		}
 	}
+	return strings.Join(parts, ",")
+}
 
-	if !strings.HasPrefix(t.URL, "http") {
-		return errors.New("no song found")
+// Rate is how many seconds of the source one second of filtered audio covers
+func (f *AudioFilter) Rate() float64 {
+	rate := 1.0
+	if p, ok := findAudioFilterPreset(f.Preset); ok {
+		rate = p.Rate
 	}
-	return nil
+	if f.Tempo > 0 {
+		rate *= f.Tempo
+	}
+	return rate
 }
 
-func (s *VoiceSession) processTrackFile(ctx context.Context, t *Track) {
-	videoID := extractVideoID(t.URL)
-	isYouTube := isYouTubeURL(t.URL)
+func (f *AudioFilter) String() string {
+	var parts []string
+	if p, ok := findAudioFilterPreset(f.Preset); ok && p.Chain != "" {
+		parts = append(parts, p.Label)
+	}
+	if f.Tempo > 0 && f.Tempo != 1 {
+		parts = append(parts, fmt.Sprintf("%.2fx tempo", f.Tempo))
+	}
+	if f.Pitch > 0 && f.Pitch != 1 {
+		parts = append(parts, fmt.Sprintf("%.2fx pitch", f.Pitch))
+	}
+	if len(parts) == 0 {
+		return "Off"
+	}
+	return strings.Join(parts, " · ")
+}
 
-	if videoID != "" && isYouTube {
-		filename := filepath.Join(AudioCacheDir, videoID+".webm")
+// SetFilter switches the session's effects; the transcoder picks the change up on its next frame
+func (s *VoiceSession) SetFilter(f *AudioFilter) {
+	if f != nil && f.Chain() == "" {
+		f = nil
+	}
+	s.Filter.Store(f)
+}
 
-		if t.Title == "" {
-			if cm := readMetadataCache(videoID); cm != nil {
-				t.mu.Lock()
-				t.Title, t.Channel, t.Duration = cm.Title, cm.Channel, cm.Duration
-				t.mu.Unlock()
-				t.SafeCloseMetadata()
-				s.updateNextTrackStatusIfNeeded(t)
-			}
-		}
+// ===========================
+// Local Library
+// ===========================
 
-		if t.Title == "" {
-			safeGo(func() {
-				var err error = errors.New("fast metadata disabled")
-				var title, uploader string
-				var dur time.Duration
+var localAudioExts = map[string]bool{
+	".mp3": true, ".flac": true, ".ogg": true, ".opus": true, ".m4a": true,
+	".aac": true, ".wav": true, ".webm": true, ".wma": true, ".aiff": true,
+}
 
-				if err != nil {
-					var dur2 time.Duration
-					var sz2 int64
-					title, uploader, _, dur2, sz2, err = ytdlpResolveMetadata(ctx, t.URL)
-					if err == nil {
-						t.mu.Lock()
-						t.TotalSize = sz2
-						t.mu.Unlock()
-					}
-					dur = dur2
-				}
+// localLibrary indexes the audio files under MUSIC_DIR as slash-separated paths relative to it
+type localLibrary struct {
+	mu      sync.Mutex
+	root    string
+	files   []string
+	scanned time.Time
+}
 
-				if err == nil {
-					t.mu.Lock()
-					t.Title = title
-					t.Channel = uploader
-					t.Duration = dur
-					t.mu.Unlock()
-					writeMetadataCache(videoID, title, uploader, dur)
-					t.SafeCloseMetadata()
-					s.updateNextTrackStatusIfNeeded(t)
-				} else {
-					LogVoice("Background metadata fetch failed for %s: %v", t.URL, err)
-					t.SafeCloseMetadata()
-				}
-			})
-		} else {
-			select {
-			case <-t.MetadataReady:
-			default:
-				t.SafeCloseMetadata()
-			}
-			safeGo(func() { writeMetadataCache(videoID, t.Title, t.Channel, t.Duration) })
-		}
+var musicLibrary localLibrary
 
-		if _, err := os.Stat(filename); err == nil {
-			t.MarkReady(filename, t.Title, t.Channel, t.Duration, nil)
-			return
-		}
+// Files returns the library index, rescanning the directory when it is stale or the configured root changed
+func (l *localLibrary) Files() []string {
+	root := ""
+	if GlobalConfig != nil {
+		root = GlobalConfig.MusicDir
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if root == "" {
+		l.files = nil
+		return nil
+	}
+	if l.root == root && time.Since(l.scanned) < LocalLibraryRescan {
+		return l.files
+	}
 
-		s.downloadAndCache(ctx, t, filename, t.URL)
+	var files []string
+	_ = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !localAudioExts[strings.ToLower(filepath.Ext(p))] {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, p); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	slices.Sort(files)
+	l.root, l.files, l.scanned = root, files, time.Now()
+	LogVoice("Indexed %d local tracks in %s", len(files), root)
+	return files
+}
+
+// Search matches every word of q against files and folders; folders end in "/"
+func (l *localLibrary) Search(q string, limit int) []string {
+	words := strings.Fields(strings.ToLower(q))
+	matches := func(e string) bool {
+		e = strings.ToLower(e)
+		for _, w := range words {
+			if !strings.Contains(e, w) {
+				return false
+			}
+		}
+		return true
+	}
 
-		safeGo(func() {
-			t.mu.Lock()
-			title, ch, d := t.Title, t.Channel, t.Duration
-			t.mu.Unlock()
-			if title != "" {
-				writeMetadataCache(videoID, title, ch, d)
+	var out []string
+	seen := make(map[string]bool)
+	for _, f := range l.Files() {
+		for dir := path.Dir(f); dir != "." && !seen[dir] && len(out) < limit; dir = path.Dir(dir) {
+			seen[dir] = true
+			if matches(dir + "/") {
+				out = append(out, dir+"/")
 			}
-		})
-		return
+		}
+		if len(out) < limit && matches(f) {
+			out = append(out, f)
+		}
+		if len(out) == limit {
+			break
+		}
 	}
+	return out
+}
 
-	meta, err := ytdlpExtractMetadata(ctx, t.URL)
-	if err != nil {
-		t.MarkError(err)
-		return
+// resolveLocalPath maps a library-relative path onto disk, refusing anything that escapes MUSIC_DIR
+func resolveLocalPath(rel string) (string, bool) {
+	if GlobalConfig == nil || GlobalConfig.MusicDir == "" {
+		return "", false
 	}
+	root := GlobalConfig.MusicDir
+	p := filepath.Join(root, filepath.FromSlash(rel))
+	if r, err := filepath.Rel(root, p); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if _, err := os.Stat(p); err != nil {
+		return "", false
+	}
+	return p, true
+}
 
-	t.Title, t.Channel, t.Duration = meta.Title, meta.Uploader, meta.Duration
-	s.updateNextTrackStatusIfNeeded(t)
+// resolveLocalQuery turns the /voice play local option into a "local:" reference, taking the best search hit for free text
+func resolveLocalQuery(q string) (string, error) {
+	if GlobalConfig == nil || GlobalConfig.MusicDir == "" {
+		return "", errors.New("no local music library is configured")
+	}
+	q = strings.TrimSpace(q)
+	if _, ok := resolveLocalPath(strings.TrimSuffix(q, "/")); ok {
+		return LocalTrackPrefix + q, nil
+	}
+	if hits := musicLibrary.Search(q, 1); len(hits) > 0 {
+		return LocalTrackPrefix + hits[0], nil
+	}
+	return "", fmt.Errorf("nothing in the local library matches **%s**", q)
+}
 
-	select {
-	case <-t.MetadataReady:
-	default:
-		t.SafeCloseMetadata()
+// localFolderTracks expands a library folder into its tracks; files return nil so they queue as a single track
+func localFolderTracks(rel string) []*Track {
+	dir := strings.TrimSuffix(rel, "/")
+	if p, ok := resolveLocalPath(dir); !ok {
+		return nil
+	} else if st, err := os.Stat(p); err != nil || !st.IsDir() {
+		return nil
 	}
 
-	if meta.ID != "" {
-		if strings.Contains(t.URL, "music.youtube.com") {
-			t.URL = "https://music.youtube.com/watch?v=" + meta.ID
-		} else {
-			t.URL = "https://www.youtube.com/watch?v=" + meta.ID
+	var tracks []*Track
+	for _, f := range musicLibrary.Files() {
+		if dir == "" || strings.HasPrefix(f, dir+"/") {
+			tracks = append(tracks, NewTrack(LocalTrackPrefix+f))
+		}
+		if len(tracks) >= PlaylistMaxTracks {
+			break
 		}
 	}
+	return tracks
+}
 
-	if _, err := os.Stat(meta.Filename); err == nil {
-		t.MarkReady(meta.Filename, meta.Title, meta.Uploader, meta.Duration, nil)
-		return
+// truncateLeft keeps the end of s, which is the informative part of a path, within n runes
+func truncateLeft(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
 	}
+	return "…" + string(r[len(r)-n+1:])
+}
 
-	s.downloadAndCache(ctx, t, meta.Filename, t.URL)
+// discordAttachmentName reports whether u is a Discord attachment and returns its file name
+func discordAttachmentName(u string) (string, bool) {
+	parsed, err := url.Parse(u)
+	if err != nil || !strings.HasPrefix(parsed.Path, "/attachments/") {
+		return "", false
+	}
+	switch strings.ToLower(parsed.Hostname()) {
+	case "cdn.discordapp.com", "media.discordapp.net":
+		return path.Base(parsed.Path), true
+	}
+	return "", false
 }
 
-func (s *VoiceSession) downloadAndCache(ctx context.Context, t *Track, filename, url string) {
-	downloadDone := make(chan struct{})
-	writeSig := make(chan struct{}, 1)
-	readySig := make(chan struct{})
-	errorSig := make(chan error, 1)
-	onceReady := sync.Once{}
-	onceError := sync.Once{}
+// isDiscordAttachment reports whether u is a Discord attachment link, which stops working once its signature expires
+func isDiscordAttachment(u string) bool {
+	_, ok := discordAttachmentName(u)
+	return ok
+}
 
-	t.mu.Lock()
-	t.FileCreated = make(chan struct{})
-	t.mu.Unlock()
+// probeAudioFile reads duration and tags straight from the container; libav handles both paths and URLs
+func probeAudioFile(in string) (title, artist string, d time.Duration, err error) {
+	fc := astiav.AllocFormatContext()
+	if fc == nil {
+		return "", "", 0, errors.New("failed to alloc ctx")
+	}
+	defer fc.Free()
+	if err := fc.OpenInput(in, nil, nil); err != nil {
+		return "", "", 0, err
+	}
+	defer fc.CloseInput()
+	if err := fc.FindStreamInfo(nil); err != nil {
+		return "", "", 0, err
+	}
+	if md := fc.Metadata(); md != nil {
+		if e := md.Get("title", nil, 0); e != nil {
+			title = e.Value()
+		}
+		if e := md.Get("artist", nil, 0); e != nil {
+			artist = e.Value()
+		}
+	}
+	if us := fc.Duration(); us > 0 {
+		d = time.Duration(us) * time.Microsecond
+	}
+	return title, artist, d, nil
+}
 
-	ctx, dcancel := context.WithCancel(ctx)
+// processLocalTrack readies a library file or attachment for the transcoder, which opens it directly
+func (s *VoiceSession) processLocalTrack(t *Track) {
+	if t.Path == "" {
+		t.MarkError(errors.New("local file is not available"))
+		return
+	}
+	title, artist, d, err := probeAudioFile(t.Path)
+	if err != nil {
+		t.MarkError(fmt.Errorf("unreadable audio file: %w", err))
+		return
+	}
 	t.mu.Lock()
-	t.downloadCancel = dcancel
+	if title != "" {
+		t.Title = title
+	}
+	if artist != "" {
+		t.Channel = artist
+	}
+	if d > 0 {
+		t.Duration = d
+	}
+	title, artist, d = t.Title, t.Channel, t.Duration
 	t.mu.Unlock()
+	t.MarkReady(t.Path, title, artist, d, nil)
+	s.updateNextTrackStatusIfNeeded(t)
+}
 
-	safeGo(func() {
-		defer close(downloadDone)
-		defer func() {
-			if r := recover(); r != nil {
-				LogVoice("CRITICAL: downloader safeGo panic recovered: %v", r)
-				onceError.Do(func() { errorSig <- fmt.Errorf("panic: %v", r) })
-			}
-		}()
-		ensureAudioCacheDir()
-		partFilename := filename + ".part"
+// ===========================
+// Lyrics
+// ===========================
 
-		t.mu.Lock()
-		ss := t.SeekOffset
-		t.mu.Unlock()
+// LyricLine is one line of lyrics; At is unused when the lyrics aren't synced
+type LyricLine struct {
+	At   time.Duration
+	Text string
+}
 
-		thresh := int64(16 * 1024) // 16KB initial buffer for transcoding
-		cacheFile, err := os.Create(partFilename)
+type Lyrics struct {
+	Lines  []LyricLine
+	Synced bool
+	Source string
+}
 
-		t.mu.Lock()
-		if t.FileCreated != nil {
-			close(t.FileCreated)
-		}
-		t.mu.Unlock()
+// LyricsQuery is what a provider gets to go on; Path is empty for tracks that aren't on disk yet
+type LyricsQuery struct {
+	Title, Artist, Path string
+	Local               bool
+}
 
-		if err != nil {
-			LogVoice("Failed to create cache file: %v", err)
-			return
-		}
+// LyricsProvider finds lyrics for a track; nil with no error means it has none
+type LyricsProvider interface {
+	Name() string
+	Lyrics(q LyricsQuery) (*Lyrics, error)
+}
 
-		sw := &TrackSignalWriter{
-			w: cacheFile,
-			onWrite: func(n int) {
-				t.mu.Lock()
-				t.WrittenBytes += int64(n)
-				wb := t.WrittenBytes
-				t.mu.Unlock()
-				if wb >= thresh {
-					onceReady.Do(func() { close(readySig) })
-				}
-				select {
-				case writeSig <- struct{}{}:
-				default:
-				}
-			},
-		}
+// LyricsProviders are consulted in order; the first hit wins
+var LyricsProviders = []LyricsProvider{
+	SidecarLyricsProvider{},
+	LRCDirectoryProvider{},
+	EmbeddedLyricsProvider{},
+}
 
-		t.mu.Lock()
-		t.downloadCancel = dcancel
-		t.mu.Unlock()
+const (
+	lyricsUnfetched = iota
+	lyricsFetching
+	lyricsFetched
+)
 
-		_, err = ytdlpStream(ctx, url, ss, sw)
-		if err != nil {
-			onceError.Do(func() { errorSig <- err })
-		}
-		cacheFile.Close()
+var (
+	lrcTimestampRegex = regexp.MustCompile(`\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	lrcOffsetRegex    = regexp.MustCompile(`(?i)^\[offset:\s*([+-]?\d+)\]`)
+	lrcTagRegex       = regexp.MustCompile(`^\[[a-zA-Z]+:.*\]$`)
+)
 
-		if err != nil {
-			LogVoice("Stream/Cache failed for %s: %v", url, err)
-			os.Remove(partFilename)
-			return
+// ParseLRC reads LRC text; lines without timestamps make the result unsynced plain lyrics
+func ParseLRC(text string) *Lyrics {
+	l := &Lyrics{}
+	var offset time.Duration
+	var plain []LyricLine
+	for raw := range strings.Lines(text) {
+		line := strings.TrimSpace(raw)
+		if m := lrcOffsetRegex.FindStringSubmatch(line); m != nil {
+			ms, _ := strconv.Atoi(m[1])
+			offset = time.Duration(ms) * time.Millisecond
+			continue
 		}
+		stamps := lrcTimestampRegex.FindAllStringSubmatchIndex(line, -1)
+		if len(stamps) == 0 || stamps[0][0] != 0 {
+			if line != "" && !lrcTagRegex.MatchString(line) {
+				plain = append(plain, LyricLine{Text: line})
+			}
+			continue
+		}
+		end := 0
+		var ats []time.Duration
+		for _, st := range stamps {
+			if st[0] != end {
+				break
+			}
+			end = st[1]
+			mins, _ := strconv.Atoi(line[st[2]:st[3]])
+			secs, _ := strconv.Atoi(line[st[4]:st[5]])
+			at := time.Duration(mins)*time.Minute + time.Duration(secs)*time.Second
+			if st[6] >= 0 {
+				frac := line[st[6]:st[7]]
+				n, _ := strconv.Atoi(frac)
+				for range 3 - len(frac) {
+					n *= 10
+				}
+				at += time.Duration(n) * time.Millisecond
+			}
+			ats = append(ats, at)
+		}
+		text := strings.TrimSpace(line[end:])
+		for _, at := range ats {
+			l.Lines = append(l.Lines, LyricLine{At: at, Text: text})
+		}
+	}
+	if len(l.Lines) == 0 {
+		if len(plain) == 0 {
+			return nil
+		}
+		l.Lines = plain
+		return l
+	}
+	// The LRC offset tag is positive when lyrics should show earlier
+	for i := range l.Lines {
+		l.Lines[i].At = max(0, l.Lines[i].At-offset)
+	}
+	slices.SortStableFunc(l.Lines, func(a, b LyricLine) int { return int(a.At - b.At) })
+	l.Synced = true
+	return l
+}
 
-		onceReady.Do(func() { close(readySig) })
-
-		t.mu.Lock()
-		finalWb := t.WrittenBytes
-		t.mu.Unlock()
+// LineAt returns the index of the line being sung at pos, or -1 before the first line
+func (l *Lyrics) LineAt(pos time.Duration) int {
+	return sort.Search(len(l.Lines), func(i int) bool { return l.Lines[i].At > pos }) - 1
+}
 
-		if finalWb < MinTrackSize {
-			onceError.Do(func() { errorSig <- fmt.Errorf("download too small: %d bytes (min %d)", finalWb, MinTrackSize) })
-			LogVoice("Download too small for %s: %d bytes. Deleting.", url, finalWb)
-			os.Remove(partFilename)
-			return
+func readLRCFile(path string) (*Lyrics, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
 		}
+		return nil, err
+	}
+	return ParseLRC(string(b)), nil
+}
 
-		if err := os.Rename(partFilename, filename); err != nil {
-			LogVoice("Failed to rename cache file for %s: %v", url, err)
-			os.Remove(partFilename)
-		} else {
-			t.mu.Lock()
-			wb := t.WrittenBytes
-			t.mu.Unlock()
-			LogVoice("Downloaded track file: %s (Size: %d bytes)", filename, wb)
-		}
-	})
+// SidecarLyricsProvider reads song.lrc next to song.flac in the local library
+type SidecarLyricsProvider struct{}
 
-	totalTimer := time.NewTimer(90 * time.Second)
-	defer totalTimer.Stop()
+func (SidecarLyricsProvider) Name() string { return "LRC file" }
 
-	stallTimer := time.NewTimer(30 * time.Second)
-	defer stallTimer.Stop()
+func (SidecarLyricsProvider) Lyrics(q LyricsQuery) (*Lyrics, error) {
+	if !q.Local || q.Path == "" || strings.HasPrefix(q.Path, "http") {
+		return nil, nil
+	}
+	return readLRCFile(strings.TrimSuffix(q.Path, filepath.Ext(q.Path)) + ".lrc")
+}
 
-loop:
-	for {
-		select {
-		case <-readySig:
-			break loop
-		case <-ctx.Done():
-			dcancel()
-			t.MarkError(ctx.Err())
-			return
-		case <-totalTimer.C:
-			dcancel()
-			t.MarkError(errors.New("timeout: download too slow (max total time exceeded)"))
-			return
-		case <-stallTimer.C:
-			dcancel()
-			t.MarkError(errors.New("timeout: download stalled or failed to start"))
-			return
-		case err := <-errorSig:
-			dcancel()
-			t.MarkError(err)
-			return
-		case <-downloadDone:
-			dcancel()
-			t.MarkError(errors.New("timeout: download process exited unexpectedly without data"))
-			return
-		case <-writeSig:
-			if !stallTimer.Stop() {
-				select {
-				case <-stallTimer.C:
-				default:
-				}
-			}
-			stallTimer.Reset(maxStall)
-		}
+// LRCDirectoryProvider matches "Artist - Title.lrc" or "Title.lrc" in LYRICS_DIR, ignoring case and punctuation
+type LRCDirectoryProvider struct{}
+
+func (LRCDirectoryProvider) Name() string { return "lyrics library" }
+
+func (LRCDirectoryProvider) Lyrics(q LyricsQuery) (*Lyrics, error) {
+	if GlobalConfig == nil || GlobalConfig.LyricsDir == "" || q.Title == "" {
+		return nil, nil
+	}
+	title := metadataBlockRegex.ReplaceAllString(q.Title, "")
+	wanted := []string{normalizeLyricsKey(title)}
+	if q.Artist != "" {
+		wanted = append([]string{normalizeLyricsKey(q.Artist + " " + title)}, wanted...)
 	}
 
-	partFilename := filename + ".part"
-	readFile, err := os.Open(partFilename)
+	entries, err := os.ReadDir(GlobalConfig.LyricsDir)
 	if err != nil {
-		select {
-		case <-time.After(50 * time.Millisecond):
-		case <-ctx.Done():
-			t.MarkError(ctx.Err())
-			return
+		return nil, err
+	}
+	for _, want := range wanted {
+		if want == "" {
+			continue
 		}
-		readFile, err = os.Open(partFilename)
-		if err != nil {
-			t.MarkError(fmt.Errorf("failed to open cache file for tailing: %w", err))
-			return
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || !strings.EqualFold(filepath.Ext(name), ".lrc") {
+				continue
+			}
+			if normalizeLyricsKey(strings.TrimSuffix(name, filepath.Ext(name))) == want {
+				return readLRCFile(filepath.Join(GlobalConfig.LyricsDir, name))
+			}
 		}
 	}
+	return nil, nil
+}
 
-	tr := &TailingReader{
-		f:    readFile,
-		done: downloadDone,
-		ctx:  ctx,
-		sig:  writeSig,
+func normalizeLyricsKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
 	}
-
-	t.MarkReady(filename, t.Title, t.Channel, t.Duration, tr)
+	return b.String()
 }
 
-func (s *VoiceSession) addToHistory(url, title, author string) {
-	s.lockQueue()
-	defer s.unlockQueue()
-	if title == "" {
-		return
-	}
-	n := normalizeTitle(title, author)
-	tokens := tokenize(n)
+// EmbeddedLyricsProvider reads LYRICS / USLT tags from the audio file, synced or not
+type EmbeddedLyricsProvider struct{}
 
-	id := extractVideoID(url)
-	if id == "" {
-		return
-	}
-	n = normalizeTitle(title, author)
-	tokens = tokenize(n)
+func (EmbeddedLyricsProvider) Name() string { return "embedded tags" }
 
-	if !slices.Contains(s.History, id) {
-		s.History = append(s.History, id)
-		if len(s.History) > 50 {
-			s.History = s.History[1:]
-		}
+func (EmbeddedLyricsProvider) Lyrics(q LyricsQuery) (*Lyrics, error) {
+	if q.Path == "" {
+		return nil, nil
 	}
-	if n != "" {
-		if !s.checkSimilarity(tokens) {
-			s.HistoryTitles = append(s.HistoryTitles, n)
-			s.HistoryAuthors = append(s.HistoryAuthors, author)
-
-			uniqueTokens := make([]string, 0, len(tokens))
-			seen := make(map[string]bool)
-			for _, t := range tokens {
-				if !seen[t] {
-					seen[t] = true
-					uniqueTokens = append(uniqueTokens, t)
-				}
-			}
-			s.HistoryTokens = append(s.HistoryTokens, uniqueTokens)
-			s.updateIDF(uniqueTokens, true)
+	fc := astiav.AllocFormatContext()
+	if fc == nil {
+		return nil, errors.New("failed to alloc ctx")
+	}
+	defer fc.Free()
+	if err := fc.OpenInput(q.Path, nil, nil); err != nil {
+		return nil, err
+	}
+	defer fc.CloseInput()
 
-			if len(s.HistoryTitles) > 50 {
-				s.HistoryTitles = s.HistoryTitles[1:]
-				s.HistoryAuthors = s.HistoryAuthors[1:]
-				oldTokens := s.HistoryTokens[0]
-				s.HistoryTokens = s.HistoryTokens[1:]
-				s.updateIDF(oldTokens, false)
+	// Prefix match, since ffmpeg exposes USLT frames as "lyrics-<lang>"
+	flags := astiav.NewDictionaryFlags(astiav.DictionaryFlagIgnoreSuffix)
+	dicts := []*astiav.Dictionary{fc.Metadata()}
+	for _, st := range fc.Streams() {
+		dicts = append(dicts, st.Metadata())
+	}
+	for _, md := range dicts {
+		if md == nil {
+			continue
+		}
+		for _, key := range []string{"lyrics", "unsyncedlyrics"} {
+			if e := md.Get(key, nil, flags); e != nil && strings.TrimSpace(e.Value()) != "" {
+				return ParseLRC(e.Value()), nil
 			}
 		}
 	}
+	return nil, nil
 }
 
-func tokenize(text string) []string {
-	return strings.Fields(strings.ToLower(text))
-}
+// loadLyrics asks each provider in turn and refreshes the panels once the track has an answer
+func (s *VoiceSession) loadLyrics(t *Track) {
+	t.mu.Lock()
+	q := LyricsQuery{Title: t.Title, Artist: t.Channel, Path: t.Path, Local: t.Local}
+	t.mu.Unlock()
 
-func (s *VoiceSession) updateIDF(tokens []string, add bool) {
-	for _, t := range tokens {
-		if add {
-			s.IDFStats[t]++
-		} else {
-			s.IDFStats[t]--
-			if s.IDFStats[t] <= 0 {
-				delete(s.IDFStats, t)
-			}
+	var found *Lyrics
+	for _, p := range LyricsProviders {
+		l, err := p.Lyrics(q)
+		if err != nil {
+			LogVoice("Lyrics provider %s failed for %s: %v", p.Name(), q.Title, err)
+			continue
+		}
+		if l != nil && len(l.Lines) > 0 {
+			l.Source = p.Name()
+			found = l
+			break
 		}
 	}
+
+	t.mu.Lock()
+	t.lyrics, t.lyricsState = found, lyricsFetched
+	t.mu.Unlock()
+	UpdateVoicePanels(s.GuildID, s.GetClient())
 }
 
-func (s *VoiceSession) checkSimilarity(candidateTokens []string) bool {
-	if len(s.HistoryTokens) == 0 {
-		return false
+// lyricsLocked returns the current track's lyrics and playback position, starting a lookup the first time; the caller holds the queue lock
+func (s *VoiceSession) lyricsLocked() (*Lyrics, time.Duration, bool) {
+	t := s.currentTrack
+	if t == nil {
+		return nil, 0, false
 	}
-
-	cMap := make(map[string]bool)
-	for _, t := range candidateTokens {
-		cMap[t] = true
+	var pos time.Duration
+	if s.transcoder != nil {
+		pos = time.Duration(s.transcoder.GetTimestamp()/48) * time.Millisecond
 	}
 
-	N := float64(len(s.HistoryTitles) + 1)
+	t.mu.Lock()
+	state, l, ready := t.lyricsState, t.lyrics, t.Downloaded && !t.Live
+	if state == lyricsUnfetched && ready {
+		t.lyricsState = lyricsFetching
+	}
+	t.mu.Unlock()
 
-	for _, hTokens := range s.HistoryTokens {
-		iScore, uScore := 0.0, 0.0
+	if state == lyricsUnfetched && ready {
+		safeGo(func() { s.loadLyrics(t) })
+	}
+	return l, pos, state == lyricsFetched
+}
 
-		for t := range cMap {
-			df := s.IDFStats[t] + 1
-			wt := math.Log(1.0 + N/float64(df))
-			uScore += wt
-		}
+func (s *VoiceSession) lyricsTextLocked() string {
+	l, pos, fetched := s.lyricsLocked()
+	if !fetched {
+		return "🎤 _Looking for lyrics..._"
+	}
+	if l == nil {
+		return "🎤 _No lyrics found for this track._"
+	}
 
-		for _, t := range hTokens {
-			if !cMap[t] {
-				df := s.IDFStats[t]
-				if cMap[t] {
-					df++
-				}
-				wt := math.Log(1.0 + N/float64(df))
-				uScore += wt
-			} else {
-				df := s.IDFStats[t] + 1
-				wt := math.Log(1.0 + N/float64(df))
-				iScore += wt
+	var b strings.Builder
+	if !l.Synced {
+		b.WriteString(fmt.Sprintf("🎤 **Lyrics** (%s, not synced)\n", l.Source))
+		for _, line := range l.Lines {
+			if b.Len()+len(line.Text) > 1500 {
+				b.WriteString("…")
+				break
 			}
+			b.WriteString(line.Text + "\n")
 		}
+		return b.String()
+	}
 
-		if uScore > 0 && (iScore/uScore) >= 0.7 {
-			return true
+	cur := l.LineAt(pos)
+	b.WriteString(fmt.Sprintf("🎤 **Lyrics** (%s)\n", l.Source))
+	for i := max(0, cur-LyricsContextBefore); i <= min(len(l.Lines)-1, max(cur, 0)+LyricsContextAfter); i++ {
+		text := l.Lines[i].Text
+		if text == "" {
+			text = "♪"
+		}
+		if i == cur {
+			b.WriteString("▶ **" + text + "**\n")
+		} else {
+			b.WriteString("-# " + text + "\n")
 		}
 	}
-	return false
+	return b.String()
 }
 
-func readMetadataCache(videoID string) *CachedMetadata {
-	f, err := os.ReadFile(filepath.Join(AudioCacheDir, videoID+".meta"))
-	if err != nil {
-		return nil
-	}
-	var cm CachedMetadata
-	if json.Unmarshal(f, &cm) != nil {
-		return nil
+// lyricsKey identifies what a lyrics panel currently shows so the ticker only edits panels when the line moves
+func (s *VoiceSession) lyricsKey() string {
+	s.lockQueue()
+	defer s.unlockQueue()
+	l, pos, fetched := s.lyricsLocked()
+	line := -1
+	if l != nil && l.Synced {
+		line = l.LineAt(pos)
 	}
-	return &cm
+	return fmt.Sprintf("%p:%t:%d:%t", s.currentTrack, fetched, line, s.IsPaused())
 }
 
-func writeMetadataCache(videoID, title, channel string, d time.Duration) {
-	ensureAudioCacheDir()
-	cm := CachedMetadata{Title: title, Channel: channel, Duration: d}
-	b, _ := json.Marshal(cm)
-	_ = os.WriteFile(filepath.Join(AudioCacheDir, videoID+".meta"), b, 0644)
-}
+// runLyricsTicker moves the highlighted line on panels in lyrics mode as playback advances
+func runLyricsTicker() {
+	ticker := time.NewTicker(LyricsRefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		VoicePanelsMu.Lock()
+		var panels []*VoicePanel
+		for _, p := range VoicePanels {
+			if p.Lyrics && now.Before(p.ExpiresAt) {
+				panels = append(panels, p)
+			}
+		}
+		VoicePanelsMu.Unlock()
 
-func (s *VoiceSession) fetchRelated(url, title, artist string) (string, error) {
-	id := extractVideoID(url)
-	if id == "" {
-		return "", errors.New("id")
+		guilds := make(map[snowflake.ID]bool)
+		for _, p := range panels {
+			if guilds[p.GuildID] {
+				continue
+			}
+			s := GetVoiceManager().GetSession(p.GuildID)
+			if s == nil {
+				continue
+			}
+			key := s.lyricsKey()
+			VoicePanelsMu.Lock()
+			changed := p.lyricsKey != key
+			VoicePanelsMu.Unlock()
+			if changed {
+				guilds[p.GuildID] = true
+				UpdateVoicePanels(p.GuildID, s.GetClient())
+			}
+		}
 	}
+}
 
-	ch := make(chan recResult, 2)
-	safeGo(func() {
-		es, _ := ytdlpExtractPlaylist(s.cancelCtx, "https://music.youtube.com/watch?v="+id+"&list=RDAMVM"+id, 20)
-		ch <- recResult{es, 0}
-	})
-	safeGo(func() {
-		es, _ := ytdlpExtractPlaylist(s.cancelCtx, "https://www.youtube.com/watch?v="+id+"&list=RD"+id, 20)
-		ch <- recResult{es, 1}
-	})
+// ===========================
+// Live Streams
+// ===========================
 
-	var es []ytdlpPlaylistEntry
-	resList := make([][]ytdlpPlaylistEntry, 2)
-	for range 2 {
-		r := <-ch
-		resList[r.prio] = r.es
-	}
-	es = append(resList[0], resList[1]...)
+// LiveStreamInfo is what probing a URL found: the stream itself after following any .pls/.m3u wrapper, and the
+// station name the server announces
+type LiveStreamInfo struct {
+	URL  string
+	Name string
+	HLS  bool
+}
+
+// liveStreamClient talks to Icecast and SHOUTcast servers; it has no overall timeout since the body never ends
+var liveStreamClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			c, err := (&net.Dialer{Timeout: LiveProbeTimeout}).DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &icyConn{Conn: c}, nil
+		},
+		ResponseHeaderTimeout: LiveProbeTimeout,
+	},
+}
 
-	if len(es) == 0 {
-		LogVoice("Autoplay: yt-dlp returned 0 results, trying native search fallback for '%s %s'", title, artist)
-		query := title
-		if artist != "" {
-			query += " " + artist
+// icyConn lets net/http read SHOUTcast v1 servers, which answer "ICY 200 OK" instead of an HTTP status line
+type icyConn struct {
+	net.Conn
+	checked bool
+	pending []byte
+}
+
+func (c *icyConn) Read(b []byte) (int, error) {
+	if !c.checked {
+		c.checked = true
+		buf := make([]byte, 4096)
+		n, err := c.Conn.Read(buf)
+		buf = buf[:n]
+		if bytes.HasPrefix(buf, []byte("ICY ")) {
+			buf = append([]byte("HTTP/1.0"), buf[3:]...)
 		}
-		c := ytsearch.NewClient(nil)
-		res, err := c.Search(s.cancelCtx, query)
-		if err == nil && len(res.Results) > 0 {
-			for _, r := range res.Results {
-				vid := r.VideoID
-				if vid != "" && vid != id {
-					es = append(es, ytdlpPlaylistEntry{
-						URL:      "https://www.youtube.com/watch?v=" + vid,
-						Title:    r.Title,
-						Uploader: r.Channel,
-					})
-				}
-			}
+		c.pending = buf
+		if n == 0 {
+			return 0, err
 		}
 	}
-
-	curID := extractVideoID(url)
-	curTitle := curID
-	if title != "" {
-		curTitle = title
+	if len(c.pending) > 0 {
+		n := copy(b, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
 	}
-	LogVoice("Autoplay: Found %d related tracks for %s", len(es), curTitle)
-
-	s.lockQueue()
-	count := min(len(s.HistoryTitles), 5)
-	historyTitles := make([]string, count)
-	copy(historyTitles, s.HistoryTitles[len(s.HistoryTitles)-count:])
-	idfCopy := make(map[string]int, len(s.IDFStats))
-	maps.Copy(idfCopy, s.IDFStats)
-
-	htTokens := make([][]string, len(s.HistoryTokens))
-	copy(htTokens, s.HistoryTokens)
-	s.unlockQueue()
+	return c.Conn.Read(b)
+}
 
-	for _, e := range es {
-		u := strings.TrimSpace(e.URL)
-		nid := ""
-		if strings.Contains(u, "watch?v=") {
-			nid = extractVideoID(u)
-		}
+// openLiveStream requests u with ICY metadata enabled; the caller closes the body
+func openLiveStream(ctx context.Context, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Icy-MetaData", "1")
+	resp, err := liveStreamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("stream answered %s", resp.Status)
+	}
+	return resp, nil
+}
 
-		nti, nup := strings.TrimSpace(e.Title), strings.TrimSpace(e.Uploader)
-		n := normalizeTitle(nti, nup)
-		tokens := tokenize(n)
+// probeLiveStream checks whether u is a live stream: an Icecast/SHOUTcast mount, an HLS playlist, or a .pls/.m3u
+// file pointing at one. It returns nil with no error for anything else
+func probeLiveStream(ctx context.Context, u string) (*LiveStreamInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, LiveProbeTimeout)
+	defer cancel()
 
-		if nid == "" || nid == curID {
+	// One hop is enough for the playlist files stations hand out
+	for range 2 {
+		resp, err := openLiveStream(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		ct := strings.ToLower(resp.Header.Get("Content-Type"))
+		info := &LiveStreamInfo{URL: u, Name: strings.TrimSpace(resp.Header.Get("icy-name"))}
+
+		if isLivePlaylist(ct, u) {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+			text := string(body)
+			if strings.Contains(text, "#EXT-X-") {
+				info.HLS = true
+				return info, nil
+			}
+			base, err := url.Parse(u)
+			if err != nil {
+				return nil, err
+			}
+			next, err := base.Parse(livePlaylistEntry(text))
+			if err != nil || next.String() == u || !strings.HasPrefix(next.Scheme, "http") {
+				return nil, nil
+			}
+			u = next.String()
 			continue
 		}
-		found := slices.Contains(s.History, nid)
-		if found {
-			continue
+		resp.Body.Close()
+
+		// A chunked audio response alone proves nothing; plenty of servers send ordinary files that way
+		if !isIcyResponse(resp) {
+			return nil, nil
 		}
+		return info, nil
+	}
+	return nil, nil
+}
 
-		if checkSimilarityAgainst(tokens, htTokens, idfCopy) {
-			found = true
+// isIcyResponse reports whether a server answered as an Icecast or SHOUTcast mount
+func isIcyResponse(resp *http.Response) bool {
+	for _, h := range []string{"icy-metaint", "icy-name", "icy-br", "ice-audio-info"} {
+		if resp.Header.Get(h) != "" {
+			return true
 		}
-		if found {
-			continue
+	}
+	return false
+}
+
+func isLivePlaylist(contentType, u string) bool {
+	if strings.Contains(contentType, "mpegurl") || strings.Contains(contentType, "scpls") {
+		return true
+	}
+	if parsed, err := url.Parse(u); err == nil {
+		switch strings.ToLower(path.Ext(parsed.Path)) {
+		case ".m3u", ".m3u8", ".pls":
+			return true
 		}
-		return u, nil
 	}
-	if len(es) > 1 {
-		LogVoice("Autoplay: Strict filtering failed, trying fallback... %s", curTitle)
-		for _, e := range es {
-			u := strings.TrimSpace(e.URL)
-			nid := ""
-			if strings.Contains(u, "watch?v=") {
-				nid = extractVideoID(u)
-			}
-			if nid != "" && nid != curID {
-				return u, nil
+	return false
+}
+
+// livePlaylistEntry returns the first stream a .pls or plain .m3u file lists
+func livePlaylistEntry(text string) string {
+	for line := range strings.Lines(text) {
+		line = strings.TrimSpace(line)
+		if k, v, ok := strings.Cut(line, "="); ok {
+			if strings.HasPrefix(strings.ToLower(k), "file") {
+				return strings.TrimSpace(v)
 			}
+			continue
+		}
+		if line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "[") {
+			return line
 		}
-	} else {
-		LogVoice("Autoplay: Not enough tracks for fallback (Count: %d)", len(es))
 	}
-	return "", errors.New("none")
+	return ""
 }
 
-func checkSimilarityAgainst(candidateTokens []string, historyTokens [][]string, idfStats map[string]int) bool {
-	if len(historyTokens) == 0 {
-		return false
+// parseStreamTitle pulls the title out of an ICY metadata block such as StreamTitle='Artist - Song';
+func parseStreamTitle(meta string) (string, bool) {
+	_, rest, ok := strings.Cut(meta, "StreamTitle='")
+	if !ok {
+		return "", false
 	}
-
-	cMap := make(map[string]bool)
-	for _, t := range candidateTokens {
-		cMap[t] = true
+	title, _, ok := strings.Cut(rest, "';")
+	if !ok {
+		title = strings.TrimRight(rest, "\x00';")
 	}
+	return strings.TrimSpace(title), true
+}
 
-	N := float64(len(historyTokens) + 1)
-
-	for _, hTokens := range historyTokens {
-		iScore, uScore := 0.0, 0.0
+// IcyReader reads an Icecast/SHOUTcast stream as one endless input: it strips the interleaved metadata, reports
+// title changes and reconnects when the server drops the connection. It connects on the first Read, so a station
+// waiting in the queue doesn't hold a connection open
+type IcyReader struct {
+	URL     string
+	OnTitle func(title string)
+	ctx     context.Context
+	cancel  context.CancelFunc
+	body    io.ReadCloser
+	metaint int
+	icy     bool
+	left    int
+	title   string
+	retries int
+}
 
-		for t := range cMap {
-			df := idfStats[t] + 1
-			wt := math.Log(1.0 + N/float64(df))
-			uScore += wt
-		}
+func NewIcyReader(ctx context.Context, u string, onTitle func(string)) *IcyReader {
+	ctx, cancel := context.WithCancel(ctx)
+	return &IcyReader{URL: u, OnTitle: onTitle, ctx: ctx, cancel: cancel}
+}
 
-		for _, t := range hTokens {
-			if !cMap[t] {
-				df := idfStats[t]
-				wt := math.Log(1.0 + N/float64(df))
-				uScore += wt
-			} else {
-				df := idfStats[t] + 1
-				wt := math.Log(1.0 + N/float64(df))
-				iScore += wt
+func (r *IcyReader) Read(p []byte) (int, error) {
+	for {
+		var err error
+		if r.body == nil {
+			err = r.connect()
+		}
+		if err == nil {
+			var n int
+			n, err = r.readAudio(p)
+			if n > 0 {
+				r.retries = 0
+				return n, nil
+			}
+			if err == nil {
+				continue
+			}
+			r.body.Close()
+			r.body = nil
+			// Only a station is expected to go on forever; anything else that ends cleanly has simply finished
+			if errors.Is(err, io.EOF) && !r.icy {
+				return 0, io.EOF
 			}
 		}
-
-		if uScore > 0 && (iScore/uScore) >= 0.7 {
-			return true
+		if r.ctx.Err() != nil {
+			return 0, io.EOF
+		}
+		r.retries++
+		if r.retries > LiveReconnectAttempts {
+			return 0, err
+		}
+		LogVoice("Live stream %s dropped (%v); reconnecting (%d/%d)", r.URL, err, r.retries, LiveReconnectAttempts)
+		select {
+		case <-time.After(time.Duration(r.retries) * time.Second):
+		case <-r.ctx.Done():
+			return 0, io.EOF
 		}
 	}
-	return false
 }
 
-// ===========================
-// yt-dlp
-// ===========================
+// Close hangs up; a Read in progress returns io.EOF
+func (r *IcyReader) Close() error {
+	r.cancel()
+	return nil
+}
 
-func newYtdlp() (*ytdlp.Command, func()) {
-	cmd := ytdlp.New().
-		Quiet().
-		NoWarnings()
+func (r *IcyReader) connect() error {
+	resp, err := openLiveStream(r.ctx, r.URL)
+	if err != nil {
+		return err
+	}
+	r.body = resp.Body
+	r.icy = isIcyResponse(resp)
+	r.metaint, _ = strconv.Atoi(resp.Header.Get("icy-metaint"))
+	r.left = r.metaint
+	return nil
+}
 
-	if proxy := os.Getenv("YOUTUBE_PROXY"); proxy != "" {
-		cmd.Proxy(proxy)
+// readAudio reads up to the next metadata block, consuming the block once it is reached
+func (r *IcyReader) readAudio(p []byte) (int, error) {
+	if r.metaint <= 0 {
+		return r.body.Read(p)
+	}
+	if r.left == 0 {
+		if err := r.readMetadata(); err != nil {
+			return 0, err
+		}
+		r.left = r.metaint
 	}
+	n, err := r.body.Read(p[:min(len(p), r.left)])
+	r.left -= n
+	return n, err
+}
 
-	return cmd, func() {}
+// readMetadata consumes one block: a length byte counting 16-byte units, then the padded metadata text
+func (r *IcyReader) readMetadata() error {
+	var size [1]byte
+	if _, err := io.ReadFull(r.body, size[:]); err != nil {
+		return err
+	}
+	if size[0] == 0 {
+		return nil
+	}
+	buf := make([]byte, int(size[0])*16)
+	if _, err := io.ReadFull(r.body, buf); err != nil {
+		return err
+	}
+	if title, ok := parseStreamTitle(string(buf)); ok && title != "" && title != r.title {
+		r.title = title
+		if r.OnTitle != nil {
+			r.OnTitle(title)
+		}
+	}
+	return nil
 }
 
-func buildYtdlpArgs() []string {
-	jsOnce.Do(func() {
-		for _, rt := range []string{"node", "deno", "quickjs"} {
-			if path, err := exec.LookPath(rt); err == nil {
-				cachedJSArgs = append(cachedJSArgs, "--js-runtimes", rt+":"+path)
-				break
+// processLiveTrack probes URLs no source claims and readies live streams to play straight from the server; it
+// reports whether it handled the track. Tracks already marked Live, such as bookmarked stations, fail instead of
+// falling through to yt-dlp when the probe does
+func (s *VoiceSession) processLiveTrack(ctx context.Context, t *Track) bool {
+	t.mu.Lock()
+	u, local, live := t.URL, t.Local, t.Live
+	t.mu.Unlock()
+	if local || !strings.HasPrefix(u, "http") || sourceForURL(u) != nil {
+		return false
+	}
+	// Probing costs a request of up to LiveProbeTimeout, so it's saved for URLs yt-dlp wouldn't know what to do with
+	if !live && (isLikelyMusicStreamingSite(u) || isYtdlpSite(u)) {
+		return false
+	}
+	info, err := probeLiveStream(ctx, u)
+	if info == nil {
+		if live {
+			if err == nil {
+				err = errors.New("not a live stream")
 			}
+			t.MarkError(err)
+			return true
 		}
-	})
+		return false
+	}
 
-	args := append([]string(nil), cachedJSArgs...)
-	args = append(args,
-		"--no-playlist",
-		"--no-check-certificates",
-		"--no-warnings",
-		"--extractor-args", "youtube:player_client=android,web",
-		"--prefer-free-formats",
-		"--socket-timeout", "15",
-		"--retries", "10",
-		"--fragment-retries", "10",
-		"--no-cache-dir",
-	)
+	host := u
+	if parsed, err := url.Parse(u); err == nil {
+		host = parsed.Host
+	}
+	t.mu.Lock()
+	t.Live = true
+	t.Station = cmp.Or(t.Title, info.Name, host)
+	station := t.Station
+	t.mu.Unlock()
 
-	if _, err := os.Stat("cookies.txt"); err == nil {
-		args = append(args, "--cookies", "cookies.txt")
-	} else if c := os.Getenv("YOUTUBE_COOKIES"); c != "" {
-		if _, err := os.Stat(c); err == nil {
-			args = append(args, "--cookies", c)
-		}
+	var reader io.Reader
+	if !info.HLS {
+		reader = NewIcyReader(ctx, info.URL, func(title string) {
+			safeGo(func() { s.setLiveTitle(t, title) })
+		})
 	}
+	LogVoice("Live stream detected: %s (%s)", station, info.URL)
+	t.MarkReady(info.URL, station, "", 0, reader)
+	t.SafeCloseMetadata()
+	s.updateNextTrackStatusIfNeeded(t)
+	return true
+}
 
-	return args
+// setLiveTitle shows what a live stream says is on air in the channel status and the panels
+func (s *VoiceSession) setLiveTitle(t *Track, title string) {
+	t.mu.Lock()
+	if t.Title == title {
+		t.mu.Unlock()
+		return
+	}
+	t.Title, t.Channel = title, t.Station
+	station := t.Station
+	t.mu.Unlock()
+
+	LogVoice("Now on %s: %s", station, title)
+	s.lockQueue()
+	current := s.currentTrack == t
+	s.unlockQueue()
+	if current {
+		s.RefreshStatus()
+		UpdateVoicePanels(s.GuildID, s.GetClient())
+	}
 }
 
-func ytdlpSearch(ctx context.Context, q string, m int) ([]ytdlpSearchResult, error) {
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
+func (t *Track) IsLive() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Live
+}
 
-	args := buildYtdlpArgs()
-	res, err := cmd.
-		FlatPlaylist().
-		Print("%(url)s\t%(title)s\t%(uploader)s\t%(duration)s").
-		PlaylistItems(fmt.Sprintf("1-%d", m)).
-		NoWarnings().
-		IgnoreConfig().
-		PreferFreeFormats().
-		Run(ctx, append(args, "ytsearch"+fmt.Sprintf("%d", m)+":"+q)...)
+func handleVoiceStation(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	if event.GuildID() == nil {
+		_ = RespondInteractionV2(*event.Client(), event, "Not in a guild.", true)
+		return
+	}
+	switch *data.SubCommandName {
+	case "add":
+		handleStationAdd(event, data)
+	case "remove":
+		handleStationRemove(event, data)
+	case "list":
+		handleStationList(event)
+	case "play":
+		handleStationPlay(event, data)
+	}
+}
+
+func canManageStation(event *events.ApplicationCommandInteractionCreate, st *VoiceStation) bool {
+	if st.AddedBy == event.User().ID {
+		return true
+	}
+	m := event.Member()
+	return m != nil && m.Permissions.Has(discord.PermissionManageGuild)
+}
 
+func handleStationAdd(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	guildID := *event.GuildID()
+	name := strings.TrimSpace(data.String("name"))
+	streamURL := strings.TrimSpace(data.String("url"))
+	if name == "" {
+		_ = RespondInteractionV2(*event.Client(), event, "Station name can't be empty.", true)
+		return
+	}
+	if !strings.HasPrefix(streamURL, "http") {
+		_ = RespondInteractionV2(*event.Client(), event, "Give the stream's http(s) URL.", true)
+		return
+	}
+
+	ctx := context.Background()
+	existing, err := GetVoiceStation(ctx, guildID, name)
 	if err != nil {
-		return nil, err
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to save station: %v", err), true)
+		return
 	}
-	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
-	rs := make([]ytdlpSearchResult, 0, len(ls))
-	for _, l := range ls {
-		ps := strings.Split(l, "\t")
-		if len(ps) < 4 {
-			continue
-		}
-		d, _ := time.ParseDuration(ps[3] + "s")
-		u := ps[0]
-		if extractVideoID(u) != "" {
-			rs = append(rs, ytdlpSearchResult{u, ps[1], ps[2], d})
+	if existing != nil && !canManageStation(event, existing) {
+		_ = RespondInteractionV2(*event.Client(), event, "Only whoever added that station or a server manager can change it.", true)
+		return
+	}
+	if existing == nil {
+		if all, err := GetVoiceStations(ctx, guildID); err == nil && len(all) >= StationMaxPerGuild {
+			_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("There are already %d stations; remove one first.", len(all)), true)
+			return
 		}
 	}
-	return rs, nil
+
+	_ = event.DeferCreateMessage(false)
+	info, err := probeLiveStream(ctx, streamURL)
+	if err != nil {
+		_ = EditInteractionV2(*event.Client(), event, fmt.Sprintf("Couldn't reach that stream: %v", err))
+		return
+	}
+	if info == nil {
+		_ = EditInteractionV2(*event.Client(), event, "That URL isn't a live stream.")
+		return
+	}
+	if err := SaveVoiceStation(ctx, guildID, event.User().ID, name, streamURL); err != nil {
+		_ = EditInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to save station: %v", err))
+		return
+	}
+	LogVoice("User %s (%s) saved station %q (%s)", event.User().Username, event.User().ID, name, streamURL)
+	kind := "Icecast/SHOUTcast"
+	if info.HLS {
+		kind = "HLS"
+	}
+	msg := fmt.Sprintf("📻 Saved station **%s** (%s", name, kind)
+	if info.Name != "" {
+		msg += " · " + info.Name
+	}
+	_ = EditInteractionV2(*event.Client(), event, msg+").")
 }
-func ytdlpSearchYTM(ctx context.Context, q string, m int) ([]ytdlpSearchResult, error) {
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
 
-	args := buildYtdlpArgs()
-	res, err := cmd.
-		FlatPlaylist().
-		Print("%(url)s\t%(title)s\t%(uploader)s\t%(duration)s").
-		PlaylistItems(fmt.Sprintf("1-%d", m)).
-		NoWarnings().
-		IgnoreConfig().
-		Run(ctx, append(args, fmt.Sprintf("ytmsearch%d:%s", m, q))...)
+func handleStationRemove(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	guildID := *event.GuildID()
+	name := data.String("name")
+
+	ctx := context.Background()
+	st, err := GetVoiceStation(ctx, guildID, name)
+	if err != nil || st == nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("No station named **%s**.", name), true)
+		return
+	}
+	if !canManageStation(event, st) {
+		_ = RespondInteractionV2(*event.Client(), event, "Only whoever added that station or a server manager can remove it.", true)
+		return
+	}
+	if err := DeleteVoiceStation(ctx, guildID, st.Name); err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to remove station: %v", err), true)
+		return
+	}
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("🗑️ Removed station **%s**.", st.Name), false)
+}
 
+func handleStationList(event *events.ApplicationCommandInteractionCreate) {
+	stations, err := GetVoiceStations(context.Background(), *event.GuildID())
 	if err != nil {
-		return nil, err
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to load stations: %v", err), true)
+		return
 	}
-	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
-	rs := make([]ytdlpSearchResult, 0, len(ls))
-	for _, l := range ls {
-		ps := strings.Split(l, "\t")
-		if len(ps) < 4 {
-			continue
-		}
-		d, _ := time.ParseDuration(ps[3] + "s")
-		u := ps[0]
-		if extractVideoID(u) != "" {
-			rs = append(rs, ytdlpSearchResult{URL: u, Title: ps[1], Uploader: ps[2], Duration: d})
+
+	components := []any{NewTextDisplay("**📻 Stations:**")}
+	if len(stations) == 0 {
+		components = append(components, NewTextDisplay("_None saved yet. Use `/voice station add`._"))
+	} else {
+		var list strings.Builder
+		for _, st := range stations {
+			list.WriteString(fmt.Sprintf("• **%s** · <%s> · by <@%d>\n", st.Name, st.URL, st.AddedBy))
 		}
+		components = append(components, NewTextDisplay(list.String()))
 	}
-	return rs, nil
+	_ = RespondInteractionContainerV2(*event.Client(), event, NewV2Container(components...), true)
 }
 
-func ytdlpSearchPlaylist(ctx context.Context, q string, m int) ([]ytdlpSearchResult, error) {
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
+func handleStationPlay(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	vs, ok := mustGetUserVoiceState(event)
+	if !ok {
+		return
+	}
+	name := data.String("name")
+	st, err := GetVoiceStation(context.Background(), *event.GuildID(), name)
+	if err != nil || st == nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("No station named **%s**.", name), true)
+		return
+	}
+	_, mode, pos, _, _ := parsePlayArguments(data)
+	if !mustAllowQueueMode(event, mode) {
+		return
+	}
 
-	searchURL := fmt.Sprintf("https://www.youtube.com/results?search_query=%s&sp=EgIQAw%%253D%%253D", url.QueryEscape(q))
+	_ = event.DeferCreateMessage(false)
 
-	args := buildYtdlpArgs()
-	res, err := cmd.
-		FlatPlaylist().
-		Print("%(url)s\t%(title)s\t%(uploader)s").
-		PlaylistItems(fmt.Sprintf("1-%d", m)).
-		NoWarnings().
-		IgnoreConfig().
-		Run(ctx, append(args, searchURL)...)
+	t := NewTrack(st.URL)
+	t.Title, t.Live = st.Name, true
+	t.RequestedBy = event.User().ID
+
+	vm := GetVoiceManager()
+	vm.Prepare(*event.Client(), *event.GuildID(), *vs.ChannelID)
+	je := make(chan error, 1)
+	safeGo(func() { je <- vm.Join(context.Background(), *event.Client(), *event.GuildID(), *vs.ChannelID) })
+	if err := vm.Enqueue(*event.GuildID(), []*Track{t}, mode, pos); err != nil {
+		_ = EditInteractionV2(*event.Client(), event, "Failed: "+err.Error())
+		return
+	}
+	if err := <-je; err != nil {
+		_ = EditInteractionV2(*event.Client(), event, "Failed: "+err.Error())
+		return
+	}
+
+	LogVoice("User %s (%s) tuned in to station %q", event.User().Username, event.User().ID, st.Name)
+	prefix := "📻 Queued"
+	switch {
+	case mode == "now":
+		prefix = "▶️ Playing now"
+	case mode == "next":
+		prefix = "⏭️ Playing next"
+	case pos > 0:
+		prefix = fmt.Sprintf("📻 Queued at position %d", pos)
+	}
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	_ = EditInteractionV2(*event.Client(), event, fmt.Sprintf("%s: **%s** (live)", prefix, st.Name))
+}
 
+func autocompleteStationNames(event *events.AutocompleteInteractionCreate) {
+	if event.GuildID() == nil {
+		_ = event.AutocompleteResult(nil)
+		return
+	}
+	stations, err := GetVoiceStations(context.Background(), *event.GuildID())
 	if err != nil {
-		return nil, err
+		_ = event.AutocompleteResult(nil)
+		return
 	}
-	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
-	rs := make([]ytdlpSearchResult, 0, len(ls))
-	for _, l := range ls {
-		ps := strings.Split(l, "\t")
-		if len(ps) < 3 || ps[1] == "" || ps[1] == "NA" {
+	typed := strings.ToLower(event.Data.Focused().String())
+	var cs []discord.AutocompleteChoice
+	for _, st := range stations {
+		if typed != "" && !strings.Contains(strings.ToLower(st.Name), typed) {
 			continue
 		}
-		rs = append(rs, ytdlpSearchResult{URL: ps[0], Title: ps[1], Uploader: ps[2]})
+		cs = append(cs, discord.AutocompleteChoiceString{Name: st.Name, Value: st.Name})
+		if len(cs) >= 25 {
+			break
+		}
+	}
+	_ = event.AutocompleteResult(cs)
+}
+
+// ===========================
+// Sources
+// ===========================
+
+// SourceResult is a track as a source describes it: a search hit, a playlist entry or a related suggestion
+type SourceResult struct {
+	URL, Title, Uploader string
+	Duration             time.Duration
+}
+
+// SourceProvider is somewhere tracks come from. A query prefix such as [YT] picks one explicitly, and URLs go to the
+// first provider that handles them
+type SourceProvider interface {
+	Name() string
+	// Prefix selects the source in a typed query; empty when it is only reachable by URL
+	Prefix() string
+	Handles(url string) bool
+	// Search is the quick lookup behind autocomplete
+	Search(ctx context.Context, q string, limit int) ([]SourceResult, error)
+	// Resolve returns candidates to pick from for a text query, or a playlist URL's entries (nil for a single track)
+	Resolve(ctx context.Context, q string, limit int) ([]SourceResult, error)
+	// Stream writes the audio at url to out, starting ss into the track
+	Stream(ctx context.Context, url string, ss time.Duration, out io.Writer) error
+	// Related suggests what autoplay could play after url; nil when the source has no recommendations
+	Related(ctx context.Context, url string, limit int) ([]SourceResult, error)
+}
+
+var (
+	YouTubeMusicSource SourceProvider = youtubeSource{music: true}
+	YouTubeSource      SourceProvider = youtubeSource{}
+	SoundCloudSource   SourceProvider = soundcloudSource{}
+	LocalSource        SourceProvider = localSource{}
+)
+
+// SourceProviders are matched in order against query prefixes and URLs
+var SourceProviders = []SourceProvider{LocalSource, SoundCloudSource, YouTubeMusicSource, YouTubeSource}
+
+// DefaultSources answer queries without a prefix; their results are listed in this order
+var DefaultSources = []SourceProvider{YouTubeMusicSource, YouTubeSource}
+
+// sourceForURL returns the provider a URL belongs to, or nil when none claims it
+func sourceForURL(url string) SourceProvider {
+	for _, p := range SourceProviders {
+		if p.Handles(url) {
+			return p
+		}
+	}
+	return nil
+}
+
+// sourceForQuery strips a source prefix from a typed query and returns its provider, or nil when there is none
+func sourceForQuery(q string) (SourceProvider, string) {
+	upper := strings.ToUpper(q)
+	for _, p := range SourceProviders {
+		if pre := p.Prefix(); pre != "" && strings.HasPrefix(upper, strings.ToUpper(pre)) {
+			return p, strings.TrimSpace(q[len(pre):])
+		}
+	}
+	return nil, q
+}
+
+// sourcesFor lists who to ask for a query: a prefixed default source leads the others, any other prefixed source
+// is asked alone
+func sourcesFor(p SourceProvider) []SourceProvider {
+	if p == nil {
+		return DefaultSources
+	}
+	if !slices.Contains(DefaultSources, p) {
+		return []SourceProvider{p}
+	}
+	rest := slices.DeleteFunc(slices.Clone(DefaultSources), func(d SourceProvider) bool { return d == p })
+	return append([]SourceProvider{p}, rest...)
+}
+
+// querySources asks every source at once and returns their results in source order; whoever hasn't answered when
+// ctx ends is left out
+func querySources(ctx context.Context, sources []SourceProvider, query func(SourceProvider) ([]SourceResult, error)) [][]SourceResult {
+	var mu sync.Mutex
+	results := make([][]SourceResult, len(sources))
+	wg := sync.WaitGroup{}
+	for i, p := range sources {
+		wg.Add(1)
+		safeGo(func() {
+			defer wg.Done()
+			rs, err := query(p)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			results[i] = rs
+			mu.Unlock()
+		})
+	}
+	done := make(chan struct{})
+	safeGo(func() {
+		wg.Wait()
+		close(done)
+	})
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return slices.Clone(results)
+}
+
+// streamSource writes a remote track's audio through its provider, falling back to yt-dlp's generic extractors
+func streamSource(ctx context.Context, url string, ss time.Duration, out io.Writer) error {
+	if src := sourceForURL(url); src != nil {
+		return src.Stream(ctx, url, ss, out)
+	}
+	_, err := ytdlpStream(ctx, url, ss, out)
+	return err
+}
+
+// trackKey identifies a track for repeat checks: its video ID when it has one, otherwise its URL
+func trackKey(url string) string {
+	if id := extractVideoID(url); id != "" {
+		return id
+	}
+	return url
+}
+
+// youtubeSource is YouTube or YouTube Music; both share video IDs and yt-dlp but differ in search and mixes
+type youtubeSource struct{ music bool }
+
+func (y youtubeSource) Name() string {
+	if y.music {
+		return "YouTube Music"
+	}
+	return "YouTube"
+}
+
+func (y youtubeSource) Prefix() string {
+	if y.music {
+		return YTMusicPrefix
+	}
+	return YoutubePrefix
+}
+
+func (y youtubeSource) Handles(url string) bool {
+	if !strings.HasPrefix(url, "http") {
+		return false
+	}
+	if strings.Contains(url, "music.youtube.com") {
+		return y.music
+	}
+	return !y.music && isYouTubeURL(url)
+}
+
+func (y youtubeSource) Search(ctx context.Context, q string, limit int) ([]SourceResult, error) {
+	var rs []SourceResult
+	if y.music {
+		r, err := ytmusic.TrackSearch(q).Next()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range r.Tracks {
+			if v.VideoID == "" {
+				continue
+			}
+			artist := ""
+			if len(v.Artists) > 0 {
+				artist = v.Artists[0].Name
+			}
+			rs = append(rs, SourceResult{URL: "https://music.youtube.com/watch?v=" + v.VideoID, Title: v.Title, Uploader: artist})
+		}
+	} else {
+		r, err := ytsearch.NewClient(nil).Search(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range r.Results {
+			if v.VideoID == "" {
+				continue
+			}
+			rs = append(rs, SourceResult{URL: "https://www.youtube.com/watch?v=" + v.VideoID, Title: v.Title, Uploader: v.Channel})
+		}
+	}
+	return rs[:min(len(rs), limit)], nil
+}
+
+func (y youtubeSource) Resolve(ctx context.Context, q string, limit int) ([]SourceResult, error) {
+	if strings.HasPrefix(q, "http") {
+		if !strings.Contains(q, "list=") {
+			return nil, nil
+		}
+		return ytdlpExtractPlaylist(ctx, q, limit)
+	}
+	if y.music {
+		return ytdlpSearchYTM(ctx, q, limit)
+	}
+	return ytdlpSearch(ctx, q, limit)
+}
+
+func (y youtubeSource) Stream(ctx context.Context, url string, ss time.Duration, out io.Writer) error {
+	_, err := ytdlpStream(ctx, url, ss, out)
+	return err
+}
+
+func (y youtubeSource) Related(ctx context.Context, url string, limit int) ([]SourceResult, error) {
+	id := extractVideoID(url)
+	if id == "" {
+		return nil, errors.New("no video ID in " + url)
+	}
+	if y.music {
+		return ytdlpExtractPlaylist(ctx, "https://music.youtube.com/watch?v="+id+"&list=RDAMVM"+id, limit)
+	}
+	return ytdlpExtractPlaylist(ctx, "https://www.youtube.com/watch?v="+id+"&list=RD"+id, limit)
+}
+
+// soundcloudSource plays SoundCloud tracks and sets through yt-dlp; SoundCloud offers no mixes to autoplay from
+type soundcloudSource struct{}
+
+func (soundcloudSource) Name() string   { return "SoundCloud" }
+func (soundcloudSource) Prefix() string { return SoundCloudPrefix }
+
+func (soundcloudSource) Handles(url string) bool {
+	return strings.HasPrefix(url, "http") && strings.Contains(url, "soundcloud.com/")
+}
+
+func (soundcloudSource) Search(ctx context.Context, q string, limit int) ([]SourceResult, error) {
+	return ytdlpSiteSearch(ctx, "scsearch", q, limit)
+}
+
+func (sc soundcloudSource) Resolve(ctx context.Context, q string, limit int) ([]SourceResult, error) {
+	if strings.HasPrefix(q, "http") {
+		if !strings.Contains(q, "/sets/") && !strings.HasSuffix(strings.TrimSuffix(q, "/"), "/likes") {
+			return nil, nil
+		}
+		return ytdlpExtractPlaylist(ctx, q, limit)
+	}
+	return sc.Search(ctx, q, limit)
+}
+
+func (soundcloudSource) Stream(ctx context.Context, url string, ss time.Duration, out io.Writer) error {
+	_, err := ytdlpStream(ctx, url, ss, out)
+	return err
+}
+
+func (soundcloudSource) Related(context.Context, string, int) ([]SourceResult, error) {
+	return nil, nil
+}
+
+// localSource is the MUSIC_DIR library; it is reached through the local option rather than a query prefix, and its
+// tracks are normally played straight from disk
+type localSource struct{}
+
+func (localSource) Name() string   { return "Local Library" }
+func (localSource) Prefix() string { return "" }
+
+func (localSource) Handles(url string) bool {
+	return strings.HasPrefix(url, LocalTrackPrefix)
+}
+
+func (localSource) Search(_ context.Context, q string, limit int) ([]SourceResult, error) {
+	var rs []SourceResult
+	for _, e := range musicLibrary.Search(q, limit) {
+		if !strings.HasSuffix(e, "/") {
+			rs = append(rs, SourceResult{URL: LocalTrackPrefix + e, Title: strings.TrimSuffix(filepath.Base(e), filepath.Ext(e))})
+		}
+	}
+	return rs, nil
+}
+
+// Resolve expands a folder into its files, up to PlaylistMaxTracks whatever the limit
+func (l localSource) Resolve(ctx context.Context, q string, limit int) ([]SourceResult, error) {
+	rel, ok := strings.CutPrefix(q, LocalTrackPrefix)
+	if !ok {
+		return l.Search(ctx, q, limit)
+	}
+	var rs []SourceResult
+	for _, t := range localFolderTracks(rel) {
+		rs = append(rs, SourceResult{URL: t.URL, Title: t.Title})
+	}
+	return rs, nil
+}
+
+// Stream copies the file as is; seeking happens in the transcoder for local tracks
+func (localSource) Stream(ctx context.Context, url string, _ time.Duration, out io.Writer) error {
+	path, ok := resolveLocalPath(strings.TrimPrefix(url, LocalTrackPrefix))
+	if !ok {
+		return errors.New("not in the music library: " + url)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(out, f)
+	return err
+}
+
+func (localSource) Related(context.Context, string, int) ([]SourceResult, error) {
+	return nil, nil
+}
+
+// ===========================
+// YT-DLP & Autocomplete
+// ===========================
+
+const (
+	YoutubePrefix    = "[YT]"
+	YTMusicPrefix    = "[YTM]"
+	SoundCloudPrefix = "[SC]"
+)
+
+func (vs *VoiceSystem) Search(q string) ([]SearchResult, error) {
+	vs.cache.RLock()
+	if item, ok := vs.cache.items[q]; ok {
+		if time.Now().Before(item.expiresAt) {
+			vs.cache.RUnlock()
+			return item.results, nil
+		}
+	}
+	vs.cache.RUnlock()
+
+	src, query := sourceForQuery(q)
+	sources := sourcesFor(src)
+	ctx, cancel := context.WithTimeout(context.Background(), 2300*time.Millisecond)
+	defer cancel()
+	results := querySources(ctx, sources, func(p SourceProvider) ([]SourceResult, error) {
+		return p.Search(ctx, query, 25)
+	})
+
+	var fin []SearchResult
+	seen := make(map[string]bool)
+	for i, rs := range results {
+		for _, r := range rs {
+			key := trackKey(r.URL)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			suffix := ""
+			if r.Uploader != "" {
+				suffix = " - " + r.Uploader
+			}
+			fin = append(fin, SearchResult{URL: r.URL, ChannelName: r.Uploader, Title: TruncateWithPreserve(r.Title, 100, sources[i].Prefix()+" ", suffix)})
+		}
+	}
+	if len(fin) > 25 {
+		fin = fin[:25]
+	}
+	if len(fin) > 0 {
+		vs.cache.Lock()
+		vs.cache.items[q] = cachedItem{results: fin, expiresAt: time.Now().Add(1 * time.Hour)}
+		vs.cache.Unlock()
+	}
+
+	return fin, nil
+}
+
+func (vs *VoiceSystem) SearchPlaylist(q string) ([]SourceResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var ytRs, ytmRs []SourceResult
+	var ytErr, ytmErr error
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	safeGo(func() {
+		defer wg.Done()
+		ytRs, ytErr = ytdlpSearchPlaylist(ctx, q, 10)
+	})
+	safeGo(func() {
+		defer wg.Done()
+		ytmRs, ytmErr = ytdlpSearchPlaylistYTM(ctx, q, 10)
+	})
+	wg.Wait()
+
+	if ytErr != nil && ytmErr != nil {
+		return nil, fmt.Errorf("YouTube: %v, YTM: %v", ytErr, ytmErr)
+	}
+
+	var res []SourceResult
+	seen := make(map[string]bool)
+	for _, r := range ytmRs {
+		if seen[r.URL] {
+			continue
+		}
+		res = append(res, SourceResult{Title: "[PL] " + r.Title, Uploader: r.Uploader, URL: r.URL})
+		seen[r.URL] = true
+	}
+	for _, r := range ytRs {
+		if seen[r.URL] {
+			continue
+		}
+		res = append(res, SourceResult{Title: "[PL] " + r.Title, Uploader: r.Uploader, URL: r.URL})
+		seen[r.URL] = true
+	}
+
+	return res, nil
+}
+
+func (s *VoiceSession) resolveTrackMetadata(ctx context.Context, t *Track) error {
+	if !t.NeedsResolution {
+		return nil
+	}
+
+	start := time.Now()
+	origURL := t.URL
+	defer func() {
+		LogVoice("Metadata resolution for %s took %v", origURL, time.Since(start))
+	}()
+
+	needsSearch := !strings.HasPrefix(t.URL, "http")
+	var targetDuration time.Duration
+
+	if !needsSearch && sourceForURL(t.URL) == nil {
+		likelyDRMSite := isLikelyMusicStreamingSite(t.URL)
+
+		resultChan := make(chan metadataResult, 2)
+
+		safeGo(func() {
+			timeout := 30 * time.Second
+			if likelyDRMSite {
+				timeout = 3 * time.Second
+			}
+
+			ytdlpCtx, ytdlpCancel := context.WithTimeout(ctx, timeout)
+			defer ytdlpCancel()
+
+			title, uploader, id, dur, sz, err := ytdlpResolveMetadata(ytdlpCtx, t.URL)
+			if err == nil {
+				t.mu.Lock()
+				t.TotalSize = sz
+				t.mu.Unlock()
+			}
+			if id != "" {
+				t.mu.Lock()
+				if !strings.HasPrefix(t.URL, "http") {
+					t.URL = "https://www.youtube.com/watch?v=" + id
+				}
+				t.mu.Unlock()
+			}
+			resultChan <- metadataResult{title, uploader, dur, "yt-dlp", err}
+		})
+
+		if likelyDRMSite {
+			safeGo(func() {
+				scrapeCtx, scrapeCancel := context.WithTimeout(ctx, 5*time.Second)
+				defer scrapeCancel()
+
+				title, artist, err := extractMetadataFromDRMSite(scrapeCtx, t.URL)
+				resultChan <- metadataResult{title, artist, 0, "scraper", err}
+			})
+		}
+
+		var ytdlpResult, scraperResult *metadataResult
+		resultsReceived := 0
+		expectedResults := 1
+		if likelyDRMSite {
+			expectedResults = 2
+		}
+
+	waitLoop:
+		for resultsReceived < expectedResults {
+			select {
+			case res := <-resultChan:
+				resultsReceived++
+				if res.source == "yt-dlp" {
+					ytdlpResult = &res
+				} else {
+					scraperResult = &res
+				}
+
+				if res.err == nil && res.title != "" {
+					t.Title = res.title
+					t.Channel = res.artist
+					targetDuration = res.duration
+					if res.artist != "" {
+						t.URL = res.title + " " + res.artist
+					} else {
+						t.URL = res.title
+					}
+					needsSearch = true
+					break waitLoop
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(1 * time.Second):
+				if resultsReceived > 0 {
+					break waitLoop
+				}
+			}
+		}
+
+		safeGo(func() {
+			for resultsReceived < expectedResults {
+				select {
+				case <-resultChan:
+					resultsReceived++
+				case <-time.After(5 * time.Second):
+					return
+				}
+			}
+		})
+
+		if !needsSearch {
+			if scraperResult != nil && scraperResult.err == nil && scraperResult.title != "" {
+				t.Title = scraperResult.title
+				t.Channel = scraperResult.artist
+				if scraperResult.artist != "" {
+					t.URL = scraperResult.title + " " + scraperResult.artist
+				} else {
+					t.URL = scraperResult.title
+				}
+				needsSearch = true
+			} else if ytdlpResult != nil && ytdlpResult.err != nil {
+				if strings.Contains(ytdlpResult.err.Error(), "DRM") {
+					LogVoice("DRM detected for %s, but scraping also failed", t.URL)
+					return fmt.Errorf("DRM-protected content not supported: %s", t.URL)
+				}
+			}
+		}
+	}
+
+	if needsSearch {
+		src, q := sourceForQuery(t.URL)
+		combined := slices.Concat(querySources(ctx, sourcesFor(src), func(p SourceProvider) ([]SourceResult, error) {
+			return p.Resolve(ctx, q, 5)
+		})...)
+
+		if len(combined) > 0 {
+			best := s.SelectBestTrack(combined, t.Title, t.Channel, targetDuration)
+			if strings.Contains(best.URL, "http") {
+				t.URL, t.Title, t.Channel, t.Duration = best.URL, best.Title, best.Uploader, best.Duration
+				s.updateNextTrackStatusIfNeeded(t)
+			}
+		}
+	}
+
+	if !strings.HasPrefix(t.URL, "http") {
+		return errors.New("no song found")
+	}
+	return nil
+}
+
+func (s *VoiceSession) processTrackFile(ctx context.Context, t *Track) {
+	if t.Local {
+		s.processLocalTrack(t)
+		return
+	}
+	videoID := extractVideoID(t.URL)
+	isYouTube := isYouTubeURL(t.URL)
+
+	if videoID != "" && isYouTube {
+		filename := filepath.Join(AudioCacheDir, videoID+".webm")
+
+		if t.Title == "" {
+			if cm := readMetadataCache(videoID); cm != nil {
+				t.mu.Lock()
+				t.Title, t.Channel, t.Duration = cm.Title, cm.Channel, cm.Duration
+				t.mu.Unlock()
+				t.SafeCloseMetadata()
+				s.updateNextTrackStatusIfNeeded(t)
+			}
+		}
+
+		if t.Title == "" {
+			safeGo(func() {
+				var err error = errors.New("fast metadata disabled")
+				var title, uploader string
+				var dur time.Duration
+
+				if err != nil {
+					var dur2 time.Duration
+					var sz2 int64
+					title, uploader, _, dur2, sz2, err = ytdlpResolveMetadata(ctx, t.URL)
+					if err == nil {
+						t.mu.Lock()
+						t.TotalSize = sz2
+						t.mu.Unlock()
+					}
+					dur = dur2
+				}
+
+				if err == nil {
+					t.mu.Lock()
+					t.Title = title
+					t.Channel = uploader
+					t.Duration = dur
+					t.mu.Unlock()
+					writeMetadataCache(videoID, title, uploader, dur)
+					t.SafeCloseMetadata()
+					s.updateNextTrackStatusIfNeeded(t)
+				} else {
+					LogVoice("Background metadata fetch failed for %s: %v", t.URL, err)
+					t.SafeCloseMetadata()
+				}
+			})
+		} else {
+			select {
+			case <-t.MetadataReady:
+			default:
+				t.SafeCloseMetadata()
+			}
+			safeGo(func() { writeMetadataCache(videoID, t.Title, t.Channel, t.Duration) })
+		}
+
+		if audioCache.Lookup(filename) {
+			t.MarkReady(filename, t.Title, t.Channel, t.Duration, nil)
+			return
+		}
+
+		s.downloadAndCache(ctx, t, filename, t.URL)
+
+		safeGo(func() {
+			t.mu.Lock()
+			title, ch, d := t.Title, t.Channel, t.Duration
+			t.mu.Unlock()
+			if title != "" {
+				writeMetadataCache(videoID, title, ch, d)
+			}
+		})
+		return
+	}
+
+	meta, err := ytdlpExtractMetadata(ctx, t.URL)
+	if err != nil {
+		t.MarkError(err)
+		return
+	}
+
+	t.Title, t.Channel, t.Duration = meta.Title, meta.Uploader, meta.Duration
+	s.updateNextTrackStatusIfNeeded(t)
+
+	select {
+	case <-t.MetadataReady:
+	default:
+		t.SafeCloseMetadata()
+	}
+
+	if meta.ID != "" {
+		if strings.Contains(t.URL, "music.youtube.com") {
+			t.URL = "https://music.youtube.com/watch?v=" + meta.ID
+		} else {
+			t.URL = "https://www.youtube.com/watch?v=" + meta.ID
+		}
+	}
+
+	if audioCache.Lookup(meta.Filename) {
+		t.MarkReady(meta.Filename, meta.Title, meta.Uploader, meta.Duration, nil)
+		return
+	}
+
+	s.downloadAndCache(ctx, t, meta.Filename, t.URL)
+}
+
+func (s *VoiceSession) downloadAndCache(ctx context.Context, t *Track, filename, url string) {
+	downloadDone := make(chan struct{})
+	writeSig := make(chan struct{}, 1)
+	readySig := make(chan struct{})
+	errorSig := make(chan error, 1)
+	onceReady := sync.Once{}
+	onceError := sync.Once{}
+
+	t.mu.Lock()
+	t.FileCreated = make(chan struct{})
+	t.mu.Unlock()
+
+	ctx, dcancel := context.WithCancel(ctx)
+	t.mu.Lock()
+	t.downloadCancel = dcancel
+	t.mu.Unlock()
+
+	safeGo(func() {
+		defer close(downloadDone)
+		defer func() {
+			if r := recover(); r != nil {
+				LogVoice("CRITICAL: downloader safeGo panic recovered: %v", r)
+				onceError.Do(func() { errorSig <- fmt.Errorf("panic: %v", r) })
+			}
+		}()
+		ensureAudioCacheDir()
+		partFilename := filename + ".part"
+
+		t.mu.Lock()
+		ss := t.SeekOffset
+		t.mu.Unlock()
+
+		thresh := int64(16 * 1024) // 16KB initial buffer for transcoding
+		cacheFile, err := os.Create(partFilename)
+
+		t.mu.Lock()
+		if t.FileCreated != nil {
+			close(t.FileCreated)
+		}
+		t.mu.Unlock()
+
+		if err != nil {
+			LogVoice("Failed to create cache file: %v", err)
+			return
+		}
+
+		sw := &TrackSignalWriter{
+			w: cacheFile,
+			onWrite: func(n int) {
+				t.mu.Lock()
+				t.WrittenBytes += int64(n)
+				wb := t.WrittenBytes
+				t.mu.Unlock()
+				if wb >= thresh {
+					onceReady.Do(func() { close(readySig) })
+				}
+				select {
+				case writeSig <- struct{}{}:
+				default:
+				}
+			},
+		}
+
+		t.mu.Lock()
+		t.downloadCancel = dcancel
+		t.mu.Unlock()
+
+		err = streamSource(ctx, url, ss, sw)
+		if err != nil {
+			onceError.Do(func() { errorSig <- err })
+		}
+		cacheFile.Close()
+
+		if err != nil {
+			LogVoice("Stream/Cache failed for %s: %v", url, err)
+			os.Remove(partFilename)
+			return
+		}
+
+		onceReady.Do(func() { close(readySig) })
+
+		t.mu.Lock()
+		finalWb := t.WrittenBytes
+		t.mu.Unlock()
+
+		if finalWb < MinTrackSize {
+			onceError.Do(func() { errorSig <- fmt.Errorf("download too small: %d bytes (min %d)", finalWb, MinTrackSize) })
+			LogVoice("Download too small for %s: %d bytes. Deleting.", url, finalWb)
+			os.Remove(partFilename)
+			return
+		}
+
+		if err := os.Rename(partFilename, filename); err != nil {
+			LogVoice("Failed to rename cache file for %s: %v", url, err)
+			os.Remove(partFilename)
+		} else {
+			t.mu.Lock()
+			wb := t.WrittenBytes
+			t.mu.Unlock()
+			LogVoice("Downloaded track file: %s (Size: %d bytes)", filename, wb)
+			audioCache.Add(filename)
+		}
+	})
+
+	totalTimer := time.NewTimer(90 * time.Second)
+	defer totalTimer.Stop()
+
+	stallTimer := time.NewTimer(30 * time.Second)
+	defer stallTimer.Stop()
+
+loop:
+	for {
+		select {
+		case <-readySig:
+			break loop
+		case <-ctx.Done():
+			dcancel()
+			t.MarkError(ctx.Err())
+			return
+		case <-totalTimer.C:
+			dcancel()
+			t.MarkError(errors.New("timeout: download too slow (max total time exceeded)"))
+			return
+		case <-stallTimer.C:
+			dcancel()
+			t.MarkError(errors.New("timeout: download stalled or failed to start"))
+			return
+		case err := <-errorSig:
+			dcancel()
+			t.MarkError(err)
+			return
+		case <-downloadDone:
+			dcancel()
+			t.MarkError(errors.New("timeout: download process exited unexpectedly without data"))
+			return
+		case <-writeSig:
+			if !stallTimer.Stop() {
+				select {
+				case <-stallTimer.C:
+				default:
+				}
+			}
+			stallTimer.Reset(maxStall)
+		}
+	}
+
+	partFilename := filename + ".part"
+	readFile, err := os.Open(partFilename)
+	if err != nil {
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-ctx.Done():
+			t.MarkError(ctx.Err())
+			return
+		}
+		readFile, err = os.Open(partFilename)
+		if err != nil {
+			t.MarkError(fmt.Errorf("failed to open cache file for tailing: %w", err))
+			return
+		}
+	}
+
+	tr := &TailingReader{
+		f:    readFile,
+		done: downloadDone,
+		ctx:  ctx,
+		sig:  writeSig,
+	}
+
+	t.MarkReady(filename, t.Title, t.Channel, t.Duration, tr)
+}
+
+// recordPlay writes a finished, skipped or stopped track to the guild's play log
+func (s *VoiceSession) recordPlay(t *Track, played time.Duration, skipped bool) {
+	if played <= 0 {
+		return
+	}
+	t.mu.Lock()
+	p := &VoicePlay{
+		GuildID:     s.GuildID,
+		RequestedBy: t.RequestedBy,
+		URL:         t.URL,
+		Title:       t.Title,
+		Channel:     t.Channel,
+		Duration:    t.Duration,
+		Played:      played,
+		Skipped:     skipped,
+	}
+	t.mu.Unlock()
+	if err := LogVoicePlay(context.Background(), p); err != nil {
+		LogVoice("Failed to log play of %s: %v", p.URL, err)
+	}
+}
+
+func (s *VoiceSession) addToHistory(url, title, author string) {
+	s.lockQueue()
+	defer s.unlockQueue()
+	if s.radio != nil {
+		s.radio.seen[trackKey(url)] = struct{}{}
+	}
+	if title == "" {
+		return
+	}
+	n := normalizeTitle(title, author)
+	tokens := tokenize(n)
+
+	id := extractVideoID(url)
+	if id == "" {
+		return
+	}
+	n = normalizeTitle(title, author)
+	tokens = tokenize(n)
+
+	if !slices.Contains(s.History, id) {
+		s.History = append(s.History, id)
+		if len(s.History) > 50 {
+			s.History = s.History[1:]
+		}
+	}
+	if n != "" {
+		if !s.checkSimilarity(tokens) {
+			s.HistoryTitles = append(s.HistoryTitles, n)
+			s.HistoryAuthors = append(s.HistoryAuthors, author)
+
+			uniqueTokens := make([]string, 0, len(tokens))
+			seen := make(map[string]bool)
+			for _, t := range tokens {
+				if !seen[t] {
+					seen[t] = true
+					uniqueTokens = append(uniqueTokens, t)
+				}
+			}
+			s.HistoryTokens = append(s.HistoryTokens, uniqueTokens)
+			s.updateIDF(uniqueTokens, true)
+
+			if len(s.HistoryTitles) > 50 {
+				s.HistoryTitles = s.HistoryTitles[1:]
+				s.HistoryAuthors = s.HistoryAuthors[1:]
+				oldTokens := s.HistoryTokens[0]
+				s.HistoryTokens = s.HistoryTokens[1:]
+				s.updateIDF(oldTokens, false)
+			}
+		}
+	}
+}
+
+func tokenize(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+func (s *VoiceSession) updateIDF(tokens []string, add bool) {
+	for _, t := range tokens {
+		if add {
+			s.IDFStats[t]++
+		} else {
+			s.IDFStats[t]--
+			if s.IDFStats[t] <= 0 {
+				delete(s.IDFStats, t)
+			}
+		}
+	}
+}
+
+func (s *VoiceSession) checkSimilarity(candidateTokens []string) bool {
+	if len(s.HistoryTokens) == 0 {
+		return false
+	}
+
+	cMap := make(map[string]bool)
+	for _, t := range candidateTokens {
+		cMap[t] = true
+	}
+
+	N := float64(len(s.HistoryTitles) + 1)
+
+	for _, hTokens := range s.HistoryTokens {
+		iScore, uScore := 0.0, 0.0
+
+		for t := range cMap {
+			df := s.IDFStats[t] + 1
+			wt := math.Log(1.0 + N/float64(df))
+			uScore += wt
+		}
+
+		for _, t := range hTokens {
+			if !cMap[t] {
+				df := s.IDFStats[t]
+				if cMap[t] {
+					df++
+				}
+				wt := math.Log(1.0 + N/float64(df))
+				uScore += wt
+			} else {
+				df := s.IDFStats[t] + 1
+				wt := math.Log(1.0 + N/float64(df))
+				iScore += wt
+			}
+		}
+
+		if uScore > 0 && (iScore/uScore) >= 0.7 {
+			return true
+		}
+	}
+	return false
+}
+
+func readMetadataCache(videoID string) *CachedMetadata {
+	f, err := os.ReadFile(filepath.Join(AudioCacheDir, videoID+".meta"))
+	if err != nil {
+		return nil
+	}
+	var cm CachedMetadata
+	if json.Unmarshal(f, &cm) != nil {
+		return nil
+	}
+	return &cm
+}
+
+func writeMetadataCache(videoID, title, channel string, d time.Duration) {
+	ensureAudioCacheDir()
+	cm := CachedMetadata{Title: title, Channel: channel, Duration: d}
+	if old := readMetadataCache(videoID); old != nil {
+		cm.Loudness = old.Loudness
+	}
+	b, _ := json.Marshal(cm)
+	_ = os.WriteFile(filepath.Join(AudioCacheDir, videoID+".meta"), b, 0644)
+}
+
+// writeLoudnessCache stores a track's measured loudness next to its cached metadata
+func writeLoudnessCache(videoID string, lufs float64) {
+	ensureAudioCacheDir()
+	cm := CachedMetadata{}
+	if old := readMetadataCache(videoID); old != nil {
+		cm = *old
+	}
+	cm.Loudness = lufs
+	b, _ := json.Marshal(cm)
+	_ = os.WriteFile(filepath.Join(AudioCacheDir, videoID+".meta"), b, 0644)
+}
+
+func (s *VoiceSession) fetchRelated(url, title, artist string) (string, error) {
+	src := sourceForURL(url)
+	if src == nil {
+		return "", errors.New("no source for " + url)
+	}
+	// YouTube and YouTube Music share video IDs, so a track from either gets both mixes
+	sources := []SourceProvider{src}
+	if slices.Contains(DefaultSources, src) {
+		sources = DefaultSources
+	}
+	es := slices.Concat(querySources(s.cancelCtx, sources, func(p SourceProvider) ([]SourceResult, error) {
+		return p.Related(s.cancelCtx, url, 20)
+	})...)
+
+	curID := trackKey(url)
+	if len(es) == 0 && slices.Contains(DefaultSources, src) {
+		LogVoice("Autoplay: yt-dlp returned 0 results, trying native search fallback for '%s %s'", title, artist)
+		query := title
+		if artist != "" {
+			query += " " + artist
+		}
+		if res, err := YouTubeSource.Search(s.cancelCtx, query, 20); err == nil {
+			for _, r := range res {
+				if trackKey(r.URL) != curID {
+					es = append(es, r)
+				}
+			}
+		}
+	}
+
+	curTitle := curID
+	if title != "" {
+		curTitle = title
+	}
+	LogVoice("Autoplay: Found %d related tracks for %s", len(es), curTitle)
+
+	s.lockQueue()
+	count := min(len(s.HistoryTitles), 5)
+	historyTitles := make([]string, count)
+	copy(historyTitles, s.HistoryTitles[len(s.HistoryTitles)-count:])
+	idfCopy := make(map[string]int, len(s.IDFStats))
+	maps.Copy(idfCopy, s.IDFStats)
+
+	htTokens := make([][]string, len(s.HistoryTokens))
+	copy(htTokens, s.HistoryTokens)
+	var seen map[string]struct{}
+	if s.radio != nil {
+		seen = maps.Clone(s.radio.seen)
+	}
+	s.unlockQueue()
+
+	for _, e := range es {
+		u := strings.TrimSpace(e.URL)
+		nid := ""
+		if strings.HasPrefix(u, "http") {
+			nid = trackKey(u)
+		}
+
+		nti, nup := strings.TrimSpace(e.Title), strings.TrimSpace(e.Uploader)
+		n := normalizeTitle(nti, nup)
+		tokens := tokenize(n)
+
+		if nid == "" || nid == curID {
+			continue
+		}
+		_, recent := seen[nid]
+		found := recent || slices.Contains(s.History, nid)
+		if found {
+			continue
+		}
+
+		if checkSimilarityAgainst(tokens, htTokens, idfCopy) {
+			found = true
+		}
+		if found {
+			continue
+		}
+		return u, nil
+	}
+	if seen != nil {
+		LogVoice("Radio: No related tracks left that haven't played recently for %s", curTitle)
+	} else if len(es) > 1 {
+		LogVoice("Autoplay: Strict filtering failed, trying fallback... %s", curTitle)
+		for _, e := range es {
+			u := strings.TrimSpace(e.URL)
+			nid := ""
+			if strings.HasPrefix(u, "http") {
+				nid = trackKey(u)
+			}
+			if nid != "" && nid != curID {
+				return u, nil
+			}
+		}
+	} else {
+		LogVoice("Autoplay: Not enough tracks for fallback (Count: %d)", len(es))
+	}
+	return "", errors.New("none")
+}
+
+func checkSimilarityAgainst(candidateTokens []string, historyTokens [][]string, idfStats map[string]int) bool {
+	if len(historyTokens) == 0 {
+		return false
+	}
+
+	cMap := make(map[string]bool)
+	for _, t := range candidateTokens {
+		cMap[t] = true
+	}
+
+	N := float64(len(historyTokens) + 1)
+
+	for _, hTokens := range historyTokens {
+		iScore, uScore := 0.0, 0.0
+
+		for t := range cMap {
+			df := idfStats[t] + 1
+			wt := math.Log(1.0 + N/float64(df))
+			uScore += wt
+		}
+
+		for _, t := range hTokens {
+			if !cMap[t] {
+				df := idfStats[t]
+				wt := math.Log(1.0 + N/float64(df))
+				uScore += wt
+			} else {
+				df := idfStats[t] + 1
+				wt := math.Log(1.0 + N/float64(df))
+				iScore += wt
+			}
+		}
+
+		if uScore > 0 && (iScore/uScore) >= 0.7 {
+			return true
+		}
+	}
+	return false
+}
+
+// ===========================
+// yt-dlp
+// ===========================
+
+func newYtdlp() (*ytdlp.Command, func()) {
+	cmd := ytdlp.New().
+		Quiet().
+		NoWarnings()
+
+	if proxy := os.Getenv("YOUTUBE_PROXY"); proxy != "" {
+		cmd.Proxy(proxy)
+	}
+
+	return cmd, func() {}
+}
+
+func buildYtdlpArgs() []string {
+	jsOnce.Do(func() {
+		for _, rt := range []string{"node", "deno", "quickjs"} {
+			if path, err := exec.LookPath(rt); err == nil {
+				cachedJSArgs = append(cachedJSArgs, "--js-runtimes", rt+":"+path)
+				break
+			}
+		}
+	})
+
+	args := append([]string(nil), cachedJSArgs...)
+	args = append(args,
+		"--no-playlist",
+		"--no-check-certificates",
+		"--no-warnings",
+		"--extractor-args", "youtube:player_client=android,web",
+		"--prefer-free-formats",
+		"--socket-timeout", "15",
+		"--retries", "10",
+		"--fragment-retries", "10",
+		"--no-cache-dir",
+	)
+
+	if _, err := os.Stat("cookies.txt"); err == nil {
+		args = append(args, "--cookies", "cookies.txt")
+	} else if c := os.Getenv("YOUTUBE_COOKIES"); c != "" {
+		if _, err := os.Stat(c); err == nil {
+			args = append(args, "--cookies", c)
+		}
+	}
+
+	return args
+}
+
+func ytdlpSearch(ctx context.Context, q string, m int) ([]SourceResult, error) {
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	args := buildYtdlpArgs()
+	res, err := cmd.
+		FlatPlaylist().
+		Print("%(url)s\t%(title)s\t%(uploader)s\t%(duration)s").
+		PlaylistItems(fmt.Sprintf("1-%d", m)).
+		NoWarnings().
+		IgnoreConfig().
+		PreferFreeFormats().
+		Run(ctx, append(args, "ytsearch"+fmt.Sprintf("%d", m)+":"+q)...)
+
+	if err != nil {
+		return nil, err
+	}
+	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
+	rs := make([]SourceResult, 0, len(ls))
+	for _, l := range ls {
+		ps := strings.Split(l, "\t")
+		if len(ps) < 4 {
+			continue
+		}
+		d, _ := time.ParseDuration(ps[3] + "s")
+		u := ps[0]
+		if extractVideoID(u) != "" {
+			rs = append(rs, SourceResult{u, ps[1], ps[2], d})
+		}
+	}
+	return rs, nil
+}
+func ytdlpSearchYTM(ctx context.Context, q string, m int) ([]SourceResult, error) {
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	args := buildYtdlpArgs()
+	res, err := cmd.
+		FlatPlaylist().
+		Print("%(url)s\t%(title)s\t%(uploader)s\t%(duration)s").
+		PlaylistItems(fmt.Sprintf("1-%d", m)).
+		NoWarnings().
+		IgnoreConfig().
+		Run(ctx, append(args, fmt.Sprintf("ytmsearch%d:%s", m, q))...)
+
+	if err != nil {
+		return nil, err
+	}
+	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
+	rs := make([]SourceResult, 0, len(ls))
+	for _, l := range ls {
+		ps := strings.Split(l, "\t")
+		if len(ps) < 4 {
+			continue
+		}
+		d, _ := time.ParseDuration(ps[3] + "s")
+		u := ps[0]
+		if extractVideoID(u) != "" {
+			rs = append(rs, SourceResult{URL: u, Title: ps[1], Uploader: ps[2], Duration: d})
+		}
+	}
+	return rs, nil
+}
+
+// ytdlpSiteSearch runs one of yt-dlp's search extractors (like scsearch) and keeps whatever it returns
+func ytdlpSiteSearch(ctx context.Context, extractor, q string, m int) ([]SourceResult, error) {
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	args := buildYtdlpArgs()
+	res, err := cmd.
+		FlatPlaylist().
+		Print("%(url)s\t%(title)s\t%(uploader)s\t%(duration)s").
+		PlaylistItems(fmt.Sprintf("1-%d", m)).
+		NoWarnings().
+		IgnoreConfig().
+		Run(ctx, append(args, fmt.Sprintf("%s%d:%s", extractor, m, q))...)
+
+	if err != nil {
+		return nil, err
+	}
+	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
+	rs := make([]SourceResult, 0, len(ls))
+	for _, l := range ls {
+		ps := strings.Split(l, "\t")
+		if len(ps) < 4 || !strings.HasPrefix(ps[0], "http") {
+			continue
+		}
+		d, _ := time.ParseDuration(ps[3] + "s")
+		rs = append(rs, SourceResult{URL: ps[0], Title: ps[1], Uploader: ps[2], Duration: d})
+	}
+	return rs, nil
+}
+
+func ytdlpSearchPlaylist(ctx context.Context, q string, m int) ([]SourceResult, error) {
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	searchURL := fmt.Sprintf("https://www.youtube.com/results?search_query=%s&sp=EgIQAw%%253D%%253D", url.QueryEscape(q))
+
+	args := buildYtdlpArgs()
+	res, err := cmd.
+		FlatPlaylist().
+		Print("%(url)s\t%(title)s\t%(uploader)s").
+		PlaylistItems(fmt.Sprintf("1-%d", m)).
+		NoWarnings().
+		IgnoreConfig().
+		Run(ctx, append(args, searchURL)...)
+
+	if err != nil {
+		return nil, err
+	}
+	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
+	rs := make([]SourceResult, 0, len(ls))
+	for _, l := range ls {
+		ps := strings.Split(l, "\t")
+		if len(ps) < 3 || ps[1] == "" || ps[1] == "NA" {
+			continue
+		}
+		rs = append(rs, SourceResult{URL: ps[0], Title: ps[1], Uploader: ps[2]})
+	}
+	return rs, nil
+}
+
+func ytdlpSearchPlaylistYTM(ctx context.Context, q string, m int) ([]SourceResult, error) {
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	searchURL := fmt.Sprintf("https://music.youtube.com/search?q=%s&filter=playlists", url.QueryEscape(q))
+
+	args := buildYtdlpArgs()
+	res, err := cmd.
+		FlatPlaylist().
+		Print("%(url)s\t%(title)s\t%(uploader)s").
+		PlaylistItems(fmt.Sprintf("1-%d", m)).
+		NoWarnings().
+		IgnoreConfig().
+		Run(ctx, append(args, searchURL)...)
+
+	if err != nil {
+		return nil, err
+	}
+	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
+	rs := make([]SourceResult, 0, len(ls))
+	for _, l := range ls {
+		ps := strings.Split(l, "\t")
+		if len(ps) < 3 || ps[1] == "" || ps[1] == "NA" {
+			continue
+		}
+		rs = append(rs, SourceResult{URL: ps[0], Title: ps[1], Uploader: ps[2]})
+	}
+	return rs, nil
+}
+
+func ytdlpExtractMetadata(ctx context.Context, u string) (*ytdlpMetadata, error) {
+	u = strings.Replace(u, "music.youtube.com", "www.youtube.com", 1)
+
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	args := buildYtdlpArgs()
+	args = append(args, "-f", "bestaudio[ext=webm]/bestaudio[ext=m4a]/bestaudio/best")
+	res, err := cmd.
+		Print("%(url)s\t%(title)s\t%(uploader)s\t%(duration)s\t%(id)s\t%(filename)s").
+		Output(filepath.Join(AudioCacheDir, "%(id)s.%(ext)s")).
+		NoWarnings().
+		IgnoreConfig().
+		Run(ctx, append(args, "--skip-download", u)...)
+
+	if err != nil {
+		LogVoice("yt-dlp metadata failed: %v, stderr: %s (URL: %s)", err, res.Stderr, u)
+		return nil, err
+	}
+
+	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
+	for _, l := range ls {
+		ps := strings.Split(l, "\t")
+		if len(ps) < 6 {
+			continue
+		}
+		d, _ := time.ParseDuration(ps[3] + "s")
+		return &ytdlpMetadata{URL: ps[0], Title: ps[1], Uploader: ps[2], Duration: d, ID: ps[4], Filename: ps[5]}, nil
+	}
+	return nil, errors.New("failed to parse metadata")
+}
+
+// ytdlpSites are popular hosts yt-dlp has extractors for that no SourceProvider claims
+var ytdlpSites = []string{
+	"bandcamp.com", "mixcloud.com", "vimeo.com", "dailymotion.com", "twitch.tv", "bilibili.com",
+	"nicovideo.jp", "tiktok.com", "twitter.com", "x.com", "instagram.com", "facebook.com", "reddit.com",
+	"archive.org", "audiomack.com", "newgrounds.com",
+}
+
+// isYtdlpSite reports whether u is on one of ytdlpSites
+func isYtdlpSite(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	return slices.ContainsFunc(ytdlpSites, func(site string) bool {
+		return host == site || strings.HasSuffix(host, "."+site)
+	})
+}
+
+func isLikelyMusicStreamingSite(url string) bool {
+	lowerURL := strings.ToLower(url)
+
+	musicPathPatterns := []string{
+		"/track/", "/tracks/",
+		"/album/", "/albums/",
+		"/song/", "/songs/",
+		"/playlist/", "/playlists/",
+		"/artist/", "/artists/",
+		"/music/",
+	}
+
+	for _, pattern := range musicPathPatterns {
+		if strings.Contains(lowerURL, pattern) {
+			return true
+		}
+	}
+
+	musicSubdomains := []string{
+		"music.", "play.", "listen.", "stream.",
+	}
+
+	for _, subdomain := range musicSubdomains {
+		if strings.Contains(lowerURL, "://"+subdomain) || strings.Contains(lowerURL, "://www."+subdomain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ytdlpStream(ctx context.Context, u string, ss time.Duration, out io.Writer) (*ytdlpMetadata, error) {
+	u = strings.Replace(u, "music.youtube.com", "www.youtube.com", 1)
+
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	proxy := os.Getenv("YOUTUBE_PROXY")
+
+	args := buildYtdlpArgs()
+	args = append(args, "--ignore-config")
+	if ss > 0 {
+		args = append(args, "--ss", fmt.Sprintf("%.3f", ss.Seconds()))
+	}
+	execCmd := cmd.
+		Format("bestaudio[ext=webm]/bestaudio[ext=m4a]/bestaudio/best").
+		Output("-").
+		NoSimulate().
+		NoPart().
+		NoPlaylist().
+		NoCheckCertificates().
+		BuildCommand(ctx, append(args, u)...)
+
+	execCmd.Stdout = out
+	execCmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	if proxy != "" {
+		execCmd.Env = append(execCmd.Env, "http_proxy="+proxy, "https_proxy="+proxy, "all_proxy="+proxy)
+	}
+	execCmd.WaitDelay = 0
+
+	var stderr bytes.Buffer
+	execCmd.Stderr = &stderr
+
+	if err := execCmd.Start(); err != nil {
+		return nil, err
+	}
+
+	if err := execCmd.Wait(); err != nil {
+		msg := strings.ToLower(err.Error() + stderr.String())
+		if strings.Contains(msg, "broken pipe") || strings.Contains(msg, "signal: killed") {
+			return &ytdlpMetadata{}, nil
+		}
+		LogVoice("yt-dlp stream failed for %s: %v, stderr: %s", u, err, stderr.String())
+		LogVoice("yt-dlp exited with error: %v", err)
+		return nil, err
+	}
+
+	return &ytdlpMetadata{}, nil
+}
+
+func ytdlpResolveMetadata(ctx context.Context, u string) (string, string, string, time.Duration, int64, error) {
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	args := append(buildYtdlpArgs(), "--skip-download")
+	res, err := cmd.
+		Print("%(title)s\t%(uploader)s\t%(duration)s\t%(id)s\t%(filesize,filesize_approx)s").
+		NoSimulate().
+		IgnoreConfig().
+		NoWarnings().
+		Run(ctx, append(args, u)...)
+
+	if err != nil {
+		stderr := strings.ToLower(res.Stderr)
+		if strings.Contains(stderr, "drm") {
+			return "", "", "", 0, 0, fmt.Errorf("DRM: %w", err)
+		}
+		return "", "", "", 0, 0, err
+	}
+	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
+	for _, l := range ls {
+		ps := strings.Split(l, "\t")
+		if len(ps) < 4 {
+			continue
+		}
+		d, _ := time.ParseDuration(ps[2] + "s")
+		sz := int64(0)
+		if len(ps) >= 5 {
+			fmt.Sscanf(ps[4], "%d", &sz)
+		}
+		return ps[0], ps[1], ps[3], d, sz, nil
+	}
+	return "", "", "", 0, 0, errors.New("failed to resolve metadata")
+}
+
+func (s *VoiceSession) enrichTrackMetadata(ctx context.Context, t *Track) {
+	t.mu.Lock()
+	if t.Enriched || t.URL == "" || t.Local || t.Live {
+		t.mu.Unlock()
+		return
+	}
+	u := t.URL
+	t.mu.Unlock()
+
+	ectx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	args := append(buildYtdlpArgs(), "--skip-download", "--get-thumbnail")
+	res, err := cmd.Run(ectx, append(args, u)...)
+	if err != nil {
+		return
+	}
+
+	thumb := strings.TrimSpace(res.Stdout)
+	if thumb != "" {
+		t.mu.Lock()
+		t.ArtworkURL = thumb
+		t.Enriched = true
+		t.mu.Unlock()
+	}
+}
+
+func ytdlpExtractPlaylist(ctx context.Context, u string, m int) ([]SourceResult, error) {
+	cmd, cleanup := newYtdlp()
+	defer cleanup()
+
+	args := buildYtdlpArgs()
+	res := cmd.
+		FlatPlaylist().
+		Print("%(url)s\t%(title)s\t%(uploader)s\t%(id)s").
+		PlaylistItems(fmt.Sprintf("1-%d", m)).
+		NoWarnings().
+		IgnoreConfig().
+		BuildCommand(ctx, append(args, u, "--yes-playlist")...)
+
+	var stdout, stderr bytes.Buffer
+	res.Stdout = &stdout
+	res.Stderr = &stderr
+
+	if err := res.Run(); err != nil {
+		return nil, fmt.Errorf("yt-dlp playlist failed: %w, stderr: %s", err, stderr.String())
+	}
+
+	rawOutput := strings.TrimSpace(stdout.String())
+	ls := strings.Split(rawOutput, "\n")
+
+	es := make([]SourceResult, 0)
+	isYouTube := isYouTubeURL(u) || strings.Contains(u, "music.youtube.com")
+
+	for _, l := range ls {
+		ps := strings.Split(l, "\t")
+		if len(ps) < 3 {
+			continue
+		}
+		url := ps[0]
+		title := ps[1]
+		uploader := ps[2]
+
+		if isYouTube && len(ps) >= 4 {
+			id := ps[3]
+			if id != "" && id != "NA" {
+				url = "https://www.youtube.com/watch?v=" + id
+			}
+		}
+
+		es = append(es, SourceResult{URL: url, Title: title, Uploader: uploader})
+	}
+	return es, nil
+}
+
+func extractMetadataFromDRMSite(ctx context.Context, url string) (title, artist string, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	body := new(strings.Builder)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Split(bufio.ScanLines)
+	linesRead := 0
+	for scanner.Scan() && linesRead < 500 {
+		body.WriteString(scanner.Text())
+		body.WriteString(" ")
+		linesRead++
+		if strings.Contains(scanner.Text(), "</head>") {
+			break
+		}
+	}
+
+	htmlContent := body.String()
+
+	titleRegex := regexp.MustCompile(`<meta[^>]*property=["']og:title["'][^>]*content=["']([^"']+)["']`)
+	if matches := titleRegex.FindStringSubmatch(htmlContent); len(matches) > 1 {
+		title = matches[1]
+		if idx := strings.Index(title, " - song and lyrics by"); idx != -1 {
+			title = title[:idx]
+		}
+		if idx := strings.Index(title, " | Spotify"); idx != -1 {
+			title = title[:idx]
+		}
+	}
+
+	descRegex := regexp.MustCompile(`<meta[^>]*property=["']og:description["'][^>]*content=["']([^"']+)["']`)
+	if matches := descRegex.FindStringSubmatch(htmlContent); len(matches) > 1 {
+		desc := matches[1]
+		if strings.Contains(strings.ToLower(url), "spotify") {
+			parts := strings.Split(desc, " · ")
+			if len(parts) >= 1 {
+				artist = strings.TrimSpace(parts[0])
+			}
+		}
+	}
+
+	if title == "" {
+		return "", "", errors.New("could not extract metadata")
+	}
+
+	return title, artist, nil
+}
+
+// ===========================
+// Command Handlers
+// ===========================
+
+func handleMusicPlay(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	q, m, p, a, l := parsePlayArguments(data)
+	if !mustAllowQueueMode(event, m) {
+		return
+	}
+
+	if att, ok := data.OptAttachment("file"); ok {
+		if att.ContentType == nil || !(strings.HasPrefix(*att.ContentType, "audio/") || strings.HasPrefix(*att.ContentType, "video/")) {
+			_ = RespondInteractionV2(*event.Client(), event, "That file isn't audio.", true)
+			return
+		}
+		q = att.URL
+	} else if lv, ok := data.OptString("local"); ok && lv != "" {
+		ref, err := resolveLocalQuery(lv)
+		if err != nil {
+			_ = RespondInteractionV2(*event.Client(), event, "Can't play that: "+err.Error()+".", true)
+			return
+		}
+		q = ref
+	}
+	if q == "" {
+		_ = RespondInteractionV2(*event.Client(), event, "Give a query, a file or a local track to play.", true)
+		return
+	}
+
+	if _, ok := mustGetUserVoiceState(event); !ok {
+		return
+	}
+
+	_ = event.DeferCreateMessage(false)
+	if strings.HasPrefix(strings.ToUpper(q), "[PL]") {
+		qBody := strings.TrimSpace(q[4:])
+		if qBody != "" && !strings.Contains(qBody, "http") {
+			rs, err := GetVoiceManager().SearchPlaylist(qBody)
+			if err == nil && len(rs) > 0 {
+				q = rs[0].URL
+			}
+		}
+	}
+
+	if err := startPlayback(event, q, m, a, l, p); err != nil {
+		_ = EditInteractionV2(*event.Client(), event, "Failed: "+err.Error())
+	}
+}
+
+// parsePlayArguments reads the play options; a and l are nil unless autoplay or loop was given
+func parsePlayArguments(data discord.SlashCommandInteractionData) (q, m string, p int, a, l *bool) {
+	q, _ = data.OptString("query")
+	qv, _ := data.OptString("queue")
+	if v, ok := data.OptBool("autoplay"); ok {
+		a = &v
+	}
+	if v, ok := data.OptBool("loop"); ok {
+		l = &v
+	}
+
+	if qv == "now" {
+		m = "now"
+	} else if qv == "next" {
+		m = "next"
+	} else if qv != "" {
+		p, _ = strconv.Atoi(qv)
+	}
+	return
+}
+
+func handleMusicStop(event *events.ApplicationCommandInteractionCreate, _ discord.SlashCommandInteractionData) {
+	LogVoice("User %s (%s) stopped playback in guild %s", event.User().Username, event.User().ID, *event.GuildID())
+	GetVoiceManager().StopRadio(*event.GuildID())
+	GetVoiceManager().Leave(context.Background(), *event.GuildID())
+	_ = RespondInteractionV2(*event.Client(), event, "🛑 Stopped and disconnected.", false)
+}
+
+func handleMusicQueue(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
+	}
+	_ = event.DeferCreateMessage(true)
+
+	page, _ := data.OptInt("page")
+	if err := EditInteractionContainerV2(*event.Client(), event, BuildQueueContainer(s, page-1)); err != nil {
+		LogWarn("Failed to edit interaction: %v", err)
+	}
+}
+
+func handleMusicShuffle(event *events.ApplicationCommandInteractionCreate) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
+	}
+	n, err := s.Shuffle()
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to shuffle: %v", err), true)
+		return
+	}
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("🔀 Shuffled **%d** tracks.", n), false)
+}
+
+func handleMusicRemove(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
+	}
+	from, to, err := parseQueueRange(data.String("position"))
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, "Invalid position (use a number like 3 or a range like 2-5).", true)
+		return
+	}
+	removed, err := s.Remove(from, to)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to remove: %v", err), true)
+		return
+	}
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	if len(removed) == 1 {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("🗑️ Removed: %s", removed[0].FullDisplay()), false)
+		return
+	}
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("🗑️ Removed **%d** tracks.", len(removed)), false)
+}
+
+func handleMusicMove(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
+	}
+	to := data.Int("to")
+	t, err := s.Move(data.Int("from"), to)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to move: %v", err), true)
+		return
+	}
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("↕️ Moved %s to position **%d**.", t.FullDisplay(), to), false)
+}
+
+func handleMusicClear(event *events.ApplicationCommandInteractionCreate) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
+	}
+	n := s.Clear()
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("🧹 Cleared **%d** tracks from the queue.", n), false)
+}
+
+func handleMusicSkipTo(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
+	}
+	t, err := s.SkipTo(data.Int("position"))
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to skip: %v", err), true)
+		return
+	}
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("⏭️ Skipping to: %s", t.FullDisplay()), false)
+}
+
+// parseQueueRange reads a single position ("3") or an inclusive range ("2-5")
+func parseQueueRange(spec string) (int, int, error) {
+	first, last, isRange := strings.Cut(strings.TrimSpace(spec), "-")
+	from, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return from, from, nil
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(last))
+	if err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+// BuildQueueContainer renders one page (0-based, clamped) of the queue with buttons to flip between pages
+func BuildQueueContainer(s *VoiceSession, page int) Container {
+	s.lockQueue()
+	defer s.unlockQueue()
+
+	var components []any
+
+	if s.currentTrack != nil {
+		components = append(components, s.buildTrackComponents(s.currentTrack, "Now Playing:")...)
+		components = append(components, NewSeparator(true))
+	}
+
+	pages := max(1, (len(s.queue)+QueuePageSize-1)/QueuePageSize)
+	page = max(0, min(page, pages-1))
+
+	components = append(components, NewTextDisplay("**Queue:**"))
+	if len(s.queue) == 0 {
+		msg := "_Empty_"
+		if s.autoplaying() && s.autoplayTrack != nil {
+			msg = "_Empty (Autoplay Ready)_"
+		}
+		components = append(components, NewTextDisplay(msg))
+	} else {
+		var qList strings.Builder
+		start := page * QueuePageSize
+		end := min(start+QueuePageSize, len(s.queue))
+		for i, t := range s.queue[start:end] {
+			qList.WriteString(fmt.Sprintf("`%d.` %s\n", start+i+1, t.FullDisplay()))
+		}
+		components = append(components, NewTextDisplay(qList.String()))
+		components = append(components, NewTextDisplay(fmt.Sprintf("-# Page %d/%d · %d tracks", page+1, pages, len(s.queue))))
+	}
+
+	if s.autoplaying() {
+		label := "**Autoplay:** Enabled"
+		if s.radio != nil {
+			label = "**Radio:** On"
+		}
+		components = append(components, NewSeparator(true))
+		components = append(components, NewTextDisplay(label))
+		if s.autoplayTrack != nil {
+			components = append(components, s.buildTrackComponents(s.autoplayTrack, "Next Up (Autoplay):")...)
+		}
+	}
+
+	if pages > 1 {
+		components = append(components, discord.NewActionRow(
+			// Custom IDs must be unique within a message, so each button names itself as well as its target page
+			discord.NewButton(discord.ButtonStyleSecondary, "⏮️ First", "voice:queue:first:0", "", 0).WithDisabled(page == 0),
+			discord.NewButton(discord.ButtonStyleSecondary, "◀️ Prev", fmt.Sprintf("voice:queue:prev:%d", page-1), "", 0).WithDisabled(page == 0),
+			discord.NewButton(discord.ButtonStyleSecondary, "Next ▶️", fmt.Sprintf("voice:queue:next:%d", page+1), "", 0).WithDisabled(page == pages-1),
+			discord.NewButton(discord.ButtonStyleSecondary, "Last ⏭️", fmt.Sprintf("voice:queue:last:%d", pages-1), "", 0).WithDisabled(page == pages-1),
+		))
+	}
+
+	return NewV2Container(components...)
+}
+
+func handleVoicePanel(event *events.ApplicationCommandInteractionCreate) {
+	userID := event.User().ID
+	guildID := event.GuildID()
+	if guildID == nil {
+		_ = RespondInteractionV2(*event.Client(), event, "Not in a guild.", true)
+		return
+	}
+
+	VoicePanelsMu.Lock()
+	if p, ok := VoicePanels[userID]; ok {
+		if time.Now().Before(p.ExpiresAt) {
+			VoicePanelsMu.Unlock()
+			_ = RespondInteractionV2(*event.Client(), event, "You already have an active panel! Dismiss it or wait for it to expire.", true)
+			return
+		}
+		delete(VoicePanels, userID)
+	}
+	VoicePanelsMu.Unlock()
+
+	s := GetVoiceManager().GetSession(*guildID)
+	if s == nil {
+		_ = RespondInteractionV2(*event.Client(), event, "Not playing anything.", true)
+		return
+	}
+
+	_ = event.DeferCreateMessage(true)
+
+	VoicePanelsMu.Lock()
+	VoicePanels[userID] = &VoicePanel{
+		UserID:    userID,
+		GuildID:   *guildID,
+		Token:     event.Token(),
+		AppID:     event.ApplicationID(),
+		ExpiresAt: time.Now().Add(15 * time.Minute),
+	}
+	VoicePanelsMu.Unlock()
+
+	UpdateVoicePanels(*guildID, *event.Client())
+}
+
+func handleVoiceFilter(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
+	}
+	preset, _ := data.OptString("preset")
+	tempo, ok := data.OptFloat("tempo")
+	if !ok {
+		tempo = 1
+	}
+	pitch, ok := data.OptFloat("pitch")
+	if !ok {
+		pitch = 1
+	}
+
+	f := &AudioFilter{Preset: preset, Tempo: tempo, Pitch: pitch}
+	s.SetFilter(f)
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	msg := fmt.Sprintf("🎛️ Filter set to **%s**.", f)
+	if s.crossfadeFiltered() {
+		msg += " " + crossfadeFilteredNote
+	}
+	_ = RespondInteractionV2(*event.Client(), event, msg, false)
+}
+
+func handleVoiceCrossfade(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
+	}
+	if secs, ok := data.OptInt("seconds"); ok {
+		s.Crossfade.Store(int64(time.Duration(secs) * time.Second))
+	}
+	if gapless, ok := data.OptBool("gapless"); ok {
+		s.Gapless.Store(gapless)
+	}
+
+	msg := "🌊 Tracks play with a short pause between them."
+	if cf := time.Duration(s.Crossfade.Load()); cf > 0 {
+		msg = fmt.Sprintf("🌊 Crossfading **%ds** between tracks.", int(cf.Seconds()))
+		if s.crossfadeFiltered() {
+			msg += " " + crossfadeFilteredNote
+		}
+	} else if s.Gapless.Load() {
+		msg = "🌊 Tracks play back-to-back without a pause."
+	}
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	_ = RespondInteractionV2(*event.Client(), event, msg, false)
+}
+
+const crossfadeFilteredNote = "Crossfade is skipped while a filter is on, so tracks play back-to-back instead; `/voice filter` with no preset turns it off."
+
+// crossfadeFiltered reports whether crossfade is set but held off by an active filter, which the mixed-in head of
+// the next track would bypass
+func (s *VoiceSession) crossfadeFiltered() bool {
+	return s.Crossfade.Load() > 0 && s.Filter.Load() != nil
+}
+
+// gapless reports whether tracks should follow each other without the trailing silence
+func (s *VoiceSession) gapless() bool {
+	return s.Gapless.Load() || s.Crossfade.Load() > 0
+}
+
+// attachLoudness seeds the transcoder with the track's known loudness, or meters it and saves the measurement
+func (s *VoiceSession) attachLoudness(t *AstiavTranscoder, track *Track) {
+	if track != nil {
+		if lufs := trackLoudness(track); lufs != 0 {
+			t.SetLoudness(lufs)
+		}
+		t.OnLoudness = func(lufs float64) {
+			track.mu.Lock()
+			track.Loudness = lufs
+			url := track.URL
+			track.mu.Unlock()
+			LogVoice("Measured loudness %.1f LUFS for %s", lufs, url)
+			if id := extractVideoID(url); id != "" {
+				writeLoudnessCache(id, lufs)
+			}
+		}
+	}
+	t.setupLoudness()
+}
+
+// prepareCrossfade opens the track that plays next in pull mode so the current transcoder can mix it into the fade;
+// it gives up on anything not fully on disk, since a still-downloading stream has a single reader
+func (s *VoiceSession) prepareCrossfade() *crossfadeSource {
+	s.lockQueue()
+	var next *Track
+	if !s.Looping {
+		if len(s.queue) > 0 {
+			next = s.queue[0]
+		} else if s.autoplaying() {
+			next = s.autoplayTrack
+		}
+	}
+	old := s.crossfadeNext
+	s.crossfadeNext = nil
+	s.unlockQueue()
+	old.discard()
+
+	if next == nil {
+		return nil
+	}
+	next.mu.Lock()
+	path, ready := next.Path, next.Downloaded && next.LiveStream == nil && !next.Live && next.Error == nil
+	next.mu.Unlock()
+	if !ready || path == "" {
+		return nil
+	}
+
+	tr := NewAstiavTranscoder()
+	err := tr.OpenInput(path, nil)
+	if err == nil {
+		err = tr.SetupDecoder()
+	}
+	if err == nil {
+		err = tr.SetupEncoder()
+	}
+	if err == nil {
+		err = tr.allocFifo()
+	}
+	if err != nil {
+		LogVoice("Crossfade skipped, could not open next track %s: %v", path, err)
+		tr.Close()
+		return nil
+	}
+	tr.volume = &s.Volume
+	tr.pull = true
+	s.attachLoudness(tr, next)
+
+	c := &crossfadeSource{track: next, tr: tr}
+	s.lockQueue()
+	s.crossfadeNext = c
+	s.unlockQueue()
+	return c
+}
+
+// takeCrossfade claims the decoder prepared for track, discarding one prepared for a track that is no longer next
+func (s *VoiceSession) takeCrossfade(track *Track) *AstiavTranscoder {
+	s.lockQueue()
+	c := s.crossfadeNext
+	s.crossfadeNext = nil
+	s.unlockQueue()
+	if c == nil {
+		return nil
+	}
+	if c.track != track {
+		c.discard()
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.taken {
+		return nil
+	}
+	c.taken = true
+	return c.tr
+}
+
+func BuildVoicePanelContainer(s *VoiceSession, showLyrics bool) Container {
+	s.lockQueue()
+	defer s.unlockQueue()
+
+	var components []any
+
+	if s.currentTrack != nil {
+		components = append(components, s.buildTrackComponents(s.currentTrack, "Now Playing:")...)
+		if showLyrics {
+			components = append(components, NewSeparator(true), NewTextDisplay(s.lyricsTextLocked()))
+		}
+	} else {
+		components = append(components, NewTextDisplay("**Nothing is currently playing.**"))
+	}
+
+	paused := s.IsPaused()
+	statusEmoji, statusText := "⏸️", "Playing"
+	if paused {
+		statusEmoji, statusText = "▶️", "Paused"
+	}
+
+	options := ""
+	if s.Looping {
+		options += " 🔄 Loop"
+	}
+	if s.Autoplay {
+		options += " 🔀 Autoplay"
+	}
+	if s.radio != nil {
+		options += " 📻 Radio"
+	}
+
+	filter := s.Filter.Load()
+	if filter != nil {
+		options += " 🎛️ " + filter.String()
+	}
+	if cf := time.Duration(s.Crossfade.Load()); cf > 0 && filter != nil {
+		options += fmt.Sprintf(" 🌊 Crossfade %ds (off while filtered)", int(cf.Seconds()))
+	} else if cf > 0 {
+		options += fmt.Sprintf(" 🌊 Crossfade %ds", int(cf.Seconds()))
+	} else if s.Gapless.Load() {
+		options += " 🌊 Gapless"
+	}
+
+	components = append(components, NewTextDisplay(fmt.Sprintf("**Status:** %s %s | **Volume:** %d%% %s", statusEmoji, statusText, s.Volume.Load(), options)))
+	components = append(components, NewTextDisplay(fmt.Sprintf("**Queue:** %d tracks remaining", len(s.queue))))
+
+	components = append(components, NewSeparator(true))
+
+	playPauseLabel := "⏸️ Pause"
+	if paused {
+		playPauseLabel = "▶️ Resume"
+	}
+
+	row1 := discord.NewActionRow(
+		discord.NewButton(discord.ButtonStyleSecondary, playPauseLabel, "voice:panel:playpause", "", 0),
+		discord.NewButton(discord.ButtonStyleSecondary, "⏭️ Skip", "voice:panel:skip", "", 0),
+		discord.NewButton(discord.ButtonStyleSecondary, "⏹️ Stop", "voice:panel:stop", "", 0),
+		discord.NewButton(discord.ButtonStyleDanger, "❌ Close", "voice:panel:close", "", 0),
+	)
+
+	lyricsStyle := discord.ButtonStyleSecondary
+	if showLyrics {
+		lyricsStyle = discord.ButtonStyleSuccess
+	}
+	row2 := discord.NewActionRow(
+		discord.NewButton(discord.ButtonStylePrimary, "🔄 Loop", "voice:panel:loop", "", 0),
+		discord.NewButton(discord.ButtonStylePrimary, "🔀 Autoplay", "voice:panel:autoplay", "", 0),
+		discord.NewButton(discord.ButtonStyleSecondary, "➖ Vol", "voice:panel:voldown", "", 0),
+		discord.NewButton(discord.ButtonStyleSecondary, "➕ Vol", "voice:panel:volup", "", 0),
+		discord.NewButton(lyricsStyle, "🎤 Lyrics", "voice:panel:lyrics", "", 0),
+	)
+
+	current := "off"
+	if filter != nil {
+		current = filter.Preset
+	}
+	filterOptions := make([]discord.StringSelectMenuOption, 0, len(AudioFilterPresets))
+	for _, p := range AudioFilterPresets {
+		filterOptions = append(filterOptions, discord.NewStringSelectMenuOption(p.Label, p.Name).WithDefault(p.Name == current))
+	}
+	row3 := discord.NewActionRow(discord.NewStringSelectMenu("voice:panel:filter", "🎛️ Filter", filterOptions...))
+
+	components = append(components, row1, row2, row3)
+
+	return NewV2Container(components...)
+}
+
+func (s *VoiceSession) IsPaused() bool {
+	s.pauseMu.RLock()
+	defer s.pauseMu.RUnlock()
+	select {
+	case <-s.pauseChan:
+		return false
+	default:
+		return true
+	}
+}
+
+func (s *VoiceSession) TogglePause() bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	isClosed := false
+	select {
+	case <-s.pauseChan:
+		isClosed = true
+	default:
+	}
+	if isClosed {
+		s.pauseChan = make(chan struct{})
+		return true // Now paused
+	} else {
+		close(s.pauseChan)
+		return false // Now playing
+	}
+}
+
+func UpdateVoicePanels(guildID snowflake.ID, cl bot.Client) {
+	VoicePanelsMu.Lock()
+	defer VoicePanelsMu.Unlock()
+
+	s := GetVoiceManager().GetSession(guildID)
+
+	var container, lyricsContainer Container
+	hasLyricsContainer := false
+	if s == nil {
+		container = NewV2Container(NewTextDisplay("The music session has ended."), discord.NewActionRow(discord.NewButton(discord.ButtonStyleDanger, "❌ Close", "voice:panel:close", "", 0)))
+	} else {
+		s.SetClient(cl)
+		container = BuildVoicePanelContainer(s, false)
+	}
+
+	now := time.Now()
+	for userID, panel := range VoicePanels {
+		if panel.GuildID != guildID {
+			continue
+		}
+		if now.After(panel.ExpiresAt) {
+			delete(VoicePanels, userID)
+			continue
+		}
+
+		c := container
+		if s != nil && panel.Lyrics {
+			if !hasLyricsContainer {
+				lyricsContainer, hasLyricsContainer = BuildVoicePanelContainer(s, true), true
+			}
+			c = lyricsContainer
+			panel.lyricsKey = s.lyricsKey()
+		}
+		safeGo(func() {
+			func(token string, appID snowflake.ID, c Container, client bot.Client) {
+				_ = EditInteractionContainerV2ByToken(client, appID, token, c)
+			}(panel.Token, panel.AppID, c, cl)
+		})
+
+		if s == nil {
+			delete(VoicePanels, userID)
+		}
+	}
+}
+
+func handleVoiceComponent(event *events.ComponentInteractionCreate) {
+	customID := event.Data.CustomID()
+	if page, ok := strings.CutPrefix(customID, "voice:queue:"); ok {
+		handleVoiceQueuePage(event, page)
+		return
+	}
+	if !strings.HasPrefix(customID, "voice:panel:") {
+		return
+	}
+
+	action := strings.TrimPrefix(customID, "voice:panel:")
+	guildID := event.GuildID()
+	if guildID == nil {
+		return
+	}
+
+	switch action {
+	case "close":
+		VoicePanelsMu.Lock()
+		delete(VoicePanels, event.User().ID)
+		VoicePanelsMu.Unlock()
+		_ = event.UpdateMessage(discord.NewMessageUpdate().WithContent("Panel closed.").ClearComponents())
+		return
+	}
+
+	s := GetVoiceManager().GetSession(*guildID)
+	if s == nil {
+		_ = event.CreateMessage(discord.NewMessageCreate().WithContent("Music session is no longer active.").WithEphemeral(true))
+		return
+	}
+	dj := isVoiceDJ(*event.Client(), event, s)
+	if voiceDJPanelActions[action] && !dj {
+		_ = event.CreateMessage(discord.NewMessageCreate().WithContent("Only DJs can do that.").WithEphemeral(true))
+		return
+	}
+	if action == "skip" && !dj {
+		msg, passed := s.castSkipVote(*event.Client(), event.User().ID)
+		if !passed {
+			_ = event.CreateMessage(discord.NewMessageCreate().WithContent(msg).WithEphemeral(true))
+			return
+		}
+		action = ""
+	}
+
+	switch action {
+	case "playpause":
+		s.TogglePause()
+		s.RefreshStatus()
+	case "skip":
+		_, _ = s.Skip()
+	case "stop":
+		GetVoiceManager().StopRadio(*guildID)
+		GetVoiceManager().Leave(context.Background(), *guildID)
+	case "loop":
+		s.lockQueue()
+		s.Looping = !s.Looping
+		s.unlockQueue()
+	case "autoplay":
+		s.lockQueue()
+		s.Autoplay = !s.Autoplay
+		s.unlockQueue()
+	case "volup":
+		v := s.Volume.Load()
+		if v < 200 {
+			s.Volume.Store(v + 10)
+		}
+	case "voldown":
+		v := s.Volume.Load()
+		if v > 0 {
+			s.Volume.Store(v - 10)
+		}
+	case "lyrics":
+		VoicePanelsMu.Lock()
+		if p, ok := VoicePanels[event.User().ID]; ok {
+			p.Lyrics = !p.Lyrics
+		}
+		VoicePanelsMu.Unlock()
+	case "filter":
+		// The panel swaps the preset but keeps any custom tempo and pitch from /voice filter
+		f := &AudioFilter{Tempo: 1, Pitch: 1}
+		if cur := s.Filter.Load(); cur != nil {
+			f.Tempo, f.Pitch = cur.Tempo, cur.Pitch
+		}
+		if values := event.StringSelectMenuInteractionData().Values; len(values) > 0 {
+			f.Preset = values[0]
+		}
+		s.SetFilter(f)
+	}
+
+	UpdateVoicePanels(*guildID, *event.Client())
+	_ = event.DeferUpdateMessage()
+}
+
+// handleVoiceQueuePage flips the queue view to another page
+func handleVoiceQueuePage(event *events.ComponentInteractionCreate, page string) {
+	guildID := event.GuildID()
+	if guildID == nil {
+		return
+	}
+	s := GetVoiceManager().GetSession(*guildID)
+	if s == nil {
+		_ = UpdateInteractionContainerV2(*event.Client(), event, NewV2Container(NewTextDisplay("Music session is no longer active.")))
+		return
+	}
+	// page is "<button>:<n>"; only the page number matters
+	n, _ := strconv.Atoi(page[strings.LastIndexByte(page, ':')+1:])
+	_ = UpdateInteractionContainerV2(*event.Client(), event, BuildQueueContainer(s, n))
+}
+
+// handleMusicAutocomplete handles autocomplete interactions for music commands.
+func handleMusicAutocomplete(event *events.AutocompleteInteractionCreate) {
+	f := event.Data.Focused()
+	if g := event.Data.SubCommandGroupName; g != nil && *g == "station" && f.Name == "name" {
+		autocompleteStationNames(event)
+		return
+	}
+	if f.Name == "name" || f.Name == "playlist" {
+		autocompletePlaylistNames(event)
+		return
+	}
+	if f.Name == "local" {
+		var cs []discord.AutocompleteChoice
+		for _, e := range musicLibrary.Search(f.String(), 25) {
+			label := "🎵 " + e
+			if strings.HasSuffix(e, "/") {
+				label = "📁 " + e
+			}
+			if len(e) > 100 {
+				continue
+			}
+			cs = append(cs, discord.AutocompleteChoiceString{Name: truncateLeft(label, 100), Value: e})
+		}
+		_ = event.AutocompleteResult(cs)
+		return
+	}
+	if f.Name == "queue" {
+		v := f.String()
+		cs := []discord.AutocompleteChoice{
+			discord.AutocompleteChoiceString{Name: "Play Now", Value: "now"},
+			discord.AutocompleteChoiceString{Name: "Play Next", Value: "next"},
+		}
+		if v != "" {
+			if _, err := strconv.Atoi(v); err == nil {
+				cs = append([]discord.AutocompleteChoice{discord.AutocompleteChoiceString{Name: "Position: " + v, Value: v}}, cs...)
+			}
+		}
+		_ = event.AutocompleteResult(cs)
+		return
+	}
+	if f.Name != "query" {
+		return
+	}
+	q := f.String()
+	if q == "" {
+		q = getRandomRecommendation(event.GuildID())
+	} else if strings.Contains(q, "http") {
+		_ = event.AutocompleteResult(nil)
+		return
+	}
+	var rs []SearchResult
+	var err error
+	if strings.HasPrefix(strings.ToUpper(q), "[PL]") {
+		if qBody := strings.TrimSpace(q[4:]); qBody != "" {
+			if ytdlpRS, searchErr := GetVoiceManager().SearchPlaylist(qBody); searchErr == nil {
+				for _, entry := range ytdlpRS {
+					rs = append(rs, SearchResult{
+						Title:       entry.Title,
+						ChannelName: entry.Uploader,
+						URL:         entry.URL,
+					})
+				}
+			} else {
+				err = searchErr
+			}
+		}
+	} else {
+		rs, err = GetVoiceManager().Search(q)
+	}
+
+	if err != nil {
+		_ = event.AutocompleteResult(nil)
+		return
+	}
+
+	var cs []discord.AutocompleteChoice
+	for i, r := range rs {
+		if i >= 25 {
+			break
+		}
+		n, v := r.Title, r.URL
+		if len(n) > 100 {
+			n = n[:97] + "..."
+		}
+		if len(v) > 100 {
+			v = r.Title
+			if len(v) > 100 {
+				v = v[:100]
+			}
+		}
+		cs = append(cs, discord.AutocompleteChoiceString{Name: n, Value: v})
+	}
+	_ = event.AutocompleteResult(cs)
+}
+
+func getRandomRecommendation(guildID *snowflake.ID) string {
+	if guildID != nil {
+		if s := GetVoiceManager().GetSession(*guildID); s != nil {
+			s.lockQueue()
+			defer s.unlockQueue()
+			l := len(s.HistoryTitles)
+			if l > 0 {
+				idx := l - 1
+				if l > 5 {
+					idx = l - 1 - (int(time.Now().UnixNano()/1000) % 5)
+				} else {
+					idx = int(time.Now().UnixNano()/1000) % l
+				}
+				if len(s.HistoryAuthors) > idx {
+					author := s.HistoryAuthors[idx]
+					if author != "" && author != "NA" {
+						return "Mix - " + author
+					}
+				}
+				return "Mix - " + s.HistoryTitles[idx]
+			}
+		}
+	}
+	return "Trending Music"
+}
+
+func startPlayback(ev *events.ApplicationCommandInteractionCreate, q, m string, a, l *bool, p int) error {
+	LogVoice("User %s (%s) requested playback: %s", ev.User().Username, ev.User().ID, q)
+	vs, ok := mustGetUserVoiceState(ev)
+	if !ok {
+		return errors.New("user not in a voice channel")
+	}
+	vm := GetVoiceManager()
+	s := vm.Prepare(*ev.Client(), *ev.GuildID(), *vs.ChannelID)
+	// Autoplay and loop are DJ controls, as on the panel, so anyone else's choice is ignored
+	if (a != nil || l != nil) && isVoiceDJ(*ev.Client(), ev, s) {
+		s.lockQueue()
+		if a != nil {
+			s.Autoplay = *a
+		}
+		if l != nil {
+			s.Looping = *l
+		}
+		s.unlockQueue()
+	}
+	je := make(chan error, 1)
+	safeGo(func() { je <- vm.Join(context.Background(), *ev.Client(), *ev.GuildID(), *vs.ChannelID) })
+	t, count, err := vm.Play(context.Background(), *ev.GuildID(), q, m, p, ev.User().ID)
+	if err != nil {
+		return err
+	}
+	if err := <-je; err != nil {
+		return err
+	}
+
+	err = finishPlaybackResponse(ev, t, m, s.Autoplay, s.Looping, p, count)
+	UpdateVoicePanels(*ev.GuildID(), *ev.Client())
+	return err
+}
+
+func finishPlaybackResponse(ev *events.ApplicationCommandInteractionCreate, t *Track, m string, a, l bool, p int, count int) error {
+	build := func() (string, string) {
+		pr := "Added to queue:"
+		if count > 1 {
+			pr = fmt.Sprintf("📂 Added **%d** tracks from playlist to queue:", count)
+			switch m {
+			case "now":
+				pr = fmt.Sprintf("▶️ Playing Now (Cleared queue and added **%d** tracks from playlist):", count)
+			case "next":
+				pr = fmt.Sprintf("⏭️ Added **%d** tracks to play next:", count)
+			}
+		} else {
+			if m == "next" {
+				pr = "⏭️ Next up:"
+			} else if m == "now" {
+				pr = "▶️ Playing Now (Skipped Current):"
+			} else if p > 0 {
+				pr = "Added to queue at position " + strconv.Itoa(p) + ":"
+			}
+		}
+
+		var ss []string
+		if a {
+			ss = append(ss, "Autoplay")
+		}
+		if l {
+			ss = append(ss, "Looping")
+		}
+		suffix := ""
+		if len(ss) > 0 {
+			suffix = " (" + strings.Join(ss, ", ") + ": Enabled)"
+		}
+
+		content := fmt.Sprintf("%s %s%s", pr, t.FullDisplay(), suffix)
+		t.mu.Lock()
+		art := t.ArtworkURL
+		t.mu.Unlock()
+		return content, art
+	}
+
+	content, art := build()
+	var err error
+	if art != "" {
+		err = EditInteractionContainerV2(*ev.Client(), ev, NewV2Container(NewTextDisplay(content), NewMediaGallery(art)))
+	} else {
+		err = EditInteractionV2(*ev.Client(), ev, content)
+	}
+
+	select {
+	case <-t.MetadataReady:
+	default:
+		safeGo(func() {
+			select {
+			case <-t.MetadataReady:
+				c, a := build()
+				if a != "" {
+					_ = EditInteractionContainerV2(*ev.Client(), ev, NewV2Container(NewTextDisplay(c), NewMediaGallery(a)))
+				} else {
+					_ = EditInteractionV2(*ev.Client(), ev, c)
+				}
+			case <-time.After(15 * time.Second):
+			}
+		})
 	}
-	return rs, nil
+	return err
 }
 
-func ytdlpSearchPlaylistYTM(ctx context.Context, q string, m int) ([]ytdlpSearchResult, error) {
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
-
-	searchURL := fmt.Sprintf("https://music.youtube.com/search?q=%s&filter=playlists", url.QueryEscape(q))
-
-	args := buildYtdlpArgs()
-	res, err := cmd.
-		FlatPlaylist().
-		Print("%(url)s\t%(title)s\t%(uploader)s").
-		PlaylistItems(fmt.Sprintf("1-%d", m)).
-		NoWarnings().
-		IgnoreConfig().
-		Run(ctx, append(args, searchURL)...)
+// ===========================
+// Playlists
+// ===========================
 
-	if err != nil {
-		return nil, err
+func handleVoicePlaylist(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	if event.GuildID() == nil {
+		_ = RespondInteractionV2(*event.Client(), event, "Not in a guild.", true)
+		return
 	}
-	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
-	rs := make([]ytdlpSearchResult, 0, len(ls))
-	for _, l := range ls {
-		ps := strings.Split(l, "\t")
-		if len(ps) < 3 || ps[1] == "" || ps[1] == "NA" {
-			continue
-		}
-		rs = append(rs, ytdlpSearchResult{URL: ps[0], Title: ps[1], Uploader: ps[2]})
+	switch *data.SubCommandName {
+	case "save":
+		handlePlaylistSave(event, data)
+	case "load":
+		handlePlaylistLoad(event, data)
+	case "list":
+		handlePlaylistList(event, data)
+	case "delete":
+		handlePlaylistDelete(event, data)
+	case "share":
+		handlePlaylistShare(event, data)
 	}
-	return rs, nil
 }
 
-func ytdlpExtractMetadata(ctx context.Context, u string) (*ytdlpMetadata, error) {
-	u = strings.Replace(u, "music.youtube.com", "www.youtube.com", 1)
-
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
-
-	args := buildYtdlpArgs()
-	args = append(args, "-f", "bestaudio[ext=webm]/bestaudio[ext=m4a]/bestaudio/best")
-	res, err := cmd.
-		Print("%(url)s\t%(title)s\t%(uploader)s\t%(duration)s\t%(id)s\t%(filename)s").
-		Output(filepath.Join(AudioCacheDir, "%(id)s.%(ext)s")).
-		NoWarnings().
-		IgnoreConfig().
-		Run(ctx, append(args, "--skip-download", u)...)
-
-	if err != nil {
-		LogVoice("yt-dlp metadata failed: %v, stderr: %s (URL: %s)", err, res.Stderr, u)
-		return nil, err
+// playlistOwner resolves the scope option to the scope and the ID that owns playlists in it
+func playlistOwner(event *events.ApplicationCommandInteractionCreate, scope string) (string, snowflake.ID) {
+	if scope == PlaylistScopeGuild {
+		return PlaylistScopeGuild, *event.GuildID()
 	}
+	return PlaylistScopeUser, event.User().ID
+}
 
-	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
-	for _, l := range ls {
-		ps := strings.Split(l, "\t")
-		if len(ps) < 6 {
-			continue
-		}
-		d, _ := time.ParseDuration(ps[3] + "s")
-		return &ytdlpMetadata{URL: ps[0], Title: ps[1], Uploader: ps[2], Duration: d, ID: ps[4], Filename: ps[5]}, nil
+// canManagePlaylist reports whether the user may overwrite or delete p; server playlists belong to their creator and to server managers
+func canManagePlaylist(event *events.ApplicationCommandInteractionCreate, p *Playlist) bool {
+	if p.Scope == PlaylistScopeUser || p.CreatedBy == event.User().ID {
+		return true
 	}
-	return nil, errors.New("failed to parse metadata")
+	m := event.Member()
+	return m != nil && m.Permissions.Has(discord.PermissionManageGuild)
 }
 
-func isLikelyMusicStreamingSite(url string) bool {
-	lowerURL := strings.ToLower(url)
+func playlistScopeLabel(scope string) string {
+	if scope == PlaylistScopeGuild {
+		return "server"
+	}
+	return "personal"
+}
 
-	musicPathPatterns := []string{
-		"/track/", "/tracks/",
-		"/album/", "/albums/",
-		"/song/", "/songs/",
-		"/playlist/", "/playlists/",
-		"/artist/", "/artists/",
-		"/music/",
+// SnapshotPlaylist captures the current track followed by the queue
+func (s *VoiceSession) SnapshotPlaylist() []PlaylistTrack {
+	tracks := s.snapshotTracks()
+	out := make([]PlaylistTrack, len(tracks))
+	for i, t := range tracks {
+		out[i] = t.PlaylistTrack
 	}
+	return out
+}
 
-	for _, pattern := range musicPathPatterns {
-		if strings.Contains(lowerURL, pattern) {
-			return true
+// snapshotTracks is SnapshotPlaylist with each track's requester attached
+func (s *VoiceSession) snapshotTracks() []SessionTrack {
+	s.lockQueue()
+	defer s.unlockQueue()
+
+	var tracks []SessionTrack
+	add := func(t *Track) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		switch {
+		case isDiscordAttachment(t.URL):
+			// Attachment links expire, so there would be nothing left to play by the time they were loaded
+		case t.Live:
+			tracks = append(tracks, SessionTrack{PlaylistTrack{URL: t.URL, Title: t.Station}, t.RequestedBy})
+		default:
+			tracks = append(tracks, SessionTrack{PlaylistTrack{URL: t.URL, Title: t.Title, Channel: t.Channel, Duration: t.Duration}, t.RequestedBy})
 		}
 	}
-
-	musicSubdomains := []string{
-		"music.", "play.", "listen.", "stream.",
+	if s.currentTrack != nil {
+		add(s.currentTrack)
 	}
-
-	for _, subdomain := range musicSubdomains {
-		if strings.Contains(lowerURL, "://"+subdomain) || strings.Contains(lowerURL, "://www."+subdomain) {
-			return true
+	for _, t := range s.queue {
+		if len(tracks) >= PlaylistMaxTracks {
+			break
 		}
+		add(t)
 	}
-
-	return false
+	return tracks
 }
 
-func ytdlpStream(ctx context.Context, u string, ss time.Duration, out io.Writer) (*ytdlpMetadata, error) {
-	u = strings.Replace(u, "music.youtube.com", "www.youtube.com", 1)
-
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
-
-	proxy := os.Getenv("YOUTUBE_PROXY")
-
-	args := buildYtdlpArgs()
-	args = append(args, "--ignore-config")
-	if ss > 0 {
-		args = append(args, "--ss", fmt.Sprintf("%.3f", ss.Seconds()))
+func handlePlaylistSave(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
 	}
-	execCmd := cmd.
-		Format("bestaudio[ext=webm]/bestaudio[ext=m4a]/bestaudio/best").
-		Output("-").
-		NoSimulate().
-		NoPart().
-		NoPlaylist().
-		NoCheckCertificates().
-		BuildCommand(ctx, append(args, u)...)
-
-	execCmd.Stdout = out
-	execCmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
-	if proxy != "" {
-		execCmd.Env = append(execCmd.Env, "http_proxy="+proxy, "https_proxy="+proxy, "all_proxy="+proxy)
+	name := strings.TrimSpace(data.String("name"))
+	scopeOpt, _ := data.OptString("scope")
+	scope, ownerID := playlistOwner(event, scopeOpt)
+	if name == "" {
+		_ = RespondInteractionV2(*event.Client(), event, "Playlist name can't be empty.", true)
+		return
 	}
-	execCmd.WaitDelay = 0
-
-	var stderr bytes.Buffer
-	execCmd.Stderr = &stderr
 
-	if err := execCmd.Start(); err != nil {
-		return nil, err
+	tracks := s.SnapshotPlaylist()
+	if len(tracks) == 0 {
+		_ = RespondInteractionV2(*event.Client(), event, "Nothing is queued to save.", true)
+		return
 	}
 
-	if err := execCmd.Wait(); err != nil {
-		msg := strings.ToLower(err.Error() + stderr.String())
-		if strings.Contains(msg, "broken pipe") || strings.Contains(msg, "signal: killed") {
-			return &ytdlpMetadata{}, nil
+	ctx := context.Background()
+	existing, err := GetPlaylist(ctx, scope, ownerID, name)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to save playlist: %v", err), true)
+		return
+	}
+	if existing != nil && !canManagePlaylist(event, existing) {
+		_ = RespondInteractionV2(*event.Client(), event, "Only its creator or a server manager can overwrite that playlist.", true)
+		return
+	}
+	if existing == nil {
+		if all, err := GetPlaylists(ctx, scope, ownerID); err == nil && len(all) >= PlaylistMaxPerOwner {
+			_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("There are already %d %s playlists; delete one first.", len(all), playlistScopeLabel(scope)), true)
+			return
 		}
-		LogVoice("yt-dlp stream failed for %s: %v, stderr: %s", u, err, stderr.String())
-		LogVoice("yt-dlp exited with error: %v", err)
-		return nil, err
 	}
 
-	return &ytdlpMetadata{}, nil
+	if err := SavePlaylist(ctx, scope, ownerID, event.User().ID, name, tracks); err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to save playlist: %v", err), true)
+		return
+	}
+	LogVoice("User %s (%s) saved %s playlist %q with %d tracks", event.User().Username, event.User().ID, scope, name, len(tracks))
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("💾 Saved **%d** tracks to %s playlist **%s**.", len(tracks), playlistScopeLabel(scope), name), scope == PlaylistScopeUser)
 }
 
-func ytdlpResolveMetadata(ctx context.Context, u string) (string, string, string, time.Duration, int64, error) {
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
-
-	args := append(buildYtdlpArgs(), "--skip-download")
-	res, err := cmd.
-		Print("%(title)s\t%(uploader)s\t%(duration)s\t%(id)s\t%(filesize,filesize_approx)s").
-		NoSimulate().
-		IgnoreConfig().
-		NoWarnings().
-		Run(ctx, append(args, u)...)
+func handlePlaylistLoad(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	vs, ok := mustGetUserVoiceState(event)
+	if !ok {
+		return
+	}
+	name := data.String("name")
+	scopeOpt, _ := data.OptString("scope")
+	scope, ownerID := playlistOwner(event, scopeOpt)
 
-	if err != nil {
-		stderr := strings.ToLower(res.Stderr)
-		if strings.Contains(stderr, "drm") {
-			return "", "", "", 0, 0, fmt.Errorf("DRM: %w", err)
-		}
-		return "", "", "", 0, 0, err
+	ctx := context.Background()
+	p, err := GetPlaylist(ctx, scope, ownerID, name)
+	if err != nil || p == nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("No %s playlist named **%s**.", playlistScopeLabel(scope), name), true)
+		return
 	}
-	ls := strings.Split(strings.TrimSpace(res.Stdout), "\n")
-	for _, l := range ls {
-		ps := strings.Split(l, "\t")
-		if len(ps) < 4 {
-			continue
-		}
-		d, _ := time.ParseDuration(ps[2] + "s")
-		sz := int64(0)
-		if len(ps) >= 5 {
-			fmt.Sscanf(ps[4], "%d", &sz)
-		}
-		return ps[0], ps[1], ps[3], d, sz, nil
+	saved, err := GetPlaylistTracks(ctx, p.ID)
+	if err != nil || len(saved) == 0 {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Playlist **%s** is empty.", p.Name), true)
+		return
 	}
-	return "", "", "", 0, 0, errors.New("failed to resolve metadata")
-}
-
-func (s *VoiceSession) enrichTrackMetadata(ctx context.Context, t *Track) {
-	t.mu.Lock()
-	if t.Enriched || t.URL == "" {
-		t.mu.Unlock()
+	_, mode, pos, _, _ := parsePlayArguments(data)
+	if !mustAllowQueueMode(event, mode) {
 		return
 	}
-	u := t.URL
-	t.mu.Unlock()
 
-	ectx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+	_ = event.DeferCreateMessage(false)
 
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
+	tracks := make([]*Track, 0, len(saved))
+	for _, st := range saved {
+		t := NewTrack(st.URL)
+		t.Title, t.Channel, t.Duration = st.Title, st.Channel, st.Duration
+		t.RequestedBy = event.User().ID
+		tracks = append(tracks, t)
+	}
 
-	args := append(buildYtdlpArgs(), "--skip-download", "--get-thumbnail")
-	res, err := cmd.Run(ectx, append(args, u)...)
-	if err != nil {
+	vm := GetVoiceManager()
+	vm.Prepare(*event.Client(), *event.GuildID(), *vs.ChannelID)
+	je := make(chan error, 1)
+	safeGo(func() { je <- vm.Join(context.Background(), *event.Client(), *event.GuildID(), *vs.ChannelID) })
+	if err := vm.Enqueue(*event.GuildID(), tracks, mode, pos); err != nil {
+		_ = EditInteractionV2(*event.Client(), event, "Failed: "+err.Error())
+		return
+	}
+	if err := <-je; err != nil {
+		_ = EditInteractionV2(*event.Client(), event, "Failed: "+err.Error())
 		return
 	}
 
-	thumb := strings.TrimSpace(res.Stdout)
-	if thumb != "" {
-		t.mu.Lock()
-		t.ArtworkURL = thumb
-		t.Enriched = true
-		t.mu.Unlock()
+	LogVoice("User %s (%s) loaded %s playlist %q (%d tracks)", event.User().Username, event.User().ID, scope, p.Name, len(tracks))
+	prefix := "📂 Queued"
+	switch {
+	case mode == "now":
+		prefix = "▶️ Playing now"
+	case mode == "next":
+		prefix = "⏭️ Playing next"
+	case pos > 0:
+		prefix = fmt.Sprintf("📂 Queued at position %d", pos)
 	}
+	UpdateVoicePanels(*event.GuildID(), *event.Client())
+	_ = EditInteractionV2(*event.Client(), event, fmt.Sprintf("%s: **%s** (%d tracks · %s)", prefix, p.Name, len(tracks), FormatDuration(p.Duration)))
 }
 
-func ytdlpExtractPlaylist(ctx context.Context, u string, m int) ([]ytdlpPlaylistEntry, error) {
-	cmd, cleanup := newYtdlp()
-	defer cleanup()
-
-	args := buildYtdlpArgs()
-	res := cmd.
-		FlatPlaylist().
-		Print("%(url)s\t%(title)s\t%(uploader)s\t%(id)s").
-		PlaylistItems(fmt.Sprintf("1-%d", m)).
-		NoWarnings().
-		IgnoreConfig().
-		BuildCommand(ctx, append(args, u, "--yes-playlist")...)
-
-	var stdout, stderr bytes.Buffer
-	res.Stdout = &stdout
-	res.Stderr = &stderr
+func handlePlaylistList(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	scopeOpt, _ := data.OptString("scope")
+	scope, ownerID := playlistOwner(event, scopeOpt)
 
-	if err := res.Run(); err != nil {
-		return nil, fmt.Errorf("yt-dlp playlist failed: %w, stderr: %s", err, stderr.String())
+	playlists, err := GetPlaylists(context.Background(), scope, ownerID)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to load playlists: %v", err), true)
+		return
 	}
 
-	rawOutput := strings.TrimSpace(stdout.String())
-	ls := strings.Split(rawOutput, "\n")
-
-	es := make([]ytdlpPlaylistEntry, 0)
-	isYouTube := isYouTubeURL(u) || strings.Contains(u, "music.youtube.com")
-
-	for _, l := range ls {
-		ps := strings.Split(l, "\t")
-		if len(ps) < 3 {
-			continue
-		}
-		url := ps[0]
-		title := ps[1]
-		uploader := ps[2]
-
-		if isYouTube && len(ps) >= 4 {
-			id := ps[3]
-			if id != "" && id != "NA" {
-				url = "https://www.youtube.com/watch?v=" + id
+	components := []any{NewTextDisplay(fmt.Sprintf("**%s Playlists:**", strings.ToUpper(playlistScopeLabel(scope)[:1])+playlistScopeLabel(scope)[1:]))}
+	if len(playlists) == 0 {
+		components = append(components, NewTextDisplay("_None saved yet. Use `/voice playlist save`._"))
+	} else {
+		var list strings.Builder
+		for _, p := range playlists {
+			list.WriteString(fmt.Sprintf("• **%s** · %d tracks · %s", p.Name, p.TrackCount, FormatDuration(p.Duration)))
+			if scope == PlaylistScopeGuild {
+				list.WriteString(fmt.Sprintf(" · by <@%d>", p.CreatedBy))
 			}
+			list.WriteString("\n")
 		}
+		components = append(components, NewTextDisplay(list.String()))
+	}
+	_ = RespondInteractionContainerV2(*event.Client(), event, NewV2Container(components...), true)
+}
 
-		es = append(es, ytdlpPlaylistEntry{URL: url, Title: title, Uploader: uploader})
+func handlePlaylistDelete(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	name := data.String("name")
+	scopeOpt, _ := data.OptString("scope")
+	scope, ownerID := playlistOwner(event, scopeOpt)
+
+	ctx := context.Background()
+	p, err := GetPlaylist(ctx, scope, ownerID, name)
+	if err != nil || p == nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("No %s playlist named **%s**.", playlistScopeLabel(scope), name), true)
+		return
 	}
-	return es, nil
+	if !canManagePlaylist(event, p) {
+		_ = RespondInteractionV2(*event.Client(), event, "Only its creator or a server manager can delete that playlist.", true)
+		return
+	}
+	if err := DeletePlaylist(ctx, p.ID); err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to delete playlist: %v", err), true)
+		return
+	}
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("🗑️ Deleted %s playlist **%s**.", playlistScopeLabel(scope), p.Name), scope == PlaylistScopeUser)
 }
 
-func extractMetadataFromDRMSite(ctx context.Context, url string) (title, artist string, err error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+func handlePlaylistShare(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	name := data.String("name")
+	guildID := *event.GuildID()
+
+	ctx := context.Background()
+	p, err := GetPlaylist(ctx, PlaylistScopeUser, event.User().ID, name)
+	if err != nil || p == nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("No personal playlist named **%s**.", name), true)
+		return
+	}
+	tracks, err := GetPlaylistTracks(ctx, p.ID)
 	if err != nil {
-		return "", "", err
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to share playlist: %v", err), true)
+		return
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	existing, err := GetPlaylist(ctx, PlaylistScopeGuild, guildID, p.Name)
 	if err != nil {
-		return "", "", err
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to share playlist: %v", err), true)
+		return
+	}
+	if existing != nil && !canManagePlaylist(event, existing) {
+		_ = RespondInteractionV2(*event.Client(), event, "The server already has a playlist with that name.", true)
+		return
+	}
+	if existing == nil {
+		if all, err := GetPlaylists(ctx, PlaylistScopeGuild, guildID); err == nil && len(all) >= PlaylistMaxPerOwner {
+			_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("The server already has %d playlists; delete one first.", len(all)), true)
+			return
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return "", "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	if err := SavePlaylist(ctx, PlaylistScopeGuild, guildID, event.User().ID, p.Name, tracks); err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to share playlist: %v", err), true)
+		return
 	}
+	_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("📤 <@%d> shared **%s** (%d tracks) with the server. Load it with `/voice playlist load scope:Server`.", event.User().ID, p.Name, len(tracks)), false)
+}
 
-	body := new(strings.Builder)
-	scanner := bufio.NewScanner(resp.Body)
-	scanner.Split(bufio.ScanLines)
-	linesRead := 0
-	for scanner.Scan() && linesRead < 500 {
-		body.WriteString(scanner.Text())
-		body.WriteString(" ")
-		linesRead++
-		if strings.Contains(scanner.Text(), "</head>") {
+// autocompletePlaylistNames suggests the playlists in the scope the command is working on
+func autocompletePlaylistNames(event *events.AutocompleteInteractionCreate) {
+	scope, ownerID := PlaylistScopeUser, event.User().ID
+	sc, _ := event.Data.OptString("scope")
+	sub := event.Data.SubCommandName
+	if sub != nil && *sub == "radio" {
+		sc = PlaylistScopeGuild
+	}
+	if sc == PlaylistScopeGuild && sub != nil && *sub != "share" {
+		if event.GuildID() == nil {
+			_ = event.AutocompleteResult(nil)
+			return
+		}
+		scope, ownerID = PlaylistScopeGuild, *event.GuildID()
+	}
+
+	playlists, err := GetPlaylists(context.Background(), scope, ownerID)
+	if err != nil {
+		_ = event.AutocompleteResult(nil)
+		return
+	}
+	typed := strings.ToLower(event.Data.Focused().String())
+	var cs []discord.AutocompleteChoice
+	for _, p := range playlists {
+		if typed != "" && !strings.Contains(strings.ToLower(p.Name), typed) {
+			continue
+		}
+		cs = append(cs, discord.AutocompleteChoiceString{Name: fmt.Sprintf("%s (%d tracks)", p.Name, p.TrackCount), Value: p.Name})
+		if len(cs) >= 25 {
 			break
 		}
 	}
+	_ = event.AutocompleteResult(cs)
+}
 
-	htmlContent := body.String()
+func handleVoiceStats(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	guildID := event.GuildID()
+	if guildID == nil {
+		_ = RespondInteractionV2(*event.Client(), event, "Not in a guild.", true)
+		return
+	}
+	period, _ := data.OptString("period")
+	since, label := voiceStatsSince(period)
+	export, _ := data.OptBool("export")
+	ctx := context.Background()
 
-	titleRegex := regexp.MustCompile(`<meta[^>]*property=["']og:title["'][^>]*content=["']([^"']+)["']`)
-	if matches := titleRegex.FindStringSubmatch(htmlContent); len(matches) > 1 {
-		title = matches[1]
-		if idx := strings.Index(title, " - song and lyrics by"); idx != -1 {
-			title = title[:idx]
+	totals, err := GetVoiceListeningTotals(ctx, *guildID, since)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to load stats: %v", err), true)
+		return
+	}
+	if totals.Plays == 0 {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Nothing has been played here (%s).", strings.ToLower(label)), true)
+		return
+	}
+	tracks, err := GetVoiceTopTracks(ctx, *guildID, since, VoiceStatsTopCount)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to load stats: %v", err), true)
+		return
+	}
+	requesters, err := GetVoiceTopRequesters(ctx, *guildID, since, VoiceStatsTopCount)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to load stats: %v", err), true)
+		return
+	}
+
+	components := []any{
+		NewTextDisplay(fmt.Sprintf("**📊 Listening Stats · %s**", label)),
+		NewTextDisplay(fmt.Sprintf("**%.1f** hours listened · **%d** plays · **%d** skipped", totals.Played.Hours(), totals.Plays, totals.Skipped)),
+		NewSeparator(true),
+	}
+
+	var list strings.Builder
+	list.WriteString("**Top Tracks:**\n")
+	for i, st := range tracks {
+		title := st.Title
+		if title == "" {
+			title = st.URL
 		}
-		if idx := strings.Index(title, " | Spotify"); idx != -1 {
-			title = title[:idx]
+		if st.Channel != "" {
+			title += " · " + st.Channel
 		}
+		list.WriteString(fmt.Sprintf("%d. **%s** · %d plays\n", i+1, title, st.Plays))
 	}
+	components = append(components, NewTextDisplay(list.String()))
 
-	descRegex := regexp.MustCompile(`<meta[^>]*property=["']og:description["'][^>]*content=["']([^"']+)["']`)
-	if matches := descRegex.FindStringSubmatch(htmlContent); len(matches) > 1 {
-		desc := matches[1]
-		if strings.Contains(strings.ToLower(url), "spotify") {
-			parts := strings.Split(desc, " · ")
-			if len(parts) >= 1 {
-				artist = strings.TrimSpace(parts[0])
-			}
+	if len(requesters) > 0 {
+		list.Reset()
+		list.WriteString("**Top Requesters:**\n")
+		for i, st := range requesters {
+			list.WriteString(fmt.Sprintf("%d. <@%d> · %d plays · %s\n", i+1, st.UserID, st.Plays, FormatDuration(st.Played)))
 		}
+		components = append(components, NewSeparator(true), NewTextDisplay(list.String()))
 	}
 
-	if title == "" {
-		return "", "", errors.New("could not extract metadata")
+	if !export {
+		_ = RespondInteractionContainerV2(*event.Client(), event, NewV2Container(components...), false)
+		return
 	}
 
-	return title, artist, nil
+	plays, err := GetVoicePlays(ctx, *guildID, since)
+	if err != nil {
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Failed to export plays: %v", err), true)
+		return
+	}
+	fileName := fmt.Sprintf("voice-plays-%s.csv", period)
+	if period == "" {
+		fileName = "voice-plays-week.csv"
+	}
+	files := []*discord.File{discord.NewFile(fileName, "", strings.NewReader(voicePlaysCSV(plays)))}
+	components = append(components, NewFile("attachment://"+fileName, ""))
+	_ = RespondInteractionContainerV2Files(*event.Client(), event, NewV2Container(components...), files, false)
 }
 
-// ===========================
-// Command Handlers
-// ===========================
-
-func handleMusicPlay(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
-	q, m, p, a, l := parsePlayArguments(data)
+// voiceStatsSince turns a /voice stats period into the earliest play time to include and its display label
+func voiceStatsSince(period string) (time.Time, string) {
+	now := time.Now()
+	switch period {
+	case "day":
+		return now.Add(-24 * time.Hour), "Past Day"
+	case "month":
+		return now.AddDate(0, -1, 0), "Past Month"
+	case "all":
+		return time.Time{}, "All Time"
+	default:
+		return now.AddDate(0, 0, -7), "Past Week"
+	}
+}
+
+func voicePlaysCSV(plays []*VoicePlay) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write([]string{"played_at", "requested_by", "title", "channel", "url", "duration_seconds", "played_seconds", "skipped"})
+	for _, p := range plays {
+		requester := ""
+		if p.RequestedBy != 0 {
+			requester = p.RequestedBy.String()
+		}
+		_ = w.Write([]string{
+			p.PlayedAt.UTC().Format(time.RFC3339),
+			requester,
+			p.Title,
+			p.Channel,
+			p.URL,
+			strconv.Itoa(int(p.Duration.Seconds())),
+			strconv.Itoa(int(p.Played.Seconds())),
+			strconv.FormatBool(p.Skipped),
+		})
+	}
+	w.Flush()
+	return b.String()
+}
 
-	if _, ok := mustGetUserVoiceState(event); !ok {
-		return
+func (s *VoiceSession) SelectBestTrack(results []SourceResult, targetTitle, targetChannel string, targetDuration time.Duration) SourceResult {
+	if len(results) == 0 {
+		return SourceResult{}
 	}
+	best := results[0]
+	maxScore := -100.0
+	var corpus []string
+	corpus = append(corpus, targetTitle)
+	for _, res := range results {
+		corpus = append(corpus, normalizeTitle(res.Title, ""))
+	}
+	weights := calculateTFIDF(corpus)
 
-	_ = event.DeferCreateMessage(false)
-	if strings.HasPrefix(strings.ToUpper(q), "[PL]") {
-		qBody := strings.TrimSpace(q[4:])
-		if qBody != "" && !strings.Contains(qBody, "http") {
-			rs, err := GetVoiceManager().SearchPlaylist(qBody)
-			if err == nil && len(rs) > 0 {
-				q = rs[0].URL
+	for _, res := range results {
+		score := 0.0
+		if targetDuration > 0 && res.Duration > 0 {
+			diff := math.Abs(float64(targetDuration - res.Duration))
+			if diff < 2.5*float64(time.Second) {
+				score += 100
+			} else if diff < 6*float64(time.Second) {
+				score += 40
+			}
+		}
+		lowCh := strings.ToLower(res.Uploader)
+		targetCh := strings.ToLower(targetChannel)
+		if targetCh != "" {
+			if lowCh == targetCh {
+				score += 80
+			} else if strings.Contains(lowCh, targetCh) {
+				score += 30
+			}
+		}
+		if targetTitle != "" {
+			if weightedSimilarity(normalizeTitle(res.Title, ""), normalizeTitle(targetTitle, ""), weights) {
+				score += 50
 			}
 		}
-	}
 
-	if err := startPlayback(event, q, m, a, l, p); err != nil {
-		_ = EditInteractionV2(*event.Client(), event, "Failed: "+err.Error())
+		if score > maxScore {
+			maxScore = score
+			best = res
+		}
 	}
+	return best
 }
 
-func parsePlayArguments(data discord.SlashCommandInteractionData) (q, m string, p int, a, l bool) {
-	q, _ = data.OptString("query")
-	qv, _ := data.OptString("queue")
-	a, _ = data.OptBool("autoplay")
-	l, _ = data.OptBool("loop")
+func extractVideoID(u string) string {
+	u = strings.TrimSpace(u)
+	if strings.Contains(u, "youtu.be/") {
+		parts := strings.Split(u, "youtu.be/")
+		if len(parts) >= 2 {
+			return strings.Split(parts[1], "?")[0]
+		}
+	}
 
-	if qv == "now" {
-		m = "now"
-	} else if qv == "next" {
-		m = "next"
-	} else if qv != "" {
-		p, _ = strconv.Atoi(qv)
+	if !strings.HasPrefix(u, "http") {
+		u = "https://" + u
+	}
+	parsed, err := url.Parse(u)
+	if err == nil {
+		if v := parsed.Query().Get("v"); v != "" {
+			return v
+		}
+		if id := parsed.Query().Get("id"); id != "" {
+			return id
+		}
+		path := parsed.Path
+		if strings.Contains(path, "/shorts/") {
+			return strings.Split(strings.TrimPrefix(path, "/shorts/"), "/")[0]
+		}
+		if strings.Contains(path, "/embed/") {
+			return strings.Split(strings.TrimPrefix(path, "/embed/"), "/")[0]
+		}
+		if strings.Contains(path, "/v/") {
+			return strings.Split(strings.TrimPrefix(path, "/v/"), "/")[0]
+		}
 	}
-	return
-}
 
-func handleMusicStop(event *events.ApplicationCommandInteractionCreate, _ discord.SlashCommandInteractionData) {
-	LogVoice("User %s (%s) stopped playback in guild %s", event.User().Username, event.User().ID, *event.GuildID())
-	GetVoiceManager().Leave(context.Background(), *event.GuildID())
-	_ = RespondInteractionV2(*event.Client(), event, "🛑 Stopped and disconnected.", false)
+	if len(u) == 11 && !strings.ContainsAny(u, "/?&.") {
+		return u
+	}
+	return ""
 }
 
-func handleMusicQueue(event *events.ApplicationCommandInteractionCreate, _ discord.SlashCommandInteractionData) {
-	s, ok := mustGetSession(event)
-	if !ok {
-		return
+func isYouTubeURL(u string) bool {
+	return extractVideoID(u) != "" || strings.Contains(u, "youtube.com") || strings.Contains(u, "youtu.be") || strings.Contains(u, "google.com/url")
+}
+func normalizeTitle(ti, ch string) string {
+	if ti == "" {
+		return ""
 	}
-	_ = event.DeferCreateMessage(true)
-
-	s.lockQueue()
-	defer s.unlockQueue()
 
-	var components []any
+	tBuf := camelCaseRegex.ReplaceAllString(ti, "${1} ${2}")
+	cBuf := camelCaseRegex.ReplaceAllString(ch, "${1} ${2}")
 
-	if s.currentTrack != nil {
-		components = append(components, s.buildTrackComponents(s.currentTrack, "Now Playing:")...)
-		components = append(components, NewSeparator(true))
-	}
+	t, c := strings.ToLower(tBuf), strings.ToLower(cBuf)
 
-	components = append(components, NewTextDisplay("**Queue:**"))
-	if len(s.queue) == 0 {
-		msg := "_Empty_"
-		if s.Autoplay && s.autoplayTrack != nil {
-			msg = "_Empty (Autoplay Ready)_"
-		}
-		components = append(components, NewTextDisplay(msg))
-	} else {
-		var qList strings.Builder
-		for i, t := range s.queue {
-			if i >= 10 {
-				qList.WriteString(fmt.Sprintf("\n*...and %d more*", len(s.queue)-10))
-				break
+	for _, sep := range []string{"|", "//", " ─ ", " - "} {
+		if strings.Contains(t, sep) {
+			ps := strings.Split(t, sep)
+			var nps []string
+			for _, p := range ps {
+				pt := strings.TrimSpace(p)
+				shouldStrip := pt == c || pt == strings.ReplaceAll(c, " ", "")
+				if !shouldStrip {
+					nps = append(nps, pt)
+				}
 			}
-			qList.WriteString(fmt.Sprintf("`%d.` %s\n", i+1, t.FullDisplay()))
+			if len(nps) > 0 {
+				t = strings.Join(nps, " ")
+			}
+			break
 		}
-		components = append(components, NewTextDisplay(qList.String()))
 	}
-
-	if s.Autoplay {
-		components = append(components, NewSeparator(true))
-		components = append(components, NewTextDisplay("**Autoplay:** Enabled"))
-		if s.autoplayTrack != nil {
-			components = append(components, s.buildTrackComponents(s.autoplayTrack, "Next Up (Autoplay):")...)
+	for {
+		t = strings.TrimSpace(t)
+		loc := metadataBlockRegex.FindStringIndex(t)
+		if loc != nil && loc[1] == len(t) {
+			t = t[:loc[0]]
+			continue
 		}
+		break
 	}
-
-	if err := EditInteractionContainerV2(*event.Client(), event, NewV2Container(components...)); err != nil {
-		LogWarn("Failed to edit interaction: %v", err)
+	if c != "" {
+		t = strings.ReplaceAll(t, c, " ")
+	}
+	var sb strings.Builder
+	for _, r := range t {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune(' ')
+		}
 	}
+	return strings.Join(strings.Fields(sb.String()), " ")
 }
 
-func handleVoicePanel(event *events.ApplicationCommandInteractionCreate) {
-	userID := event.User().ID
-	guildID := event.GuildID()
-	if guildID == nil {
-		_ = RespondInteractionV2(*event.Client(), event, "Not in a guild.", true)
-		return
+func calculateTFIDF(corpus []string) map[string]float64 {
+	df := make(map[string]int)
+	total := len(corpus)
+	if total == 0 {
+		return nil
 	}
-
-	VoicePanelsMu.Lock()
-	if p, ok := VoicePanels[userID]; ok {
-		if time.Now().Before(p.ExpiresAt) {
-			VoicePanelsMu.Unlock()
-			_ = RespondInteractionV2(*event.Client(), event, "You already have an active panel! Dismiss it or wait for it to expire.", true)
-			return
+	for _, doc := range corpus {
+		seen := make(map[string]bool)
+		for _, w := range strings.Fields(strings.ToLower(doc)) {
+			if !seen[w] {
+				df[w]++
+				seen[w] = true
+			}
 		}
-		delete(VoicePanels, userID)
-	}
-	VoicePanelsMu.Unlock()
-
-	s := GetVoiceManager().GetSession(*guildID)
-	if s == nil {
-		_ = RespondInteractionV2(*event.Client(), event, "Not playing anything.", true)
-		return
 	}
-
-	_ = event.DeferCreateMessage(true)
-
-	VoicePanelsMu.Lock()
-	VoicePanels[userID] = &VoicePanel{
-		UserID:    userID,
-		GuildID:   *guildID,
-		Token:     event.Token(),
-		AppID:     event.ApplicationID(),
-		ExpiresAt: time.Now().Add(15 * time.Minute),
+	weights := make(map[string]float64)
+	for w, count := range df {
+		weights[w] = math.Log(1.0 + float64(total)/float64(count))
 	}
-	VoicePanelsMu.Unlock()
-
-	UpdateVoicePanels(*guildID, *event.Client())
+	return weights
 }
 
-func BuildVoicePanelContainer(s *VoiceSession) Container {
-	s.lockQueue()
-	defer s.unlockQueue()
-
-	var components []any
+func weightedSimilarity(a, b string, weights map[string]float64) bool {
+	wa, wb := strings.Fields(strings.ToLower(a)), strings.Fields(strings.ToLower(b))
+	sa, sb := make(map[string]bool), make(map[string]bool)
+	union := make(map[string]bool)
 
-	if s.currentTrack != nil {
-		components = append(components, s.buildTrackComponents(s.currentTrack, "Now Playing:")...)
-	} else {
-		components = append(components, NewTextDisplay("**Nothing is currently playing.**"))
+	for _, w := range wa {
+		sa[w] = true
+		union[w] = true
 	}
-
-	paused := s.IsPaused()
-	statusEmoji, statusText := "⏸️", "Playing"
-	if paused {
-		statusEmoji, statusText = "▶️", "Paused"
+	for _, w := range wb {
+		sb[w] = true
+		union[w] = true
 	}
-
-	options := ""
-	if s.Looping {
-		options += " 🔄 Loop"
+	if len(union) == 0 {
+		return false
 	}
-	if s.Autoplay {
-		options += " 🔀 Autoplay"
+	if a == b {
+		return true
 	}
 
-	components = append(components, NewTextDisplay(fmt.Sprintf("**Status:** %s %s | **Volume:** %d%% %s", statusEmoji, statusText, s.Volume.Load(), options)))
-	components = append(components, NewTextDisplay(fmt.Sprintf("**Queue:** %d tracks remaining", len(s.queue))))
-
-	components = append(components, NewSeparator(true))
-
-	playPauseLabel := "⏸️ Pause"
-	if paused {
-		playPauseLabel = "▶️ Resume"
+	iScore, uScore := 0.0, 0.0
+	for w := range union {
+		wt := 1.0
+		if weights != nil {
+			if val, ok := weights[w]; ok {
+				wt = val
+			} else {
+				wt = math.Log(1.0 + float64(len(weights)))
+			}
+		}
+		if sa[w] && sb[w] {
+			iScore += wt
+		}
+		uScore += wt
 	}
+	if uScore == 0 {
+		return false
+	}
+	return (iScore / uScore) >= 0.7
+}
 
-	row1 := discord.NewActionRow(
-		discord.NewButton(discord.ButtonStyleSecondary, playPauseLabel, "voice:panel:playpause", "", 0),
-		discord.NewButton(discord.ButtonStyleSecondary, "⏭️ Skip", "voice:panel:skip", "", 0),
-		discord.NewButton(discord.ButtonStyleSecondary, "⏹️ Stop", "voice:panel:stop", "", 0),
-		discord.NewButton(discord.ButtonStyleDanger, "❌ Close", "voice:panel:close", "", 0),
-	)
-
-	row2 := discord.NewActionRow(
-		discord.NewButton(discord.ButtonStylePrimary, "🔄 Loop", "voice:panel:loop", "", 0),
-		discord.NewButton(discord.ButtonStylePrimary, "🔀 Autoplay", "voice:panel:autoplay", "", 0),
-		discord.NewButton(discord.ButtonStyleSecondary, "➖ Vol", "voice:panel:voldown", "", 0),
-		discord.NewButton(discord.ButtonStyleSecondary, "➕ Vol", "voice:panel:volup", "", 0),
-	)
+// ===========================
+// Audio Cache
+// ===========================
 
-	components = append(components, row1, row2)
+// AudioCache indexes finished downloads in AudioCacheDir, named by video ID, so they survive restarts. Last use is
+// mirrored to each file's mtime, which keeps the LRU order across runs too
+type AudioCache struct {
+	mu        sync.Mutex
+	entries   map[string]*audioCacheEntry
+	total     int64
+	hits      int64
+	misses    int64
+	evictions int64
+}
 
-	return NewV2Container(components...)
+type audioCacheEntry struct {
+	size     int64
+	lastUsed time.Time
+	// pinned shields a file Lookup has just handed out until the track records it as its Path
+	pinned time.Time
 }
 
-func (s *VoiceSession) IsPaused() bool {
-	s.pauseMu.RLock()
-	defer s.pauseMu.RUnlock()
-	select {
-	case <-s.pauseChan:
-		return false
-	default:
-		return true
-	}
+// AudioCacheStats is what /voice cache stats reports; the counters cover the current run only
+type AudioCacheStats struct {
+	Tracks    int
+	Size      int64
+	Hits      int64
+	Misses    int64
+	Evictions int64
 }
 
-func (s *VoiceSession) TogglePause() bool {
-	s.pauseMu.Lock()
-	defer s.pauseMu.Unlock()
-	isClosed := false
-	select {
-	case <-s.pauseChan:
-		isClosed = true
-	default:
-	}
-	if isClosed {
-		s.pauseChan = make(chan struct{})
-		return true // Now paused
-	} else {
-		close(s.pauseChan)
-		return false // Now playing
+// audioCacheLimit is AUDIO_CACHE_MAX_MB in bytes; a negative setting lets the cache grow without bound
+func audioCacheLimit() int64 {
+	if GlobalConfig == nil {
+		return 0
 	}
+	return int64(GlobalConfig.AudioCacheMaxMB) << 20
 }
 
-func UpdateVoicePanels(guildID snowflake.ID, cl bot.Client) {
-	VoicePanelsMu.Lock()
-	defer VoicePanelsMu.Unlock()
-
-	s := GetVoiceManager().GetSession(guildID)
+func formatMB(n int64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/1024/1024)
+}
 
-	var container Container
-	if s == nil {
-		container = NewV2Container(NewTextDisplay("The music session has ended."), discord.NewActionRow(discord.NewButton(discord.ButtonStyleDanger, "❌ Close", "voice:panel:close", "", 0)))
-	} else {
-		s.SetClient(cl)
-		container = BuildVoicePanelContainer(s)
+// Load rebuilds the index from disk, discarding leftovers of interrupted downloads and files too small to be whole
+func (c *AudioCache) Load() {
+	files, err := os.ReadDir(AudioCacheDir)
+	if err != nil {
+		LogError("Failed to read %s: %v", AudioCacheDir, err)
+		return
 	}
-
-	now := time.Now()
-	for userID, panel := range VoicePanels {
-		if panel.GuildID != guildID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*audioCacheEntry)
+	c.total = 0
+	for _, f := range files {
+		if !f.Type().IsRegular() {
 			continue
 		}
-		if now.After(panel.ExpiresAt) {
-			delete(VoicePanels, userID)
+		path := filepath.Join(AudioCacheDir, f.Name())
+		switch filepath.Ext(path) {
+		case ".part":
+			_ = os.Remove(path)
+			continue
+		case ".meta":
 			continue
 		}
-
-		safeGo(func() {
-			func(token string, appID snowflake.ID, c Container, client bot.Client) {
-				_ = EditInteractionContainerV2ByToken(client, appID, token, c)
-			}(panel.Token, panel.AppID, container, cl)
-		})
-
-		if s == nil {
-			delete(VoicePanels, userID)
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		if info.Size() < MinTrackSize {
+			LogVoice("Discarding truncated cache file %s (%d bytes)", path, info.Size())
+			_ = os.Remove(path)
+			continue
 		}
+		c.entries[path] = &audioCacheEntry{size: info.Size(), lastUsed: info.ModTime()}
+		c.total += info.Size()
 	}
+	LogVoice("Audio cache holds %d tracks (%s)", len(c.entries), formatMB(c.total))
 }
 
-func handleVoiceComponent(event *events.ComponentInteractionCreate) {
-	customID := event.Data.CustomID()
-	if !strings.HasPrefix(customID, "voice:panel:") {
-		return
+// Lookup reports whether an intact copy of path is cached, marks it as just used and pins it for AudioCachePin so
+// Evict leaves it alone until the caller's track holds it. A file under MinTrackSize is deleted so the track gets
+// downloaded again
+func (c *AudioCache) Lookup(path string) bool {
+	ensureAudioCacheDir()
+	info, err := os.Stat(path)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil || info.Size() < MinTrackSize {
+		if err == nil {
+			LogVoice("Discarding truncated cache file %s (%d bytes)", path, info.Size())
+			_ = os.Remove(path)
+		}
+		if e := c.entries[path]; e != nil {
+			c.total -= e.size
+			delete(c.entries, path)
+		}
+		c.misses++
+		return false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	e := c.entries[path]
+	if e == nil {
+		e = &audioCacheEntry{size: info.Size()}
+		c.entries[path] = e
+		c.total += e.size
 	}
+	e.lastUsed = now
+	e.pinned = now.Add(AudioCachePin)
+	c.hits++
+	return true
+}
 
-	action := strings.TrimPrefix(customID, "voice:panel:")
-	guildID := event.GuildID()
-	if guildID == nil {
+// Has reports whether path is a cached track, which Track.Cleanup must leave on disk
+func (c *AudioCache) Has(path string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[path] != nil
+}
+
+// Add indexes a finished download and evicts whatever no longer fits. Files outside AudioCacheDir, such as seek
+// fragments, are not cached
+func (c *AudioCache) Add(path string) {
+	if filepath.Dir(path) != AudioCacheDir {
 		return
 	}
-
-	switch action {
-	case "close":
-		VoicePanelsMu.Lock()
-		delete(VoicePanels, event.User().ID)
-		VoicePanelsMu.Unlock()
-		_ = event.UpdateMessage(discord.NewMessageUpdate().WithContent("Panel closed.").ClearComponents())
+	info, err := os.Stat(path)
+	if err != nil {
 		return
 	}
+	c.mu.Lock()
+	if e := c.entries[path]; e != nil {
+		c.total -= e.size
+	}
+	c.entries[path] = &audioCacheEntry{size: info.Size(), lastUsed: time.Now()}
+	c.total += info.Size()
+	c.mu.Unlock()
+	c.Evict()
+}
 
-	s := GetVoiceManager().GetSession(*guildID)
-	if s == nil {
-		_ = event.CreateMessage(discord.NewMessageCreate().WithContent("Music session is no longer active.").WithEphemeral(true))
+// Evict deletes the least recently used tracks and their metadata until the cache fits its limit, sparing anything a
+// session is playing or has queued and anything Lookup has just pinned
+func (c *AudioCache) Evict() {
+	limit := audioCacheLimit()
+	c.mu.Lock()
+	over := limit > 0 && c.total > limit
+	c.mu.Unlock()
+	if !over {
 		return
 	}
+	inUse := activeTrackPaths()
 
-	switch action {
-	case "playpause":
-		s.TogglePause()
-		s.RefreshStatus()
-	case "skip":
-		_, _ = s.Skip()
-	case "stop":
-		GetVoiceManager().Leave(context.Background(), *guildID)
-	case "loop":
-		s.lockQueue()
-		s.Looping = !s.Looping
-		s.unlockQueue()
-	case "autoplay":
-		s.lockQueue()
-		s.Autoplay = !s.Autoplay
-		s.unlockQueue()
-	case "volup":
-		v := s.Volume.Load()
-		if v < 200 {
-			s.Volume.Store(v + 10)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	paths := slices.Collect(maps.Keys(c.entries))
+	slices.SortFunc(paths, func(a, b string) int {
+		return c.entries[a].lastUsed.Compare(c.entries[b].lastUsed)
+	})
+	now := time.Now()
+	for _, path := range paths {
+		if c.total <= limit {
+			break
+		}
+		if inUse[path] || now.Before(c.entries[path].pinned) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			LogVoice("Failed to evict %s: %v", path, err)
+			continue
 		}
-	case "voldown":
-		v := s.Volume.Load()
-		if v > 0 {
-			s.Volume.Store(v - 10)
+		_ = os.Remove(strings.TrimSuffix(path, filepath.Ext(path)) + ".meta")
+		LogVoice("Evicted %s from the audio cache (%s)", path, formatMB(c.entries[path].size))
+		c.total -= c.entries[path].size
+		delete(c.entries, path)
+		c.evictions++
+	}
+}
+
+// Purge deletes every cached track that isn't playing, queued or pinned by Lookup, along with its metadata, and
+// returns how many tracks and bytes went
+func (c *AudioCache) Purge() (int, int64) {
+	inUse := activeTrackPaths()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, freed := 0, int64(0)
+	now := time.Now()
+	for path, e := range c.entries {
+		if inUse[path] || now.Before(e.pinned) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			LogVoice("Failed to purge %s: %v", path, err)
+			continue
 		}
+		_ = os.Remove(strings.TrimSuffix(path, filepath.Ext(path)) + ".meta")
+		c.total -= e.size
+		delete(c.entries, path)
+		n++
+		freed += e.size
 	}
+	return n, freed
+}
 
-	UpdateVoicePanels(*guildID, *event.Client())
-	_ = event.DeferUpdateMessage()
+func (c *AudioCache) Stats() AudioCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return AudioCacheStats{
+		Tracks:    len(c.entries),
+		Size:      c.total,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
 }
 
-// handleMusicAutocomplete handles autocomplete interactions for music commands.
-func handleMusicAutocomplete(event *events.AutocompleteInteractionCreate) {
-	f := event.Data.Focused()
-	if f.Name == "queue" {
-		v := f.String()
-		cs := []discord.AutocompleteChoice{
-			discord.AutocompleteChoiceString{Name: "Play Now", Value: "now"},
-			discord.AutocompleteChoiceString{Name: "Play Next", Value: "next"},
-		}
-		if v != "" {
-			if _, err := strconv.Atoi(v); err == nil {
-				cs = append([]discord.AutocompleteChoice{discord.AutocompleteChoiceString{Name: "Position: " + v, Value: v}}, cs...)
+// activeTrackPaths lists the files sessions are playing or about to play
+func activeTrackPaths() map[string]bool {
+	vs := GetVoiceManager()
+	vs.mu.Lock()
+	sessions := slices.Collect(maps.Values(vs.sessions))
+	vs.mu.Unlock()
+
+	paths := make(map[string]bool)
+	for _, s := range sessions {
+		s.lockQueue()
+		tracks := append([]*Track{s.currentTrack, s.autoplayTrack}, s.queue...)
+		s.unlockQueue()
+		for _, t := range tracks {
+			if t == nil {
+				continue
+			}
+			t.mu.Lock()
+			if t.Path != "" {
+				paths[t.Path] = true
 			}
+			t.mu.Unlock()
 		}
-		_ = event.AutocompleteResult(cs)
-		return
-	}
-	if f.Name != "query" {
-		return
 	}
-	q := f.String()
-	if q == "" {
-		q = getRandomRecommendation(event.GuildID())
-	} else if strings.Contains(q, "http") {
-		_ = event.AutocompleteResult(nil)
+	return paths
+}
+
+func handleVoiceCache(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	if !IsBotOwner(event.User().ID) {
+		_ = RespondInteractionV2(*event.Client(), event, "Only the bot's owners can manage the audio cache.", true)
 		return
 	}
-	var rs []SearchResult
-	var err error
-	if strings.HasPrefix(strings.ToUpper(q), "[PL]") {
-		if qBody := strings.TrimSpace(q[4:]); qBody != "" {
-			if ytdlpRS, searchErr := GetVoiceManager().SearchPlaylist(qBody); searchErr == nil {
-				for _, entry := range ytdlpRS {
-					rs = append(rs, SearchResult{
-						Title:       entry.Title,
-						ChannelName: entry.Uploader,
-						URL:         entry.URL,
-					})
-				}
-			} else {
-				err = searchErr
-			}
+	switch *data.SubCommandName {
+	case "stats":
+		st := audioCache.Stats()
+		limit := "no limit"
+		if l := audioCacheLimit(); l > 0 {
+			limit = formatMB(l)
 		}
-	} else {
-		rs, err = GetVoiceManager().Search(q)
+		hitRate := 0.0
+		if st.Hits+st.Misses > 0 {
+			hitRate = float64(st.Hits) / float64(st.Hits+st.Misses) * 100
+		}
+		_ = RespondInteractionContainerV2(*event.Client(), event, NewV2Container(
+			NewTextDisplay("**💾 Audio Cache**"),
+			NewTextDisplay(fmt.Sprintf("**%d** tracks · **%s** of %s", st.Tracks, formatMB(st.Size), limit)),
+			NewTextDisplay(fmt.Sprintf("**%d** hits · **%d** misses (%.0f%%) · **%d** evicted since startup", st.Hits, st.Misses, hitRate, st.Evictions)),
+		), true)
+	case "purge":
+		n, freed := audioCache.Purge()
+		_ = RespondInteractionV2(*event.Client(), event, fmt.Sprintf("Purged **%d** cached tracks (%s). Anything playing or queued was kept.", n, formatMB(freed)), true)
 	}
+}
+
+// ===========================
+// Recording
+// ===========================
 
+// recordingFormat is a /voice record output: the muxer and encoder behind it and the bytes it spends per second,
+// which decides how long a recording can run before it no longer fits in a Discord upload
+type recordingFormat struct {
+	Muxer, Codec, Ext string
+	BytesPerSecond    int
+}
+
+var recordingFormats = map[string]recordingFormat{
+	"ogg": {Muxer: "ogg", Codec: "libopus", Ext: ".ogg", BytesPerSecond: RecordingBitRate / 8},
+	"wav": {Muxer: "wav", Codec: "pcm_s16le", Ext: ".wav", BytesPerSecond: 48000 * 2},
+}
+
+// VoiceRecorder receives what members say in the session's channel. Each speaker's Opus packets are decoded and
+// placed on a shared timeline by their RTP timestamps, then summed into one mono mix and, when asked, a file per
+// speaker. Audio is encoded RecordingJitter behind real time as it arrives, so memory stays flat however long the
+// recording runs
+type VoiceRecorder struct {
+	mu        sync.Mutex
+	client    bot.Client
+	GuildID   snowflake.ID
+	ChannelID snowflake.ID // the voice channel being recorded
+	PostTo    snowflake.ID // the text channel the result goes to
+	StartedBy snowflake.ID
+	Format    string
+	MaxLength time.Duration
+	separate  bool
+	started   time.Time
+	dir       string
+	mix       *recordingTrack
+	speakers  map[snowflake.ID]*recordedSpeaker
+	order     []snowflake.ID
+	stop      chan struct{}
+	closed    bool
+	err       error
+}
+
+// recordedSpeaker decodes one member's packets; offset is the timeline sample their anchor timestamp maps to
+type recordedSpeaker struct {
+	dec      *astiav.CodecContext
+	packet   *astiav.Packet
+	frame    *astiav.Frame
+	pcm      *astiav.Frame
+	resample *astiav.SoftwareResampleContext
+	anchored bool
+	anchor   uint32
+	offset   int64
+	track    *recordingTrack
+}
+
+// recordingTrack encodes one output file. pending holds summed samples not yet encoded, starting at written
+type recordingTrack struct {
+	path    string
+	userID  snowflake.ID
+	fc      *astiav.FormatContext
+	io      *astiav.IOContext
+	enc     *astiav.CodecContext
+	stream  *astiav.Stream
+	frame   *astiav.Frame
+	packet  *astiav.Packet
+	pending []int32
+	written int64
+}
+
+func newRecordingTrack(path, format string) (*recordingTrack, error) {
+	f := recordingFormats[format]
+	fc, err := astiav.AllocOutputFormatContext(nil, f.Muxer, path)
 	if err != nil {
-		_ = event.AutocompleteResult(nil)
+		return nil, err
+	}
+	rt := &recordingTrack{path: path, fc: fc}
+	fail := func(err error) (*recordingTrack, error) {
+		rt.free()
+		return nil, err
+	}
+
+	codec := astiav.FindEncoderByName(f.Codec)
+	if codec == nil {
+		return fail(fmt.Errorf("no %s encoder", f.Codec))
+	}
+	rt.enc = astiav.AllocCodecContext(codec)
+	rt.enc.SetSampleRate(48000)
+	rt.enc.SetChannelLayout(astiav.ChannelLayoutMono)
+	rt.enc.SetSampleFormat(astiav.SampleFormatS16)
+	rt.enc.SetTimeBase(astiav.NewRational(1, 48000))
+	if fc.OutputFormat().Flags().Has(astiav.IOFormatFlagGlobalheader) {
+		rt.enc.SetFlags(rt.enc.Flags().Add(astiav.CodecContextFlagGlobalHeader))
+	}
+	o := astiav.NewDictionary()
+	defer o.Free()
+	if f.Codec == "libopus" {
+		rt.enc.SetBitRate(RecordingBitRate)
+		o.Set("application", "voip", 0)
+		o.Set("frame_size", "20", 0)
+	}
+	if err := rt.enc.Open(codec, o); err != nil {
+		return fail(err)
+	}
+
+	rt.stream = fc.NewStream(nil)
+	if err := rt.stream.CodecParameters().FromCodecContext(rt.enc); err != nil {
+		return fail(err)
+	}
+	rt.stream.SetTimeBase(rt.enc.TimeBase())
+	if rt.io, err = astiav.OpenIOContext(path, astiav.NewIOContextFlags(astiav.IOContextFlagWrite), nil, nil); err != nil {
+		return fail(err)
+	}
+	fc.SetPb(rt.io)
+	if err := fc.WriteHeader(nil); err != nil {
+		return fail(err)
+	}
+	rt.frame = astiav.AllocFrame()
+	rt.packet = astiav.AllocPacket()
+	return rt, nil
+}
+
+// add sums 16-bit samples into the track at timeline position pos. Whatever falls before written arrived too late
+// and is dropped
+func (rt *recordingTrack) add(pos int64, pcm []int16) {
+	skip := int(max(0, rt.written-pos))
+	if skip >= len(pcm) {
 		return
 	}
+	start := int(pos + int64(skip) - rt.written)
+	if end := start + len(pcm) - skip; end > len(rt.pending) {
+		rt.pending = append(rt.pending, make([]int32, end-len(rt.pending))...)
+	}
+	for i, v := range pcm[skip:] {
+		rt.pending[start+i] += int32(v)
+	}
+}
 
-	var cs []discord.AutocompleteChoice
-	for i, r := range rs {
-		if i >= 25 {
-			break
-		}
-		n, v := r.Title, r.URL
-		if len(n) > 100 {
-			n = n[:97] + "..."
+// flush encodes every whole frame that ends by upto; anything nobody said in that span is silence
+func (rt *recordingTrack) flush(upto int64) error {
+	for rt.written+RecordingFrameSamples <= upto {
+		n := min(len(rt.pending), RecordingFrameSamples)
+		if err := rt.encode(rt.pending[:n]); err != nil {
+			return err
 		}
-		if len(v) > 100 {
-			v = r.Title
-			if len(v) > 100 {
-				v = v[:100]
+		rt.pending = rt.pending[n:]
+	}
+	return nil
+}
+
+// encode writes one frame, clipping the summed voices back to 16 bits and padding short input with silence
+func (rt *recordingTrack) encode(samples []int32) error {
+	rt.frame.Unref()
+	rt.frame.SetNbSamples(RecordingFrameSamples)
+	rt.frame.SetChannelLayout(astiav.ChannelLayoutMono)
+	rt.frame.SetSampleFormat(astiav.SampleFormatS16)
+	rt.frame.SetSampleRate(48000)
+	if err := rt.frame.AllocBuffer(0); err != nil {
+		return err
+	}
+	data := make([]byte, RecordingFrameSamples*2)
+	for i, v := range samples {
+		v = max(-32768, min(32767, v))
+		data[2*i] = byte(v)
+		data[2*i+1] = byte(v >> 8)
+	}
+	if err := rt.frame.Data().SetBytes(data, 1); err != nil {
+		return err
+	}
+	rt.frame.SetPts(rt.written)
+	rt.written += RecordingFrameSamples
+	if err := rt.enc.SendFrame(rt.frame); err != nil {
+		return err
+	}
+	return rt.drain()
+}
+
+func (rt *recordingTrack) drain() error {
+	for {
+		rt.packet.Unref()
+		if err := rt.enc.ReceivePacket(rt.packet); err != nil {
+			if errors.Is(err, astiav.ErrEagain) || errors.Is(err, astiav.ErrEof) {
+				return nil
 			}
+			return err
+		}
+		rt.packet.RescaleTs(rt.enc.TimeBase(), rt.stream.TimeBase())
+		rt.packet.SetStreamIndex(rt.stream.Index())
+		if err := rt.fc.WriteInterleavedFrame(rt.packet); err != nil {
+			return err
 		}
-		cs = append(cs, discord.AutocompleteChoiceString{Name: n, Value: v})
 	}
-	_ = event.AutocompleteResult(cs)
 }
 
-func getRandomRecommendation(guildID *snowflake.ID) string {
-	if guildID != nil {
-		if s := GetVoiceManager().GetSession(*guildID); s != nil {
-			s.lockQueue()
-			defer s.unlockQueue()
-			l := len(s.HistoryTitles)
-			if l > 0 {
-				idx := l - 1
-				if l > 5 {
-					idx = l - 1 - (int(time.Now().UnixNano()/1000) % 5)
-				} else {
-					idx = int(time.Now().UnixNano()/1000) % l
-				}
-				if len(s.HistoryAuthors) > idx {
-					author := s.HistoryAuthors[idx]
-					if author != "" && author != "NA" {
-						return "Mix - " + author
-					}
-				}
-				return "Mix - " + s.HistoryTitles[idx]
-			}
+// finish encodes everything up to end, flushes the encoder and closes the file
+func (rt *recordingTrack) finish(end int64) error {
+	err := rt.flush(end + RecordingFrameSamples - 1)
+	if err == nil {
+		if err = rt.enc.SendFrame(nil); err == nil {
+			err = rt.drain()
 		}
 	}
-	return "Trending Music"
+	if terr := rt.fc.WriteTrailer(); err == nil {
+		err = terr
+	}
+	rt.free()
+	return err
 }
 
-func startPlayback(ev *events.ApplicationCommandInteractionCreate, q, m string, a, l bool, p int) error {
-	LogVoice("User %s (%s) requested playback: %s", ev.User().Username, ev.User().ID, q)
-	vs, ok := mustGetUserVoiceState(ev)
-	if !ok {
-		return errors.New("user not in a voice channel")
+func (rt *recordingTrack) free() {
+	if rt.io != nil {
+		_ = rt.io.Close()
+		rt.io = nil
 	}
-	vm := GetVoiceManager()
-	s := vm.Prepare(*ev.Client(), *ev.GuildID(), *vs.ChannelID)
-	s.lockQueue()
-	s.Autoplay, s.Looping = a, l
-	s.unlockQueue()
-	je := make(chan error, 1)
-	safeGo(func() { je <- vm.Join(context.Background(), *ev.Client(), *ev.GuildID(), *vs.ChannelID) })
-	t, count, err := vm.Play(context.Background(), *ev.GuildID(), q, m, p)
-	if err != nil {
-		return err
+	if rt.enc != nil {
+		rt.enc.Free()
+		rt.enc = nil
 	}
-	if err := <-je; err != nil {
-		return err
+	if rt.frame != nil {
+		rt.frame.Free()
+		rt.frame = nil
+	}
+	if rt.packet != nil {
+		rt.packet.Free()
+		rt.packet = nil
+	}
+	if rt.fc != nil {
+		rt.fc.Free()
+		rt.fc = nil
 	}
+}
 
-	err = finishPlaybackResponse(ev, t, m, s.Autoplay, s.Looping, p, count)
-	UpdateVoicePanels(*ev.GuildID(), *ev.Client())
-	return err
+func newRecordedSpeaker() (*recordedSpeaker, error) {
+	d := astiav.FindDecoder(astiav.CodecIDOpus)
+	if d == nil {
+		return nil, errors.New("no opus decoder")
+	}
+	sp := &recordedSpeaker{
+		dec:      astiav.AllocCodecContext(d),
+		packet:   astiav.AllocPacket(),
+		frame:    astiav.AllocFrame(),
+		pcm:      astiav.AllocFrame(),
+		resample: astiav.AllocSoftwareResampleContext(),
+	}
+	sp.dec.SetSampleRate(48000)
+	sp.dec.SetChannelLayout(astiav.ChannelLayoutStereo)
+	if err := sp.dec.Open(d, nil); err != nil {
+		sp.free()
+		return nil, err
+	}
+	return sp, nil
 }
 
-func finishPlaybackResponse(ev *events.ApplicationCommandInteractionCreate, t *Track, m string, a, l bool, p int, count int) error {
-	build := func() (string, string) {
-		pr := "Added to queue:"
-		if count > 1 {
-			pr = fmt.Sprintf("📂 Added **%d** tracks from playlist to queue:", count)
-			switch m {
-			case "now":
-				pr = fmt.Sprintf("▶️ Playing Now (Cleared queue and added **%d** tracks from playlist):", count)
-			case "next":
-				pr = fmt.Sprintf("⏭️ Added **%d** tracks to play next:", count)
+// decode turns one Opus packet into 16-bit mono samples at 48kHz
+func (sp *recordedSpeaker) decode(opus []byte) ([]int16, error) {
+	sp.packet.Unref()
+	if err := sp.packet.FromData(opus); err != nil {
+		return nil, err
+	}
+	if err := sp.dec.SendPacket(sp.packet); err != nil {
+		return nil, err
+	}
+	var out []int16
+	for {
+		if err := sp.dec.ReceiveFrame(sp.frame); err != nil {
+			if errors.Is(err, astiav.ErrEagain) || errors.Is(err, astiav.ErrEof) {
+				return out, nil
 			}
-		} else {
-			if m == "next" {
-				pr = "⏭️ Next up:"
-			} else if m == "now" {
-				pr = "▶️ Playing Now (Skipped Current):"
-			} else if p > 0 {
-				pr = "Added to queue at position " + strconv.Itoa(p) + ":"
+			return out, err
+		}
+		sp.pcm.Unref()
+		sp.pcm.SetChannelLayout(astiav.ChannelLayoutMono)
+		sp.pcm.SetSampleFormat(astiav.SampleFormatS16)
+		sp.pcm.SetSampleRate(48000)
+		sp.pcm.SetNbSamples(sp.frame.NbSamples())
+		if sp.pcm.AllocBuffer(0) == nil && sp.resample.ConvertFrame(sp.frame, sp.pcm) == nil {
+			if data, err := sp.pcm.Data().Bytes(1); err == nil {
+				for i := 0; i+1 < len(data) && i/2 < sp.pcm.NbSamples(); i += 2 {
+					out = append(out, int16(uint16(data[i])|uint16(data[i+1])<<8))
+				}
 			}
 		}
+		sp.frame.Unref()
+	}
+}
 
-		var ss []string
-		if a {
-			ss = append(ss, "Autoplay")
-		}
-		if l {
-			ss = append(ss, "Looping")
-		}
-		suffix := ""
-		if len(ss) > 0 {
-			suffix = " (" + strings.Join(ss, ", ") + ": Enabled)"
+// position maps a packet's RTP timestamp onto the recording's timeline. A speaker is anchored to the wall clock by
+// their first packet and follows their timestamps from there, which keep counting through silence; a client
+// that restarts or drifts more than RecordingJitter off is anchored again
+func (sp *recordedSpeaker) position(ts uint32, now int64) int64 {
+	if sp.anchored {
+		pos := sp.offset + int64(int32(ts-sp.anchor))
+		if d := pos - now; d > -recordingJitterSamples && d < recordingJitterSamples {
+			return pos
 		}
-
-		content := fmt.Sprintf("%s %s%s", pr, t.FullDisplay(), suffix)
-		t.mu.Lock()
-		art := t.ArtworkURL
-		t.mu.Unlock()
-		return content, art
 	}
+	sp.anchored, sp.anchor, sp.offset = true, ts, now
+	return now
+}
 
-	content, art := build()
-	var err error
-	if art != "" {
-		err = EditInteractionContainerV2(*ev.Client(), ev, NewV2Container(NewTextDisplay(content), NewMediaGallery(art)))
-	} else {
-		err = EditInteractionV2(*ev.Client(), ev, content)
+func (sp *recordedSpeaker) free() {
+	if sp.dec != nil {
+		sp.dec.Free()
+	}
+	if sp.resample != nil {
+		sp.resample.Free()
 	}
+	if sp.packet != nil {
+		sp.packet.Free()
+	}
+	if sp.frame != nil {
+		sp.frame.Free()
+	}
+	if sp.pcm != nil {
+		sp.pcm.Free()
+	}
+}
 
-	select {
-	case <-t.MetadataReady:
-	default:
-		safeGo(func() {
-			select {
-			case <-t.MetadataReady:
-				c, a := build()
-				if a != "" {
-					_ = EditInteractionContainerV2(*ev.Client(), ev, NewV2Container(NewTextDisplay(c), NewMediaGallery(a)))
-				} else {
-					_ = EditInteractionV2(*ev.Client(), ev, c)
-				}
-			case <-time.After(15 * time.Second):
-			}
-		})
+// now is the timeline position, in samples, of the current moment
+func (r *VoiceRecorder) now() int64 {
+	return int64(time.Since(r.started) * 48000 / time.Second)
+}
+
+// ReceiveOpusFrame implements voice.OpusFrameReceiver; packets that slip in after Finish, before takeRecorder has
+// swapped the receiver out, are ignored
+func (r *VoiceRecorder) ReceiveOpusFrame(userID snowflake.ID, packet *voice.Packet) error {
+	if userID == 0 || packet == nil || len(packet.Opus) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.err != nil {
+		return nil
+	}
+	sp := r.speaker(userID)
+	if sp == nil {
+		return nil
+	}
+	pcm, err := sp.decode(packet.Opus)
+	if err != nil || len(pcm) == 0 {
+		return nil
 	}
-	return err
+	pos := sp.position(packet.Timestamp, r.now())
+	r.mix.add(pos, pcm)
+	if sp.track != nil {
+		sp.track.add(pos, pcm)
+	}
+	return nil
 }
 
-func (s *VoiceSession) SelectBestTrack(results []ytdlpSearchResult, targetTitle, targetChannel string, targetDuration time.Duration) ytdlpSearchResult {
-	if len(results) == 0 {
-		return ytdlpSearchResult{}
-	}
-	best := results[0]
-	maxScore := -100.0
-	var corpus []string
-	corpus = append(corpus, targetTitle)
-	for _, res := range results {
-		corpus = append(corpus, normalizeTitle(res.Title, ""))
+// CleanupUser implements voice.OpusFrameReceiver. A member who leaves keeps their track, but their timestamps
+// start over if they come back
+func (r *VoiceRecorder) CleanupUser(userID snowflake.ID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sp := r.speakers[userID]; sp != nil {
+		sp.anchored = false
 	}
-	weights := calculateTFIDF(corpus)
+}
 
-	for _, res := range results {
-		score := 0.0
-		if targetDuration > 0 && res.Duration > 0 {
-			diff := math.Abs(float64(targetDuration - res.Duration))
-			if diff < 2.5*float64(time.Second) {
-				score += 100
-			} else if diff < 6*float64(time.Second) {
-				score += 40
-			}
-		}
-		lowCh := strings.ToLower(res.Uploader)
-		targetCh := strings.ToLower(targetChannel)
-		if targetCh != "" {
-			if lowCh == targetCh {
-				score += 80
-			} else if strings.Contains(lowCh, targetCh) {
-				score += 30
-			}
+// Close implements voice.OpusFrameReceiver; the recording is finished by its session, not by the connection
+func (r *VoiceRecorder) Close() {}
+
+// speaker returns userID's decoder, creating it, and their own track when recording separately, on first sight.
+// A speaker whose decoder can't be set up is remembered as nil so they are skipped rather than retried per packet
+func (r *VoiceRecorder) speaker(userID snowflake.ID) *recordedSpeaker {
+	if sp, ok := r.speakers[userID]; ok {
+		return sp
+	}
+	r.order = append(r.order, userID)
+	sp, err := newRecordedSpeaker()
+	if err == nil && r.separate && len(r.order) <= RecordingMaxSpeakers {
+		name := userID.String()
+		if m, ok := r.client.Caches.Member(r.GuildID, userID); ok {
+			name = m.User.Username
 		}
-		if targetTitle != "" {
-			if weightedSimilarity(normalizeTitle(res.Title, ""), normalizeTitle(targetTitle, ""), weights) {
-				score += 50
-			}
+		path := filepath.Join(r.dir, "voice-"+name+recordingFormats[r.Format].Ext)
+		if sp.track, err = newRecordingTrack(path, r.Format); err != nil {
+			sp.free()
+		} else {
+			sp.track.userID = userID
 		}
+	}
+	if err != nil {
+		LogVoice("Recording in guild %s: can't set up audio for %s: %v", r.GuildID, userID, err)
+		sp = nil
+	}
+	r.speakers[userID] = sp
+	return sp
+}
 
-		if score > maxScore {
-			maxScore = score
-			best = res
+// flush encodes everything that is now older than RecordingJitter. An encoder error ends the recording's capture;
+// Finish reports it
+func (r *VoiceRecorder) flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.err != nil {
+		return
+	}
+	upto := r.now() - recordingJitterSamples
+	for _, rt := range r.tracks() {
+		if err := rt.flush(upto); err != nil {
+			r.err = err
+			LogVoice("Recording in guild %s failed: %v", r.GuildID, err)
+			return
 		}
 	}
-	return best
 }
 
-func extractVideoID(u string) string {
-	u = strings.TrimSpace(u)
-	if strings.Contains(u, "youtu.be/") {
-		parts := strings.Split(u, "youtu.be/")
-		if len(parts) >= 2 {
-			return strings.Split(parts[1], "?")[0]
+// tracks lists the files being written, the mix first and then speakers in the order they first spoke
+func (r *VoiceRecorder) tracks() []*recordingTrack {
+	tracks := []*recordingTrack{r.mix}
+	for _, id := range r.order {
+		if sp := r.speakers[id]; sp != nil && sp.track != nil {
+			tracks = append(tracks, sp.track)
 		}
 	}
+	return tracks
+}
 
-	if !strings.HasPrefix(u, "http") {
-		u = "https://" + u
+// Finish stops capturing and writes out what is still buffered. It returns the tracks written, the mix first
+func (r *VoiceRecorder) Finish() ([]*recordingTrack, time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, 0, errors.New("recording already finished")
 	}
-	parsed, err := url.Parse(u)
-	if err == nil {
-		if v := parsed.Query().Get("v"); v != "" {
-			return v
-		}
-		if id := parsed.Query().Get("id"); id != "" {
-			return id
-		}
-		path := parsed.Path
-		if strings.Contains(path, "/shorts/") {
-			return strings.Split(strings.TrimPrefix(path, "/shorts/"), "/")[0]
-		}
-		if strings.Contains(path, "/embed/") {
-			return strings.Split(strings.TrimPrefix(path, "/embed/"), "/")[0]
+	r.closed = true
+	close(r.stop)
+
+	end := r.now()
+	err := r.err
+	tracks := r.tracks()
+	for _, rt := range tracks {
+		if ferr := rt.finish(end); ferr != nil && err == nil {
+			err = ferr
 		}
-		if strings.Contains(path, "/v/") {
-			return strings.Split(strings.TrimPrefix(path, "/v/"), "/")[0]
+	}
+	for _, sp := range r.speakers {
+		if sp != nil {
+			sp.free()
 		}
 	}
+	return tracks, time.Duration(end) * time.Second / 48000, err
+}
 
-	if len(u) == 11 && !strings.ContainsAny(u, "/?&.") {
-		return u
+// StartRecording starts receiving the session's channel. The result is posted to postTo when it stops, whether by
+// /voice record stop, MaxLength, or the bot leaving
+func (s *VoiceSession) StartRecording(postTo, startedBy snowflake.ID, format string, separate bool) (*VoiceRecorder, error) {
+	f, ok := recordingFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q", format)
 	}
-	return ""
-}
+	s.channelMu.RLock()
+	channelID := s.ChannelID
+	s.channelMu.RUnlock()
 
-func isYouTubeURL(u string) bool {
-	return extractVideoID(u) != "" || strings.Contains(u, "youtube.com") || strings.Contains(u, "youtu.be") || strings.Contains(u, "google.com/url")
-}
-func normalizeTitle(ti, ch string) string {
-	if ti == "" {
-		return ""
+	s.lockQueue()
+	defer s.unlockQueue()
+	if s.Conn == nil {
+		return nil, errors.New("not connected to voice")
 	}
+	if s.recorder != nil {
+		return nil, errors.New("already recording")
+	}
+	dir, err := os.MkdirTemp("", "voice-recording-*")
+	if err != nil {
+		return nil, err
+	}
+	mix, err := newRecordingTrack(filepath.Join(dir, "voice-recording"+f.Ext), format)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	rec := &VoiceRecorder{
+		client:    s.GetClient(),
+		GuildID:   s.GuildID,
+		ChannelID: channelID,
+		PostTo:    postTo,
+		StartedBy: startedBy,
+		Format:    format,
+		MaxLength: min(RecordingMaxDuration, time.Duration(RecordingMaxUpload/f.BytesPerSecond)*time.Second),
+		separate:  separate,
+		started:   time.Now(),
+		dir:       dir,
+		mix:       mix,
+		speakers:  make(map[snowflake.ID]*recordedSpeaker),
+		stop:      make(chan struct{}),
+	}
+	s.recorder = rec
+	s.Conn.SetOpusFrameReceiver(rec)
+	safeGo(func() { s.runRecording(rec) })
+	return rec, nil
+}
+
+// takeRecorder detaches the session's recorder so exactly one caller finishes it; given a recorder, it only
+// detaches that one
+func (s *VoiceSession) takeRecorder(rec *VoiceRecorder) *VoiceRecorder {
+	s.lockQueue()
+	defer s.unlockQueue()
+	cur := s.recorder
+	if cur == nil || (rec != nil && cur != rec) {
+		return nil
+	}
+	s.recorder = nil
+	if s.Conn != nil {
+		s.Conn.SetOpusFrameReceiver(discardOpusReceiver{})
+	}
+	return cur
+}
 
-	tBuf := camelCaseRegex.ReplaceAllString(ti, "${1} ${2}")
-	cBuf := camelCaseRegex.ReplaceAllString(ch, "${1} ${2}")
+// discardOpusReceiver replaces a finished recorder. disgo has no way to stop receiving, so the connection keeps
+// reading and decrypting voice packets until the bot leaves; they are just dropped here
+type discardOpusReceiver struct{}
 
-	t, c := strings.ToLower(tBuf), strings.ToLower(cBuf)
+func (discardOpusReceiver) ReceiveOpusFrame(snowflake.ID, *voice.Packet) error { return nil }
+func (discardOpusReceiver) CleanupUser(snowflake.ID)                           {}
+func (discardOpusReceiver) Close()                                             {}
 
-	for _, sep := range []string{"|", "//", " ─ ", " - "} {
-		if strings.Contains(t, sep) {
-			ps := strings.Split(t, sep)
-			var nps []string
-			for _, p := range ps {
-				pt := strings.TrimSpace(p)
-				shouldStrip := pt == c || pt == strings.ReplaceAll(c, " ", "")
-				if !shouldStrip {
-					nps = append(nps, pt)
+// recordingPosts tracks recordings being uploaded in the background, which Shutdown waits for
+var recordingPosts sync.WaitGroup
+
+// postRecording finishes and uploads rec without holding up the caller
+func postRecording(rec *VoiceRecorder, note string) {
+	recordingPosts.Add(1)
+	safeGo(func() {
+		defer recordingPosts.Done()
+		rec.Post(note)
+	})
+}
+
+func (s *VoiceSession) runRecording(rec *VoiceRecorder) {
+	ticker := time.NewTicker(RecordingFlushInterval)
+	defer ticker.Stop()
+	limit := time.NewTimer(rec.MaxLength)
+	defer limit.Stop()
+	for {
+		select {
+		case <-rec.stop:
+			return
+		case <-limit.C:
+			if s.takeRecorder(rec) != nil {
+				note := fmt.Sprintf("Stopped automatically after %s, the most that fits in one upload.", FormatDuration(rec.MaxLength))
+				if rec.MaxLength == RecordingMaxDuration {
+					note = fmt.Sprintf("Stopped automatically after %s.", FormatDuration(rec.MaxLength))
 				}
+				postRecording(rec, note)
 			}
-			if len(nps) > 0 {
-				t = strings.Join(nps, " ")
-			}
-			break
+			return
+		case <-ticker.C:
+			rec.flush()
 		}
 	}
-	for {
-		t = strings.TrimSpace(t)
-		loc := metadataBlockRegex.FindStringIndex(t)
-		if loc != nil && loc[1] == len(t) {
-			t = t[:loc[0]]
-			continue
-		}
-		break
+}
+
+// upload posts a finished recording through send, starting a new message whenever the next file would take the
+// current one past RecordingMaxUpload. A message that fails is tried RecordingPostAttempts times; the files are
+// deleted afterwards either way, so a recording of people's voices never lingers on disk
+func (r *VoiceRecorder) upload(tracks []*recordingTrack, length time.Duration, note string, send func(Container, []*discord.File) error) {
+	defer os.RemoveAll(r.dir)
+	summary := fmt.Sprintf("%s from <#%s> · started by <@%s> · %d speaker(s)", FormatDuration(max(length, time.Second)), r.ChannelID, r.StartedBy, len(r.order))
+	components := []any{
+		NewTextDisplay("**⏺️ Voice Recording**"),
+		NewTextDisplay(summary),
 	}
-	if c != "" {
-		t = strings.ReplaceAll(t, c, " ")
+	if r.separate && len(r.order) > RecordingMaxSpeakers {
+		note = strings.TrimSpace(note + fmt.Sprintf(" Only the first %d speakers got their own track; everyone is in the mix.", RecordingMaxSpeakers))
 	}
-	var sb strings.Builder
-	for _, r := range t {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-			sb.WriteRune(r)
-		} else {
-			sb.WriteRune(' ')
-		}
+	if note != "" {
+		components = append(components, NewTextDisplay(note))
 	}
-	return strings.Join(strings.Fields(sb.String()), " ")
-}
 
-func calculateTFIDF(corpus []string) map[string]float64 {
-	df := make(map[string]int)
-	total := len(corpus)
-	if total == 0 {
-		return nil
-	}
-	for _, doc := range corpus {
-		seen := make(map[string]bool)
-		for _, w := range strings.Fields(strings.ToLower(doc)) {
-			if !seen[w] {
-				df[w]++
-				seen[w] = true
+	var files []*discord.File
+	var opened []*os.File
+	var size int64
+	ok := true
+	flush := func() {
+		var err error
+		for attempt := 1; attempt <= RecordingPostAttempts; attempt++ {
+			if attempt > 1 {
+				time.Sleep(time.Duration(attempt-1) * RecordingRetryDelay)
+				for _, f := range opened {
+					_, _ = f.Seek(0, io.SeekStart)
+				}
 			}
+			if err = send(NewV2Container(components...), files); err == nil {
+				break
+			}
+			LogVoice("Failed to post recording in guild %s (attempt %d of %d): %v", r.GuildID, attempt, RecordingPostAttempts, err)
+		}
+		if err != nil {
+			ok = false
+		}
+		for _, f := range opened {
+			_ = f.Close()
 		}
+		components = []any{NewTextDisplay("**⏺️ Voice Recording** (continued)")}
+		files, opened, size = nil, nil, 0
 	}
-	weights := make(map[string]float64)
-	for w, count := range df {
-		weights[w] = math.Log(1.0 + float64(total)/float64(count))
+	for _, rt := range tracks {
+		f, err := os.Open(rt.path)
+		if err != nil {
+			LogVoice("Recording in guild %s: can't open %s: %v", r.GuildID, rt.path, err)
+			continue
+		}
+		var n int64
+		if info, err := f.Stat(); err == nil {
+			n = info.Size()
+		}
+		if len(files) > 0 && size+n > RecordingMaxUpload {
+			flush()
+		}
+		opened = append(opened, f)
+		size += n
+		name := filepath.Base(rt.path)
+		if rt.userID != 0 {
+			components = append(components, NewTextDisplay(fmt.Sprintf("<@%s>", rt.userID)))
+		}
+		components = append(components, NewFile("attachment://"+name, ""))
+		files = append(files, discord.NewFile(name, "", f))
 	}
-	return weights
-}
-
-func weightedSimilarity(a, b string, weights map[string]float64) bool {
-	wa, wb := strings.Fields(strings.ToLower(a)), strings.Fields(strings.ToLower(b))
-	sa, sb := make(map[string]bool), make(map[string]bool)
-	union := make(map[string]bool)
+	flush()
 
-	for _, w := range wa {
-		sa[w] = true
-		union[w] = true
-	}
-	for _, w := range wb {
-		sb[w] = true
-		union[w] = true
+	if !ok {
+		LogVoice("Recording in guild %s couldn't be fully posted and was deleted", r.GuildID)
 	}
-	if len(union) == 0 {
-		return false
+}
+
+// Post finishes the recording and uploads it to the channel it was started from
+func (r *VoiceRecorder) Post(note string) {
+	tracks, length, err := r.Finish()
+	if err != nil {
+		_ = os.RemoveAll(r.dir)
+		LogVoice("Recording in guild %s couldn't be saved: %v", r.GuildID, err)
+		_, _ = SendMessageV2(r.client, r.PostTo, fmt.Sprintf("⏹️ The recording of <#%s> stopped but couldn't be saved.", r.ChannelID), nil, nil, nil)
+		return
 	}
-	if a == b {
-		return true
+	r.upload(tracks, length, note, func(container Container, files []*discord.File) error {
+		_, err := SendContainerV2Files(r.client, r.PostTo, container, files)
+		return err
+	})
+}
+
+func handleVoiceRecord(event *events.ApplicationCommandInteractionCreate, data discord.SlashCommandInteractionData) {
+	s, ok := mustGetSession(event)
+	if !ok {
+		return
 	}
+	client := *event.Client()
+	s.channelMu.RLock()
+	channelID := s.ChannelID
+	s.channelMu.RUnlock()
+	vs, inChannel := client.Caches.VoiceState(s.GuildID, event.User().ID)
+	inChannel = inChannel && vs.ChannelID != nil && *vs.ChannelID == channelID
 
-	iScore, uScore := 0.0, 0.0
-	for w := range union {
-		wt := 1.0
-		if weights != nil {
-			if val, ok := weights[w]; ok {
-				wt = val
-			} else {
-				wt = math.Log(1.0 + float64(len(weights)))
+	switch *data.SubCommandName {
+	case "start":
+		if !mustBeVoiceDJ(event) {
+			return
+		}
+		if !inChannel {
+			_ = RespondInteractionV2(client, event, "You must be in my voice channel to record it.", true)
+			return
+		}
+		format := cmp.Or(data.String("format"), "ogg")
+		separate, _ := data.OptBool("separate")
+		rec, err := s.StartRecording(event.Channel().ID(), event.User().ID, format, separate)
+		if err != nil {
+			_ = RespondInteractionV2(client, event, fmt.Sprintf("Failed to start recording: %v", err), true)
+			return
+		}
+		notice := fmt.Sprintf("🔴 **Recording started** by <@%s>. Everything said in <#%s> is being recorded and will be posted in <#%s> when it stops (after %s at most). If you don't want to be recorded, leave the channel or use `/voice record stop`.",
+			rec.StartedBy, rec.ChannelID, rec.PostTo, FormatDuration(rec.MaxLength))
+		_ = RespondInteractionV2(client, event, notice, false)
+		if rec.PostTo != rec.ChannelID {
+			if _, err := SendMessageV2(client, rec.ChannelID, notice, nil, nil, nil); err != nil {
+				LogVoice("Failed to post recording notice in %s: %v", rec.ChannelID, err)
 			}
 		}
-		if sa[w] && sb[w] {
-			iScore += wt
+	case "stop":
+		if !inChannel && !isVoiceDJ(client, event, s) {
+			_ = RespondInteractionV2(client, event, "Only DJs or members of my voice channel can stop a recording.", true)
+			return
 		}
-		uScore += wt
-	}
-	if uScore == 0 {
-		return false
+		rec := s.takeRecorder(nil)
+		if rec == nil {
+			_ = RespondInteractionV2(client, event, "Nothing is being recorded.", true)
+			return
+		}
+		_ = event.DeferCreateMessage(false)
+		tracks, length, err := rec.Finish()
+		if err != nil {
+			_ = os.RemoveAll(rec.dir)
+			LogVoice("Recording in guild %s couldn't be saved: %v", rec.GuildID, err)
+			_ = EditInteractionV2(client, event, "⏹️ Recording stopped, but it couldn't be saved.")
+			return
+		}
+		first := true
+		rec.upload(tracks, length, fmt.Sprintf("Stopped by <@%s>.", event.User().ID), func(container Container, files []*discord.File) error {
+			if !first {
+				return FollowupContainerV2Files(client, event, container, files)
+			}
+			if err := EditInteractionContainerV2Files(client, event, container, files); err != nil {
+				return err
+			}
+			first = false
+			return nil
+		})
 	}
-	return (iScore / uScore) >= 0.7
 }
 
 // ===========================
@@ -4541,6 +9680,9 @@ func (s *VoiceSession) downloadLoop() {
 				ctx, cancel := context.WithCancel(s.cancelCtx)
 				track.cancel = cancel
 
+				if s.processLiveTrack(ctx, track) {
+					return
+				}
 				if err := s.resolveTrackMetadata(ctx, track); err != nil {
 					track.MarkError(err)
 					return